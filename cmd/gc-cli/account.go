@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/timboy697/gc-cli/internal/account"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/offline"
+)
+
+// dataRoot is the profile's data directory before it is narrowed to the
+// signed-in account's.
+var dataRoot string
+
+// scopeDataToAccount points cfg at the state of the account last signed in
+// under the current data root.
+func scopeDataToAccount(cfg *config.Config) error {
+	dataRoot = cfg.DataDir
+	m, err := account.Read(dataRoot)
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		cfg.UseDataDir(account.Dir(dataRoot, m.ID))
+	}
+	return nil
+}
+
+// switchAccount looks up who the saved token belongs to and moves cfg onto
+// that account's state, so a different sign-in never sees the previous
+// account's data.
+func switchAccount(ctx context.Context, cfg *config.Config) error {
+	prev, err := account.Read(dataRoot)
+	if err != nil {
+		return err
+	}
+	client, err := newAPIClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	p, err := adoptAccount(ctx, cfg, client)
+	if err != nil {
+		return err
+	}
+
+	who := p.EmailAddress
+	if who == "" {
+		who = p.Name.FullName
+	}
+	fmt.Printf("Signed in as %s\n", who)
+	if prev != nil && prev.ID != p.ID {
+		fmt.Printf("Switched local data from %s; it is kept until they sign in again.\n", prev.Email)
+	}
+	return nil
+}
+
+// adoptAccount records the client's account as the data root's and, the
+// first time, moves state saved before accounts were tracked into it.
+func adoptAccount(ctx context.Context, cfg *config.Config, client *api.Client) (*api.UserProfile, error) {
+	p, err := client.GetUserProfile(ctx, "me")
+	if err != nil {
+		return nil, err
+	}
+	dir, migrated, err := account.Adopt(dataRoot, p.ID, p.EmailAddress, clk.Now())
+	if err != nil {
+		return nil, err
+	}
+	cfg.UseDataDir(dir)
+	if !migrated {
+		return p, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Moved existing local data to %s\n", dir)
+	st, err := cfg.Store()
+	if err != nil {
+		return p, err
+	}
+	if err := offline.Relocate(st, filepath.Join(dataRoot, "offline"), filepath.Join(dir, "offline")); err != nil {
+		return p, fmt.Errorf("failed to update offline file paths: %w", err)
+	}
+	return p, nil
+}
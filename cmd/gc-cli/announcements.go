@@ -2,15 +2,13 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/timboy697/gc-cli/internal/api"
-	"github.com/timboy697/gc-cli/internal/auth"
 	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/output"
 	"github.com/urfave/cli/v2"
 )
 
@@ -18,17 +16,23 @@ func AnnouncementsCmd(cfg *config.Config) *cli.Command {
 	return &cli.Command{
 		Name:  "announcements",
 		Usage: "list announcements for a course",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
-				Name:     "course",
-				Usage:    "course ID to fetch announcements from",
-				Required: true,
+				Name:  "course",
+				Usage: "course ID to fetch announcements from",
 			},
 			&cli.BoolFlag{
-				Name:  "json",
-				Usage: "output as JSON",
+				Name:  "unread-only",
+				Usage: "only show announcements not yet marked as read",
 			},
-		},
+			&cli.IntFlag{
+				Name:  "preview-lines",
+				Usage: "lines of announcement text to show per row, wrapped to the terminal width",
+				Value: 1,
+			},
+			copyFlag(),
+			interactiveFlag(),
+		}, outputFlags()...),
 		Action: handleAnnouncements(cfg),
 	}
 }
@@ -37,20 +41,25 @@ func handleAnnouncements(cfg *config.Config) func(*cli.Context) error {
 	return func(c *cli.Context) error {
 		ctx := context.Background()
 
-		courseID := c.String("course")
+		courseID, err := courseArg(c)
+		if err != nil {
+			return err
+		}
 		if courseID == "" {
 			return fmt.Errorf("course ID is required (use --course flag)")
 		}
-
-		token, err := auth.GetValidToken(ctx, auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile))
+		previewLines := c.Int("preview-lines")
+		if previewLines < 1 {
+			return fmt.Errorf("--preview-lines must be at least 1")
+		}
+		format, err := outputFormat(c)
 		if err != nil {
-			return fmt.Errorf("authentication required: %w", err)
+			return err
 		}
 
-		authCfg := auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile)
-		client, err := api.NewClientFromToken(ctx, authCfg.OAuth2Config(), token)
+		client, err := newAPIClient(ctx, cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create API client: %w", err)
+			return err
 		}
 
 		announcements, _, err := client.ListAnnouncements(ctx, courseID, 100)
@@ -58,27 +67,60 @@ func handleAnnouncements(cfg *config.Config) func(*cli.Context) error {
 			return fmt.Errorf("failed to list announcements: %w", err)
 		}
 
-		if c.Bool("json") {
-			return outputAnnouncementsJSON(announcements)
+		isUnread, err := unreadFilter(c, cfg)
+		if err != nil {
+			return err
+		}
+		if isUnread != nil {
+			var unread []api.Announcement
+			for _, a := range announcements {
+				if isUnread(a.ID) {
+					unread = append(unread, a)
+				}
+			}
+			announcements = unread
+		}
+
+		announcements, err = filterOutput(announcements)
+		if err != nil {
+			return err
+		}
+
+		if c.Bool("interactive") {
+			return runInteractive(c, "Announcements", announcementRows(announcements), false)
+		}
+
+		if format != output.Table {
+			err = writeOutput(format, announcements, announcementColumns)
+		} else {
+			ppl := newPeople(ctx, cfg, client)
+			err = outputAnnouncementsTable(announcements, ppl, previewLines)
+			ppl.save()
+		}
+		if err != nil {
+			return err
+		}
+
+		targets := make([]copyTarget, len(announcements))
+		for i, a := range announcements {
+			targets[i] = copyTarget{Label: truncate(strings.TrimSpace(stripHTML(a.Text)), 60), ID: a.ID, Link: a.AlternateLink}
 		}
-		return outputAnnouncementsTable(announcements)
+		return copySelection(c, targets)
 	}
 }
 
-func outputAnnouncementsJSON(announcements []api.Announcement) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(announcements)
-}
+// minPreviewWidth keeps the text column usable in narrow terminals.
+const minPreviewWidth = 30
 
-func outputAnnouncementsTable(announcements []api.Announcement) error {
+// outputAnnouncementsTable fits the text column to whatever width the other
+// columns leave, showing up to lines wrapped lines of each announcement.
+func outputAnnouncementsTable(announcements []api.Announcement, ppl *people, lines int) error {
 	if len(announcements) == 0 {
 		fmt.Println("No announcements")
 		return nil
 	}
 
 	idWidth := 12
-	textWidth := 50
 	authorWidth := 15
 	dateWidth := 20
 
@@ -86,16 +128,18 @@ func outputAnnouncementsTable(announcements []api.Announcement) error {
 		if len(a.ID) > idWidth {
 			idWidth = len(a.ID)
 		}
-		textLen := len(strings.TrimSpace(stripHTML(a.Text)))
-		if textLen > textWidth {
-			textWidth = textLen
-		}
-		authorLen := len(a.CreatorUserID)
+		// Room for the two-cell avatar and a space before the name.
+		authorLen := len(ppl.Name(a.CreatorUserID)) + 3
 		if authorLen > authorWidth {
 			authorWidth = authorLen
 		}
 	}
 
+	textWidth := terminalWidth() - idWidth - authorWidth - dateWidth
+	if textWidth < minPreviewWidth {
+		textWidth = minPreviewWidth
+	}
+
 	header := lipgloss.JoinHorizontal(
 		lipgloss.Left,
 		headerStyle.Width(idWidth).Render("ID"),
@@ -103,20 +147,19 @@ func outputAnnouncementsTable(announcements []api.Announcement) error {
 		headerStyle.Width(authorWidth).Render("Author"),
 		headerStyle.Width(dateWidth).Render("Posted Date"),
 	)
-	separator := separatorStyle.Render("─")
-
 	fmt.Println(header)
-	fmt.Println(lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		separator+separator+separator+separator,
-	))
+	fmt.Println(separatorStyle.Render(strings.Repeat("─", idWidth+textWidth+authorWidth+dateWidth)))
 
-	for _, a := range announcements {
+	for i, a := range announcements {
+		if lines > 1 && i > 0 {
+			fmt.Println()
+		}
+		preview := previewText(stripHTML(a.Text), textWidth-2, lines)
 		row := lipgloss.JoinHorizontal(
-			lipgloss.Left,
-			cellStyle.Width(idWidth).Render(truncate(a.ID, idWidth)),
-			cellStyle.Width(textWidth).Render(truncate(strings.TrimSpace(stripHTML(a.Text)), textWidth)),
-			cellStyle.Width(authorWidth).Render(truncate(a.CreatorUserID, authorWidth)),
+			lipgloss.Top,
+			cellStyle.Width(idWidth).Render(truncate(displayID(a.ID), idWidth)),
+			cellStyle.Width(textWidth).Render(strings.Join(preview, "\n")),
+			cellStyle.Width(authorWidth).Render(ppl.Badge(a.CreatorUserID)+" "+truncate(ppl.Name(a.CreatorUserID), authorWidth-3)),
 			cellStyle.Width(dateWidth).Render(a.CreationTime.Format("2006-01-02 15:04")),
 		)
 		fmt.Println(row)
@@ -127,6 +170,48 @@ func outputAnnouncementsTable(announcements []api.Announcement) error {
 	return nil
 }
 
+// previewText wraps text into lines of at most width characters and keeps
+// the first n, ending the last with "..." if anything was cut.
+func previewText(text string, width, n int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		w := []rune(word)
+		// Words too long for a line are split across lines.
+		for len(w) > width {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(w[:width]))
+			w = w[width:]
+		}
+		switch {
+		case len(line) == 0:
+			line = w
+		case len(line)+1+len(w) <= width:
+			line = append(append(line, ' '), w...)
+		default:
+			lines = append(lines, string(line))
+			line = w
+		}
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+
+	if len(lines) <= n {
+		return lines
+	}
+	lines = lines[:n]
+	last := []rune(lines[n-1])
+	if len(last)+3 > width {
+		last = last[:width-3]
+	}
+	lines[n-1] = string(last) + "..."
+	return lines
+}
+
 func stripHTML(s string) string {
 	s = strings.ReplaceAll(s, "<br>", " ")
 	s = strings.ReplaceAll(s, "<br/>", " ")
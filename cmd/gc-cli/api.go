@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func APICmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "api",
+		Usage: "send a raw request to the Classroom API and print the JSON response",
+		Description: "Paths are relative to https://classroom.googleapis.com/v1, e.g.\n" +
+			"'gc-cli api get /courses/123/courseWork --param pageSize=5'. Full\n" +
+			"googleapis.com URLs reach companion APIs such as Drive.",
+		Subcommands: []*cli.Command{
+			apiMethodCmd(cfg, http.MethodGet, false),
+			apiMethodCmd(cfg, http.MethodPost, true),
+			apiMethodCmd(cfg, http.MethodPatch, true),
+			apiMethodCmd(cfg, http.MethodPut, true),
+			apiMethodCmd(cfg, http.MethodDelete, false),
+		},
+	}
+}
+
+func apiMethodCmd(cfg *config.Config, method string, hasBody bool) *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "param",
+			Usage: "query parameter as key=value (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "compact",
+			Usage: "print the response as received instead of indented",
+		},
+	}
+	if hasBody {
+		flags = append(flags, &cli.StringFlag{
+			Name:  "body",
+			Usage: "JSON request body, @file.json to read a file, or - for stdin",
+		})
+	}
+	return &cli.Command{
+		Name:      strings.ToLower(method),
+		Usage:     "send a " + method + " request",
+		ArgsUsage: "<path>",
+		Flags:     flags,
+		Action:    handleAPIRequest(cfg, method),
+	}
+}
+
+func handleAPIRequest(cfg *config.Config, method string) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		req, err := parseAPIRequest(c)
+		if err != nil {
+			return err
+		}
+		params, err := parseAPIParams(req.params)
+		if err != nil {
+			return err
+		}
+		body, err := readAPIBody(req.body)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Raw(ctx, method, req.path, params, body)
+		if err != nil {
+			return err
+		}
+
+		if !req.compact {
+			var out bytes.Buffer
+			if json.Indent(&out, resp, "", "  ") == nil {
+				resp = out.Bytes()
+			}
+		}
+		os.Stdout.Write(resp)
+		if len(resp) > 0 && resp[len(resp)-1] != '\n' {
+			fmt.Println()
+		}
+		return nil
+	}
+}
+
+type apiRequest struct {
+	path    string
+	params  []string
+	body    string
+	compact bool
+}
+
+// parseAPIRequest reads the path and flags. urfave/cli stops parsing flags
+// at the first argument, but the path reads best first, so flags after it
+// are parsed here and combined with any given before it.
+func parseAPIRequest(c *cli.Context) (*apiRequest, error) {
+	usage := fmt.Errorf("usage: gc-cli api %s <path> [--param key=value]...", c.Command.Name)
+	if c.Args().Len() < 1 {
+		return nil, usage
+	}
+	req := &apiRequest{
+		path:    c.Args().First(),
+		params:  c.StringSlice("param"),
+		body:    c.String("body"),
+		compact: c.Bool("compact"),
+	}
+	if c.Args().Len() == 1 {
+		return req, nil
+	}
+
+	fs := flag.NewFlagSet(c.Command.Name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	for _, f := range c.Command.Flags {
+		if err := f.Apply(fs); err != nil {
+			return nil, err
+		}
+	}
+	if err := fs.Parse(c.Args().Tail()); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, usage
+	}
+	tail := cli.NewContext(c.App, fs, nil)
+	req.params = append(req.params, tail.StringSlice("param")...)
+	if tail.IsSet("body") {
+		req.body = tail.String("body")
+	}
+	req.compact = req.compact || tail.Bool("compact")
+	return req, nil
+}
+
+func parseAPIParams(pairs []string) (url.Values, error) {
+	params := url.Values{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --param %q: want key=value", pair)
+		}
+		params.Add(key, value)
+	}
+	return params, nil
+}
+
+// readAPIBody returns nil when no body was given, so GET-style requests
+// aren't sent an empty one.
+func readAPIBody(arg string) ([]byte, error) {
+	var body []byte
+	var err error
+	switch {
+	case arg == "":
+		return nil, nil
+	case arg == "-":
+		body, err = io.ReadAll(os.Stdin)
+	case strings.HasPrefix(arg, "@"):
+		body, err = os.ReadFile(arg[1:])
+	default:
+		body = []byte(arg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("request body is not valid JSON")
+	}
+	return body, nil
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/cache"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	cacheProfiles  = "profiles"
+	cacheRoles     = "roles"
+	cacheAvatars   = "avatars"
+	cacheResponses = "responses"
+	cacheAll       = "all"
+)
+
+var cacheKinds = []string{offline.Coursework, offline.Announcements, offline.Files, cacheProfiles, cacheRoles, cacheAvatars, cacheResponses}
+
+// noCache is set by --no-cache.
+var noCache bool
+
+// responseCache returns the API response cache for the current account, or
+// nil when caching is off. The in-memory storage backend promises to keep
+// nothing between runs, so it gets no cache either.
+func responseCache(cfg *config.Config) *cache.Cache {
+	if noCache || cfg.Storage.Backend == "memory" {
+		return nil
+	}
+	return openResponseCache(cfg)
+}
+
+// openResponseCache opens the cache under the user cache directory, in a
+// directory named after the account's data directory so accounts and
+// profiles never share responses.
+func openResponseCache(cfg *config.Config) *cache.Cache {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = filepath.Join(cfg.DataDir, "cache")
+	}
+	sum := sha256.Sum256([]byte(cfg.DataDir))
+	dir := filepath.Join(base, "gc-cli", hex.EncodeToString(sum[:6]))
+	return cache.Open(dir, cache.TTLs{
+		cache.Courses:       cfg.Cache.Courses,
+		cache.Coursework:    cfg.Cache.Coursework,
+		cache.Announcements: cfg.Cache.Announcements,
+		cache.Submissions:   cfg.Cache.Submissions,
+	}, clk.Now)
+}
+
+func CacheCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "inspect or clear locally cached data",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "status",
+				Usage:  "show what is cached, how big it is and how old",
+				Action: handleCacheStatus(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "only show this course",
+					},
+				},
+			},
+			{
+				Name:   "clear",
+				Usage:  "drop cached data so it is fetched again",
+				Action: handleCacheClear(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "only clear data for this course",
+					},
+					&cli.StringFlag{
+						Name:  "what",
+						Usage: "what to clear: " + strings.Join(cacheKinds, ", ") + " or all",
+						Value: cacheAll,
+					},
+				},
+			},
+		},
+	}
+}
+
+type cacheEntry struct {
+	Collection string
+	Course     string
+	Items      int
+	Size       int64
+	Updated    time.Time
+}
+
+func jsonSize(v any) int64 {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
+func handleCacheStatus(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		courseID, err := optionalCourseArg(c)
+		if err != nil {
+			return err
+		}
+
+		var entries []cacheEntry
+
+		snap, err := offline.Load(st)
+		if err != nil {
+			return err
+		}
+		for _, oc := range snap.Courses {
+			if courseID != "" && oc.Course.ID != courseID {
+				continue
+			}
+			updated := snap.Age(oc)
+			entries = append(entries,
+				cacheEntry{offline.Coursework, oc.Course.Name, len(oc.Coursework), jsonSize(oc.Coursework), updated},
+				cacheEntry{offline.Announcements, oc.Course.Name, len(oc.Announcements), jsonSize(oc.Announcements), updated},
+			)
+			files := cacheEntry{Collection: offline.Files, Course: oc.Course.Name, Updated: updated}
+			for _, f := range oc.Files {
+				if f.Path != "" {
+					files.Items++
+					files.Size += f.Size
+				}
+			}
+			entries = append(entries, files)
+		}
+
+		roles, err := st.Roles()
+		if err != nil {
+			return err
+		}
+		rolesEntry := cacheEntry{Collection: cacheRoles, Course: "all"}
+		for id, cr := range roles.Courses {
+			if courseID != "" && id != courseID {
+				continue
+			}
+			rolesEntry.Items++
+			rolesEntry.Size += jsonSize(cr)
+			rolesEntry.Updated = oldest(rolesEntry.Updated, cr.Checked)
+		}
+		if courseID != "" {
+			rolesEntry.Course = courseID
+		}
+		entries = append(entries, rolesEntry)
+
+		if courseID == "" {
+			profiles, err := st.Profiles()
+			if err != nil {
+				return err
+			}
+			profilesEntry := cacheEntry{Collection: cacheProfiles, Course: "all", Items: len(profiles.Users)}
+			for _, p := range profiles.Users {
+				profilesEntry.Size += jsonSize(p)
+				profilesEntry.Updated = oldest(profilesEntry.Updated, p.Fetched)
+			}
+			responses, err := responseCacheEntry(cfg)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, profilesEntry, avatarCacheEntry(cfg), responses)
+		}
+
+		if snap.SyncedAt.IsZero() {
+			fmt.Println("No offline data; run 'gc-cli sync' to cache courses.")
+			fmt.Println()
+		}
+		return outputCacheStatus(entries)
+	}
+}
+
+func avatarCacheEntry(cfg *config.Config) cacheEntry {
+	entry := cacheEntry{Collection: cacheAvatars, Course: "all"}
+	files, _ := os.ReadDir(filepath.Join(cfg.DataDir, "avatars"))
+	for _, f := range files {
+		info, err := f.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+		entry.Items++
+		entry.Size += info.Size()
+		entry.Updated = oldest(entry.Updated, info.ModTime())
+	}
+	return entry
+}
+
+func responseCacheEntry(cfg *config.Config) (cacheEntry, error) {
+	n, size, fetched, err := openResponseCache(cfg).Usage()
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	return cacheEntry{Collection: cacheResponses, Course: "all", Items: n, Size: size, Updated: fetched}, nil
+}
+
+func oldest(current, t time.Time) time.Time {
+	if current.IsZero() || (!t.IsZero() && t.Before(current)) {
+		return t
+	}
+	return current
+}
+
+func formatAge(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
+
+func outputCacheStatus(entries []cacheEntry) error {
+	collectionWidth := 16
+	courseWidth := 32
+	itemsWidth := 8
+	sizeWidth := 12
+	ageWidth := 14
+
+	header := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(collectionWidth).Render("Collection"),
+		headerStyle.Width(courseWidth).Render("Course"),
+		headerStyle.Width(itemsWidth).Render("Items"),
+		headerStyle.Width(sizeWidth).Render("Size"),
+		headerStyle.Width(ageWidth).Render("Oldest"),
+	)
+	separator := separatorStyle.Render("─")
+
+	fmt.Println(header)
+	fmt.Println(lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		separator+separator+separator+separator,
+	))
+
+	now := clk.Now()
+	var total int64
+	for _, e := range entries {
+		total += e.Size
+		row := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(collectionWidth).Render(e.Collection),
+			cellStyle.Width(courseWidth).Render(truncate(e.Course, courseWidth-2)),
+			cellStyle.Width(itemsWidth).Render(fmt.Sprintf("%d", e.Items)),
+			cellStyle.Width(sizeWidth).Render(formatBytes(e.Size)),
+			cellStyle.Width(ageWidth).Render(formatAge(e.Updated, now)),
+		)
+		fmt.Println(row)
+	}
+
+	fmt.Println()
+	fmt.Printf("Total: %s\n", formatBytes(total))
+	return nil
+}
+
+func handleCacheClear(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		what := strings.ToLower(c.String("what"))
+		kinds := []string{what}
+		if what == cacheAll {
+			kinds = cacheKinds
+		} else if !isCacheKind(what) {
+			return fmt.Errorf("unknown cache %q (use %s or all)", what, strings.Join(cacheKinds, ", "))
+		}
+
+		courseID, err := optionalCourseArg(c)
+		if err != nil {
+			return err
+		}
+		if courseID != "" && (what == cacheProfiles || what == cacheAvatars || what == cacheResponses) {
+			return fmt.Errorf("%s are shared across courses and can't be cleared per course", what)
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+
+		for _, kind := range kinds {
+			var removed int
+			switch kind {
+			case cacheProfiles, cacheAvatars:
+				if courseID != "" {
+					continue
+				}
+				if removed, err = clearShared(cfg, st, kind); err != nil {
+					return err
+				}
+			case cacheResponses:
+				if courseID != "" {
+					continue
+				}
+				if removed, err = openResponseCache(cfg).Clear(); err != nil {
+					return err
+				}
+			case cacheRoles:
+				if removed, err = clearRoles(st, courseID); err != nil {
+					return err
+				}
+			default:
+				if removed, err = clearOffline(st, courseID, kind); err != nil {
+					return err
+				}
+			}
+			fmt.Printf("Cleared %s (%d item(s))\n", kind, removed)
+		}
+		return nil
+	}
+}
+
+func isCacheKind(kind string) bool {
+	for _, k := range cacheKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func clearOffline(st *store.Store, courseID, what string) (int, error) {
+	snap, err := offline.Load(st)
+	if err != nil {
+		return 0, err
+	}
+	removed, err := snap.Forget(courseID, what)
+	if err != nil {
+		return removed, err
+	}
+	if err := offline.Save(st, snap); err != nil {
+		return removed, fmt.Errorf("failed to save offline data: %w", err)
+	}
+	return removed, nil
+}
+
+func clearRoles(st *store.Store, courseID string) (int, error) {
+	roles, err := st.Roles()
+	if err != nil {
+		return 0, err
+	}
+	removed := len(roles.Courses)
+	if courseID == "" {
+		roles.Courses = map[string]store.CourseRole{}
+	} else if _, ok := roles.Courses[courseID]; ok {
+		delete(roles.Courses, courseID)
+		removed = 1
+	} else {
+		removed = 0
+	}
+	return removed, st.SaveRoles(roles)
+}
+
+func clearShared(cfg *config.Config, st *store.Store, kind string) (int, error) {
+	if kind == cacheProfiles {
+		profiles, err := st.Profiles()
+		if err != nil {
+			return 0, err
+		}
+		removed := len(profiles.Users)
+		return removed, st.SaveProfiles(&store.Profiles{Users: map[string]store.CachedProfile{}})
+	}
+
+	dir := filepath.Join(cfg.DataDir, "avatars")
+	files, _ := os.ReadDir(dir)
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("failed to delete %s: %w", dir, err)
+	}
+	return len(files), nil
+}
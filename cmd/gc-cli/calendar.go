@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/coursename"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/feed"
+	"github.com/urfave/cli/v2"
+)
+
+func CalendarCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "calendar",
+		Usage: "export due dates for calendar apps",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "export",
+				Usage: "write coursework due dates across all active courses as an iCalendar (.ics) file",
+				Description: "Each assignment with a due date becomes an event at its due time (all day if\n" +
+					"it has no time). Event IDs stay the same between exports, so re-exporting to\n" +
+					"a file your calendar app subscribes to updates moved deadlines in place.",
+				Action: handleCalendarExport(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "out",
+						Usage: "write the calendar to this file (default: stdout)",
+					},
+					&cli.StringSliceFlag{
+						Name:  "course",
+						Usage: "only include this course, by ID, link or name (repeatable)",
+					},
+					&cli.IntFlag{
+						Name:  "days",
+						Usage: "only include work due in the next N days",
+					},
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "include work already turned in",
+					},
+					&cli.BoolFlag{
+						Name:  "todos",
+						Usage: "write tasks (VTODO) instead of events, for task apps",
+					},
+					&cli.BoolFlag{
+						Name:  "per-course",
+						Usage: "write a calendar per course into the --out directory instead of one for all",
+					},
+				},
+			},
+			{
+				Name:  "serve",
+				Usage: "serve due dates from the last sync for calendar apps to subscribe to",
+				Description: "All courses are at /calendar.ics and each course at /courses/<course-id>.ics.\n" +
+					"They are rebuilt from the last 'gc-cli sync' on every request, so schedule a\n" +
+					"sync to keep subscriptions fresh. The webcal:// links are printed at start-up.",
+				Action: handleCalendarServe(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "address to listen on (use :8788 to accept other devices on your network)",
+						Value: "127.0.0.1:8788",
+					},
+					&cli.StringFlag{
+						Name:    "token",
+						Usage:   "require ?token=TOKEN in calendar URLs",
+						EnvVars: []string{"GC_CLI_CALENDAR_TOKEN"},
+					},
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "include work already turned in",
+					},
+					&cli.BoolFlag{
+						Name:  "todos",
+						Usage: "serve tasks (VTODO) instead of events, for task apps",
+					},
+				},
+			},
+		},
+	}
+}
+
+// combinedCalendarName titles the calendar of every course.
+const combinedCalendarName = "Classroom due dates"
+
+func handleCalendarExport(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		days := c.Int("days")
+		if c.IsSet("days") && days < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		items, err := agenda.Collect(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		courses := c.StringSlice("course")
+		now := clk.Now()
+		var export []agenda.Item
+		var exported []api.Course
+		matched := make(map[string]bool)
+		seen := make(map[string]bool)
+		for _, item := range items {
+			want, ok := calendarCourseWanted(item, courses)
+			if !ok {
+				continue
+			}
+			matched[want] = true
+			// Every wanted course gets a calendar with --per-course, even one
+			// with nothing due, so a stale file isn't left behind.
+			if !seen[item.Course.ID] {
+				seen[item.Course.ID] = true
+				exported = append(exported, item.Course)
+			}
+			if !keepCalendarItem(item, c.Bool("all"), days, now) {
+				continue
+			}
+			export = append(export, item)
+		}
+		for _, course := range courses {
+			if !matched[course] {
+				return fmt.Errorf("no active course matches %q", course)
+			}
+		}
+
+		out := c.String("out")
+		if c.Bool("per-course") {
+			if out == "" {
+				return fmt.Errorf("--per-course writes a file per course; give a directory with --out")
+			}
+			courseorder.New(cfg.Courses).SortCourses(exported)
+			return writeCourseCalendars(out, exported, export, c.Bool("todos"), now)
+		}
+		write := func(w io.Writer) error {
+			return feed.WriteICS(w, combinedCalendarName, export, c.Bool("todos"), now)
+		}
+		if out == "" {
+			return write(os.Stdout)
+		}
+		if err := writeReportFile(out, write); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d due dates to %s\n", countDated(export), out)
+		return nil
+	}
+}
+
+// keepCalendarItem drops work already turned in unless all is set, and with
+// days, work not due within that many days.
+func keepCalendarItem(item agenda.Item, all bool, days int, now time.Time) bool {
+	if item.Done() && !all {
+		return false
+	}
+	if due := item.Due(); days > 0 && (due.IsZero() || due.Before(now) || !due.Before(now.AddDate(0, 0, days))) {
+		return false
+	}
+	return true
+}
+
+// writeCourseCalendars writes each course's items to its own file in dir,
+// named after the course, so each can be subscribed to and coloured
+// separately.
+func writeCourseCalendars(dir string, courses []api.Course, items []agenda.Item, todos bool, now time.Time) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	byCourse := make(map[string][]agenda.Item)
+	for _, item := range items {
+		byCourse[item.Course.ID] = append(byCourse[item.Course.ID], item)
+	}
+	labels := coursename.Labels(courses, nil)
+	for _, course := range courses {
+		label := labels[course.ID]
+		path := filepath.Join(dir, calendarFileName(label))
+		courseItems := byCourse[course.ID]
+		err := writeReportFile(path, func(w io.Writer) error {
+			return feed.WriteICS(w, label+" due dates", courseItems, todos, now)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d due dates to %s\n", countDated(courseItems), path)
+	}
+	return nil
+}
+
+// calendarFileName turns a course label into a file name, e.g. "CS 101:
+// Intro" into "cs-101-intro.ics".
+func calendarFileName(label string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(label) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString("course")
+	}
+	return b.String() + ".ics"
+}
+
+// calendarCourseWanted reports whether item's course is one of the --course
+// values, and which one matched. With no --course every course is wanted.
+func calendarCourseWanted(item agenda.Item, courses []string) (string, bool) {
+	if len(courses) == 0 {
+		return "", true
+	}
+	for _, course := range courses {
+		if resolveCourseID(course) == item.Course.ID || strings.EqualFold(course, item.Course.Name) {
+			return course, true
+		}
+	}
+	return "", false
+}
+
+func countDated(items []agenda.Item) int {
+	n := 0
+	for _, item := range items {
+		if !item.Due().IsZero() {
+			n++
+		}
+	}
+	return n
+}
+
+func handleCalendarServe(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+		token := c.String("token")
+		all, todos := c.Bool("all"), c.Bool("todos")
+
+		// Demo mode has no sync to read, so it serves the demo courses.
+		open := func(ctx context.Context) (*api.Client, time.Time, error) {
+			if demoMode {
+				client, err := newAPIClient(ctx, cfg)
+				return client, clk.Now(), err
+			}
+			return openSyncedClient(ctx, cfg)
+		}
+
+		serveCalendar := func(w http.ResponseWriter, r *http.Request, courseID string) {
+			if token != "" && subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(token)) != 1 {
+				http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+				return
+			}
+			client, syncedAt, err := open(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			items, err := agenda.Collect(r.Context(), client)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+
+			now := clk.Now()
+			name := combinedCalendarName
+			found := courseID == ""
+			var export []agenda.Item
+			for _, item := range items {
+				if courseID != "" {
+					if item.Course.ID != courseID {
+						continue
+					}
+					name, found = item.Course.Name+" due dates", true
+				}
+				if keepCalendarItem(item, all, 0, now) {
+					export = append(export, item)
+				}
+			}
+			if !found {
+				http.NotFound(w, r)
+				return
+			}
+
+			var buf bytes.Buffer
+			if err := feed.WriteICS(&buf, name, export, todos, now); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+			w.Header().Set("Last-Modified", syncedAt.UTC().Format(http.TimeFormat))
+			_, _ = w.Write(buf.Bytes())
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/calendar.ics", func(w http.ResponseWriter, r *http.Request) {
+			serveCalendar(w, r, "")
+		})
+		mux.HandleFunc("/courses/", func(w http.ResponseWriter, r *http.Request) {
+			id := strings.TrimPrefix(r.URL.Path, "/courses/")
+			if !strings.HasSuffix(id, ".ics") || strings.Contains(id, "/") {
+				http.NotFound(w, r)
+				return
+			}
+			serveCalendar(w, r, strings.TrimSuffix(id, ".ics"))
+		})
+
+		addr := c.String("listen")
+		base := calendarBaseURL(addr)
+		link := func(path string) string {
+			if token != "" {
+				path += "?token=" + url.QueryEscape(token)
+			}
+			return base + path
+		}
+		var courses []api.Course
+		if client, _, err := open(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; calendars will fail until you run 'gc-cli sync'\n", err)
+		} else if listed, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive)); err == nil {
+			for _, course := range listed {
+				if course.CourseState == api.CourseActive {
+					courses = append(courses, course)
+				}
+			}
+			courseorder.New(cfg.Courses).SortCourses(courses)
+		}
+
+		fmt.Printf("Serving due dates on %s\n\n", addr)
+		fmt.Printf("  %-36s %s\n", "All courses", link("/calendar.ics"))
+		labels := coursename.Labels(courses, nil)
+		for _, course := range courses {
+			fmt.Printf("  %-36s %s\n", truncate(labels[course.ID], 36), link("/courses/"+url.PathEscape(course.ID)+".ics"))
+		}
+		fmt.Println()
+		return http.ListenAndServe(addr, mux)
+	}
+}
+
+// calendarBaseURL is the webcal:// address calendar apps subscribe to. An
+// address without a host listens everywhere, so it's shown as localhost.
+func calendarBaseURL(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "webcal://" + addr
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return "webcal://" + net.JoinHostPort(host, port)
+}
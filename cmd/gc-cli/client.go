@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/account"
+	"github.com/timboy697/gc-cli/internal/anonymize"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/logging"
+	"github.com/urfave/cli/v2"
+)
+
+// apiStats collects request metrics across every client created during a
+// single command so the --verbose footer can summarize them.
+var apiStats = api.NewStats()
+
+// apiLog traces requests when --verbose, --debug or --debug-file is given,
+// and is nil otherwise.
+var apiLog *logging.Logger
+
+// keepTokenFresh is set by long-running commands, whose clients renew the
+// access token auth.refresh_margin before it expires and save it.
+var keepTokenFresh bool
+
+// retryPolicy is how clients retry rate-limited and failed requests.
+var retryPolicy = api.DefaultRetryPolicy()
+
+// backOffPatiently is for commands that run for a long time, like the TUI
+// and watch. They can afford to wait out a rate limit, and coming back
+// early would only prolong it.
+func backOffPatiently() {
+	retryPolicy.Retries = 5
+	retryPolicy.MaxBackoff = time.Minute
+	retryPolicy.Jitter = 0.5
+	retryPolicy.MaxRetryAfter = 5 * time.Minute
+}
+
+// shownNotices are the notes on where data comes from printed so far. A
+// command may make more than one client but says each note once.
+var shownNotices = make(map[string]bool)
+
+func noticeOnce(msg string) {
+	if !shownNotices[msg] {
+		shownNotices[msg] = true
+		fmt.Fprintln(os.Stderr, msg)
+	}
+}
+
+func newAPIClient(ctx context.Context, cfg *config.Config) (*api.Client, error) {
+	if demoMode {
+		return newDemoClient(ctx)
+	}
+	if assumeDisabled {
+		return newOfflineClient(ctx, cfg)
+	}
+	authCfg := cfg.AuthConfig()
+
+	token, err := auth.GetValidToken(ctx, authCfg)
+	if err != nil {
+		return nil, fmt.Errorf("authentication required: %w", err)
+	}
+	if err := checkScopes(cfg); err != nil {
+		return nil, err
+	}
+
+	opts := []api.Option{api.WithStats(apiStats), api.WithLogger(apiLog), api.WithRetryPolicy(retryPolicy)}
+	if rc := responseCache(cfg); rc != nil {
+		opts = append(opts, api.WithCache(rc))
+	}
+	if cfg.ReadOnly {
+		opts = append(opts, api.WithReadOnly())
+	}
+	if cfg.Anonymize {
+		anon, err := newAnonymizer(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, api.WithResponseFilter(anon.JSON))
+	}
+
+	var client *api.Client
+	if keepTokenFresh {
+		client, err = api.NewClient(ctx, auth.RefreshingTokenSource(ctx, authCfg, token, cfg.Auth.RefreshMargin), opts...)
+	} else {
+		client, err = api.NewClientFromToken(ctx, authCfg.OAuth2Config(), token, opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+
+	// Tokens saved before accounts were tracked are matched to their account
+	// on first use. Failing that, state stays where it is until next time.
+	if dataRoot != "" && !cfg.StoreOpened() {
+		if m, err := account.Read(dataRoot); err == nil && m == nil {
+			adoptAccount(ctx, cfg, client)
+		}
+	}
+
+	return client, nil
+}
+
+func newAnonymizer(cfg *config.Config) (*anonymize.Anonymizer, error) {
+	st, err := cfg.Store()
+	if err != nil {
+		return nil, err
+	}
+	salt, err := st.AnonymizeSalt()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load anonymization salt: %w", err)
+	}
+	return anonymize.New(salt), nil
+}
+
+func printStatsFooter(w io.Writer, elapsed time.Duration, stats *api.Stats) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, separatorStyle.Render("── summary ──"))
+	fmt.Fprintf(w, "Time:       %s\n", elapsed.Round(time.Millisecond))
+	fmt.Fprintf(w, "API calls:  %d\n", stats.TotalCalls())
+	for _, ec := range stats.Calls() {
+		fmt.Fprintf(w, "  %4d  %s\n", ec.Calls, ec.Endpoint)
+	}
+	fmt.Fprintf(w, "Cache hits: %d\n", stats.CacheHits())
+	fmt.Fprintf(w, "Bytes:      %s\n", formatBytes(stats.Bytes()))
+	fmt.Fprintf(w, "Retries:    %d\n", stats.Retries())
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// startLogging sets up apiLog from the global flags. The returned function
+// closes the dump file, if any.
+func startLogging(c *cli.Context) (func() error, error) {
+	closeFn := func() error { return nil }
+	var level logging.Level
+	switch {
+	case c.Bool("debug"):
+		level = logging.LevelDebug
+	case c.Bool("verbose"):
+		level = logging.LevelInfo
+	case c.String("debug-file") == "":
+		return closeFn, nil
+	}
+
+	var w io.Writer
+	if c.Bool("debug") || c.Bool("verbose") {
+		w = os.Stderr
+	}
+	apiLog = logging.New(w, level)
+	if path := c.String("debug-file"); path != "" {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+		if err != nil {
+			return closeFn, fmt.Errorf("failed to open debug file: %w", err)
+		}
+		apiLog.DumpBodies(f)
+		closeFn = f.Close
+	}
+	return closeFn, nil
+}
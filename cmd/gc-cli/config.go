@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func ConfigCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "manage the config file",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "migrate",
+				Usage: "update the config file to this version's layout",
+				Description: "gc-cli does this by itself the first time it runs after an upgrade, saving\n" +
+					"the old file as config.yaml.v<N>.bak. --dry-run shows what would change.",
+				Action: handleConfigMigrate(cfg),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "show the changes without writing them",
+					},
+				},
+			},
+		},
+	}
+}
+
+func handleConfigMigrate(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		m, err := config.PlanMigration(cfg.ConfigPath)
+		if err != nil {
+			return err
+		}
+		switch {
+		case m == nil:
+			fmt.Printf("No config file at %s; nothing to migrate.\n", cfg.ConfigPath)
+			return nil
+		case m.Newer():
+			return fmt.Errorf("%s is version %d, newer than this gc-cli understands (%d); upgrade gc-cli instead", cfg.ConfigPath, m.From, config.SchemaVersion)
+		case !m.Needed():
+			fmt.Printf("%s is up to date (version %d).\n", cfg.ConfigPath, m.From)
+			return nil
+		}
+
+		if c.Bool("dry-run") {
+			fmt.Printf("Migrating %s from version %d to %d changes:\n", cfg.ConfigPath, m.From, m.To)
+			for _, change := range m.Changes {
+				fmt.Println("  - " + change)
+			}
+			fmt.Println()
+			outputTextDiff(filepath.Base(cfg.ConfigPath), string(m.Before), string(m.After), 3)
+			return nil
+		}
+		if err := m.Apply(); err != nil {
+			return err
+		}
+		reportMigration(os.Stdout, m)
+		return nil
+	}
+}
+
+// migrateConfig writes back a config file Load had to migrate, so the
+// upgrade happens once. 'config' commands are left to do it themselves.
+func migrateConfig(c *cli.Context, cfg *config.Config) {
+	m := cfg.Migration
+	if m == nil || c.Args().First() == "config" {
+		return
+	}
+	if m.Newer() {
+		fmt.Fprintf(os.Stderr, "Warning: %s is version %d, newer than this gc-cli understands (%d); some settings may be ignored\n", m.Path, m.From, config.SchemaVersion)
+		return
+	}
+	if !m.Needed() {
+		return
+	}
+	if err := m.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s uses an older layout and couldn't be updated (%v); run 'gc-cli config migrate'\n", m.Path, err)
+		return
+	}
+	reportMigration(os.Stderr, m)
+}
+
+func reportMigration(w io.Writer, m *config.Migration) {
+	fmt.Fprintf(w, "Updated %s from version %d to %d (the old file is at %s); changes:\n", m.Path, m.From, m.To, m.Backup)
+	for _, change := range m.Changes {
+		fmt.Fprintln(w, "  - "+change)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/clipboard"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+// copyTarget is a row of list output that --copy can place on the clipboard.
+type copyTarget struct {
+	Label string
+	ID    string
+	Link  string
+}
+
+func copyFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "copy",
+		Usage: "copy an item's `id` or `link` to the clipboard, prompting to pick when several are listed",
+	}
+}
+
+// copySelection handles --copy after a list command has printed its output.
+// A single result is copied directly; otherwise the user picks one.
+func copySelection(c *cli.Context, targets []copyTarget) error {
+	what := c.String("copy")
+	if what == "" {
+		return nil
+	}
+	if what != "id" && what != "link" {
+		return fmt.Errorf("invalid --copy value %q (want id or link)", what)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("nothing to copy")
+	}
+
+	target := targets[0]
+	if len(targets) > 1 {
+		picked, err := pickTarget(targets)
+		if err != nil {
+			return err
+		}
+		target = picked
+	}
+
+	text := target.ID
+	if what == "link" {
+		text = target.Link
+	}
+	if text == "" {
+		return fmt.Errorf("%q has no %s to copy", target.Label, what)
+	}
+
+	if err := clipboard.Write(text); err != nil {
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Copied %s of %q\n", what, target.Label)
+	return nil
+}
+
+func pickTarget(targets []copyTarget) (copyTarget, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return copyTarget{}, fmt.Errorf("%d items listed; narrow the results to copy one non-interactively", len(targets))
+	}
+
+	fmt.Fprintln(os.Stderr)
+	for i, t := range targets {
+		fmt.Fprintf(os.Stderr, "%3d) %s\n", i+1, t.Label)
+	}
+	fmt.Fprintf(os.Stderr, "Copy which? [1-%d]: ", len(targets))
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return copyTarget{}, fmt.Errorf("failed to read selection: %w", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || n < 1 || n > len(targets) {
+		return copyTarget{}, fmt.Errorf("invalid selection %q", strings.TrimSpace(line))
+	}
+	return targets[n-1], nil
+}
@@ -2,15 +2,19 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
+	"strconv"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/timboy697/gc-cli/internal/api"
-	"github.com/timboy697/gc-cli/internal/auth"
 	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/coursename"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/output"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 )
 
 func CoursesCmd(cfg *config.Config) *cli.Command {
@@ -22,55 +26,88 @@ func CoursesCmd(cfg *config.Config) *cli.Command {
 				Name:   "list",
 				Usage:  "list all enrolled courses",
 				Action: handleCoursesList(cfg),
-				Flags: []cli.Flag{
-					&cli.BoolFlag{
-						Name:  "json",
-						Usage: "output as JSON",
+				Flags: append(outputFlags(),
+					&cli.IntFlag{
+						Name:  "limit",
+						Usage: "stop after this many courses (0 for all)",
 					},
-				},
+					copyFlag(),
+					interactiveFlag(),
+				),
 			},
+			coursesHistoryCmd(cfg),
 		},
 	}
 }
 
 func handleCoursesList(cfg *config.Config) func(*cli.Context) error {
 	return func(c *cli.Context) error {
-		ctx := context.Background()
+		// Ctrl-C stops paging through a long course list.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
 
-		token, err := auth.GetValidToken(ctx, auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile))
+		if c.Int("limit") < 0 {
+			return fmt.Errorf("--limit must not be negative")
+		}
+		format, err := outputFormat(c)
 		if err != nil {
-			return fmt.Errorf("authentication required: %w", err)
+			return err
 		}
 
-		authCfg := auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile)
-		client, err := api.NewClientFromToken(ctx, authCfg.OAuth2Config(), token)
+		client, err := newAPIClient(ctx, cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create API client: %w", err)
+			return err
 		}
 
-		courses, _, err := client.ListCourses(ctx, 100)
+		courses, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive), api.WithLimit(c.Int("limit")))
 		if err != nil {
 			return fmt.Errorf("failed to list courses: %w (debug: %+v)", err, err)
 		}
 
 		var studentCourses []api.Course
 		for _, course := range courses {
-			if course.CourseState == "ACTIVE" {
+			if course.CourseState == api.CourseActive {
 				studentCourses = append(studentCourses, course)
 			}
 		}
 
-		if c.Bool("json") {
-			return outputJSON(studentCourses)
+		rememberRoles(ctx, cfg, client, studentCourses)
+		if c.Int("limit") == 0 {
+			rememberEnrollment(cfg, courses, false)
+		}
+
+		order := courseorder.New(cfg.Courses)
+		order.SortCourses(studentCourses)
+
+		// Pickers need courses that share a name told apart.
+		ppl := newPeople(ctx, cfg, client)
+		names := coursename.Labels(studentCourses, ppl.Name)
+		ppl.save()
+
+		studentCourses, err = filterOutput(studentCourses)
+		if err != nil {
+			return err
+		}
+
+		if c.Bool("interactive") {
+			return runInteractive(c, "Courses", courseRows(studentCourses, order, names), true)
+		}
+
+		if format != output.Table {
+			err = writeOutput(format, studentCourses, courseColumns)
+		} else {
+			err = outputTable(studentCourses, order)
+		}
+		if err != nil {
+			return err
 		}
-		return outputTable(studentCourses)
-	}
-}
 
-func outputJSON(courses []api.Course) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(courses)
+		targets := make([]copyTarget, len(studentCourses))
+		for i, course := range studentCourses {
+			targets[i] = copyTarget{Label: names[course.ID], ID: course.ID, Link: course.AlternateLink}
+		}
+		return copySelection(c, targets)
+	}
 }
 
 var (
@@ -85,20 +122,28 @@ var (
 			Foreground(lipgloss.Color("240"))
 )
 
-func outputTable(courses []api.Course) error {
+func outputTable(courses []api.Course, order *courseorder.Order) error {
 	if len(courses) == 0 {
 		fmt.Println("No enrolled courses found.")
 		return nil
 	}
 
 	idWidth := 12
+	periodWidth := 0
 	nameWidth := 40
 	sectionWidth := 20
 	roomWidth := 15
 
+	if order.HasPeriods() {
+		periodWidth = 10
+	}
+
 	for _, c := range courses {
-		if len(c.ID) > idWidth {
-			idWidth = len(c.ID)
+		if p := order.Period(c.ID, c.Name); periodWidth > 0 && len(p) > periodWidth {
+			periodWidth = len(p)
+		}
+		if id := displayID(c.ID); len(id) > idWidth {
+			idWidth = len(id)
 		}
 		if len(c.Name) > nameWidth {
 			nameWidth = len(c.Name)
@@ -112,13 +157,16 @@ func outputTable(courses []api.Course) error {
 	}
 
 	// Print header
-	header := lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		headerStyle.Width(idWidth).Render("ID"),
+	headers := []string{headerStyle.Width(idWidth).Render("ID")}
+	if periodWidth > 0 {
+		headers = append(headers, headerStyle.Width(periodWidth).Render("Period"))
+	}
+	headers = append(headers,
 		headerStyle.Width(nameWidth).Render("Name"),
 		headerStyle.Width(sectionWidth).Render("Section"),
 		headerStyle.Width(roomWidth).Render("Room"),
 	)
+	header := lipgloss.JoinHorizontal(lipgloss.Left, headers...)
 	separator := separatorStyle.Render("─")
 
 	fmt.Println(header)
@@ -128,14 +176,16 @@ func outputTable(courses []api.Course) error {
 	))
 
 	for _, c := range courses {
-		row := lipgloss.JoinHorizontal(
-			lipgloss.Left,
-			cellStyle.Width(idWidth).Render(truncate(c.ID, idWidth)),
+		cells := []string{cellStyle.Width(idWidth).Render(truncate(displayID(c.ID), idWidth))}
+		if periodWidth > 0 {
+			cells = append(cells, cellStyle.Width(periodWidth).Render(order.Period(c.ID, c.Name)))
+		}
+		cells = append(cells,
 			cellStyle.Width(nameWidth).Render(truncate(c.Name, nameWidth)),
 			cellStyle.Width(sectionWidth).Render(truncate(c.Section, sectionWidth)),
 			cellStyle.Width(roomWidth).Render(truncate(c.Room, roomWidth)),
 		)
-		fmt.Println(row)
+		fmt.Println(lipgloss.JoinHorizontal(lipgloss.Left, cells...))
 	}
 
 	fmt.Println()
@@ -149,3 +199,18 @@ func truncate(s string, maxLen int) string {
 	}
 	return s[:maxLen-3] + "..."
 }
+
+// defaultTableWidth is used when stdout isn't a terminal and $COLUMNS is
+// unset, e.g. when piping into a pager.
+const defaultTableWidth = 100
+
+// terminalWidth is how many columns a table may use.
+func terminalWidth() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return defaultTableWidth
+}
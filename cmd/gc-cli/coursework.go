@@ -2,16 +2,19 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/agenda"
 	"github.com/timboy697/gc-cli/internal/api"
 	"github.com/timboy697/gc-cli/internal/auth"
 	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/output"
+	"github.com/timboy697/gc-cli/internal/store"
 	"github.com/urfave/cli/v2"
 )
 
@@ -24,19 +27,60 @@ func CourseworkCmd(cfg *config.Config) *cli.Command {
 				Name:   "list",
 				Usage:  "list coursework for a course",
 				Action: handleCourseworkList(cfg),
-				Flags: []cli.Flag{
+				Flags: append([]cli.Flag{
 					&cli.StringFlag{
-						Name:     "course",
-						Usage:    "course ID to list coursework for",
-						Required: true,
+						Name:  "course",
+						Usage: "course ID to list coursework for",
 					},
 					&cli.BoolFlag{
-						Name:  "json",
-						Usage: "output as JSON",
+						Name:  "all",
+						Usage: "include all coursework (including draft and scheduled)",
+					},
+					&cli.StringFlag{
+						Name:  "state",
+						Usage: "only show coursework in this state (published, draft, deleted)",
+					},
+					&cli.StringFlag{
+						Name:  "type",
+						Usage: "only show this kind of coursework (assignment, short_answer_question, multiple_choice_question)",
 					},
 					&cli.BoolFlag{
-						Name:  "all",
-						Usage: "include all coursework (including draft)",
+						Name:  "unread-only",
+						Usage: "only show coursework not yet marked as read",
+					},
+					copyFlag(),
+					interactiveFlag(),
+				}, outputFlags()...),
+			},
+			{
+				Name:      "view",
+				Usage:     "show an assignment's details, materials and add-ons",
+				ArgsUsage: "<coursework-id | %N>",
+				Action:    handleCourseworkView(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "course ID (not needed for %N rows of the last listing or Classroom links)",
+					},
+				},
+			},
+			{
+				Name:   "copy-template",
+				Usage:  "locate your personal copy of a \"make a copy for each student\" template",
+				Action: handleCopyTemplate(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "course ID",
+					},
+					&cli.StringFlag{
+						Name:     "assignment",
+						Usage:    "assignment (coursework) ID",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:  "open",
+						Usage: "open the copy (or the assignment, to have Classroom create it) in the browser",
 					},
 				},
 			},
@@ -48,35 +92,73 @@ func handleCourseworkList(cfg *config.Config) func(*cli.Context) error {
 	return func(c *cli.Context) error {
 		ctx := context.Background()
 
-		token, err := auth.GetValidToken(ctx, auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile))
+		// Without --state or --all, only published work is shown.
+		state := api.CourseWorkPublished
+		if c.IsSet("state") {
+			var err error
+			if state, err = api.ParseCourseWorkState(c.String("state")); err != nil {
+				return err
+			}
+		} else if c.Bool("all") {
+			state = ""
+		}
+		var workType api.WorkType
+		if c.IsSet("type") {
+			var err error
+			if workType, err = api.ParseWorkType(c.String("type")); err != nil {
+				return err
+			}
+		}
+
+		format, err := outputFormat(c)
 		if err != nil {
-			return fmt.Errorf("authentication required: %w", err)
+			return err
 		}
 
-		authCfg := auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile)
-		client, err := api.NewClientFromToken(ctx, authCfg.OAuth2Config(), token)
+		client, err := newAPIClient(ctx, cfg)
 		if err != nil {
-			return fmt.Errorf("failed to create API client: %w", err)
+			return err
 		}
 
-		courseID := c.String("course")
-		if _, err := client.GetCourse(ctx, courseID); err != nil {
-			return fmt.Errorf("course %s not found or access denied: %w", courseID, err)
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+		// Checking the course first only pays off for a clearer error, so
+		// it is skipped for courses already known here.
+		if !courseKnown(cfg, client, courseID) {
+			if _, err := client.GetCourse(ctx, courseID); err != nil {
+				return courseAccessError(courseID, err)
+			}
 		}
 
 		coursework, _, err := client.ListCourseWork(ctx, courseID, 100)
 		if err != nil {
+			if api.IsNotFound(err) || api.IsForbidden(err) {
+				return courseAccessError(courseID, err)
+			}
 			return fmt.Errorf("failed to list coursework: %w", err)
 		}
 
-		filteredCoursework := coursework
-		if !c.Bool("all") {
-			filteredCoursework = []api.CourseWork{}
-			for _, cw := range coursework {
-				if cw.State == "PUBLISHED" {
-					filteredCoursework = append(filteredCoursework, cw)
+		filteredCoursework := []api.CourseWork{}
+		for _, cw := range coursework {
+			if (state == "" || cw.State == state) && (workType == "" || cw.WorkType == workType) {
+				filteredCoursework = append(filteredCoursework, cw)
+			}
+		}
+
+		isUnread, err := unreadFilter(c, cfg)
+		if err != nil {
+			return err
+		}
+		if isUnread != nil {
+			var unread []api.CourseWork
+			for _, cw := range filteredCoursework {
+				if isUnread(cw.ID) {
+					unread = append(unread, cw)
 				}
 			}
+			filteredCoursework = unread
 		}
 
 		sort.Slice(filteredCoursework, func(i, j int) bool {
@@ -95,13 +177,150 @@ func handleCourseworkList(cfg *config.Config) func(*cli.Context) error {
 			return dateI.Before(dateJ)
 		})
 
-		if c.Bool("json") {
-			return outputCourseworkJSON(filteredCoursework)
+		filteredCoursework, err = filterOutput(filteredCoursework)
+		if err != nil {
+			return err
+		}
+
+		if c.Bool("interactive") {
+			return runInteractive(c, "Coursework", courseworkRows(ctx, cfg, client, filteredCoursework), false)
 		}
-		return outputCourseworkTable(filteredCoursework)
+
+		if format != output.Table {
+			err = writeOutput(format, filteredCoursework, courseworkColumns)
+		} else {
+			err = outputCourseworkTable(filteredCoursework)
+		}
+		if err != nil {
+			return err
+		}
+
+		if format == output.Table {
+			items := make([]store.SelectedItem, len(filteredCoursework))
+			for i, cw := range filteredCoursework {
+				items[i] = store.SelectedItem{CourseID: cw.CourseID, ID: cw.ID, Title: cw.Title, Link: cw.AlternateLink}
+			}
+			if err := saveSelection(items); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not save row numbers for %%N references: %v\n", err)
+			}
+		}
+
+		targets := make([]copyTarget, len(filteredCoursework))
+		for i, cw := range filteredCoursework {
+			targets[i] = copyTarget{Label: cw.Title, ID: cw.ID, Link: cw.AlternateLink}
+		}
+		return copySelection(c, targets)
 	}
 }
 
+func handleCourseworkView(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("coursework ID required")
+		}
+		courseID, courseWorkID, err := resolveItem(c, c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		cw, err := client.GetCourseWork(ctx, courseID, courseWorkID)
+		if err != nil {
+			return fmt.Errorf("failed to get coursework: %w", err)
+		}
+
+		printCourseworkDetail(*cw, clk.Now())
+		return offerSteps(cfg, *cw)
+	}
+}
+
+func handleCopyTemplate(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+		assignmentID := assignmentArg(c)
+
+		cw, err := client.GetCourseWork(ctx, courseID, assignmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get coursework: %w", err)
+		}
+
+		var templates []api.DriveFile
+		for _, m := range cw.Materials {
+			if m.DriveFile != nil && m.DriveFile.ShareMode == "STUDENT_COPY" {
+				templates = append(templates, m.DriveFile.DriveFile)
+			}
+		}
+		if len(templates) == 0 {
+			return fmt.Errorf("%q has no \"make a copy for each student\" templates", cw.Title)
+		}
+
+		submission, err := client.GetMySubmission(ctx, courseID, assignmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get your submission: %w", err)
+		}
+
+		attachments, err := submission.Attachments()
+		if err != nil {
+			return err
+		}
+
+		missing := false
+		for _, tmpl := range templates {
+			copyFile := findStudentCopy(tmpl, attachments)
+			if copyFile == nil {
+				missing = true
+				fmt.Printf("✗ %s: no personal copy yet\n", tmpl.Title)
+				continue
+			}
+
+			fmt.Printf("✓ %s\n  %s\n", copyFile.Title, copyFile.AlternateLink)
+			if c.Bool("open") {
+				_ = auth.OpenBrowser(copyFile.AlternateLink)
+			}
+		}
+
+		if missing {
+			fmt.Println("\nClassroom creates your copy the first time you open the assignment:")
+			fmt.Printf("  %s\n", submission.AlternateLink)
+			if c.Bool("open") {
+				_ = auth.OpenBrowser(submission.AlternateLink)
+			}
+		}
+
+		return nil
+	}
+}
+
+// findStudentCopy returns the submission attachment Classroom generated from
+// tmpl. Copies are named "<student name> - <template title>".
+func findStudentCopy(tmpl api.DriveFile, attachments []api.Attachment) *api.DriveFile {
+	for _, a := range attachments {
+		if a.DriveFile == nil || a.DriveFile.ID == tmpl.ID {
+			continue
+		}
+		if a.DriveFile.Title == tmpl.Title || strings.HasSuffix(a.DriveFile.Title, " - "+tmpl.Title) {
+			return a.DriveFile
+		}
+	}
+	return nil
+}
+
 func getDueDate(cw api.CourseWork) time.Time {
 	if cw.DueDate == nil {
 		return time.Time{}
@@ -109,8 +328,14 @@ func getDueDate(cw api.CourseWork) time.Time {
 	return time.Date(cw.DueDate.Year, time.Month(cw.DueDate.Month), cw.DueDate.Day, 0, 0, 0, 0, time.UTC)
 }
 
-func getStatus(cw api.CourseWork) string {
-	if cw.State == "DRAFT" {
+func getStatus(cw api.CourseWork, now time.Time) string {
+	if opens := agenda.OpensAt(cw, now); !opens.IsZero() {
+		return fmt.Sprintf("Opens in %s (%s)", agenda.Until(now, opens), opens.Format("01/02 15:04"))
+	}
+	if cw.IsScheduled() {
+		return "Scheduled " + cw.ScheduledTime.Local().Format("01/02 15:04")
+	}
+	if cw.State == api.CourseWorkDraft {
 		return "Draft"
 	}
 
@@ -124,9 +349,15 @@ func getStatus(cw api.CourseWork) string {
 			dueTime = time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 23, 59, 59, 0, time.UTC)
 		}
 
-		if time.Now().After(dueTime) {
+		if now.After(dueTime) {
 			return "Overdue"
 		}
+		if window := agenda.Window(cw, now); window != "" {
+			return "Pending, " + window
+		}
+		if schoolCal.Configured() {
+			return fmt.Sprintf("Pending, %d school day(s)", schoolCal.SchoolDaysUntil(now, agenda.DueTime(cw)))
+		}
 	}
 
 	return "Pending"
@@ -143,22 +374,19 @@ func formatDueDate(cw api.CourseWork) string {
 	return date
 }
 
-func outputCourseworkJSON(coursework []api.CourseWork) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(coursework)
-}
-
 func outputCourseworkTable(coursework []api.CourseWork) error {
 	if len(coursework) == 0 {
 		fmt.Println("No coursework found.")
 		return nil
 	}
 
+	now := clk.Now()
+	rowWidth := len(fmt.Sprint(len(coursework))) + 2
 	idWidth := 12
 	titleWidth := 40
 	dueDateWidth := 16
 	statusWidth := 12
+	expectsWidth := 14
 
 	for _, cw := range coursework {
 		if len(cw.ID) > idWidth {
@@ -171,7 +399,7 @@ func outputCourseworkTable(coursework []api.CourseWork) error {
 		if len(dueStr) > dueDateWidth {
 			dueDateWidth = len(dueStr)
 		}
-		status := getStatus(cw)
+		status := getStatus(cw, now)
 		if len(status) > statusWidth {
 			statusWidth = len(status)
 		}
@@ -192,10 +420,12 @@ func outputCourseworkTable(coursework []api.CourseWork) error {
 
 	header := lipgloss.JoinHorizontal(
 		lipgloss.Left,
+		headerStyle.Width(rowWidth).Render("#"),
 		headerStyle.Width(idWidth).Render("ID"),
 		headerStyle.Width(titleWidth).Render("Title"),
 		headerStyle.Width(dueDateWidth).Render("Due Date"),
 		headerStyle.Width(statusWidth).Render("Status"),
+		headerStyle.Width(expectsWidth).Render("Expects"),
 	)
 	separator := separatorStyle.Render("─")
 
@@ -205,18 +435,28 @@ func outputCourseworkTable(coursework []api.CourseWork) error {
 		separator+separator+separator+separator,
 	))
 
-	for _, cw := range coursework {
+	inBrowser := 0
+	for i, cw := range coursework {
+		expects := agenda.ExpectsOf(cw)
+		if !expects.InCLI() {
+			inBrowser++
+		}
 		row := lipgloss.JoinHorizontal(
 			lipgloss.Left,
-			cellStyle.Width(idWidth).Render(truncate(cw.ID, idWidth)),
+			cellStyle.Width(rowWidth).Render(fmt.Sprint(i+1)),
+			cellStyle.Width(idWidth).Render(truncate(displayID(cw.ID), idWidth)),
 			cellStyle.Width(titleWidth).Render(truncate(cw.Title, titleWidth)),
 			cellStyle.Width(dueDateWidth).Render(formatDueDate(cw)),
-			cellStyle.Width(statusWidth).Render(getStatus(cw)),
+			cellStyle.Width(statusWidth).Render(getStatus(cw, now)),
+			cellStyle.Width(expectsWidth).Render(expects.Label()),
 		)
 		fmt.Println(row)
 	}
 
 	fmt.Println()
 	fmt.Printf("Total: %d coursework item(s)\n", len(coursework))
+	if inBrowser > 0 {
+		fmt.Printf("↗ %d can only be done in the browser: 'gc-cli open %%N' opens row N\n", inBrowser)
+	}
 	return nil
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/demo"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// demoMode is set by --demo: commands read made-up courses instead of
+// signing in to Classroom.
+var demoMode bool
+
+// demoDataFile is --demo-data: fixtures to use instead of the built-in
+// demo courses.
+var demoDataFile string
+
+// useDemoData keeps demo runs away from real local state. Notes, read
+// markers and the like work for the run, but live only in memory.
+func useDemoData(cfg *config.Config) {
+	demoMode = true
+	cfg.Storage.Backend = store.BackendMemory
+	cfg.UseDataDir(cfg.DataDir)
+}
+
+// newDemoClient returns a client that answers from the demo courses
+// without signing in.
+func newDemoClient(ctx context.Context) (*api.Client, error) {
+	d, err := loadDemoData()
+	if err != nil {
+		return nil, err
+	}
+	noticeOnce("Demo mode: showing made-up courses (nothing is sent to Google)")
+	return demo.NewClient(ctx, d, api.WithStats(apiStats), api.WithLogger(apiLog))
+}
+
+func loadDemoData() (*demo.Data, error) {
+	if demoDataFile == "" {
+		return demo.Load(clk.Now())
+	}
+	data, err := os.ReadFile(demoDataFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read demo data: %w", err)
+	}
+	return demo.Parse(data, clk.Now())
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/timboy697/gc-cli/internal/textdiff"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	diffDeleteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	diffInsertStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	diffHunkStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+)
+
+func DiffCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "diff",
+		Usage: "show how assignments changed between syncs",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list assignments edited since they were first synced",
+				Action: handleDiffList(cfg),
+			},
+			{
+				Name:      "show",
+				Usage:     "show what changed in an assignment",
+				ArgsUsage: "<coursework-id>",
+				Action:    handleDiffShow(cfg),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "show every change, not just the latest",
+					},
+					&cli.IntFlag{
+						Name:  "context",
+						Usage: "lines of unchanged description around each change",
+						Value: 3,
+					},
+				},
+			},
+		},
+	}
+}
+
+func handleDiffList(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		rev, err := loadRevisions(cfg)
+		if err != nil {
+			return err
+		}
+		if len(rev.CourseWork) == 0 {
+			fmt.Println("No assignment changes recorded yet. Changes are noticed by 'gc-cli sync'.")
+			return nil
+		}
+
+		ids := make([]string, 0, len(rev.CourseWork))
+		for id := range rev.CourseWork {
+			ids = append(ids, id)
+		}
+		// Most recently changed first.
+		sort.Slice(ids, func(i, j int) bool {
+			return latestVersion(rev, ids[i]).Seen.After(latestVersion(rev, ids[j]).Seen)
+		})
+
+		idWidth := 20
+		titleWidth := 40
+		countWidth := 10
+		seenWidth := 20
+
+		fmt.Println(lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			headerStyle.Width(idWidth).Render("ID"),
+			headerStyle.Width(titleWidth).Render("Title"),
+			headerStyle.Width(countWidth).Render("Changes"),
+			headerStyle.Width(seenWidth).Render("Last changed"),
+		))
+		separator := separatorStyle.Render("─")
+		fmt.Println(separator + separator + separator + separator)
+
+		for _, id := range ids {
+			latest := latestVersion(rev, id)
+			fmt.Println(lipgloss.JoinHorizontal(
+				lipgloss.Left,
+				cellStyle.Width(idWidth).Render(truncate(displayID(id), idWidth-2)),
+				cellStyle.Width(titleWidth).Render(truncate(latest.Title, titleWidth-2)),
+				cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", len(rev.CourseWork[id])-1)),
+				cellStyle.Width(seenWidth).Render(latest.Seen.Format("Mon Jan 02 15:04")),
+			))
+		}
+		return nil
+	}
+}
+
+func handleDiffShow(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("coursework ID required")
+		}
+		id := resolveItemID(c.Args().First())
+		if c.Int("context") < 0 {
+			return fmt.Errorf("--context must not be negative")
+		}
+
+		rev, err := loadRevisions(cfg)
+		if err != nil {
+			return err
+		}
+		versions := rev.CourseWork[id]
+		if len(versions) < 2 {
+			return fmt.Errorf("no changes recorded for %s; changes are noticed by 'gc-cli sync'", id)
+		}
+
+		first := len(versions) - 2
+		if c.Bool("all") {
+			first = 0
+		}
+		for i := first; i < len(versions)-1; i++ {
+			if i > first {
+				fmt.Println()
+			}
+			outputVersionDiff(versions[i], versions[i+1], c.Int("context"))
+		}
+		return nil
+	}
+}
+
+func loadRevisions(cfg *config.Config) (*store.Revisions, error) {
+	st, err := cfg.Store()
+	if err != nil {
+		return nil, err
+	}
+	return st.Revisions()
+}
+
+func latestVersion(rev *store.Revisions, id string) store.CourseWorkVersion {
+	versions := rev.CourseWork[id]
+	return versions[len(versions)-1]
+}
+
+func outputVersionDiff(before, after store.CourseWorkVersion, context int) {
+	fmt.Println(headerStyle.Render(after.Title))
+	fmt.Println(separatorStyle.Render(fmt.Sprintf("%s → %s",
+		before.Seen.Format("Mon Jan 02 15:04"), after.Seen.Format("Mon Jan 02 15:04"))))
+
+	if before.Title != after.Title {
+		outputFieldChange("Title", before.Title, after.Title)
+	}
+	if !before.Due.Equal(after.Due) {
+		outputFieldChange("Due", formatVersionDue(before.Due), formatVersionDue(after.Due))
+	}
+	if before.MaxPoints != after.MaxPoints {
+		outputFieldChange("Points", fmt.Sprintf("%d", before.MaxPoints), fmt.Sprintf("%d", after.MaxPoints))
+	}
+
+	switch {
+	case before.Description == nil || after.Description == nil:
+		fmt.Println(separatorStyle.Render("Description not compared; use 'gc-cli sync --deep' to track descriptions."))
+	case *before.Description != *after.Description:
+		fmt.Println()
+		outputTextDiff("description", *before.Description, *after.Description, context)
+	}
+}
+
+func outputFieldChange(name, before, after string) {
+	fmt.Printf("%-8s %s → %s\n", name+":", diffDeleteStyle.Render(before), diffInsertStyle.Render(after))
+}
+
+func formatVersionDue(t time.Time) string {
+	if t.IsZero() {
+		return "no due date"
+	}
+	return t.Format("Mon Jan 02 15:04")
+}
+
+func outputTextDiff(label, before, after string, context int) {
+	fmt.Println(diffDeleteStyle.Render("--- " + label + " (before)"))
+	fmt.Println(diffInsertStyle.Render("+++ " + label + " (after)"))
+	for _, h := range textdiff.Hunks(textdiff.Lines(before, after), context) {
+		fmt.Println(diffHunkStyle.Render(fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.AStart, h.ALen), hunkRange(h.BStart, h.BLen))))
+		for _, l := range h.Lines {
+			switch l.Op {
+			case textdiff.Delete:
+				fmt.Println(diffDeleteStyle.Render("-" + l.Text))
+			case textdiff.Insert:
+				fmt.Println(diffInsertStyle.Render("+" + l.Text))
+			default:
+				fmt.Println(" " + l.Text)
+			}
+		}
+	}
+}
+
+func hunkRange(start, n int) string {
+	if n == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, n)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/offline"
+)
+
+// assumeDisabled is set by --assume-disabled: the Classroom API is known to
+// be unavailable, so reads are answered from the last sync instead.
+var assumeDisabled bool
+
+// newOfflineClient returns a client that reads from the offline snapshot
+// without signing in.
+func newOfflineClient(ctx context.Context, cfg *config.Config) (*api.Client, error) {
+	client, synced, err := openSyncedClient(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("--assume-disabled reads data from 'gc-cli sync', but %w", err)
+	}
+	noticeOnce(fmt.Sprintf("Using data synced %s (--assume-disabled)", formatAge(synced, time.Now())))
+	return client, nil
+}
+
+// openSyncedClient returns a client answering reads from the offline
+// snapshot, and when that was synced.
+func openSyncedClient(ctx context.Context, cfg *config.Config) (*api.Client, time.Time, error) {
+	st, err := cfg.Store()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	snap, err := offline.Load(st)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if snap.SyncedAt.IsZero() {
+		return nil, time.Time{}, fmt.Errorf("nothing has been synced yet")
+	}
+
+	hc := &http.Client{Transport: offline.Transport(snap)}
+	opts := []api.Option{api.WithHTTPClient(hc), api.WithStats(apiStats), api.WithLogger(apiLog)}
+	// Synced data is real, so it's disguised the same way as live responses.
+	if cfg.Anonymize {
+		anon, err := newAnonymizer(cfg)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		opts = append(opts, api.WithResponseFilter(anon.JSON))
+	}
+	client, err := api.NewClient(ctx, nil, opts...)
+	return client, snap.SyncedAt, err
+}
+
+// explainAPIDisabled is printed after any error caused by a school admin
+// blocking the Classroom API, which otherwise shows up as a bare 403.
+func explainAPIDisabled(cfg *config.Config) string {
+	return `
+Your school's Google Workspace admin has blocked gc-cli from Google Classroom
+for this account. This is a domain setting; signing in again won't help.
+
+To request access, ask your school's IT admin to:
+  1. In the Admin console (admin.google.com), open Apps > Google Workspace >
+     Classroom > Data access, and turn on "Users can authorize apps to access
+     their Google Classroom data" for students.
+  2. If third-party apps are restricted, open Security > Access and data
+     control > API controls > Manage third-party app access, and trust
+     gc-cli's OAuth client:
+       ` + cfg.Auth.ClientID + `
+
+Until then, 'gc-cli --assume-disabled <command>' reads from the last
+'gc-cli sync', if you synced before access was turned off. Run 'gc-cli doctor'
+to check again.
+`
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cpuguy83/go-md2man/v2/md2man"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func DocsCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "docs",
+		Usage: "generate and install man pages",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "man",
+				Usage:  "print the gc-cli(1) man page",
+				Action: handleDocsMan,
+			},
+			{
+				Name:  "install",
+				Usage: "write gc-cli(1) and a page per help topic where man finds them",
+				Description: "Pages go in man1 and man7 under --dir. The default is\n" +
+					"$XDG_DATA_HOME/man, or ~/.local/share/man, which man searches on most\n" +
+					"systems; then 'man gc-cli' and 'man gc-cli-output-formats' work.",
+				Action: handleDocsInstall,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "dir",
+						Usage:       "man directory to install into",
+						DefaultText: "~/.local/share/man",
+					},
+				},
+			},
+		},
+	}
+}
+
+func handleDocsMan(c *cli.Context) error {
+	page, err := manPage(c.App)
+	if err != nil {
+		return err
+	}
+	fmt.Print(page)
+	return nil
+}
+
+func handleDocsInstall(c *cli.Context) error {
+	dir := c.String("dir")
+	if dir == "" {
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to find home directory: %w", err)
+			}
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		dir = filepath.Join(dataHome, "man")
+	}
+
+	page, err := manPage(c.App)
+	if err != nil {
+		return err
+	}
+	pages := map[string]string{filepath.Join("man1", "gc-cli.1"): page}
+	for _, t := range helpTopics {
+		pages[filepath.Join("man7", "gc-cli-"+t.Name+".7")] = topicManPage(t)
+	}
+
+	for name, content := range pages {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	fmt.Printf("Installed %d man pages in %s; try 'man gc-cli'.\n", len(pages), dir)
+	return nil
+}
+
+// manPage is gc-cli(1), generated from the command definitions, with the
+// help topics under SEE ALSO.
+func manPage(app *cli.App) (string, error) {
+	page, err := app.ToManWithSection(1)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate man page: %w", err)
+	}
+	refs := make([]string, len(helpTopics))
+	for i, t := range helpTopics {
+		refs[i] = `\fBgc-cli-` + t.Name + `\fP(7)`
+	}
+	return page + ".SH SEE ALSO\n" + strings.Join(refs, ", ") + "\n", nil
+}
+
+func topicManPage(t helpTopic) string {
+	md := fmt.Sprintf("%% gc-cli-%s 7\n\n# NAME\n\ngc-cli-%s - %s\n\n# DESCRIPTION\n\n%s\n# SEE ALSO\n\n**gc-cli**(1)\n",
+		t.Name, t.Name, t.Usage, t.Body)
+	return string(md2man.Render([]byte(md)))
+}
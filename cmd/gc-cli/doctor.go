@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/urfave/cli/v2"
+)
+
+func DoctorCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "doctor",
+		Usage:  "check the config, sign-in, Classroom API access and offline data",
+		Action: handleDoctor(cfg),
+	}
+}
+
+func handleDoctor(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+		var checks []selftestCheck
+		fail := func(name string, err error) {
+			checks = append(checks, selftestCheck{Name: name, Status: checkFail, Detail: describeCheckError(err)})
+		}
+		pass := func(name, detail string) {
+			checks = append(checks, selftestCheck{Name: name, Status: checkPass, Detail: detail})
+		}
+		skip := func(name, reason string) {
+			checks = append(checks, selftestCheck{Name: name, Status: checkSkip, Detail: reason})
+		}
+
+		if _, err := os.Stat(cfg.ConfigPath); err == nil {
+			pass("config", cfg.ConfigPath)
+		} else {
+			skip("config", "no config file; using defaults")
+		}
+
+		disabled := false
+		switch {
+		case assumeDisabled:
+			skip("sign-in", "skipped (--assume-disabled)")
+			skip("Classroom API", "skipped (--assume-disabled)")
+		default:
+			token, err := auth.GetValidToken(ctx, cfg.AuthConfig())
+			if err != nil {
+				disabled = api.IsAPIDisabled(err)
+				fail("sign-in", err)
+				skip("Classroom API", "needs a valid sign-in")
+				break
+			}
+			pass("sign-in", "token valid until "+token.Expiry.Local().Format("Jan 2 15:04"))
+
+			client, err := newAPIClient(ctx, cfg)
+			if err == nil {
+				_, _, err = client.ListCourses(ctx, 1, api.WithLimit(1))
+			}
+			if err != nil {
+				disabled = api.IsAPIDisabled(err)
+				fail("Classroom API", err)
+			} else {
+				pass("Classroom API", "reachable")
+			}
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		snap, err := offline.Load(st)
+		if err != nil {
+			return err
+		}
+		if snap.SyncedAt.IsZero() {
+			skip("offline data", "never synced; run 'gc-cli sync' while the API works")
+		} else {
+			pass("offline data", fmt.Sprintf("%d course(s), synced %s", len(snap.Courses), formatAge(snap.SyncedAt, time.Now())))
+		}
+
+		failed := printSelftest(checks)
+		if disabled {
+			fmt.Print(explainAPIDisabled(cfg))
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/output"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/urfave/cli/v2"
+)
+
+func coursesHistoryCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "history",
+		Usage:  "show the courses you've been in, by term",
+		Action: handleCoursesHistory(cfg),
+		Flags: append(outputFlags(),
+			&cli.BoolFlag{
+				Name:  "events",
+				Usage: "list when courses were joined, archived and left instead",
+			},
+		),
+	}
+}
+
+// rememberEnrollment adds a course listing to the enrollment history. A
+// complete listing has courses in every state, so courses missing from it
+// are recorded as left. It's best effort, like rememberRoles.
+func rememberEnrollment(cfg *config.Config, courses []api.Course, complete bool) *store.Enrollment {
+	st, err := cfg.Store()
+	if err != nil {
+		return nil
+	}
+	enrollment, err := st.Enrollment()
+	if err != nil {
+		return nil
+	}
+	// Anonymized names are made up, and the offline client only has what
+	// was synced, so neither says anything new about enrollment.
+	if cfg.Anonymize || assumeDisabled {
+		return enrollment
+	}
+	seen := make([]store.EnrolledCourse, len(courses))
+	for i, c := range courses {
+		seen[i] = store.EnrolledCourse{ID: c.ID, Name: c.Name, Section: c.Section, Created: c.CreationTime, State: string(c.CourseState)}
+	}
+	enrollment.Observe(seen, complete, clk.Now())
+	_ = st.SaveEnrollment(enrollment)
+	return enrollment
+}
+
+type enrollmentEntry struct {
+	Term      string    `json:"term"`
+	CourseID  string    `json:"courseId"`
+	Name      string    `json:"name"`
+	Section   string    `json:"section,omitempty"`
+	State     string    `json:"state"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+
+	// started places the course in its term: when it was created, or when
+	// gc-cli first saw it if that's unknown.
+	started time.Time
+}
+
+type enrollmentEventEntry struct {
+	At       time.Time `json:"at"`
+	Kind     string    `json:"kind"`
+	CourseID string    `json:"courseId"`
+	Name     string    `json:"name"`
+}
+
+func handleCoursesHistory(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		format, err := outputFormat(c)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		// Without a state filter the list includes archived courses too.
+		courses, _, err := client.ListCourses(ctx, 100)
+		if err != nil {
+			return fmt.Errorf("failed to list courses: %w", err)
+		}
+		enrollment := rememberEnrollment(cfg, courses, true)
+		if enrollment == nil {
+			return fmt.Errorf("failed to read enrollment history")
+		}
+
+		// Stored names are real ones, so they're disguised like the rest.
+		name := func(c *store.EnrolledCourse) (string, string) { return c.Name, c.Section }
+		if cfg.Anonymize {
+			anon, err := newAnonymizer(cfg)
+			if err != nil {
+				return err
+			}
+			name = func(c *store.EnrolledCourse) (string, string) {
+				return anon.Course(c.Name), anon.Section(c.Section)
+			}
+		}
+
+		if c.Bool("events") {
+			events := make([]enrollmentEventEntry, 0, len(enrollment.Events))
+			for _, e := range enrollment.Events {
+				entry := enrollmentEventEntry{At: e.At, Kind: e.Kind, CourseID: e.CourseID}
+				if course := enrollment.Courses[e.CourseID]; course != nil {
+					entry.Name, _ = name(course)
+				}
+				events = append(events, entry)
+			}
+			events, err := filterOutput(events)
+			if err != nil {
+				return err
+			}
+
+			if format != output.Table {
+				return writeOutput(format, events, enrollmentEventColumns)
+			}
+			return outputEnrollmentEvents(events)
+		}
+
+		entries := make([]enrollmentEntry, 0, len(enrollment.Courses))
+		for _, course := range enrollment.Courses {
+			started := course.Created
+			if started.IsZero() {
+				started = course.FirstSeen
+			}
+			n, section := name(course)
+			entries = append(entries, enrollmentEntry{
+				Term:      schoolCal.TermName(started.Local()),
+				CourseID:  course.ID,
+				Name:      n,
+				Section:   section,
+				State:     enrollmentState(course.State),
+				FirstSeen: course.FirstSeen,
+				LastSeen:  course.LastSeen,
+				started:   started,
+			})
+		}
+		sortEnrollment(entries)
+
+		entries, err = filterOutput(entries)
+		if err != nil {
+			return err
+		}
+		if format != output.Table {
+			return writeOutput(format, entries, enrollmentColumns)
+		}
+		return outputEnrollmentTable(entries)
+	}
+}
+
+func enrollmentState(state string) string {
+	if state == store.CourseGone {
+		return "left"
+	}
+	return strings.ToLower(state)
+}
+
+// sortEnrollment orders terms from the oldest, and courses by name within a
+// term.
+func sortEnrollment(entries []enrollmentEntry) {
+	termStart := make(map[string]time.Time)
+	for _, e := range entries {
+		if s, ok := termStart[e.Term]; !ok || e.started.Before(s) {
+			termStart[e.Term] = e.started
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Term != b.Term {
+			return termStart[a.Term].Before(termStart[b.Term])
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+func outputEnrollmentTable(entries []enrollmentEntry) error {
+	if len(entries) == 0 {
+		fmt.Println("No courses recorded yet.")
+		return nil
+	}
+
+	nameWidth := 30
+	sectionWidth := 14
+	stateWidth := 10
+	idWidth := 16
+	dateWidth := 12
+	for _, e := range entries {
+		if len(e.Name)+2 > nameWidth {
+			nameWidth = len(e.Name) + 2
+		}
+		if id := displayID(e.CourseID); len(id)+2 > idWidth {
+			idWidth = len(id) + 2
+		}
+	}
+	width := nameWidth + sectionWidth + stateWidth + idWidth + 2*dateWidth
+
+	for i, e := range entries {
+		if i == 0 || entries[i-1].Term != e.Term {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(headerStyle.Render(e.Term))
+			fmt.Println(lipgloss.JoinHorizontal(
+				lipgloss.Left,
+				headerStyle.Width(nameWidth).Render("Name"),
+				headerStyle.Width(sectionWidth).Render("Section"),
+				headerStyle.Width(stateWidth).Render("State"),
+				headerStyle.Width(idWidth).Render("Course ID"),
+				headerStyle.Width(dateWidth).Render("First seen"),
+				headerStyle.Width(dateWidth).Render("Last seen"),
+			))
+			fmt.Println(separatorStyle.Render(strings.Repeat("─", width)))
+		}
+		section := e.Section
+		if section == "" {
+			section = "-"
+		}
+		fmt.Println(lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(nameWidth).Render(e.Name),
+			cellStyle.Width(sectionWidth).Render(truncate(section, sectionWidth-2)),
+			cellStyle.Width(stateWidth).Render(e.State),
+			cellStyle.Width(idWidth).Render(displayID(e.CourseID)),
+			cellStyle.Width(dateWidth).Render(e.FirstSeen.Local().Format("2006-01-02")),
+			cellStyle.Width(dateWidth).Render(e.LastSeen.Local().Format("2006-01-02")),
+		))
+	}
+
+	fmt.Println()
+	fmt.Printf("Total: %d course(s). Courses are recorded as 'courses list' and 'courses history' see them.\n", len(entries))
+	return nil
+}
+
+func outputEnrollmentEvents(events []enrollmentEventEntry) error {
+	if len(events) == 0 {
+		fmt.Println("No changes recorded yet.")
+		return nil
+	}
+
+	dateWidth := 18
+	kindWidth := 10
+	nameWidth := 30
+	idWidth := 16
+	for _, e := range events {
+		if len(e.Name)+2 > nameWidth {
+			nameWidth = len(e.Name) + 2
+		}
+	}
+
+	fmt.Println(lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(dateWidth).Render("When"),
+		headerStyle.Width(kindWidth).Render("Change"),
+		headerStyle.Width(nameWidth).Render("Course"),
+		headerStyle.Width(idWidth).Render("Course ID"),
+	))
+	fmt.Println(separatorStyle.Render(strings.Repeat("─", dateWidth+kindWidth+nameWidth+idWidth)))
+	for _, e := range events {
+		fmt.Println(lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(dateWidth).Render(e.At.Local().Format("2006-01-02 15:04")),
+			cellStyle.Width(kindWidth).Render(e.Kind),
+			cellStyle.Width(nameWidth).Render(e.Name),
+			cellStyle.Width(idWidth).Render(displayID(e.CourseID)),
+		))
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/evidence"
+	"github.com/urfave/cli/v2"
+)
+
+func EvidenceCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "evidence",
+		Usage:  "bundle proof of when and what you turned in, for contesting a late mark",
+		Action: handleEvidence(cfg),
+		Flags: append(submissionFlags(),
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "zip file to write (default: evidence-<assignment>-<time>.zip)",
+			},
+		),
+	}
+}
+
+func handleEvidence(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+		courseID, err := courseArg(c)
+		if err != nil {
+			return err
+		}
+		assignmentID := assignmentArg(c)
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		auditLog, err := st.AuditLog()
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		course, err := client.GetCourse(ctx, courseID)
+		if err != nil {
+			return err
+		}
+		work, err := client.GetCourseWork(ctx, courseID, assignmentID)
+		if err != nil {
+			return err
+		}
+		submission, err := client.GetMySubmission(ctx, courseID, assignmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get your submission: %w", err)
+		}
+
+		now := clk.Now()
+		bundle := &evidence.Bundle{
+			Generated:  now,
+			Version:    Version,
+			Course:     *course,
+			Work:       *work,
+			Submission: submission,
+			Audit:      auditLog.For(courseID, assignmentID),
+		}
+		if bundle.Attachments, err = evidenceAttachments(ctx, client, submission); err != nil {
+			return err
+		}
+
+		out := c.String("out")
+		if out == "" {
+			out = fmt.Sprintf("evidence-%s-%s.zip", assignmentID, now.Format("20060102-150405"))
+		}
+		if err := writeReportFile(out, func(w io.Writer) error { return bundle.Write(w) }); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stderr, "Wrote %s (%d attachment(s), %d logged action(s))\n", out, len(bundle.Attachments), len(bundle.Audit))
+		if turnedIn, err := bundle.TurnedIn(); err == nil && len(turnedIn) == 0 {
+			fmt.Fprintln(os.Stderr, "Note: Classroom has no record of this submission being turned in.")
+		}
+		return nil
+	}
+}
+
+// evidenceAttachments looks up checksums for the submission's Drive files.
+// Google Docs have no checksum, and files Drive won't show are recorded
+// with the reason rather than failing the bundle.
+func evidenceAttachments(ctx context.Context, client *api.Client, submission *api.StudentSubmission) ([]evidence.Attachment, error) {
+	attachments, err := submission.Attachments()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]evidence.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		title, link := attachmentSummary(a)
+		ea := evidence.Attachment{Title: title, Link: link}
+		if a.DriveFile != nil {
+			ea.DriveFileID = a.DriveFile.ID
+			info, err := client.GetDriveFile(ctx, a.DriveFile.ID)
+			if err != nil {
+				ea.Err = downloadError(err)
+			} else {
+				ea.MimeType = info.MimeType
+				ea.Size = info.Size
+				ea.MD5Checksum = info.MD5Checksum
+				ea.ModifiedTime = info.ModifiedTime
+			}
+		}
+		result = append(result, ea)
+	}
+	return result, nil
+}
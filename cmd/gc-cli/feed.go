@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/feed"
+	"github.com/urfave/cli/v2"
+)
+
+func FeedCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "feed",
+		Usage: "generate an Atom feed of announcements and new coursework",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "course",
+				Usage: "course ID to build the feed for",
+			},
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "write the feed to this file (default: stdout)",
+			},
+			&cli.StringFlag{
+				Name:  "serve",
+				Usage: "serve the feed over HTTP on this address (e.g. :8080)",
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "how often to refresh the feed in serve mode",
+				Value: 15 * time.Minute,
+			},
+		},
+		Action: handleFeed(cfg),
+	}
+}
+
+func handleFeed(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+
+		if addr := c.String("serve"); addr != "" {
+			return serveFeed(ctx, client, courseID, addr, c.Duration("interval"))
+		}
+
+		data, err := buildFeed(ctx, client, courseID)
+		if err != nil {
+			return err
+		}
+
+		if out := c.String("out"); out != "" {
+			if err := os.WriteFile(out, data, 0644); err != nil {
+				return fmt.Errorf("failed to write feed: %w", err)
+			}
+			fmt.Printf("Feed written to %s\n", out)
+			return nil
+		}
+
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+}
+
+func buildFeed(ctx context.Context, client *api.Client, courseID string) ([]byte, error) {
+	course, err := client.GetCourse(ctx, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("course %s not found or access denied: %w", courseID, err)
+	}
+
+	announcements, _, err := client.ListAnnouncements(ctx, courseID, 100)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list announcements: %w", err)
+	}
+
+	coursework, _, err := client.ListCourseWork(ctx, courseID, 100)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coursework: %w", err)
+	}
+
+	return feed.Build(course, announcements, coursework).Marshal()
+}
+
+// serveFeed rebuilds the feed every interval and serves the latest good copy,
+// so a transient API failure doesn't break subscribers.
+func serveFeed(ctx context.Context, client *api.Client, courseID, addr string, interval time.Duration) error {
+	var mu sync.RWMutex
+	data, err := buildFeed(ctx, client, courseID)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			fresh, err := buildFeed(ctx, client, courseID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: feed refresh failed: %v\n", err)
+				continue
+			}
+			mu.Lock()
+			data = fresh
+			mu.Unlock()
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.RLock()
+		defer mu.RUnlock()
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		_, _ = w.Write(data)
+	})
+
+	fmt.Printf("Serving feed on http://%s/ (refreshing every %s)\n", addr, interval)
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/output"
+	"github.com/urfave/cli/v2"
+)
+
+// outputFilter is the output_filters command of the command being run, if
+// it has one.
+var outputFilter string
+
+// filteredCommands are the commands that pass their results through
+// filterOutput, in every output format.
+var filteredCommands = map[string]bool{
+	"announcements":   true,
+	"courses list":    true,
+	"courses history": true,
+	"coursework list": true,
+	"grades":          true,
+	"grades summary":  true,
+	"grades stats":    true,
+	"grades history":  true,
+	"roster":          true,
+}
+
+// addOutputFilters makes each command named in filters record its filter
+// when it starts. Filters naming no command, or one that doesn't filter its
+// output, are warned about, since either would otherwise quietly leave the
+// output as it is.
+func addOutputFilters(cmds []*cli.Command, filters map[string]string) {
+	unused := make(map[string]bool, len(filters))
+	for path := range filters {
+		unused[path] = true
+	}
+	walkOutputFilters(cmds, "", filters, unused)
+	for path := range unused {
+		fmt.Fprintf(os.Stderr, "Warning: output_filters names %q, which isn't a command\n", path)
+	}
+}
+
+func walkOutputFilters(cmds []*cli.Command, parent string, filters map[string]string, unused map[string]bool) {
+	for _, cmd := range cmds {
+		path := strings.TrimSpace(parent + " " + cmd.Name)
+		delete(unused, path)
+		if filter := strings.TrimSpace(filters[path]); filter != "" && !filteredCommands[path] {
+			fmt.Fprintf(os.Stderr, "Warning: output_filters names %q, which doesn't support output filters\n", path)
+		} else if filter != "" {
+			before := cmd.Before
+			cmd.Before = func(c *cli.Context) error {
+				outputFilter = filter
+				if before != nil {
+					return before(c)
+				}
+				return nil
+			}
+		}
+		walkOutputFilters(cmd.Subcommands, path, filters, unused)
+	}
+}
+
+// filterOutput passes a command's results through its output filter before
+// they're printed in any format.
+func filterOutput[T any](items []T) ([]T, error) {
+	if outputFilter == "" {
+		return items, nil
+	}
+	return output.Filter(context.Background(), outputFilter, items)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/forecast"
+	"github.com/urfave/cli/v2"
+)
+
+func ForecastCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "forecast",
+		Usage:  "estimate weekly workload from upcoming assignments and tracked time",
+		Action: handleForecast(cfg),
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "weeks",
+				Usage: "number of weeks to forecast",
+				Value: 4,
+			},
+			&cli.Float64Flag{
+				Name:  "limit",
+				Usage: "flag weeks estimated above this many hours (default: forecast.weekly_limit_hours)",
+			},
+		},
+	}
+}
+
+func handleForecast(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		weeks := c.Int("weeks")
+		if weeks < 1 {
+			return fmt.Errorf("--weeks must be at least 1")
+		}
+		limitHours := cfg.Forecast.WeeklyLimitHours
+		if c.IsSet("limit") {
+			limitHours = c.Float64("limit")
+		}
+
+		est, source, err := newEstimator(cfg)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		items, err := agenda.Collect(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		limit := time.Duration(limitHours * float64(time.Hour))
+		forecastWeeks := forecast.Build(items, est, schoolCal, clk.Now(), weeks)
+
+		fmt.Printf("Using %.1f min/point (%s), %.0f min for ungraded work\n\n", est.MinutesPerPoint, source, est.DefaultMinutes)
+		return outputForecastTable(forecastWeeks, limit)
+	}
+}
+
+// newEstimator uses my tracked minutes per point once there's enough
+// history, and the configured rate until then. source says which.
+func newEstimator(cfg *config.Config) (est forecast.Estimator, source string, err error) {
+	st, err := cfg.Store()
+	if err != nil {
+		return est, "", err
+	}
+	log, err := st.TrackLog()
+	if err != nil {
+		return est, "", err
+	}
+	est = forecast.Estimator{
+		MinutesPerPoint: cfg.Forecast.MinutesPerPoint,
+		DefaultMinutes:  cfg.Forecast.DefaultMinutes,
+	}
+	source = "configured"
+	if rate, ok := log.MinutesPerPoint(); ok {
+		est.MinutesPerPoint = rate
+		source = "tracked"
+	}
+	return est, source, nil
+}
+
+// weekLabel names the school week starting at start, e.g. "Week 7 of Term
+// 2", or the break it falls in.
+func weekLabel(start time.Time) string {
+	if label := schoolCal.TermWeek(start); label != "" {
+		return label
+	}
+	for i := 0; i < 7; i++ {
+		if name, ok := schoolCal.Break(start.AddDate(0, 0, i)); ok {
+			return name
+		}
+	}
+	return "-"
+}
+
+func outputForecastTable(weeks []forecast.Week, limit time.Duration) error {
+	weekWidth := 16
+	countWidth := 10
+	pointsWidth := 10
+	estimateWidth := 12
+	termWidth := 0
+
+	if schoolCal.Configured() {
+		termWidth = 12
+		for _, w := range weeks {
+			if l := len(weekLabel(w.Start)); l+2 > termWidth {
+				termWidth = l + 2
+			}
+		}
+	}
+
+	headers := []string{headerStyle.Width(weekWidth).Render("Week of")}
+	if termWidth > 0 {
+		headers = append(headers, headerStyle.Width(termWidth).Render("School week"))
+	}
+	headers = append(headers,
+		headerStyle.Width(countWidth).Render("Items"),
+		headerStyle.Width(pointsWidth).Render("Points"),
+		headerStyle.Width(estimateWidth).Render("Estimate"),
+	)
+	header := lipgloss.JoinHorizontal(lipgloss.Left, headers...)
+	separator := separatorStyle.Render("─")
+
+	fmt.Println(header)
+	fmt.Println(lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		separator+separator+separator+separator,
+	))
+
+	var heavy int
+	for _, w := range weeks {
+		estimate := fmt.Sprintf("%.1fh", w.Estimate.Hours())
+		if w.Over(limit) {
+			estimate += " ⚠"
+			heavy++
+		}
+		cells := []string{cellStyle.Width(weekWidth).Render(w.Start.Format("Mon Jan 02"))}
+		if termWidth > 0 {
+			cells = append(cells, cellStyle.Width(termWidth).Render(weekLabel(w.Start)))
+		}
+		cells = append(cells,
+			cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", len(w.Items))),
+			cellStyle.Width(pointsWidth).Render(fmt.Sprintf("%d", w.Points)),
+			cellStyle.Width(estimateWidth).Render(estimate),
+		)
+		fmt.Println(lipgloss.JoinHorizontal(lipgloss.Left, cells...))
+	}
+
+	if heavy > 0 {
+		fmt.Printf("\n⚠ %d week(s) over %.1fh\n", heavy, limit.Hours())
+	}
+	return nil
+}
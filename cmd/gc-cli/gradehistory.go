@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/output"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/urfave/cli/v2"
+)
+
+func gradesHistoryCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "history",
+		Usage: "show every grade change seen in a course, and the course average after each",
+		Description: "Classroom only keeps the current grade, so gc-cli records each grade it sees\n" +
+			"with when it saw it, whenever grades are listed. The history starts the first\n" +
+			"time a course's grades are looked at, and grows as grades are given or changed.\n" +
+			"--chart plots the course average over that time instead.",
+		Action: handleGradesHistory(cfg),
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "course",
+				Usage: "course ID to show the grade history of",
+			},
+			&cli.BoolFlag{
+				Name:  "chart",
+				Usage: "plot the course average over time",
+			},
+		}, outputFlags()...),
+	}
+}
+
+// rememberGrades adds the returned grades among submissions to the grade
+// history. It's best effort, like rememberEnrollment.
+func rememberGrades(cfg *config.Config, courseID string, coursework []api.CourseWork, submissions []api.StudentSubmission) *store.GradeHistory {
+	st, err := cfg.Store()
+	if err != nil {
+		return nil
+	}
+	history, err := st.GradeHistory()
+	if err != nil {
+		return nil
+	}
+	// Anonymized grades are made up, and the offline client only has what
+	// was synced, so neither is a grade seen now.
+	if cfg.Anonymize || assumeDisabled {
+		return history
+	}
+	byID := make(map[string]api.CourseWork, len(coursework))
+	for _, cw := range coursework {
+		byID[cw.ID] = cw
+	}
+	now := clk.Now()
+	changed := false
+	for _, sub := range submissions {
+		cw, ok := byID[sub.CourseWorkID]
+		if !ok || cw.MaxPoints <= 0 || sub.State != api.SubmissionReturned {
+			continue
+		}
+		if history.Observe(courseID, cw.ID, cw.Title, sub.AssignedGrade, float64(cw.MaxPoints), now) {
+			changed = true
+		}
+	}
+	if changed {
+		_ = st.SaveGradeHistory(history)
+	}
+	return history
+}
+
+type gradeHistoryEntry struct {
+	At           time.Time `json:"at"`
+	CourseWorkID string    `json:"courseWorkId"`
+	Assignment   string    `json:"assignment"`
+	Grade        float64   `json:"grade"`
+	MaxPoints    float64   `json:"maxPoints"`
+	Previous     *float64  `json:"previous,omitempty"`
+	Average      float64   `json:"average"`
+}
+
+func handleGradesHistory(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		courseID, err := courseArg(c)
+		if err != nil {
+			return err
+		}
+		if courseID == "" {
+			return fmt.Errorf("course ID is required (use --course flag)")
+		}
+		format, err := outputFormat(c)
+		if err != nil {
+			return err
+		}
+		if c.Bool("chart") && format != output.Table {
+			return fmt.Errorf("--chart can't be combined with --output %s", format)
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		coursework, _, err := client.ListCourseWork(ctx, courseID, 100)
+		if err != nil {
+			return fmt.Errorf("failed to list coursework: %w", err)
+		}
+		submissions, _, err := client.ListStudentSubmissions(ctx, courseID, "-", 100, api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+		if err != nil {
+			return fmt.Errorf("failed to list your submissions: %w", err)
+		}
+		history := rememberGrades(cfg, courseID, coursework, submissions)
+		if history == nil {
+			return fmt.Errorf("failed to read grade history")
+		}
+
+		// Stored grades are real ones, so they're disguised like the rest.
+		// The factor is per piece of work, which keeps the trend's shape.
+		if cfg.Anonymize {
+			anon, err := newAnonymizer(cfg)
+			if err != nil {
+				return err
+			}
+			for _, w := range history.Course(courseID) {
+				for i := range w.Grades {
+					w.Grades[i].Grade = anon.Grade(w.CourseWorkID, w.Grades[i].Grade)
+				}
+			}
+		}
+
+		averages := history.Averages(courseID)
+		if c.Bool("chart") {
+			return outputGradeTrend(averages)
+		}
+
+		entries := gradeHistoryEntries(history.Course(courseID), averages)
+		entries, err = filterOutput(entries)
+		if err != nil {
+			return err
+		}
+		if format != output.Table {
+			return writeOutput(format, entries, gradeHistoryColumns)
+		}
+		return outputGradeHistoryTable(entries)
+	}
+}
+
+// gradeHistoryEntries lists every grade change of work in the order seen,
+// each with the course average once everything seen at that time counted.
+func gradeHistoryEntries(work []*store.GradedWork, averages []store.AveragePoint) []gradeHistoryEntry {
+	var entries []gradeHistoryEntry
+	for _, w := range work {
+		for i, obs := range w.Grades {
+			entry := gradeHistoryEntry{
+				At:           obs.At,
+				CourseWorkID: w.CourseWorkID,
+				Assignment:   w.Title,
+				Grade:        obs.Grade,
+				MaxPoints:    obs.MaxPoints,
+			}
+			if i > 0 {
+				prev := w.Grades[i-1].Grade
+				entry.Previous = &prev
+			}
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+
+	j := 0
+	for i := range entries {
+		for j < len(averages)-1 && averages[j].At.Before(entries[i].At) {
+			j++
+		}
+		if j < len(averages) {
+			entries[i].Average = averages[j].Percent()
+		}
+	}
+	return entries
+}
+
+func outputGradeHistoryTable(entries []gradeHistoryEntry) error {
+	if len(entries) == 0 {
+		fmt.Println("No returned grades recorded yet.")
+		return nil
+	}
+
+	dateWidth := 18
+	titleWidth := 30
+	gradeWidth := 18
+	avgWidth := 10
+	for _, e := range entries {
+		if len(e.Assignment)+2 > titleWidth {
+			titleWidth = len(e.Assignment) + 2
+		}
+	}
+	if titleWidth > 50 {
+		titleWidth = 50
+	}
+
+	fmt.Println(lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(dateWidth).Render("When"),
+		headerStyle.Width(titleWidth).Render("Assignment"),
+		headerStyle.Width(gradeWidth).Render("Grade"),
+		headerStyle.Width(avgWidth).Render("Average"),
+	))
+	fmt.Println(separatorStyle.Render(strings.Repeat("─", dateWidth+titleWidth+gradeWidth+avgWidth)))
+	for _, e := range entries {
+		grade := fmt.Sprintf("%g/%g", e.Grade, e.MaxPoints)
+		if e.Previous != nil {
+			grade = fmt.Sprintf("%g → %s", *e.Previous, grade)
+		}
+		fmt.Println(lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(dateWidth).Render(e.At.Local().Format("2006-01-02 15:04")),
+			cellStyle.Width(titleWidth).Render(truncate(e.Assignment, titleWidth-2)),
+			cellStyle.Width(gradeWidth).Render(grade),
+			cellStyle.Width(avgWidth).Render(fmt.Sprintf("%.1f%%", e.Average)),
+		))
+	}
+
+	fmt.Println()
+	fmt.Printf("Total: %d change(s). Grades are recorded whenever 'grades' lists them.\n", len(entries))
+	return nil
+}
+
+const (
+	trendWidth  = 60
+	trendHeight = 11
+)
+
+func outputGradeTrend(averages []store.AveragePoint) error {
+	if len(averages) == 0 {
+		fmt.Println("No returned grades recorded yet.")
+		return nil
+	}
+	for _, line := range plotTrend(averages, trendWidth, trendHeight) {
+		fmt.Println(line)
+	}
+	fmt.Println()
+	last := averages[len(averages)-1]
+	fmt.Printf("Average now: %.1f%% (%g/%g points)", last.Percent(), last.Earned, last.Possible)
+	if len(averages) == 1 {
+		fmt.Print(". Only one point so far; the trend fills in as grades are given")
+	}
+	fmt.Println()
+	return nil
+}
+
+// plotTrend draws the course average as a step line, width columns across
+// the time from the first to the last point and height rows from just below
+// the lowest average to just above the highest. • marks a change; · carries
+// the average until the next one.
+func plotTrend(points []store.AveragePoint, width, height int) []string {
+	lo, hi := 100.0, 0.0
+	for _, p := range points {
+		lo = math.Min(lo, p.Percent())
+		hi = math.Max(hi, p.Percent())
+	}
+	lo = math.Floor(lo/5) * 5
+	hi = math.Ceil(hi/5) * 5
+	if hi-lo < 10 {
+		hi = lo + 10
+	}
+	row := func(v float64) int {
+		return int(math.Round((v - lo) / (hi - lo) * float64(height-1)))
+	}
+
+	grid := make([][]rune, height)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", width))
+	}
+	first, last := points[0].At, points[len(points)-1].At
+	span := last.Sub(first)
+	column := func(t time.Time) int {
+		if span <= 0 {
+			return 0
+		}
+		return int(float64(t.Sub(first)) / float64(span) * float64(width-1))
+	}
+	for i, p := range points {
+		from := column(p.At)
+		to := width
+		if i+1 < len(points) {
+			to = column(points[i+1].At)
+		}
+		r := row(p.Percent())
+		for x := from; x < to; x++ {
+			if grid[r][x] == ' ' {
+				grid[r][x] = '·'
+			}
+		}
+		grid[r][from] = '•'
+	}
+
+	lines := make([]string, 0, height+2)
+	for r := height - 1; r >= 0; r-- {
+		label := "      "
+		if r == height-1 || r == 0 || r == (height-1)/2 {
+			label = fmt.Sprintf("%5.1f%%", lo+(hi-lo)*float64(r)/float64(height-1))
+		}
+		lines = append(lines, label+" ┤"+string(grid[r]))
+	}
+	lines = append(lines, "       └"+strings.Repeat("─", width))
+
+	from := first.Local().Format("Jan 2")
+	to := last.Local().Format("Jan 2")
+	gap := width - len(from) - len(to)
+	if span <= 0 || gap < 1 {
+		lines = append(lines, "        "+from)
+	} else {
+		lines = append(lines, "        "+from+strings.Repeat(" ", gap)+to)
+	}
+	return lines
+}
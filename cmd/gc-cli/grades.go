@@ -2,23 +2,32 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/timboy697/gc-cli/internal/api"
-	"github.com/timboy697/gc-cli/internal/auth"
 	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/coursename"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+	"github.com/timboy697/gc-cli/internal/output"
 	"github.com/urfave/cli/v2"
 )
 
 type GradeEntry struct {
-	Assignment string
-	Grade      string
-	MaxPoints  string
-	Feedback   string
+	Assignment string `json:"assignment"`
+	Grade      string `json:"grade"`
+	MaxPoints  string `json:"maxPoints"`
+	Letter     string `json:"letter"`
+	Feedback   string `json:"feedback"`
+
+	Earned   float64   `json:"-"`
+	Possible float64   `json:"-"`
+	Date     time.Time `json:"-"`
 }
 
 func GradesCmd(cfg *config.Config) *cli.Command {
@@ -28,16 +37,59 @@ func GradesCmd(cfg *config.Config) *cli.Command {
 		Action: func(c *cli.Context) error {
 			return handleGrades(c, cfg)
 		},
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "course",
+				Usage: "course ID to view grades for",
+			},
 			&cli.StringFlag{
-				Name:     "course",
-				Usage:    "course ID to view grades for",
-				Required: true,
+				Name:  "period",
+				Usage: "only show grades for a grading period (e.g. Q1) or date range (2024-01-01..2024-03-31)",
+			},
+			&cli.BoolFlag{
+				Name:  "compare",
+				Usage: "show a per-period comparison table",
 			},
 			&cli.BoolFlag{
-				Name:  "json",
-				Usage: "output as JSON",
+				Name:  "summary",
+				Usage: "show averages per grade category, missing work and best and worst grades",
+			},
+		}, outputFlags()...),
+		Subcommands: []*cli.Command{
+			{
+				Name:  "summary",
+				Usage: "show points earned and possible, percentage and missing work per course",
+				Description: "With --course it is the same as 'gc-cli grades --summary'. --all lists every\n" +
+					"active course with its totals instead. Only returned grades count; ungraded\n" +
+					"and excused work adds nothing to the points possible.",
+				Action: handleGradesSummary(cfg),
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "course ID to summarise",
+					},
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "summarise every active course",
+					},
+					&cli.StringFlag{
+						Name:  "period",
+						Usage: "only count work from a grading period (e.g. Q1) or date range; needs --course",
+					},
+				}, outputFlags()...),
 			},
+			{
+				Name:   "stats",
+				Usage:  "show how long each course takes to return graded work",
+				Action: handleGradeStats(cfg),
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "only this course (default: all active courses)",
+					},
+				}, outputFlags()...),
+			},
+			gradesHistoryCmd(cfg),
 		},
 	}
 }
@@ -45,35 +97,58 @@ func GradesCmd(cfg *config.Config) *cli.Command {
 func handleGrades(c *cli.Context, cfg *config.Config) error {
 	ctx := context.Background()
 
-	token, err := auth.GetValidToken(ctx, auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile))
+	courseID, err := courseArg(c)
 	if err != nil {
-		return fmt.Errorf("authentication required: %w", err)
+		return err
 	}
-
-	authCfg := auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile)
-	client, err := api.NewClientFromToken(ctx, authCfg.OAuth2Config(), token)
+	if courseID == "" {
+		return fmt.Errorf("course ID is required (use --course flag)")
+	}
+	format, err := outputFormat(c)
 	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+		return err
 	}
 
-	courseID := c.String("course")
+	client, err := newAPIClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
 
 	coursework, _, err := client.ListCourseWork(ctx, courseID, 100)
 	if err != nil {
 		return fmt.Errorf("failed to list coursework: %w", err)
 	}
 
+	if c.Bool("summary") {
+		if outputFilter != "" {
+			fmt.Fprintln(os.Stderr, "Warning: output_filters doesn't apply to grades --summary")
+		}
+		return handleGradeSummary(ctx, c, cfg, client, courseID, coursework, format)
+	}
+
 	var publishedCoursework []api.CourseWork
 	for _, cw := range coursework {
-		if cw.State == "PUBLISHED" {
+		if cw.State == api.CourseWorkPublished {
 			publishedCoursework = append(publishedCoursework, cw)
 		}
 	}
 
+	scale := gradebook.NewScale(cfg.Grades.Scale)
+
+	submissions, _, err := client.ListStudentSubmissions(ctx, courseID, "-", 100, api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+	if err != nil {
+		return fmt.Errorf("failed to list your submissions: %w", err)
+	}
+	rememberGrades(cfg, courseID, coursework, submissions)
+	mySubmissions := make(map[string]api.StudentSubmission, len(submissions))
+	for _, sub := range submissions {
+		mySubmissions[sub.CourseWorkID] = sub
+	}
+
 	var grades []GradeEntry
 	for _, cw := range publishedCoursework {
-		submission, err := client.GetMySubmission(ctx, courseID, cw.ID)
-		if err != nil {
+		submission, ok := mySubmissions[cw.ID]
+		if !ok {
 			continue
 		}
 
@@ -86,32 +161,144 @@ func handleGrades(c *cli.Context, cfg *config.Config) error {
 			feedback := "Not returned"
 			if !submission.ReturnTimestamp.IsZero() {
 				feedback = "Returned"
-			} else if submission.State == "TURNED_IN" {
+			} else if submission.State == api.SubmissionTurnedIn {
 				feedback = "Graded"
 			}
 
+			date := getDueDate(cw)
+			if date.IsZero() {
+				date = cw.CreateTime
+			}
+
+			letter := ""
+			if cw.MaxPoints > 0 {
+				letter = scale.Letter(grade / float64(cw.MaxPoints) * 100)
+			}
+
 			grades = append(grades, GradeEntry{
 				Assignment: cw.Title,
 				Grade:      fmt.Sprintf("%.1f", grade),
 				MaxPoints:  fmt.Sprintf("%d", cw.MaxPoints),
+				Letter:     letter,
 				Feedback:   feedback,
+				Earned:     grade,
+				Possible:   float64(cw.MaxPoints),
+				Date:       date,
 			})
 		}
 	}
 
-	if c.Bool("json") {
-		return outputGradesJSON(grades)
+	grades, err = filterOutput(grades)
+	if err != nil {
+		return err
 	}
-	return outputGradesTable(grades)
+
+	if c.String("period") != "" || c.Bool("compare") {
+		periods, err := loadGradingPeriods(ctx, client, courseID, cfg)
+		if err != nil {
+			return err
+		}
+
+		if spec := c.String("period"); spec != "" {
+			period, err := gradebook.ParsePeriod(spec, periods)
+			if err != nil {
+				return err
+			}
+			var inPeriod []GradeEntry
+			for _, g := range grades {
+				if period.Contains(g.Date) {
+					inPeriod = append(inPeriod, g)
+				}
+			}
+			grades = inPeriod
+		}
+
+		if c.Bool("compare") {
+			if len(periods) == 0 {
+				return fmt.Errorf("no grading periods found for this course; configure grades.periods in %s", cfg.ConfigPath)
+			}
+			return outputPeriodComparison(periods, grades, scale)
+		}
+	}
+
+	if format != output.Table {
+		return writeOutput(format, grades, gradeColumns)
+	}
+	return outputGradesTable(grades, scale)
 }
 
-func outputGradesJSON(grades []GradeEntry) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(grades)
+// loadGradingPeriods prefers the course's own grading period settings and
+// falls back to the periods configured locally when the course has none or
+// won't show them. Any other failure to read the settings is an error.
+func loadGradingPeriods(ctx context.Context, client *api.Client, courseID string, cfg *config.Config) ([]gradebook.Period, error) {
+	settings, err := client.GetGradingPeriodSettings(ctx, courseID)
+	switch {
+	case err == nil:
+		if periods := gradebook.PeriodsFromAPI(settings.GradingPeriods); len(periods) > 0 {
+			return periods, nil
+		}
+	case api.IsForbidden(err) || api.IsNotFound(err):
+		// Students often may not read a course's grading period settings;
+		// the configured periods are what's left.
+	default:
+		return nil, fmt.Errorf("failed to load grading periods: %w", err)
+	}
+	return gradebook.PeriodsFromConfig(cfg.Grades.Periods)
+}
+
+func outputPeriodComparison(periods []gradebook.Period, grades []GradeEntry, scale gradebook.Scale) error {
+	periodWidth := 30
+	countWidth := 8
+	pointsWidth := 16
+	percentWidth := 12
+
+	header := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(periodWidth).Render("Period"),
+		headerStyle.Width(countWidth).Render("Graded"),
+		headerStyle.Width(pointsWidth).Render("Points"),
+		headerStyle.Width(percentWidth).Render("Percent"),
+	)
+	separator := separatorStyle.Render("─")
+
+	fmt.Println(header)
+	fmt.Println(lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		separator+separator+separator+separator,
+	))
+
+	for _, p := range periods {
+		var count int
+		var earned, possible float64
+		for _, g := range grades {
+			if p.Contains(g.Date) {
+				count++
+				earned += g.Earned
+				possible += g.Possible
+			}
+		}
+
+		points, percent := "-", "-"
+		if possible > 0 {
+			points = fmt.Sprintf("%.1f/%.0f", earned, possible)
+			pct := earned / possible * 100
+			percent = fmt.Sprintf("%.1f%% %s", pct, scale.Letter(pct))
+		}
+
+		row := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(periodWidth).Render(truncate(p.Name, periodWidth)),
+			cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", count)),
+			cellStyle.Width(pointsWidth).Render(points),
+			cellStyle.Width(percentWidth).Render(percent),
+		)
+		fmt.Println(row)
+	}
+
+	return nil
 }
 
-func outputGradesTable(grades []GradeEntry) error {
+func outputGradesTable(grades []GradeEntry, scale gradebook.Scale) error {
 	if len(grades) == 0 {
 		fmt.Println("No grades yet")
 		return nil
@@ -124,6 +311,7 @@ func outputGradesTable(grades []GradeEntry) error {
 	assignmentWidth := 40
 	gradeWidth := 10
 	maxPointsWidth := 12
+	letterWidth := 8
 	feedbackWidth := 15
 
 	for _, g := range grades {
@@ -159,6 +347,7 @@ func outputGradesTable(grades []GradeEntry) error {
 		headerStyle.Width(assignmentWidth).Render("Assignment"),
 		headerStyle.Width(gradeWidth).Render("Grade"),
 		headerStyle.Width(maxPointsWidth).Render("Max Points"),
+		headerStyle.Width(letterWidth).Render("Letter"),
 		headerStyle.Width(feedbackWidth).Render("Feedback"),
 	)
 	separator := separatorStyle.Render("─")
@@ -175,12 +364,525 @@ func outputGradesTable(grades []GradeEntry) error {
 			cellStyle.Width(assignmentWidth).Render(truncate(g.Assignment, assignmentWidth)),
 			cellStyle.Width(gradeWidth).Render(g.Grade),
 			cellStyle.Width(maxPointsWidth).Render(g.MaxPoints),
+			cellStyle.Width(letterWidth).Render(g.Letter),
 			cellStyle.Width(feedbackWidth).Render(g.Feedback),
 		)
 		fmt.Println(row)
 	}
 
+	var earned, possible float64
+	for _, g := range grades {
+		earned += g.Earned
+		possible += g.Possible
+	}
+
 	fmt.Println()
 	fmt.Printf("Total: %d grade(s)\n", len(grades))
+	if possible > 0 {
+		pct := earned / possible * 100
+		fmt.Printf("Overall: %.1f/%.0f (%.1f%%, %s)\n", earned, possible, pct, scale.Letter(pct))
+	}
+	return nil
+}
+
+// summaryExtremes is how many best and worst grades --summary lists.
+const summaryExtremes = 3
+
+type gradeSummaryJSON struct {
+	Course     string                 `json:"course"`
+	CourseID   string                 `json:"courseId"`
+	Graded     int                    `json:"graded"`
+	Percent    *float64               `json:"percent,omitempty"`
+	Weighted   *float64               `json:"weightedPercent,omitempty"`
+	Categories []categorySummaryJSON  `json:"categories"`
+	Missing    []gradeSummaryWorkJSON `json:"missing"`
+	Best       []gradeSummaryWorkJSON `json:"best"`
+	Worst      []gradeSummaryWorkJSON `json:"worst"`
+}
+
+type categorySummaryJSON struct {
+	Name    string   `json:"name"`
+	Weight  float64  `json:"weight,omitempty"`
+	Graded  int      `json:"graded"`
+	Missing int      `json:"missing"`
+	Percent *float64 `json:"percent,omitempty"`
+	Class   *float64 `json:"classPercent,omitempty"`
+}
+
+type gradeSummaryWorkJSON struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Grade     *float64 `json:"grade,omitempty"`
+	MaxPoints int64    `json:"maxPoints,omitempty"`
+	Percent   *float64 `json:"percent,omitempty"`
+	Link      string   `json:"link,omitempty"`
+}
+
+// handleGradeSummary prints a health check of one course. Teachers see the
+// class average per category alongside; students only ever see their own
+// grades, so for them it is left out.
+func handleGradeSummary(ctx context.Context, c *cli.Context, cfg *config.Config, client *api.Client, courseID string, coursework []api.CourseWork, format output.Format) error {
+	if spec := c.String("period"); spec != "" {
+		periods, err := loadGradingPeriods(ctx, client, courseID, cfg)
+		if err != nil {
+			return err
+		}
+		period, err := gradebook.ParsePeriod(spec, periods)
+		if err != nil {
+			return err
+		}
+		var inPeriod []api.CourseWork
+		for _, cw := range coursework {
+			date := getDueDate(cw)
+			if date.IsZero() {
+				date = cw.CreateTime
+			}
+			if period.Contains(date) {
+				inPeriod = append(inPeriod, cw)
+			}
+		}
+		coursework = inPeriod
+	}
+
+	course, err := client.GetCourse(ctx, courseID)
+	if err != nil {
+		return err
+	}
+	submissions, _, err := client.ListStudentSubmissions(ctx, courseID, "-", 100)
+	if err != nil {
+		return fmt.Errorf("failed to list submissions: %w", err)
+	}
+	var me string
+	if p, err := client.GetUserProfile(ctx, "me"); err == nil {
+		me = p.ID
+	}
+
+	h := gradebook.CourseHealth(coursework, submissions, me, summaryExtremes)
+	switch format {
+	case output.JSON, output.YAML:
+		return output.WriteOne(os.Stdout, format, gradeSummaryToJSON(course, h))
+	case output.CSV, output.TSV:
+		// A spreadsheet gets the per-category rows.
+		return writeOutput(format, gradeSummaryToJSON(course, h).Categories, categorySummaryColumns)
+	}
+	return outputGradeSummary(course, h, gradebook.NewScale(cfg.Grades.Scale))
+}
+
+func gradeSummaryToJSON(course *api.Course, h gradebook.Health) gradeSummaryJSON {
+	out := gradeSummaryJSON{
+		Course:     course.Name,
+		CourseID:   course.ID,
+		Graded:     h.Overall.Graded,
+		Categories: []categorySummaryJSON{},
+		Missing:    []gradeSummaryWorkJSON{},
+	}
+	if pct, ok := h.Overall.Percent(); ok {
+		out.Percent = &pct
+	}
+	if h.HasWeighted {
+		out.Weighted = &h.Weighted
+	}
+	for _, cat := range h.Categories {
+		cs := categorySummaryJSON{Name: cat.Name, Weight: cat.Weight, Graded: cat.Graded, Missing: cat.Missing}
+		if pct, ok := cat.Percent(); ok {
+			cs.Percent = &pct
+		}
+		if cat.HasClass {
+			class := cat.Class
+			cs.Class = &class
+		}
+		out.Categories = append(out.Categories, cs)
+	}
+	for _, cw := range h.Missing {
+		out.Missing = append(out.Missing, gradeSummaryWorkJSON{ID: cw.ID, Title: cw.Title, MaxPoints: cw.MaxPoints, Link: cw.AlternateLink})
+	}
+	scored := func(list []gradebook.Scored) []gradeSummaryWorkJSON {
+		result := []gradeSummaryWorkJSON{}
+		for _, s := range list {
+			s := s
+			result = append(result, gradeSummaryWorkJSON{ID: s.Work.ID, Title: s.Work.Title, Grade: &s.Grade, MaxPoints: s.Work.MaxPoints, Percent: &s.Percent, Link: s.Work.AlternateLink})
+		}
+		return result
+	}
+	out.Best, out.Worst = scored(h.Best), scored(h.Worst)
+	return out
+}
+
+func outputGradeSummary(course *api.Course, h gradebook.Health, scale gradebook.Scale) error {
+	fmt.Println(headerStyle.Render(course.Name))
+	if pct, ok := h.Overall.Percent(); ok {
+		fmt.Printf("Overall: %.1f/%.0f (%.1f%%, %s) from %d graded\n", h.Overall.Earned, h.Overall.Possible, pct, scale.Letter(pct), h.Overall.Graded)
+	} else {
+		fmt.Println("Overall: nothing graded yet")
+	}
+	if h.HasWeighted {
+		fmt.Printf("Weighted by category: %.1f%% (%s)\n", h.Weighted, scale.Letter(h.Weighted))
+	}
+	fmt.Println()
+
+	var hasClass, hasWeights bool
+	for _, cat := range h.Categories {
+		hasClass = hasClass || cat.HasClass
+		hasWeights = hasWeights || cat.Weight > 0
+	}
+
+	nameWidth := 24
+	weightWidth := 8
+	countWidth := 9
+	pointsWidth := 16
+	percentWidth := 12
+
+	headers := []string{
+		headerStyle.Width(nameWidth).Render("Category"),
+	}
+	if hasWeights {
+		headers = append(headers, headerStyle.Width(weightWidth).Render("Weight"))
+	}
+	headers = append(headers,
+		headerStyle.Width(countWidth).Render("Graded"),
+		headerStyle.Width(countWidth).Render("Missing"),
+		headerStyle.Width(pointsWidth).Render("Points"),
+		headerStyle.Width(percentWidth).Render("Mine"),
+	)
+	if hasClass {
+		headers = append(headers, headerStyle.Width(percentWidth).Render("Class"))
+	}
+	header := lipgloss.JoinHorizontal(lipgloss.Left, headers...)
+	fmt.Println(header)
+	fmt.Println(separatorStyle.Render(strings.Repeat("─", lipgloss.Width(header))))
+
+	for _, cat := range h.Categories {
+		points, percent := "-", "-"
+		if pct, ok := cat.Percent(); ok {
+			points = fmt.Sprintf("%.1f/%.0f", cat.Earned, cat.Possible)
+			percent = fmt.Sprintf("%.1f%% %s", pct, scale.Letter(pct))
+		}
+		cells := []string{
+			cellStyle.Width(nameWidth).Render(truncate(cat.Name, nameWidth-2)),
+		}
+		if hasWeights {
+			weight := "-"
+			if cat.Weight > 0 {
+				weight = fmt.Sprintf("%g%%", cat.Weight)
+			}
+			cells = append(cells, cellStyle.Width(weightWidth).Render(weight))
+		}
+		cells = append(cells,
+			cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", cat.Graded)),
+			cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", cat.Missing)),
+			cellStyle.Width(pointsWidth).Render(points),
+			cellStyle.Width(percentWidth).Render(percent),
+		)
+		if hasClass {
+			class := "-"
+			if cat.HasClass {
+				class = fmt.Sprintf("%.1f%%", cat.Class)
+			}
+			cells = append(cells, cellStyle.Width(percentWidth).Render(class))
+		}
+		fmt.Println(lipgloss.JoinHorizontal(lipgloss.Left, cells...))
+	}
+
+	fmt.Println()
+	if len(h.Missing) == 0 {
+		fmt.Println("Missing: none")
+	} else {
+		fmt.Printf("Missing: %d\n", len(h.Missing))
+		for _, cw := range h.Missing {
+			fmt.Printf("  %s\n", cw.Title)
+		}
+	}
+	printScored := func(label string, list []gradebook.Scored) {
+		if len(list) == 0 {
+			return
+		}
+		fmt.Println(label + ":")
+		for _, s := range list {
+			fmt.Printf("  %-40s %.1f/%d (%.1f%%)\n", truncate(s.Work.Title, 40), s.Grade, s.Work.MaxPoints, s.Percent)
+		}
+	}
+	printScored("Best", h.Best)
+	printScored("Worst", h.Worst)
+	return nil
+}
+
+type courseGradeSummary struct {
+	Course   string   `json:"course"`
+	CourseID string   `json:"courseId"`
+	Earned   float64  `json:"earned"`
+	Possible float64  `json:"possible"`
+	Percent  *float64 `json:"percent,omitempty"`
+	Graded   int      `json:"graded"`
+	Missing  int      `json:"missing"`
+}
+
+func handleGradesSummary(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		all := c.Bool("all")
+		if all && c.IsSet("course") {
+			return fmt.Errorf("--all and --course can't be used together")
+		}
+		if all && c.String("period") != "" {
+			return fmt.Errorf("--period needs a single course (use --course)")
+		}
+		format, err := outputFormat(c)
+		if err != nil {
+			return err
+		}
+		var courseID string
+		if !all {
+			courseID, err = courseArg(c)
+			if err != nil {
+				return err
+			}
+			if courseID == "" {
+				return fmt.Errorf("course ID is required (use --course flag, or --all for every course)")
+			}
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		if !all {
+			coursework, _, err := client.ListCourseWork(ctx, courseID, 100)
+			if err != nil {
+				return fmt.Errorf("failed to list coursework: %w", err)
+			}
+			return handleGradeSummary(ctx, c, cfg, client, courseID, coursework, format)
+		}
+
+		listed, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive))
+		if err != nil {
+			return fmt.Errorf("failed to list courses: %w", err)
+		}
+		var courses []api.Course
+		for _, course := range listed {
+			if course.CourseState == api.CourseActive {
+				courses = append(courses, course)
+			}
+		}
+		courseorder.New(cfg.Courses).SortCourses(courses)
+		labels := coursename.Labels(courses, nil)
+
+		var summaries []courseGradeSummary
+		for _, course := range courses {
+			coursework, _, err := client.ListCourseWork(ctx, course.ID, 100)
+			if err != nil {
+				return fmt.Errorf("failed to list coursework for %s: %w", course.Name, err)
+			}
+			submissions, _, err := client.ListStudentSubmissions(ctx, course.ID, "-", 100, api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+			if err != nil {
+				return fmt.Errorf("failed to list submissions for %s: %w", course.Name, err)
+			}
+			rememberGrades(cfg, course.ID, coursework, submissions)
+			s := gradebook.Summarize(coursework, submissions)
+			summary := courseGradeSummary{
+				Course:   labels[course.ID],
+				CourseID: course.ID,
+				Earned:   s.Earned,
+				Possible: s.Possible,
+				Graded:   s.Graded,
+				Missing:  s.Missing,
+			}
+			if pct, ok := s.Percent(); ok {
+				summary.Percent = &pct
+			}
+			summaries = append(summaries, summary)
+		}
+
+		summaries, err = filterOutput(summaries)
+		if err != nil {
+			return err
+		}
+
+		if format != output.Table {
+			return writeOutput(format, summaries, courseGradeSummaryColumns)
+		}
+		return outputGradesOverview(summaries, gradebook.NewScale(cfg.Grades.Scale))
+	}
+}
+
+// outputGradesOverview prints a row per course, then the average of the
+// course percentages, so a course graded out of thousands of points doesn't
+// outweigh the rest.
+func outputGradesOverview(summaries []courseGradeSummary, scale gradebook.Scale) error {
+	if len(summaries) == 0 {
+		fmt.Println("No active courses")
+		return nil
+	}
+
+	nameWidth := 36
+	countWidth := 9
+	pointsWidth := 16
+	percentWidth := 12
+
+	header := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(nameWidth).Render("Course"),
+		headerStyle.Width(countWidth).Render("Graded"),
+		headerStyle.Width(countWidth).Render("Missing"),
+		headerStyle.Width(pointsWidth).Render("Points"),
+		headerStyle.Width(percentWidth).Render("Percent"),
+	)
+	fmt.Println(header)
+	fmt.Println(separatorStyle.Render(strings.Repeat("─", lipgloss.Width(header))))
+
+	var total float64
+	var graded, missing int
+	for _, s := range summaries {
+		points, percent := "-", "-"
+		if s.Percent != nil {
+			points = fmt.Sprintf("%.1f/%.0f", s.Earned, s.Possible)
+			percent = fmt.Sprintf("%.1f%% %s", *s.Percent, scale.Letter(*s.Percent))
+			total += *s.Percent
+			graded++
+		}
+		missing += s.Missing
+		row := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(nameWidth).Render(truncate(s.Course, nameWidth-2)),
+			cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", s.Graded)),
+			cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", s.Missing)),
+			cellStyle.Width(pointsWidth).Render(points),
+			cellStyle.Width(percentWidth).Render(percent),
+		)
+		fmt.Println(row)
+	}
+
+	fmt.Println()
+	if graded > 0 {
+		avg := total / float64(graded)
+		fmt.Printf("Average: %.1f%% (%s) across %d graded course(s)\n", avg, scale.Letter(avg), graded)
+	} else {
+		fmt.Println("Average: nothing graded yet")
+	}
+	fmt.Printf("Missing: %d\n", missing)
 	return nil
 }
+
+type courseTurnaround struct {
+	Course   string  `json:"course"`
+	CourseID string  `json:"courseId"`
+	Returned int     `json:"returned"`
+	Average  float64 `json:"averageHours"`
+	Slowest  float64 `json:"slowestHours"`
+}
+
+func handleGradeStats(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		format, err := outputFormat(c)
+		if err != nil {
+			return err
+		}
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		var courses []api.Course
+		if c.IsSet("course") {
+			courseID, err := courseArg(c)
+			if err != nil {
+				return err
+			}
+			course, err := client.GetCourse(ctx, courseID)
+			if err != nil {
+				return err
+			}
+			courses = []api.Course{*course}
+		} else {
+			all, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive))
+			if err != nil {
+				return fmt.Errorf("failed to list courses: %w", err)
+			}
+			for _, course := range all {
+				if course.CourseState == api.CourseActive {
+					courses = append(courses, course)
+				}
+			}
+			courseorder.New(cfg.Courses).SortCourses(courses)
+		}
+
+		var stats []courseTurnaround
+		for _, course := range courses {
+			submissions, _, err := client.ListStudentSubmissions(ctx, course.ID, "-", 100, api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+			if err != nil {
+				return fmt.Errorf("failed to list submissions for %s: %w", course.Name, err)
+			}
+			t := gradebook.MeasureTurnaround(submissions)
+			stats = append(stats, courseTurnaround{
+				Course:   course.Name,
+				CourseID: course.ID,
+				Returned: t.Returned,
+				Average:  t.Average.Hours(),
+				Slowest:  t.Slowest.Hours(),
+			})
+		}
+
+		stats, err = filterOutput(stats)
+		if err != nil {
+			return err
+		}
+
+		if format != output.Table {
+			return writeOutput(format, stats, turnaroundColumns)
+		}
+		return outputTurnaroundTable(stats)
+	}
+}
+
+func outputTurnaroundTable(stats []courseTurnaround) error {
+	nameWidth := 36
+	countWidth := 10
+	timeWidth := 16
+
+	header := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(nameWidth).Render("Course"),
+		headerStyle.Width(countWidth).Render("Returned"),
+		headerStyle.Width(timeWidth).Render("Avg turnaround"),
+		headerStyle.Width(timeWidth).Render("Slowest"),
+	)
+	separator := separatorStyle.Render("─")
+
+	fmt.Println(header)
+	fmt.Println(lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		separator+separator+separator+separator,
+	))
+
+	for _, s := range stats {
+		avg, slowest := "-", "-"
+		if s.Returned > 0 {
+			avg = formatWait(time.Duration(s.Average * float64(time.Hour)))
+			slowest = formatWait(time.Duration(s.Slowest * float64(time.Hour)))
+		}
+		row := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(nameWidth).Render(truncate(s.Course, nameWidth-2)),
+			cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", s.Returned)),
+			cellStyle.Width(timeWidth).Render(avg),
+			cellStyle.Width(timeWidth).Render(slowest),
+		)
+		fmt.Println(row)
+	}
+
+	fmt.Println()
+	fmt.Println("Turnaround is the time from your last turn-in to the work being returned.")
+	return nil
+}
+
+// formatWait renders a turnaround in days and hours, e.g. "3d 4h".
+func formatWait(d time.Duration) string {
+	hours := int(d.Round(time.Hour).Hours())
+	if hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// helpTopic is a page of help about gc-cli as a whole rather than one
+// command. Body is Markdown: printed as is by 'gc-cli help <topic>' and
+// rendered into the topic's man page by 'gc-cli docs'.
+type helpTopic struct {
+	Name  string
+	Usage string
+	Body  string
+}
+
+var helpTopics = []helpTopic{
+	{
+		Name:  "auth",
+		Usage: "signing in, tokens, profiles and scopes",
+		Body: "`gc-cli auth login` opens a browser to sign in with Google and saves the token\n" +
+			"in `auth.token_file` (by default `token.json` next to the config file). The token\n" +
+			"is renewed automatically while it is used.\n\n" +
+			"- `gc-cli auth status` shows whether the token works. With `--refresh-if-needed`\n" +
+			"  it renews a token that expires within `--margin` and fails without a usable\n" +
+			"  one, so cron jobs can run it before anything else.\n" +
+			"- `gc-cli auth logout` revokes the token with Google and deletes it. Local data\n" +
+			"  such as notes and syncs is kept. `--local-only` only deletes the file.\n" +
+			"- `gc-cli profile` manages named credential profiles, picked with `--profile` or\n" +
+			"  `GC_CLI_PROFILE`. A read-only profile can view courses, work, grades and\n" +
+			"  announcements but not change anything, e.g. for a parent.\n\n" +
+			"New features sometimes need permissions an older token lacks. Commands check\n" +
+			"before they start and name the missing one; `gc-cli auth status` lists all that\n" +
+			"are missing. Run `gc-cli auth login` again to grant them.\n\n" +
+			"If your school has blocked the Classroom API, `--assume-disabled` reads from the\n" +
+			"last `gc-cli sync` instead of signing in.\n",
+	},
+	{
+		Name:  "config",
+		Usage: "the config file and what it can set",
+		Body: "gc-cli works without a config file. To change its defaults, create\n" +
+			"`~/.config/gc-cli/config.yaml`, or point `--config` at another file. Settings\n" +
+			"include:\n\n" +
+			"- `google_classroom.course_id`: the course used when `--course` is left out.\n" +
+			"- `google_classroom.aliases`: short names for courses, e.g. `math: 123456789`,\n" +
+			"  usable anywhere a course ID is.\n" +
+			"- `shortcuts`: names for whole command lines; see `gc-cli shortcuts list`.\n" +
+			"- `output_filters`: shell commands, per command, that rewrite its results as\n" +
+			"  JSON before they're printed, e.g. to translate announcements.\n" +
+			"- `courses`: the order courses are listed in, with optional period labels.\n" +
+			"- `grades.periods` and `grades.scale`: grading periods and letter grades.\n" +
+			"- `calendar`: the school year's terms, holidays and school days.\n" +
+			"- `notify.quiet_hours`: times no notifications are sent.\n" +
+			"- `cache`: how long API responses are reused, per kind of data.\n" +
+			"- `tui`: the deadline banner and whether the TUI reopens where you left it.\n" +
+			"- `display.redact_ids`: `hash` or `truncate` to hide IDs when sharing a screen.\n" +
+			"- `storage`: where notes, syncs and other local state are kept.\n\n" +
+			"The README has an example of each section.\n\n" +
+			"`version` records the file's layout. When an upgrade changes it, gc-cli updates\n" +
+			"the file the next time it runs and keeps the old one as `config.yaml.v1.bak`\n" +
+			"(for version 1). `gc-cli config migrate --dry-run` shows what would change.\n",
+	},
+	{
+		Name:  "output-formats",
+		Usage: "table, JSON, YAML, CSV and TSV output",
+		Body: "List commands print a table by default. `--output` (or `-o`) picks another\n" +
+			"format for scripts and spreadsheets:\n\n" +
+			"- `json`: the full objects, as the Classroom API names their fields.\n" +
+			"- `yaml`: the same objects and field names as YAML.\n" +
+			"- `csv` and `tsv`: one row per item with the most useful fields. Columns are\n" +
+			"  named after the JSON fields they come from.\n\n" +
+			"It works with `courses list`, `courses history`, `coursework list`, `grades`,\n" +
+			"`grades summary`, `grades stats`, `grades history`, `announcements`, `roster`\n" +
+			"and `submit status`. `--json` is short for `--output json`.\n\n" +
+			"Dates in CSV and TSV are ISO 8601 in UTC, e.g. `2024-03-01T23:59:00Z`, which\n" +
+			"spreadsheets read as dates.\n\n" +
+			"`gc-cli watch --format ndjson` prints one JSON object per change instead.\n",
+	},
+	{
+		Name:  "exit-codes",
+		Usage: "what gc-cli's exit status means",
+		Body: "- `0`: the command succeeded.\n" +
+			"- `1`: it failed. The reason is printed to standard error, prefixed with\n" +
+			"  `Error:`. This includes unknown flags, failed API calls, and\n" +
+			"  `gc-cli auth status --refresh-if-needed` finding no usable token.\n" +
+			"- `3`: there is no command or help topic of that name, as in `gc-cli nope` or\n" +
+			"  `gc-cli help nope`.\n\n" +
+			"Warnings, such as a missing config file, go to standard error without changing\n" +
+			"the exit status.\n",
+	},
+	{
+		Name:  "teacher-mode",
+		Usage: "commands and views for courses you teach",
+		Body: "gc-cli notices which courses you teach from `gc-cli courses list` and keeps\n" +
+			"that in its local data. Teacher features are:\n\n" +
+			"- `gc-cli teach coursework publish-now` publishes a scheduled or draft item.\n" +
+			"- `gc-cli teach roster export` writes a course's students as CSV or vCard.\n" +
+			"- `gc-cli roster` shows who is enrolled, with `--students` for just students.\n" +
+			"- `gc-cli coursework list --all` or `--state draft` includes unpublished work.\n" +
+			"- `gc-cli grades --summary` adds the class average of each grade category.\n" +
+			"- In the TUI, `n` in the announcements view writes a new announcement.\n\n" +
+			"`teach` is left out of the help once gc-cli knows you teach nothing; it still\n" +
+			"runs, and says so if you don't teach the course.\n",
+	},
+}
+
+func findHelpTopic(name string) *helpTopic {
+	for i := range helpTopics {
+		if helpTopics[i].Name == name {
+			return &helpTopics[i]
+		}
+	}
+	return nil
+}
+
+// helpTopicList lists the topics for the end of the app's help.
+func helpTopicList() string {
+	var b strings.Builder
+	b.WriteString("\nHELP TOPICS:\n")
+	for _, t := range helpTopics {
+		fmt.Fprintf(&b, "   %-16s%s\n", t.Name, t.Usage)
+	}
+	b.WriteString("\nRun 'gc-cli help <topic>' to read one.\n")
+	return b.String()
+}
+
+// HelpCmd replaces the built-in help command so that 'gc-cli help <name>'
+// also finds the topics above. Command names still show the command's help.
+func HelpCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "help",
+		Aliases:   []string{"h"},
+		Usage:     "show help for a command or topic",
+		ArgsUsage: "[command | topic]",
+		Action: func(c *cli.Context) error {
+			name := c.Args().First()
+			if t := findHelpTopic(name); t != nil {
+				fmt.Printf("%s - %s\n\n%s", strings.ToUpper(t.Name), t.Usage, t.Body)
+				return nil
+			}
+			// Commands are looked up from the app, not from help itself.
+			app := c.Lineage()[1]
+			if name == "" {
+				return cli.ShowAppHelp(app)
+			}
+			return cli.ShowCommandHelp(app, name)
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/urfave/cli/v2"
+)
+
+// The snippets refresh the marker at most once per interval, so most prompts
+// don't start gc-cli at all. The marker itself comes from 'hook prompt',
+// which only reads the offline snapshot.
+const zshHook = `# gc-cli prompt hook: eval "$(gc-cli hook zsh)"
+typeset -g _gc_cli_prompt='' _gc_cli_prompt_at=-100000
+_gc_cli_precmd() {
+  if (( SECONDS - _gc_cli_prompt_at >= %[1]d )); then
+    _gc_cli_prompt="$(command gc-cli hook prompt 2>/dev/null)"
+    _gc_cli_prompt_at=$SECONDS
+  fi
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook precmd _gc_cli_precmd
+setopt prompt_subst
+RPROMPT='%%F{red}${_gc_cli_prompt}%%f'"${RPROMPT:+ $RPROMPT}"
+`
+
+const bashHook = `# gc-cli prompt hook: eval "$(gc-cli hook bash)"
+_gc_cli_prompt='' _gc_cli_prompt_at=-100000
+_gc_cli_precmd() {
+  if (( SECONDS - _gc_cli_prompt_at >= %[1]d )); then
+    _gc_cli_prompt="$(command gc-cli hook prompt 2>/dev/null)"
+    _gc_cli_prompt_at=$SECONDS
+  fi
+}
+PROMPT_COMMAND="_gc_cli_precmd${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+PS1='${_gc_cli_prompt:+\[\e[31m\]$_gc_cli_prompt\[\e[0m\] }'"$PS1"
+`
+
+const fishHook = `# gc-cli prompt hook: gc-cli hook fish | source
+set -g __gc_cli_prompt ''
+set -g __gc_cli_prompt_at 0
+function __gc_cli_precmd --on-event fish_prompt
+    set -l now (date +%%s)
+    if test (math $now - $__gc_cli_prompt_at) -ge %[1]d
+        set -g __gc_cli_prompt (command gc-cli hook prompt 2>/dev/null)
+        set -g __gc_cli_prompt_at $now
+    end
+end
+if functions -q fish_right_prompt
+    functions -c fish_right_prompt __gc_cli_right_prompt
+end
+function fish_right_prompt
+    if test -n "$__gc_cli_prompt"
+        set_color red
+        echo -n "$__gc_cli_prompt "
+        set_color normal
+    end
+    functions -q __gc_cli_right_prompt; and __gc_cli_right_prompt
+end
+`
+
+var shellHooks = map[string]string{
+	"zsh":  zshHook,
+	"bash": bashHook,
+	"fish": fishHook,
+}
+
+func HookCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "hook",
+		Usage:     "print a shell snippet that shows work due today in your prompt",
+		ArgsUsage: "<zsh|bash|fish>",
+		Action:    handleHook(cfg),
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "interval",
+				Usage: "seconds between refreshes of the prompt marker",
+				Value: 60,
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:   "prompt",
+				Usage:  "print the prompt marker (reads synced data only, never the network)",
+				Hidden: true,
+				Action: handleHookPrompt(cfg),
+			},
+		},
+	}
+}
+
+func handleHook(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		shell := strings.ToLower(c.Args().First())
+		snippet, ok := shellHooks[shell]
+		if !ok {
+			return fmt.Errorf("shell required: zsh, bash or fish")
+		}
+		if c.Int("interval") < 1 {
+			return fmt.Errorf("--interval must be at least 1 second")
+		}
+		fmt.Printf(snippet, c.Int("interval"))
+		return nil
+	}
+}
+
+// handleHookPrompt runs on every prompt refresh, so it stays silent on any
+// error and prints nothing when there's nothing due.
+func handleHookPrompt(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		st, err := cfg.Store()
+		if err != nil {
+			return nil
+		}
+		snap, err := offline.Load(st)
+		if err != nil {
+			return nil
+		}
+		if n := len(snap.DueOn(clk.Now())); n > 0 {
+			fmt.Printf("⚠ %d due today\n", n)
+		}
+		return nil
+	}
+}
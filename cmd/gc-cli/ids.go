@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/coursename"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/urfave/cli/v2"
+)
+
+// courseAliases and defaultCourse come from the google_classroom section of
+// the config.
+var (
+	courseAliases map[string]string
+	defaultCourse string
+)
+
+func setCourseAliases(cc config.ClassroomConfig) {
+	courseAliases = make(map[string]string, len(cc.Aliases))
+	for name, course := range cc.Aliases {
+		// The config's keys arrive lowercased.
+		courseAliases[strings.ToLower(name)] = course
+	}
+	defaultCourse = cc.CourseID
+}
+
+// resolveCourseID accepts a course ID, any Classroom link within the course
+// or an alias from the config.
+func resolveCourseID(s string) string {
+	if course, ok := courseAliases[strings.ToLower(s)]; ok {
+		s = course
+	}
+	if link, ok := api.ParseClassroomLink(s); ok {
+		return link.CourseID
+	}
+	return s
+}
+
+// numericID is what Classroom course IDs look like; anything else given as
+// --course may be a course name.
+var numericID = regexp.MustCompile(`^[0-9]+$`)
+
+// resolveCourseArg is resolveCourseID that also accepts a course name, or a
+// label such as "Biology (Period 5)" when names are shared.
+func resolveCourseArg(s string) (string, error) {
+	id := resolveCourseID(s)
+	if id == "" || numericID.MatchString(id) || courseNames == nil {
+		return id, nil
+	}
+	return courseNames.lookup(id)
+}
+
+// courseNames matches --course values against course names; main sets it
+// up once the config and flags are read.
+var courseNames *courseNameIndex
+
+type courseNameIndex struct {
+	ctx     context.Context
+	cfg     *config.Config
+	loaded  bool
+	courses []api.Course
+	labels  map[string]string
+}
+
+func (ix *courseNameIndex) load() {
+	if ix.loaded {
+		return
+	}
+	ix.loaded = true
+	client, err := newAPIClient(ix.ctx, ix.cfg)
+	if err != nil {
+		return
+	}
+	courses, _, err := client.ListCourses(ix.ctx, 0)
+	if err != nil {
+		return
+	}
+	ppl := newPeople(ix.ctx, ix.cfg, client)
+	ix.courses = courses
+	ix.labels = coursename.Labels(courses, ppl.Name)
+	ppl.save()
+}
+
+// lookup returns the ID of the course named s, or s itself if no course has
+// that name or courses can't be listed, so the command reports the problem.
+// A name several courses share is an error listing how to pick one, unless
+// only one of them is still active.
+func (ix *courseNameIndex) lookup(s string) (string, error) {
+	ix.load()
+	for _, c := range ix.courses {
+		if c.ID == s {
+			return s, nil
+		}
+	}
+	matches := coursename.Match(ix.courses, s, ix.labels)
+	switch len(matches) {
+	case 0:
+		return s, nil
+	case 1:
+		return matches[0].ID, nil
+	}
+	var active []api.Course
+	for _, c := range matches {
+		if c.CourseState == api.CourseActive {
+			active = append(active, c)
+		}
+	}
+	if len(active) == 1 {
+		return active[0].ID, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d courses are named %q; pick one with --course:", len(matches), s)
+	for _, c := range matches {
+		label := ix.labels[c.ID]
+		if c.CourseState != api.CourseActive && c.CourseState != "" {
+			label += ", " + strings.ToLower(string(c.CourseState))
+		}
+		fmt.Fprintf(&b, "\n  --course %-12s %s", displayID(c.ID), label)
+	}
+	fmt.Fprintf(&b, "\nor by its full name, e.g. --course %q", ix.labels[matches[0].ID])
+	return "", fmt.Errorf("%s", b.String())
+}
+
+// resolveItemID accepts either a coursework/announcement ID or a Classroom
+// link to the item.
+func resolveItemID(s string) string {
+	if link, ok := api.ParseClassroomLink(s); ok && link.ItemID != "" {
+		return link.ItemID
+	}
+	return s
+}
+
+// resolveItem returns the course and item that arg refers to: a %N row of
+// the last listing, a Classroom link, or an item ID in the --course course.
+func resolveItem(c *cli.Context, arg string) (courseID, itemID string, err error) {
+	courseID, err = optionalCourseArg(c)
+	if err != nil {
+		return "", "", err
+	}
+	if n, ok := selectionRef(arg); ok {
+		item, err := selectedItem(n)
+		if err != nil {
+			return "", "", err
+		}
+		if courseID != "" && courseID != item.CourseID {
+			return "", "", fmt.Errorf("%s is in course %s, not %s", arg, displayID(item.CourseID), displayID(courseID))
+		}
+		return item.CourseID, item.ID, nil
+	}
+	if link, ok := api.ParseClassroomLink(arg); ok && link.ItemID != "" {
+		return link.CourseID, link.ItemID, nil
+	}
+	if courseID == "" {
+		courseID, err = resolveCourseArg(defaultCourse)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if courseID == "" {
+		return "", "", fmt.Errorf("--course is required unless the item is given as %%N or a Classroom link")
+	}
+	return courseID, arg, nil
+}
+
+// courseArg is the --course course, or the default course when it's left
+// out.
+func courseArg(c *cli.Context) (string, error) {
+	if course, err := optionalCourseArg(c); course != "" || err != nil {
+		return course, err
+	}
+	return resolveCourseArg(defaultCourse)
+}
+
+// requireCourse is courseArg for commands that can't run without a course.
+func requireCourse(c *cli.Context) (string, error) {
+	courseID, err := courseArg(c)
+	if err != nil {
+		return "", err
+	}
+	if courseID == "" {
+		return "", fmt.Errorf("--course is required (or set google_classroom.course_id in the config)")
+	}
+	return courseID, nil
+}
+
+// courseKnown reports whether courseID is a course seen recently, by the
+// client or in the last sync, so it needn't be checked before use.
+func courseKnown(cfg *config.Config, client *api.Client, courseID string) bool {
+	if _, ok := client.KnownCourse(courseID); ok {
+		return true
+	}
+	st, err := cfg.Store()
+	if err != nil {
+		return false
+	}
+	snap, err := offline.Load(st)
+	if err != nil {
+		return false
+	}
+	for _, oc := range snap.Courses {
+		if oc.Course.ID == courseID {
+			return true
+		}
+	}
+	return false
+}
+
+func courseAccessError(courseID string, err error) error {
+	return fmt.Errorf("course %s not found or access denied: %w", displayID(courseID), err)
+}
+
+// optionalCourseArg is the --course course for commands where leaving it
+// out means every course, so the default course doesn't apply.
+func optionalCourseArg(c *cli.Context) (string, error) {
+	return resolveCourseArg(c.String("course"))
+}
+
+func assignmentArg(c *cli.Context) string {
+	return resolveItemID(c.String("assignment"))
+}
+
+const (
+	redactHash     = "hash"
+	redactTruncate = "truncate"
+)
+
+// idRedaction is display.redact_ids unless --show-ids was given. JSON output
+// always has full IDs.
+var idRedaction string
+
+func setIDRedaction(mode string) error {
+	switch mode {
+	case "", "off", redactHash, redactTruncate:
+		idRedaction = mode
+		return nil
+	}
+	return fmt.Errorf("unknown display.redact_ids %q (use hash, truncate or off)", mode)
+}
+
+// displayID returns id as it should appear in tables and messages: a short
+// stable hash, the last few characters, or unchanged.
+func displayID(id string) string {
+	if id == "" {
+		return id
+	}
+	switch idRedaction {
+	case redactHash:
+		sum := sha256.Sum256([]byte(id))
+		return "#" + hex.EncodeToString(sum[:3])
+	case redactTruncate:
+		if len(id) > 4 {
+			return "…" + id[len(id)-4:]
+		}
+	}
+	return id
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/output"
+	"github.com/timboy697/gc-cli/internal/picker"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+func interactiveFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    "interactive",
+		Aliases: []string{"i"},
+		Usage:   "pick a row with the arrow keys, then choose what to do with it",
+	}
+}
+
+type rowAction struct {
+	Label string
+	// Key, if set, runs the action straight from the row list.
+	Key string
+	Run func() error
+}
+
+// interactiveRow is one selectable line of list output and what can be done
+// with it.
+type interactiveRow struct {
+	Label string
+	// Keywords is extra text the search box matches besides the label.
+	Keywords string
+	Actions  []rowAction
+}
+
+// runInteractive replaces a list command's table with a picker. Picking a row
+// offers its actions; after an action runs the user can pick again. With
+// search, the picker has a search box to narrow long lists by typing.
+func runInteractive(c *cli.Context, title string, rows []interactiveRow, search bool) error {
+	if format, err := outputFormat(c); err != nil {
+		return err
+	} else if format != output.Table {
+		return fmt.Errorf("--interactive can't be combined with --output %s", format)
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("--interactive needs a terminal")
+	}
+	if len(rows) == 0 {
+		fmt.Println("Nothing to show.")
+		return nil
+	}
+
+	labels := make([]string, len(rows))
+	keywords := make([]string, len(rows))
+	var shortcuts []picker.Shortcut
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		labels[i] = row.Label
+		keywords[i] = row.Keywords
+		for _, action := range row.Actions {
+			if action.Key != "" && !seen[action.Key] {
+				seen[action.Key] = true
+				shortcuts = append(shortcuts, picker.Shortcut{Key: action.Key, Label: action.Label})
+			}
+		}
+	}
+
+	for {
+		var i int
+		var key string
+		var err error
+		if search {
+			i, key, err = picker.PickFiltered(title, labels, keywords, shortcuts)
+		} else {
+			i, key, err = picker.PickWithShortcuts(title, labels, shortcuts)
+		}
+		if errors.Is(err, picker.ErrCancelled) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		row := rows[i]
+		if key != "" {
+			runRowShortcut(row, key)
+			continue
+		}
+		actionLabels := make([]string, len(row.Actions)+1)
+		for j, action := range row.Actions {
+			actionLabels[j] = action.Label
+		}
+		actionLabels[len(row.Actions)] = "back"
+
+		j, err := picker.Pick(row.Label, actionLabels)
+		if errors.Is(err, picker.ErrCancelled) || j == len(row.Actions) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := row.Actions[j].Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		fmt.Println()
+	}
+}
+
+func runRowShortcut(row interactiveRow, key string) {
+	for _, action := range row.Actions {
+		if action.Key != key {
+			continue
+		}
+		if err := action.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		fmt.Println()
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Nothing to %s for %s\n\n", key, row.Label)
+}
+
+func openAction(link string) rowAction {
+	return rowAction{Label: "open in browser", Key: "o", Run: func() error {
+		if link == "" {
+			return fmt.Errorf("no link available")
+		}
+		return auth.OpenBrowser(link)
+	}}
+}
+
+func prompt(question string) (string, error) {
+	fmt.Fprint(os.Stderr, question)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// courseRows labels courses by the names coursename.Labels gave them, which
+// already include the section where it tells two courses apart.
+func courseRows(courses []api.Course, order *courseorder.Order, names map[string]string) []interactiveRow {
+	rows := make([]interactiveRow, len(courses))
+	for i, course := range courses {
+		course := course
+		label := order.Label(course.ID, names[course.ID])
+		if course.Section != "" && names[course.ID] == course.Name {
+			label += " — " + course.Section
+		}
+		rows[i] = interactiveRow{Label: label, Keywords: course.Room + " " + course.ID, Actions: []rowAction{
+			{Label: "view", Run: func() error {
+				printField("Name", course.Name)
+				printField("Section", course.Section)
+				printField("Room", course.Room)
+				printField("About", course.Description)
+				printField("ID", displayID(course.ID))
+				printField("Link", course.AlternateLink)
+				return nil
+			}},
+			openAction(course.AlternateLink),
+		}}
+	}
+	return rows
+}
+
+func courseworkRows(ctx context.Context, cfg *config.Config, client *api.Client, coursework []api.CourseWork) []interactiveRow {
+	now := clk.Now()
+	rows := make([]interactiveRow, len(coursework))
+	for i, cw := range coursework {
+		cw := cw
+		rows[i] = interactiveRow{
+			Label: fmt.Sprintf("%-40s %-18s %s", truncate(cw.Title, 40), formatDueDate(cw), getStatus(cw, now)),
+			Actions: []rowAction{
+				{Label: "view", Run: func() error {
+					printCourseworkDetail(cw, now)
+					return nil
+				}},
+				openAction(cw.AlternateLink),
+				{Label: "download attachments", Run: func() error {
+					return downloadMaterials(ctx, client, cw.Materials)
+				}},
+				{Label: "submit a file", Key: "s", Run: func() error {
+					fmt.Printf("Submitting to %q (course %s, assignment %s)\n", cw.Title, displayID(cw.CourseID), displayID(cw.ID))
+					path, err := prompt("File to submit: ")
+					if err != nil {
+						return err
+					}
+					if err := validateFile(path); err != nil {
+						return err
+					}
+					sub, err := submitFile(ctx, cfg, client, cw.CourseID, cw.ID, path, true, printReporter{})
+					if err != nil {
+						return err
+					}
+					fmt.Printf("✓ Turned in %q (submission %s)\n", cw.Title, sub.ID)
+					return nil
+				}},
+			},
+		}
+	}
+	return rows
+}
+
+func announcementRows(announcements []api.Announcement) []interactiveRow {
+	rows := make([]interactiveRow, len(announcements))
+	for i, a := range announcements {
+		a := a
+		text := strings.TrimSpace(stripHTML(a.Text))
+		rows[i] = interactiveRow{
+			Label: fmt.Sprintf("%s  %s", a.CreationTime.Local().Format("Jan 02"), truncate(strings.ReplaceAll(text, "\n", " "), 60)),
+			Actions: []rowAction{
+				{Label: "view", Run: func() error {
+					printField("Posted", a.CreationTime.Local().Format("Mon Jan 2 15:04"))
+					printField("Link", a.AlternateLink)
+					fmt.Println()
+					fmt.Println(text)
+					return nil
+				}},
+				openAction(a.AlternateLink),
+			},
+		}
+	}
+	return rows
+}
+
+func printField(label, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Printf("%s %s\n", headerStyle.Render(fmt.Sprintf("%-8s", label+":")), value)
+}
+
+func printCourseworkDetail(cw api.CourseWork, now time.Time) {
+	printField("Title", cw.Title)
+	printField("Due", formatDueDate(cw))
+	printField("Status", getStatus(cw, now))
+	if cw.MaxPoints > 0 {
+		printField("Points", fmt.Sprintf("%d", cw.MaxPoints))
+	}
+	printField("Link", cw.AlternateLink)
+	if cw.Description != "" {
+		fmt.Println()
+		fmt.Println(cw.Description)
+	}
+	printMaterials(cw.Materials)
+	printAddOns(cw)
+}
+
+// printAddOns lists add-on attachments (Kami, Edpuzzle, ...). Those without
+// their own URL open through the assignment page.
+func printAddOns(cw api.CourseWork) {
+	if len(cw.AddOnAttachments) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(headerStyle.Render("Add-ons:"))
+	for _, a := range cw.AddOnAttachments {
+		link := a.OpenURL()
+		if link == "" {
+			link = cw.AlternateLink + " (open in Classroom)"
+		}
+		fmt.Printf("  🧩 %s  %s\n", a.Title, link)
+	}
+}
+
+func printMaterials(materials []api.Material) {
+	if len(materials) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(headerStyle.Render("Materials:"))
+	for _, m := range materials {
+		switch {
+		case m.DriveFile != nil:
+			fmt.Printf("  📄 %s  %s\n", m.DriveFile.DriveFile.Title, m.DriveFile.DriveFile.AlternateLink)
+		case m.YouTubeVideo != nil:
+			fmt.Printf("  ▶ %s  %s\n", m.YouTubeVideo.Title, m.YouTubeVideo.AlternateLink)
+		case m.Link != nil:
+			fmt.Printf("  🔗 %s  %s\n", m.Link.Title, m.Link.URL)
+		case m.Form != nil:
+			fmt.Printf("  📝 %s  %s\n", m.Form.Title, m.Form.FormURL)
+		}
+	}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._ -]+`)
+
+// downloadMaterials saves the coursework's Drive files to the current
+// directory. Google Docs and files this app can't read are reported and
+// skipped.
+func downloadMaterials(ctx context.Context, client *api.Client, materials []api.Material) error {
+	var found bool
+	for _, m := range materials {
+		if m.DriveFile == nil {
+			continue
+		}
+		found = true
+		df := m.DriveFile.DriveFile
+
+		info, err := client.GetDriveFile(ctx, df.ID)
+		if err != nil {
+			fmt.Printf("✗ %s: %s\n", df.Title, downloadError(err))
+			continue
+		}
+		if info.IsGoogleDoc() {
+			fmt.Printf("- %s: Google Docs file, open it online: %s\n", df.Title, df.AlternateLink)
+			continue
+		}
+
+		name := filepath.Base(unsafeFileChars.ReplaceAllString(info.Name, "_"))
+		if _, err := os.Stat(name); err == nil {
+			fmt.Printf("- %s: %s already exists, skipping\n", df.Title, name)
+			continue
+		}
+
+		data, err := client.DownloadDriveFile(ctx, df.ID)
+		if err != nil {
+			fmt.Printf("✗ %s: %s\n", df.Title, downloadError(err))
+			continue
+		}
+		if err := os.WriteFile(name, data, 0644); err != nil {
+			return fmt.Errorf("failed to save %s: %w", name, err)
+		}
+		fmt.Printf("✓ Saved %s (%s)\n", name, formatBytes(int64(len(data))))
+	}
+	if !found {
+		fmt.Println("No Drive attachments to download.")
+	}
+	return nil
+}
+
+func downloadError(err error) string {
+	if api.IsForbidden(err) || api.IsNotFound(err) {
+		return "no access (open it in the browser instead)"
+	}
+	return err.Error()
+}
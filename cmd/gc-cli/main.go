@@ -2,19 +2,38 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/timboy697/gc-cli/internal/api"
 	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/clock"
 	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/profile"
+	"github.com/timboy697/gc-cli/internal/schoolcal"
 	"github.com/timboy697/gc-cli/internal/tui"
+	"github.com/timboy697/gc-cli/internal/useragent"
 
 	"github.com/urfave/cli/v2"
 )
 
 var Version = "dev"
 
+var startTime time.Time
+
+// closeDebugFile closes the --debug-file dump once the command is done.
+var closeDebugFile = func() error { return nil }
+
+// clk is the time used for due and overdue decisions. The hidden --now flag
+// pins it for tests and what-if reports.
+var clk clock.Clock = clock.System{}
+
+// schoolCal is the configured school year, used to count school days and
+// label term weeks.
+var schoolCal *schoolcal.Calendar
+
 func main() {
 	ctx := context.Background()
 
@@ -32,13 +51,61 @@ func main() {
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:  "verbose",
-				Usage: "enable verbose output",
+				Usage: "log each API request to stderr, and print a timing and API call summary after each command",
+			},
+			&cli.BoolFlag{
+				Name:  "debug",
+				Usage: "like --verbose, but also log each attempt and cache use",
+			},
+			&cli.StringFlag{
+				Name:    "debug-file",
+				Usage:   "append API request and response bodies to this file, with tokens and email addresses removed",
+				EnvVars: []string{"GC_CLI_DEBUG_FILE"},
 			},
 			&cli.StringFlag{
 				Name:        "config",
 				Usage:       "path to config file",
 				DefaultText: cfg.ConfigPath,
 			},
+			&cli.StringFlag{
+				Name:   "now",
+				Usage:  "pretend the current time is this (e.g. 2024-05-01T00:00)",
+				Hidden: true,
+			},
+			&cli.StringFlag{
+				Name:    "profile",
+				Usage:   "use the credentials of a named profile (see `gc-cli profile list`)",
+				EnvVars: []string{"GC_CLI_PROFILE"},
+			},
+			&cli.BoolFlag{
+				Name:  "show-ids",
+				Usage: "show full course and user IDs even if display.redact_ids is set",
+			},
+			&cli.BoolFlag{
+				Name:    "assume-disabled",
+				Usage:   "don't use the Classroom API (e.g. when your school has disabled it); read from the last sync instead",
+				EnvVars: []string{"GC_CLI_ASSUME_DISABLED"},
+			},
+			&cli.BoolFlag{
+				Name:    "no-cache",
+				Usage:   "fetch everything from the Classroom API instead of reusing recent responses",
+				EnvVars: []string{"GC_CLI_NO_CACHE"},
+			},
+			&cli.BoolFlag{
+				Name:    "demo",
+				Usage:   "use made-up courses instead of your Classroom account, to try gc-cli without signing in",
+				EnvVars: []string{"GC_CLI_DEMO"},
+			},
+			&cli.StringFlag{
+				Name:    "demo-data",
+				Usage:   "like --demo, but with the courses in this file, laid out like internal/demo/classroom.json",
+				EnvVars: []string{"GC_CLI_DEMO_DATA"},
+			},
+			&cli.BoolFlag{
+				Name:    "anonymize",
+				Usage:   "replace course names, people and grades with consistent fake values (for screenshots and bug reports)",
+				EnvVars: []string{"GC_CLI_ANONYMIZE"},
+			},
 		},
 		Commands: []*cli.Command{
 			{
@@ -48,26 +115,63 @@ func main() {
 					{
 						Name:  "login",
 						Usage: "authenticate with Google",
+						Flags: loginFlags(),
 						Action: func(c *cli.Context) error {
-							return handleLogin(ctx, cfg)
+							return handleLogin(ctx, cfg, c.Bool("drive"))
 						},
 					},
 					{
 						Name:  "status",
 						Usage: "check authentication status",
+						Description: "With --refresh-if-needed the token is renewed when it expires within\n" +
+							"--margin, and the exit status is non-zero unless a usable token remains,\n" +
+							"so cron jobs can run it first.",
+						Flags: []cli.Flag{
+							&cli.BoolFlag{
+								Name:  "refresh-if-needed",
+								Usage: "renew the token if it expires soon, and fail if it can't be used",
+							},
+							&cli.DurationFlag{
+								Name:        "margin",
+								Usage:       "with --refresh-if-needed, renew tokens expiring within this long",
+								DefaultText: "auth.refresh_margin, 10m",
+							},
+						},
 						Action: func(c *cli.Context) error {
+							if c.Bool("refresh-if-needed") {
+								margin := cfg.Auth.RefreshMargin
+								if c.IsSet("margin") {
+									margin = c.Duration("margin")
+								}
+								return handleAuthRefresh(ctx, cfg, margin)
+							}
 							return handleAuthStatus(ctx, cfg)
 						},
 					},
+					{
+						Name:  "logout",
+						Usage: "revoke the saved token with Google and delete it from this machine",
+						Description: "Local data such as notes, syncs and the submit journal is kept; signing in\n" +
+							"to the same account again picks it up.",
+						Flags:  logoutFlags(),
+						Action: handleLogout(ctx, cfg),
+					},
 				},
 			},
 			{
 				Name:  "login",
 				Usage: "authenticate with Google (alias for auth login)",
+				Flags: loginFlags(),
 				Action: func(c *cli.Context) error {
-					return handleLogin(ctx, cfg)
+					return handleLogin(ctx, cfg, c.Bool("drive"))
 				},
 			},
+			{
+				Name:   "logout",
+				Usage:  "revoke and remove the saved token (alias for auth logout)",
+				Flags:  logoutFlags(),
+				Action: handleLogout(ctx, cfg),
+			},
 			CoursesCmd(cfg),
 			{
 				Name:  "course",
@@ -81,7 +185,7 @@ func main() {
 							if c.Args().Len() < 1 {
 								return fmt.Errorf("course ID required")
 							}
-							fmt.Printf("Viewing course: %s\n", c.Args().First())
+							fmt.Printf("Viewing course: %s\n", displayID(resolveCourseID(c.Args().First())))
 							return nil
 						},
 					},
@@ -97,32 +201,182 @@ func main() {
 			},
 			CourseworkCmd(cfg),
 			SubmitCmd(cfg),
+			EvidenceCmd(cfg),
 			GradesCmd(cfg),
 			AnnouncementsCmd(cfg),
+			RosterCmd(cfg),
+			MarkCmd(cfg),
+			FeedCmd(cfg),
+			WebCmd(cfg),
+			ServeCmd(cfg),
+			TrackCmd(cfg),
+			ForecastCmd(cfg),
+			PlanCmd(cfg),
+			TodoCmd(cfg),
+			CalendarCmd(cfg),
+			ShareCmd(cfg),
+			NotifyCmd(cfg),
+			NoteCmd(cfg),
+			ProfileCmd(cfg),
+			PresetCmd(cfg),
+			ReportCmd(cfg),
+			TeachCmd(cfg),
+			SelftestCmd(cfg),
+			DoctorCmd(cfg),
+			SyncCmd(cfg),
+			DiffCmd(cfg),
+			WatchCmd(cfg),
+			CacheCmd(cfg),
+			HookCmd(cfg),
+			APICmd(cfg),
+			OpenCmd(cfg),
+			ShortcutsCmd(cfg),
+			ConfigCmd(cfg),
+			DocsCmd(cfg),
+			HelpCmd(),
 			{
 				Name:  "tui",
 				Usage: "launch interactive TUI mode",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "demo",
+						Usage: "use made-up courses instead of your Classroom account (same as gc-cli --demo tui)",
+					},
+				},
 				Action: func(c *cli.Context) error {
-					return tui.Run(cfg)
+					if c.Bool("demo") {
+						useDemoData(cfg)
+					}
+					backOffPatiently()
+					opts := tui.Options{
+						NewClient: func(ctx context.Context) (api.ClassroomService, error) {
+							client, err := newAPIClient(ctx, cfg)
+							if err != nil {
+								return nil, err
+							}
+							return client, nil
+						},
+						Submit: tuiSubmit(cfg),
+						Demo:   demoMode,
+						Clock:  clk,
+					}
+					if !demoMode {
+						opts.OfflineClient = func(ctx context.Context) (api.ClassroomService, error) {
+							client, _, err := openSyncedClient(ctx, cfg)
+							if err != nil {
+								return nil, fmt.Errorf("can't work offline: %w; run 'gc-cli sync' while online first", err)
+							}
+							return client, nil
+						}
+					}
+					return tui.Run(cfg, opts)
 				},
 			},
 		},
 		Before: func(c *cli.Context) error {
+			startTime = time.Now()
+			closeLog, err := startLogging(c)
+			if err != nil {
+				return err
+			}
+			closeDebugFile = closeLog
+			useragent.Set(Version, cfg.UserAgentSuffix)
+			cal, err := schoolcal.FromConfig(cfg.Calendar)
+			if err != nil {
+				return err
+			}
+			schoolCal = cal
+			if c.IsSet("now") {
+				fixed, err := clock.Parse(c.String("now"))
+				if err != nil {
+					return err
+				}
+				clk = fixed
+			}
 			if c.String("config") != "" {
 				cfg.ConfigPath = c.String("config")
 			}
+			migrateConfig(c, cfg)
+			cfg.Anonymize = c.Bool("anonymize")
+			setCourseAliases(cfg.GoogleClassroom)
+			if !c.Bool("show-ids") {
+				if err := setIDRedaction(cfg.Display.RedactIDs); err != nil {
+					return err
+				}
+			}
+			name := cfg.Profile
+			if c.IsSet("profile") {
+				name = c.String("profile")
+			}
+			if name != "" {
+				if err := profile.Apply(cfg, name); err != nil {
+					return err
+				}
+			}
+			openStore = cfg.Store
+			assumeDisabled = c.Bool("assume-disabled")
+			noCache = c.Bool("no-cache")
+			courseNames = &courseNameIndex{ctx: ctx, cfg: cfg}
+			demoDataFile = c.String("demo-data")
+			if c.Bool("demo") || demoDataFile != "" {
+				useDemoData(cfg)
+			} else if err := scopeDataToAccount(cfg); err != nil {
+				return err
+			}
+			hideTeachCmd(c.App, cfg)
 			return nil
 		},
+		After: func(c *cli.Context) error {
+			if (c.Bool("verbose") || c.Bool("debug")) && c.Args().Present() {
+				printStatsFooter(os.Stderr, time.Since(startTime), apiStats)
+			}
+			return closeDebugFile()
+		},
 	}
 
-	if err := app.Run(os.Args); err != nil {
+	app.CustomAppHelpTemplate = cli.AppHelpTemplate + helpTopicList()
+	addScopePreflight(app.Commands, "")
+	addOutputFilters(app.Commands, cfg.OutputFilters)
+
+	args, err := expandShortcut(app, cfg.Shortcuts, os.Args)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if err := app.Run(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if api.IsAPIDisabled(err) {
+			fmt.Fprint(os.Stderr, explainAPIDisabled(cfg))
+		} else if api.IsInsufficientScope(err) {
+			fmt.Fprint(os.Stderr, explainMissingScope(cfg))
+		}
+		os.Exit(1)
+	}
 }
 
-func handleLogin(ctx context.Context, cfg *config.Config) error {
-	authCfg := auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile)
+func loginFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "drive",
+			Usage: "also grant access to all your Drive files, so 'share' works on files attached in Classroom itself",
+		},
+	}
+}
+
+// handleLogin signs in with the profile's scopes, plus auth.DriveScope if
+// drive is set.
+func handleLogin(ctx context.Context, cfg *config.Config, drive bool) error {
+	authCfg := cfg.AuthConfig()
+	if drive {
+		if cfg.ReadOnly {
+			return fmt.Errorf("the read-only profile %q can't be granted Drive access", cfg.Profile)
+		}
+		scopes := authCfg.Scopes
+		if len(scopes) == 0 {
+			scopes = auth.Scopes
+		}
+		authCfg.Scopes = append(append([]string(nil), scopes...), auth.DriveScope)
+	}
 
 	fmt.Println("Starting OAuth authentication flow...")
 	fmt.Println("A browser window will open for you to sign in with your Google account.")
@@ -139,10 +393,79 @@ func handleLogin(ctx context.Context, cfg *config.Config) error {
 	fmt.Println("\n✓ Authentication successful!")
 	fmt.Printf("Token saved to: %s\n", cfg.Auth.TokenFile)
 
+	if err := switchAccount(ctx, cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not identify the account; local data is not separated per account: %v\n", err)
+	}
+
 	return nil
 }
 
+// handleAuthRefresh renews the token if it expires within margin and
+// returns an error, and so a failing exit status, unless it can be used.
+func handleAuthRefresh(ctx context.Context, cfg *config.Config, margin time.Duration) error {
+	token, refreshed, err := auth.RefreshIfNeeded(ctx, cfg.AuthConfig(), margin)
+	if err != nil {
+		return err
+	}
+	if refreshed {
+		fmt.Printf("Status: Logged in (token refreshed)\nToken expires: %s\n", token.Expiry.Format("2006-01-02 15:04:05"))
+		return nil
+	}
+	fmt.Println("Status: Logged in")
+	if !token.Expiry.IsZero() {
+		fmt.Printf("Token expires: %s\n", token.Expiry.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+func logoutFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "local-only",
+			Usage: "only delete the token here, e.g. when offline; it stays valid until revoked at myaccount.google.com",
+		},
+	}
+}
+
+func handleLogout(ctx context.Context, cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if !auth.TokenExists(cfg.Auth.TokenFile) {
+			fmt.Println("Not logged in")
+			return nil
+		}
+
+		if !c.Bool("local-only") {
+			token, err := auth.TokenFromFile(cfg.Auth.TokenFile)
+			if err == nil {
+				err = auth.Revoke(ctx, token)
+			}
+			switch {
+			case errors.Is(err, auth.ErrTokenInvalid):
+				fmt.Println("Token was already revoked or expired")
+			case err != nil:
+				return fmt.Errorf("%w (the token was kept; use --local-only to just delete it)", err)
+			default:
+				fmt.Println("✓ Access revoked with Google")
+			}
+		}
+
+		if err := auth.RemoveToken(cfg.Auth.TokenFile); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s\n", cfg.Auth.TokenFile)
+		return nil
+	}
+}
+
 func handleAuthStatus(ctx context.Context, cfg *config.Config) error {
+	if cfg.Profile != "" {
+		access := ""
+		if cfg.ReadOnly {
+			access = " (read-only)"
+		}
+		fmt.Printf("Profile: %s%s\n", cfg.Profile, access)
+	}
+
 	if !auth.TokenExists(cfg.Auth.TokenFile) {
 		fmt.Println("Status: Not logged in")
 		fmt.Println("Run 'gc-cli auth login' to authenticate")
@@ -165,7 +488,25 @@ func handleAuthStatus(ctx context.Context, cfg *config.Config) error {
 	} else {
 		fmt.Println("Status: Not logged in (token expired)")
 		fmt.Println("Run 'gc-cli auth login' to authenticate")
+		return nil
 	}
 
+	if granted := auth.GrantedScopes(cfg.Auth.TokenFile); granted != nil && !cfg.ReadOnly {
+		if len(auth.Missing(granted, auth.NeedAllDrive)) == 0 {
+			fmt.Println("Drive: all your files (auth login --drive)")
+		} else {
+			fmt.Println("Drive: only files gc-cli uploaded (auth login --drive widens this)")
+		}
+		missing := auth.Missing(granted, allNeeds...)
+		if len(missing) == 0 {
+			fmt.Println("Permissions: all granted")
+			return nil
+		}
+		fmt.Println("Missing permissions:")
+		for _, n := range missing {
+			fmt.Printf("  %s (%s)\n", n.What, auth.ShortScope(n.Scopes[0]))
+		}
+		fmt.Printf("Run '%s' to grant them\n", loginCommand(cfg))
+	}
 	return nil
 }
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func MarkCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "mark",
+		Usage: "mark announcements and coursework as read or unread",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "read",
+				Usage:     "mark items as read",
+				ArgsUsage: "<id> [id...]",
+				Action: func(c *cli.Context) error {
+					return handleMark(c, cfg, true)
+				},
+			},
+			{
+				Name:      "unread",
+				Usage:     "mark items as unread",
+				ArgsUsage: "<id> [id...]",
+				Action: func(c *cli.Context) error {
+					return handleMark(c, cfg, false)
+				},
+			},
+		},
+	}
+}
+
+func handleMark(c *cli.Context, cfg *config.Config, read bool) error {
+	if c.Args().Len() < 1 {
+		return fmt.Errorf("at least one announcement or coursework ID required")
+	}
+
+	st, err := cfg.Store()
+	if err != nil {
+		return err
+	}
+	markers, err := st.ReadMarkers()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, arg := range c.Args().Slice() {
+		id := resolveItemID(arg)
+		if read {
+			markers.MarkRead(id, now)
+		} else {
+			markers.MarkUnread(id)
+		}
+	}
+
+	if err := st.SaveReadMarkers(markers); err != nil {
+		return err
+	}
+
+	state := "read"
+	if !read {
+		state = "unread"
+	}
+	fmt.Printf("Marked %d item(s) as %s\n", c.Args().Len(), state)
+	return nil
+}
+
+// unreadFilter returns a predicate reporting whether an item ID is unread,
+// or nil when --unread-only was not requested.
+func unreadFilter(c *cli.Context, cfg *config.Config) (func(id string) bool, error) {
+	if !c.Bool("unread-only") {
+		return nil, nil
+	}
+
+	st, err := cfg.Store()
+	if err != nil {
+		return nil, err
+	}
+	markers, err := st.ReadMarkers()
+	if err != nil {
+		return nil, err
+	}
+	return func(id string) bool { return !markers.IsRead(id) }, nil
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/checklist"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+func NoteCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "note",
+		Usage: "keep local notes on coursework",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "subtask",
+				Usage: "break an assignment into a local checklist",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "add",
+						Usage:     "add a subtask",
+						ArgsUsage: "<coursework-id> <text>",
+						Action:    handleSubtaskAdd(cfg),
+					},
+					{
+						Name:      "import",
+						Usage:     "add the numbered or checkbox steps in the assignment's instructions",
+						ArgsUsage: "<coursework-id | %N>",
+						Action:    handleSubtaskImport(cfg),
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "course",
+								Usage: "course ID (not needed for %N rows of the last listing or Classroom links)",
+							},
+						},
+					},
+					{
+						Name:      "list",
+						Usage:     "list subtasks and progress",
+						ArgsUsage: "<coursework-id>",
+						Action:    handleSubtaskList(cfg),
+					},
+					{
+						Name:      "done",
+						Usage:     "check off a subtask",
+						ArgsUsage: "<coursework-id> <number>",
+						Action:    handleSubtaskSet(cfg, true),
+					},
+					{
+						Name:      "undo",
+						Usage:     "uncheck a subtask",
+						ArgsUsage: "<coursework-id> <number>",
+						Action:    handleSubtaskSet(cfg, false),
+					},
+					{
+						Name:      "remove",
+						Usage:     "delete a subtask (it goes to the trash)",
+						ArgsUsage: "<coursework-id> <number>",
+						Action:    handleSubtaskRemove(cfg),
+					},
+				},
+			},
+			trashCmd(cfg),
+		},
+	}
+}
+
+func handleSubtaskAdd(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 2 {
+			return fmt.Errorf("usage: gc-cli note subtask add <coursework-id> <text>")
+		}
+		id := resolveItemID(c.Args().First())
+		text := strings.Join(c.Args().Tail(), " ")
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		notes, err := st.Notes()
+		if err != nil {
+			return err
+		}
+		notes.AddSubtask(id, text, time.Now())
+		if err := st.SaveNotes(notes); err != nil {
+			return err
+		}
+
+		done, total := notes.Get(id).Progress()
+		fmt.Printf("Added subtask %d (%d/%d done)\n", total, done, total)
+		return nil
+	}
+}
+
+func handleSubtaskList(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("coursework ID required")
+		}
+		id := resolveItemID(c.Args().First())
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		notes, err := st.Notes()
+		if err != nil {
+			return err
+		}
+		note := notes.Get(id)
+		done, total := note.Progress()
+		if total == 0 {
+			fmt.Println("No subtasks")
+			return nil
+		}
+
+		for i, st := range note.Subtasks {
+			box := "[ ]"
+			if st.Done {
+				box = "[x]"
+			}
+			fmt.Printf("%3d. %s %s\n", i+1, box, st.Text)
+		}
+		fmt.Printf("\n%d/%d done (%d%%)\n", done, total, done*100/total)
+		return nil
+	}
+}
+
+func handleSubtaskImport(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("coursework ID required")
+		}
+		courseID, courseWorkID, err := resolveItem(c, c.Args().First())
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		cw, err := client.GetCourseWork(ctx, courseID, courseWorkID)
+		if err != nil {
+			return fmt.Errorf("failed to get coursework: %w", err)
+		}
+
+		steps := checklist.Extract(cw.Description)
+		if len(steps) == 0 {
+			fmt.Printf("No numbered or checkbox steps in the instructions for %q\n", cw.Title)
+			return nil
+		}
+		return importSteps(cfg, cw.ID, steps)
+	}
+}
+
+func importSteps(cfg *config.Config, id string, steps []string) error {
+	st, err := cfg.Store()
+	if err != nil {
+		return err
+	}
+	notes, err := st.Notes()
+	if err != nil {
+		return err
+	}
+	added := notes.ImportSteps(id, steps, time.Now())
+	if err := st.SaveNotes(notes); err != nil {
+		return err
+	}
+
+	done, total := notes.Get(id).Progress()
+	if added == 0 {
+		fmt.Printf("All %d step(s) are already subtasks (%d/%d done)\n", len(steps), done, total)
+		return nil
+	}
+	fmt.Printf("Added %d subtask(s) (%d/%d done); see 'gc-cli note subtask list %s'\n", added, done, total, id)
+	return nil
+}
+
+// offerSteps offers, the first time an assignment is viewed, to add the
+// steps in its instructions as subtasks. Without a terminal to ask on, it
+// only says how.
+func offerSteps(cfg *config.Config, cw api.CourseWork) error {
+	steps := checklist.Extract(cw.Description)
+	if len(steps) == 0 {
+		return nil
+	}
+	st, err := cfg.Store()
+	if err != nil {
+		return err
+	}
+	notes, err := st.Notes()
+	if err != nil {
+		return err
+	}
+	if note := notes.Get(cw.ID); note != nil && note.StepsOffered {
+		return nil
+	}
+
+	fmt.Println()
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Printf("The instructions list %d step(s); 'gc-cli note subtask import --course %s %s' adds them as subtasks.\n", len(steps), cw.CourseID, cw.ID)
+		return nil
+	}
+	fmt.Printf("The instructions list %d step(s):\n", len(steps))
+	for i, step := range steps {
+		fmt.Printf("%3d. %s\n", i+1, step)
+	}
+	answer, err := prompt("Add them as subtasks? [Y/n] ")
+	if err != nil {
+		return err
+	}
+	if a := strings.ToLower(answer); a != "" && a != "y" && a != "yes" {
+		notes.DeclineSteps(cw.ID)
+		return st.SaveNotes(notes)
+	}
+	return importSteps(cfg, cw.ID, steps)
+}
+
+// subtaskArgs parses <coursework-id> <number>, returning a zero-based index.
+func subtaskArgs(c *cli.Context) (string, int, error) {
+	if c.Args().Len() < 2 {
+		return "", 0, fmt.Errorf("coursework ID and subtask number required")
+	}
+	n, err := strconv.Atoi(c.Args().Get(1))
+	if err != nil || n < 1 {
+		return "", 0, fmt.Errorf("invalid subtask number %q", c.Args().Get(1))
+	}
+	return resolveItemID(c.Args().First()), n - 1, nil
+}
+
+func handleSubtaskSet(cfg *config.Config, done bool) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		id, index, err := subtaskArgs(c)
+		if err != nil {
+			return err
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		notes, err := st.Notes()
+		if err != nil {
+			return err
+		}
+		if !notes.SetSubtaskDone(id, index, done) {
+			return fmt.Errorf("no subtask %d for %s", index+1, id)
+		}
+		if err := st.SaveNotes(notes); err != nil {
+			return err
+		}
+
+		n, total := notes.Get(id).Progress()
+		fmt.Printf("%d/%d done (%d%%)\n", n, total, n*100/total)
+		return nil
+	}
+}
+
+func handleSubtaskRemove(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		id, index, err := subtaskArgs(c)
+		if err != nil {
+			return err
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		notes, err := st.Notes()
+		if err != nil {
+			return err
+		}
+		removed, ok := notes.RemoveSubtask(id, index)
+		if !ok {
+			return fmt.Errorf("no subtask %d for %s", index+1, id)
+		}
+		trashID, err := moveToTrash(st, store.TrashItem{Kind: store.TrashSubtask, CourseWorkID: id, Index: index, Subtask: &removed})
+		if err != nil {
+			return err
+		}
+		if err := st.SaveNotes(notes); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed subtask %d; 'gc-cli note trash restore %s' brings it back\n", index+1, trashID)
+		return nil
+	}
+}
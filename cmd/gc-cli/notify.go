@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/notify"
+	"github.com/urfave/cli/v2"
+)
+
+func NotifyCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "notify",
+		Usage: "manage notification snoozes, quiet hours and grade alerts",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "snooze",
+				Usage:     "mute notifications about an item for a while",
+				ArgsUsage: "<id> <duration>",
+				Action:    handleSnooze(cfg),
+			},
+			{
+				Name:      "unsnooze",
+				Usage:     "resume notifications about an item",
+				ArgsUsage: "<id>",
+				Action:    handleUnsnooze(cfg),
+			},
+			{
+				Name:   "status",
+				Usage:  "show quiet hours and snoozed items",
+				Action: handleNotifyStatus(cfg),
+			},
+			notifyRulesCmd(cfg),
+			{
+				Name:   "check",
+				Usage:  "evaluate grade alert rules now and print new alerts",
+				Action: handleNotifyCheck(cfg),
+			},
+		},
+	}
+}
+
+// parseSnoozeDuration accepts Go durations (90m, 2h) plus whole days (3d).
+func parseSnoozeDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		n, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q (e.g. 2h, 90m or 3d)", s)
+	}
+	return d, nil
+}
+
+func handleSnooze(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 2 {
+			return fmt.Errorf("usage: gc-cli notify snooze <id> <duration>")
+		}
+		id := resolveItemID(c.Args().Get(0))
+		d, err := parseSnoozeDuration(c.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		snoozes, err := st.Snoozes()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		until := now.Add(d)
+		snoozes.Prune(now)
+		snoozes.Snooze(id, until)
+		if err := st.SaveSnoozes(snoozes); err != nil {
+			return err
+		}
+
+		fmt.Printf("Snoozed %s until %s\n", id, until.Format("Mon Jan 2 15:04"))
+		return nil
+	}
+}
+
+func handleUnsnooze(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("item ID required")
+		}
+		id := resolveItemID(c.Args().First())
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		snoozes, err := st.Snoozes()
+		if err != nil {
+			return err
+		}
+		snoozes.Unsnooze(id)
+		if err := st.SaveSnoozes(snoozes); err != nil {
+			return err
+		}
+
+		fmt.Printf("Notifications resumed for %s\n", id)
+		return nil
+	}
+}
+
+func handleNotifyStatus(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		gate, err := notifyGate(cfg)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if gate.Quieted(now) {
+			fmt.Println("Quiet hours: active now")
+		} else {
+			fmt.Println("Quiet hours: not active")
+		}
+		for _, w := range cfg.Notify.QuietHours {
+			days := "every day"
+			if len(w.Days) > 0 {
+				days = strings.Join(w.Days, ", ")
+			}
+			fmt.Printf("  %s–%s (%s)\n", w.Start, w.End, days)
+		}
+
+		gate.Snoozes.Prune(now)
+		if len(gate.Snoozes.Until) == 0 {
+			fmt.Println("\nNo snoozed items")
+			return nil
+		}
+
+		ids := make([]string, 0, len(gate.Snoozes.Until))
+		for id := range gate.Snoozes.Until {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool {
+			return gate.Snoozes.Until[ids[i]].Before(gate.Snoozes.Until[ids[j]])
+		})
+
+		fmt.Println("\nSnoozed:")
+		for _, id := range ids {
+			fmt.Printf("  %s until %s\n", id, gate.Snoozes.Until[id].Format("Mon Jan 2 15:04"))
+		}
+		return nil
+	}
+}
+
+// notifyGate loads the snooze list and quiet hours that every notification
+// source must respect.
+func notifyGate(cfg *config.Config) (*notify.Gate, error) {
+	quiet, err := notify.QuietWindowsFromConfig(cfg.Notify.QuietHours)
+	if err != nil {
+		return nil, err
+	}
+	st, err := cfg.Store()
+	if err != nil {
+		return nil, err
+	}
+	snoozes, err := st.Snoozes()
+	if err != nil {
+		return nil, err
+	}
+	return &notify.Gate{Quiet: quiet, Snoozes: snoozes}, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func OpenCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:      "open",
+		Usage:     "open coursework in the browser",
+		ArgsUsage: "<%N | coursework-id>",
+		Description: "%N is a row number from the last 'coursework list', e.g.\n" +
+			"'gc-cli open %3'. A coursework ID needs --course.",
+		Action: handleOpen(cfg),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "course",
+				Usage: "course ID, for coursework given by ID",
+			},
+		},
+	}
+}
+
+func handleOpen(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("coursework ID or %%N required")
+		}
+		arg := c.Args().First()
+
+		if n, ok := selectionRef(arg); ok {
+			item, err := selectedItem(n)
+			if err != nil {
+				return err
+			}
+			if item.Link != "" {
+				fmt.Printf("Opening %s\n", item.Title)
+				return auth.OpenBrowser(item.Link)
+			}
+		}
+		if _, ok := api.ParseClassroomLink(arg); ok {
+			return auth.OpenBrowser(arg)
+		}
+
+		courseID, courseWorkID, err := resolveItem(c, arg)
+		if err != nil {
+			return err
+		}
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		cw, err := client.GetCourseWork(ctx, courseID, courseWorkID)
+		if err != nil {
+			return fmt.Errorf("failed to get coursework: %w", err)
+		}
+		fmt.Printf("Opening %s\n", cw.Title)
+		return auth.OpenBrowser(cw.AlternateLink)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/output"
+	"github.com/urfave/cli/v2"
+)
+
+// outputFlags are --output and the older --json, kept as a hidden shorthand
+// for --output json so existing scripts keep working.
+func outputFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "output format: table, json, yaml, csv or tsv",
+			Value:   string(output.Table),
+		},
+		&cli.BoolFlag{
+			Name:   "json",
+			Usage:  "same as --output json",
+			Hidden: true,
+		},
+	}
+}
+
+// outputFormat reads --output, or --json on commands that only have that.
+func outputFormat(c *cli.Context) (output.Format, error) {
+	if c.Bool("json") {
+		if c.IsSet("output") && !strings.EqualFold(c.String("output"), string(output.JSON)) {
+			return "", fmt.Errorf("--json can't be combined with --output %s", c.String("output"))
+		}
+		return output.JSON, nil
+	}
+	return output.Parse(c.String("output"))
+}
+
+// writeOutput writes items to stdout in a machine-readable format.
+func writeOutput[T any](format output.Format, items []T, cols []output.Column[T]) error {
+	if items == nil {
+		// An empty list rather than null, for scripts.
+		items = []T{}
+	}
+	return output.Write(os.Stdout, format, items, cols)
+}
+
+// The CSV and TSV columns of each listing, named after the JSON fields.
+var (
+	courseColumns = []output.Column[api.Course]{
+		{Name: "id", Value: func(c api.Course) string { return c.ID }},
+		{Name: "name", Value: func(c api.Course) string { return c.Name }},
+		{Name: "section", Value: func(c api.Course) string { return c.Section }},
+		{Name: "room", Value: func(c api.Course) string { return c.Room }},
+		{Name: "courseState", Value: func(c api.Course) string { return string(c.CourseState) }},
+		{Name: "alternateLink", Value: func(c api.Course) string { return c.AlternateLink }},
+	}
+
+	courseworkColumns = []output.Column[api.CourseWork]{
+		{Name: "id", Value: func(cw api.CourseWork) string { return cw.ID }},
+		{Name: "courseId", Value: func(cw api.CourseWork) string { return cw.CourseID }},
+		{Name: "title", Value: func(cw api.CourseWork) string { return cw.Title }},
+		{Name: "workType", Value: func(cw api.CourseWork) string { return string(cw.WorkType) }},
+		{Name: "state", Value: func(cw api.CourseWork) string { return string(cw.State) }},
+		{Name: "maxPoints", Value: func(cw api.CourseWork) string { return formatPoints(cw.MaxPoints) }},
+		{Name: "dueDate", Value: func(cw api.CourseWork) string { return formatDueISO(cw) }},
+		{Name: "alternateLink", Value: func(cw api.CourseWork) string { return cw.AlternateLink }},
+	}
+
+	announcementColumns = []output.Column[api.Announcement]{
+		{Name: "id", Value: func(a api.Announcement) string { return a.ID }},
+		{Name: "courseId", Value: func(a api.Announcement) string { return a.CourseID }},
+		{Name: "creatorUserId", Value: func(a api.Announcement) string { return a.CreatorUserID }},
+		{Name: "creationTime", Value: func(a api.Announcement) string { return formatRFC3339(a.CreationTime) }},
+		{Name: "text", Value: func(a api.Announcement) string { return strings.TrimSpace(stripHTML(a.Text)) }},
+		{Name: "alternateLink", Value: func(a api.Announcement) string { return a.AlternateLink }},
+	}
+
+	gradeColumns = []output.Column[GradeEntry]{
+		{Name: "assignment", Value: func(g GradeEntry) string { return g.Assignment }},
+		{Name: "grade", Value: func(g GradeEntry) string { return g.Grade }},
+		{Name: "maxPoints", Value: func(g GradeEntry) string { return g.MaxPoints }},
+		{Name: "letter", Value: func(g GradeEntry) string { return g.Letter }},
+		{Name: "feedback", Value: func(g GradeEntry) string { return g.Feedback }},
+	}
+
+	submissionColumns = []output.Column[api.StudentSubmission]{
+		{Name: "id", Value: func(s api.StudentSubmission) string { return s.ID }},
+		{Name: "courseId", Value: func(s api.StudentSubmission) string { return s.CourseID }},
+		{Name: "courseWorkId", Value: func(s api.StudentSubmission) string { return s.CourseWorkID }},
+		{Name: "userId", Value: func(s api.StudentSubmission) string { return s.UserID }},
+		{Name: "state", Value: func(s api.StudentSubmission) string { return string(s.State) }},
+		{Name: "late", Value: func(s api.StudentSubmission) string { return strconv.FormatBool(s.Late) }},
+		{Name: "assignedGrade", Value: func(s api.StudentSubmission) string {
+			if s.State != api.SubmissionReturned {
+				return ""
+			}
+			return strconv.FormatFloat(s.AssignedGrade, 'f', -1, 64)
+		}},
+		{Name: "submittedTimestamp", Value: func(s api.StudentSubmission) string { return formatRFC3339(s.SubmittedTimestamp) }},
+		{Name: "alternateLink", Value: func(s api.StudentSubmission) string { return s.AlternateLink }},
+	}
+
+	categorySummaryColumns = []output.Column[categorySummaryJSON]{
+		{Name: "name", Value: func(cs categorySummaryJSON) string { return cs.Name }},
+		{Name: "weight", Value: func(cs categorySummaryJSON) string { return strconv.FormatFloat(cs.Weight, 'f', -1, 64) }},
+		{Name: "graded", Value: func(cs categorySummaryJSON) string { return strconv.Itoa(cs.Graded) }},
+		{Name: "missing", Value: func(cs categorySummaryJSON) string { return strconv.Itoa(cs.Missing) }},
+		{Name: "percent", Value: func(cs categorySummaryJSON) string { return formatPercent(cs.Percent) }},
+		{Name: "classPercent", Value: func(cs categorySummaryJSON) string { return formatPercent(cs.Class) }},
+	}
+
+	courseGradeSummaryColumns = []output.Column[courseGradeSummary]{
+		{Name: "course", Value: func(s courseGradeSummary) string { return s.Course }},
+		{Name: "courseId", Value: func(s courseGradeSummary) string { return s.CourseID }},
+		{Name: "earned", Value: func(s courseGradeSummary) string { return strconv.FormatFloat(s.Earned, 'f', -1, 64) }},
+		{Name: "possible", Value: func(s courseGradeSummary) string { return strconv.FormatFloat(s.Possible, 'f', -1, 64) }},
+		{Name: "percent", Value: func(s courseGradeSummary) string { return formatPercent(s.Percent) }},
+		{Name: "graded", Value: func(s courseGradeSummary) string { return strconv.Itoa(s.Graded) }},
+		{Name: "missing", Value: func(s courseGradeSummary) string { return strconv.Itoa(s.Missing) }},
+	}
+
+	enrollmentColumns = []output.Column[enrollmentEntry]{
+		{Name: "term", Value: func(e enrollmentEntry) string { return e.Term }},
+		{Name: "courseId", Value: func(e enrollmentEntry) string { return e.CourseID }},
+		{Name: "name", Value: func(e enrollmentEntry) string { return e.Name }},
+		{Name: "section", Value: func(e enrollmentEntry) string { return e.Section }},
+		{Name: "state", Value: func(e enrollmentEntry) string { return e.State }},
+		{Name: "firstSeen", Value: func(e enrollmentEntry) string { return formatRFC3339(e.FirstSeen) }},
+		{Name: "lastSeen", Value: func(e enrollmentEntry) string { return formatRFC3339(e.LastSeen) }},
+	}
+
+	enrollmentEventColumns = []output.Column[enrollmentEventEntry]{
+		{Name: "at", Value: func(e enrollmentEventEntry) string { return formatRFC3339(e.At) }},
+		{Name: "kind", Value: func(e enrollmentEventEntry) string { return e.Kind }},
+		{Name: "courseId", Value: func(e enrollmentEventEntry) string { return e.CourseID }},
+		{Name: "name", Value: func(e enrollmentEventEntry) string { return e.Name }},
+	}
+
+	gradeHistoryColumns = []output.Column[gradeHistoryEntry]{
+		{Name: "at", Value: func(e gradeHistoryEntry) string { return formatRFC3339(e.At) }},
+		{Name: "courseWorkId", Value: func(e gradeHistoryEntry) string { return e.CourseWorkID }},
+		{Name: "assignment", Value: func(e gradeHistoryEntry) string { return e.Assignment }},
+		{Name: "grade", Value: func(e gradeHistoryEntry) string { return strconv.FormatFloat(e.Grade, 'f', -1, 64) }},
+		{Name: "maxPoints", Value: func(e gradeHistoryEntry) string { return strconv.FormatFloat(e.MaxPoints, 'f', -1, 64) }},
+		{Name: "previous", Value: func(e gradeHistoryEntry) string {
+			if e.Previous == nil {
+				return ""
+			}
+			return strconv.FormatFloat(*e.Previous, 'f', -1, 64)
+		}},
+		{Name: "average", Value: func(e gradeHistoryEntry) string { return strconv.FormatFloat(e.Average, 'f', 1, 64) }},
+	}
+
+	turnaroundColumns = []output.Column[courseTurnaround]{
+		{Name: "course", Value: func(t courseTurnaround) string { return t.Course }},
+		{Name: "courseId", Value: func(t courseTurnaround) string { return t.CourseID }},
+		{Name: "returned", Value: func(t courseTurnaround) string { return strconv.Itoa(t.Returned) }},
+		{Name: "averageHours", Value: func(t courseTurnaround) string { return strconv.FormatFloat(t.Average, 'f', 1, 64) }},
+		{Name: "slowestHours", Value: func(t courseTurnaround) string { return strconv.FormatFloat(t.Slowest, 'f', 1, 64) }},
+	}
+)
+
+func formatPoints(points int64) string {
+	if points == 0 {
+		return ""
+	}
+	return strconv.FormatInt(points, 10)
+}
+
+// formatDueISO is the due date in a form spreadsheets read as a date:
+// 2024-03-01 or 2024-03-01T23:59:00Z, in UTC as Classroom stores it.
+func formatDueISO(cw api.CourseWork) string {
+	if cw.DueDate == nil {
+		return ""
+	}
+	date := fmt.Sprintf("%04d-%02d-%02d", cw.DueDate.Year, cw.DueDate.Month, cw.DueDate.Day)
+	if cw.DueTime != nil {
+		date += fmt.Sprintf("T%02d:%02d:00Z", cw.DueTime.Hours, cw.DueTime.Minutes)
+	}
+	return date
+}
+
+func formatPercent(pct *float64) string {
+	if pct == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*pct, 'f', 1, 64)
+}
+
+func formatRFC3339(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/avatar"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/timboy697/gc-cli/internal/termimg"
+	"golang.org/x/term"
+)
+
+const profileCacheTTL = 30 * 24 * time.Hour
+
+// people turns user IDs into names and avatars. Profiles are cached in the
+// store and photos under data_dir/avatars, so a listing costs at most one
+// request per new person.
+type people struct {
+	ctx      context.Context
+	client   *api.Client
+	st       *store.Store
+	profiles *store.Profiles
+	photos   *avatar.Cache
+	images   bool
+	persist  bool
+	changed  bool
+	// failed remembers lookups that errored this run, typically because the
+	// token predates the roster scope.
+	failed map[string]bool
+}
+
+func newPeople(ctx context.Context, cfg *config.Config, client *api.Client) *people {
+	p := &people{
+		ctx:      ctx,
+		client:   client,
+		profiles: &store.Profiles{Users: map[string]store.CachedProfile{}},
+		photos:   avatar.NewCache(filepath.Join(cfg.DataDir, "avatars")),
+		images:   term.IsTerminal(int(os.Stdout.Fd())) && termimg.BlocksSupported(),
+		persist:  true,
+		failed:   make(map[string]bool),
+	}
+	// Anonymized output must not show real faces or cache fake names.
+	if cfg.Anonymize {
+		p.images = false
+		p.persist = false
+	}
+	if st, err := cfg.Store(); err == nil {
+		p.st = st
+		if loaded, err := st.Profiles(); err == nil {
+			p.profiles = loaded
+		}
+	}
+	return p
+}
+
+func (p *people) lookup(userID string) (store.CachedProfile, bool) {
+	if userID == "" || p.failed[userID] {
+		return store.CachedProfile{}, false
+	}
+	now := clk.Now()
+	if cp, ok := p.profiles.Get(userID, profileCacheTTL, now); ok {
+		return cp, true
+	}
+
+	profile, err := p.client.GetUserProfile(p.ctx, userID)
+	if err != nil {
+		p.failed[userID] = true
+		return store.CachedProfile{}, false
+	}
+	cp := store.CachedProfile{Name: profile.Name.FullName, PhotoURL: profile.Photo(), Fetched: now}
+	if p.persist {
+		p.profiles.Set(userID, cp)
+		p.changed = true
+	}
+	return cp, true
+}
+
+// remember caches a profile that came with another response, such as a
+// roster, so it isn't looked up again.
+func (p *people) remember(userID string, profile api.UserProfile) {
+	if !p.persist || userID == "" || profile.Name.FullName == "" {
+		return
+	}
+	p.profiles.Set(userID, store.CachedProfile{Name: profile.Name.FullName, PhotoURL: profile.Photo(), Fetched: clk.Now()})
+	p.changed = true
+}
+
+// Name returns the user's full name, or the ID if it can't be looked up.
+func (p *people) Name(userID string) string {
+	if cp, ok := p.lookup(userID); ok && cp.Name != "" {
+		return cp.Name
+	}
+	return displayID(userID)
+}
+
+// Badge returns a two-cell avatar for the user.
+func (p *people) Badge(userID string) string {
+	cp, ok := p.lookup(userID)
+	if !ok {
+		return avatar.Badge(nil, "", false)
+	}
+	if !p.images || cp.PhotoURL == "" {
+		return avatar.Badge(nil, cp.Name, false)
+	}
+	img, err := p.photos.Fetch(p.ctx, userID, cp.PhotoURL)
+	if err != nil {
+		return avatar.Badge(nil, cp.Name, false)
+	}
+	return avatar.Badge(img, cp.Name, true)
+}
+
+// save writes newly fetched profiles back to the store. Failures only cost
+// a refetch next time, so they're ignored.
+func (p *people) save() {
+	if p.changed && p.st != nil {
+		_ = p.st.SaveProfiles(p.profiles)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/plan"
+	"github.com/urfave/cli/v2"
+)
+
+func PlanCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "plan",
+		Usage:  "propose a day-by-day plan for upcoming assignments",
+		Action: handlePlan(cfg),
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "days",
+				Usage: "number of days to plan, starting today",
+				Value: 7,
+			},
+			&cli.StringFlag{
+				Name:  "ics",
+				Usage: "also write the plan to this calendar (.ics) file",
+			},
+			&cli.StringFlag{
+				Name:  "csv",
+				Usage: "also write the plan to this CSV file, for task managers",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as JSON",
+			},
+		},
+	}
+}
+
+func handlePlan(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		days := c.Int("days")
+		if days < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+		avail, err := plan.AvailabilityFromConfig(cfg.Plan)
+		if err != nil {
+			return err
+		}
+		est, _, err := newEstimator(cfg)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		items, err := agenda.Collect(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		now := clk.Now()
+		p := plan.Build(items, est, avail, schoolCal, now, days)
+
+		if path := c.String("ics"); path != "" {
+			if err := writeReportFile(path, func(w io.Writer) error { return plan.WriteICS(w, p, now) }); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Wrote calendar to %s\n", path)
+		}
+		if path := c.String("csv"); path != "" {
+			if err := writeReportFile(path, func(w io.Writer) error { return plan.WriteCSV(w, p) }); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Wrote tasks to %s\n", path)
+		}
+
+		if c.Bool("json") {
+			return outputPlanJSON(p)
+		}
+		outputPlan(p)
+		return nil
+	}
+}
+
+func outputPlan(p *plan.Plan) {
+	var planned int
+	for _, day := range p.Days {
+		if week := schoolCal.TermWeek(day.Date); week != "" && (day.Date.Equal(p.Days[0].Date) || day.Date.Equal(schoolCal.WeekStart(day.Date))) {
+			fmt.Println(separatorStyle.Render("── " + week + " ──"))
+		}
+		load := fmt.Sprintf("%s / %s", plan.FormatDuration(day.Planned()), plan.FormatDuration(day.Available))
+		if day.Break != "" {
+			load += " · " + day.Break
+		}
+		fmt.Printf("%s  %s\n", headerStyle.Render(day.Date.Format("Mon Jan 02")), separatorStyle.Render(load))
+		if len(day.Blocks) == 0 {
+			fmt.Println(separatorStyle.Render("  nothing planned"))
+		}
+		for _, blk := range day.Blocks {
+			planned++
+			fmt.Printf("  %-6s %s %s\n",
+				plan.FormatDuration(blk.Duration),
+				truncate(blk.Item.Work.Title, 40),
+				separatorStyle.Render(fmt.Sprintf("(%s, due %s)", truncate(blk.Item.Course.Name, 24), blk.Item.Due().Format("Mon 15:04"))))
+		}
+		fmt.Println()
+	}
+
+	if planned == 0 && len(p.Shortfall) == 0 {
+		fmt.Println("Nothing due in this period.")
+		return
+	}
+	for _, s := range p.Shortfall {
+		fmt.Printf("⚠ %s (%s) needs %s more than fits before it's due\n",
+			s.Item.Work.Title, s.Item.Course.Name, plan.FormatDuration(s.Missing))
+	}
+}
+
+type planBlockJSON struct {
+	CourseID     string `json:"courseId"`
+	CourseName   string `json:"courseName"`
+	CourseWorkID string `json:"courseWorkId"`
+	Title        string `json:"title"`
+	Minutes      int    `json:"minutes"`
+	Due          string `json:"due"`
+}
+
+type planDayJSON struct {
+	Date             string          `json:"date"`
+	AvailableMinutes int             `json:"availableMinutes"`
+	Break            string          `json:"break,omitempty"`
+	Blocks           []planBlockJSON `json:"blocks"`
+}
+
+type planJSON struct {
+	Days      []planDayJSON   `json:"days"`
+	Shortfall []planBlockJSON `json:"shortfall,omitempty"`
+}
+
+func outputPlanJSON(p *plan.Plan) error {
+	block := func(item agenda.Item, minutes int) planBlockJSON {
+		return planBlockJSON{
+			CourseID:     item.Course.ID,
+			CourseName:   item.Course.Name,
+			CourseWorkID: item.Work.ID,
+			Title:        item.Work.Title,
+			Minutes:      minutes,
+			Due:          item.Due().Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	out := planJSON{Days: make([]planDayJSON, len(p.Days))}
+	for i, day := range p.Days {
+		d := planDayJSON{
+			Date:             day.Date.Format("2006-01-02"),
+			AvailableMinutes: int(day.Available.Minutes()),
+			Break:            day.Break,
+			Blocks:           []planBlockJSON{},
+		}
+		for _, blk := range day.Blocks {
+			d.Blocks = append(d.Blocks, block(blk.Item, int(blk.Duration.Minutes())))
+		}
+		out.Days[i] = d
+	}
+	for _, s := range p.Shortfall {
+		out.Shortfall = append(out.Shortfall, block(s.Item, int(s.Missing.Minutes())))
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/preset"
+	"github.com/timboy697/gc-cli/internal/schoolcal"
+	"github.com/urfave/cli/v2"
+)
+
+func PresetCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "preset",
+		Usage: "share course order, calendar, quiet hours, alert rules, pins and mutes with classmates",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "export",
+				Usage:     "write your shareable setup to a YAML file",
+				ArgsUsage: "<file.yaml>",
+				Action:    handlePresetExport(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "description shown when the preset is imported, e.g. \"Grade 11, fall semester\"",
+					},
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     "merge a preset into your setup",
+				ArgsUsage: "<file.yaml>",
+				Action:    handlePresetImport(cfg),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "overwrite",
+						Usage: "replace course order, calendar and quiet hours you have already set",
+					},
+				},
+			},
+		},
+	}
+}
+
+func handlePresetExport(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("usage: gc-cli preset export <file.yaml>")
+		}
+		path := c.Args().First()
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		p, err := preset.Build(cfg, st, c.String("name"), time.Now())
+		if err != nil {
+			return err
+		}
+		if err := preset.Write(path, p); err != nil {
+			return err
+		}
+
+		fmt.Printf("✓ Preset written to %s\n", path)
+		fmt.Printf("  %s\n", presetSummary(len(p.Courses), p.HasCalendar(), len(p.QuietHours), len(p.Rules), len(p.Pins), len(p.Mutes)))
+		return nil
+	}
+}
+
+func handlePresetImport(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("usage: gc-cli preset import <file.yaml>")
+		}
+
+		p, err := preset.Read(c.Args().First())
+		if err != nil {
+			return err
+		}
+		if _, err := schoolcal.FromConfig(p.Calendar); err != nil {
+			return fmt.Errorf("preset has an invalid calendar: %w", err)
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		res, err := preset.Apply(cfg, st, p, c.Bool("overwrite"), time.Now())
+		if err != nil {
+			return err
+		}
+
+		if p.Name != "" {
+			fmt.Printf("✓ Imported preset %q\n", p.Name)
+		} else {
+			fmt.Println("✓ Imported preset")
+		}
+		fmt.Printf("  %s\n", presetSummary(res.Courses, res.Calendar, res.QuietHours, res.Rules, res.Pins, res.Mutes))
+		if len(res.Skipped) > 0 {
+			fmt.Printf("  Kept your own %s; use --overwrite to replace them\n", strings.Join(res.Skipped, ", "))
+		}
+		return nil
+	}
+}
+
+func presetSummary(courses int, calendar bool, quietHours, rules, pins, mutes int) string {
+	var parts []string
+	if courses > 0 {
+		parts = append(parts, fmt.Sprintf("%d course slot(s)", courses))
+	}
+	if calendar {
+		parts = append(parts, "school calendar")
+	}
+	if quietHours > 0 {
+		parts = append(parts, fmt.Sprintf("%d quiet hours window(s)", quietHours))
+	}
+	if rules > 0 {
+		parts = append(parts, fmt.Sprintf("%d alert rule(s)", rules))
+	}
+	if pins > 0 {
+		parts = append(parts, fmt.Sprintf("%d pin(s)", pins))
+	}
+	if mutes > 0 {
+		parts = append(parts, fmt.Sprintf("%d mute(s)", mutes))
+	}
+	if len(parts) == 0 {
+		return "Nothing new"
+	}
+	return strings.Join(parts, ", ")
+}
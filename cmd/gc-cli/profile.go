@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/profile"
+	"github.com/urfave/cli/v2"
+)
+
+func ProfileCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "profile",
+		Usage: "manage named credential profiles",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list installed profiles",
+				Action: handleProfileList(cfg),
+			},
+			{
+				Name:   "export-readonly",
+				Usage:  "sign in with read-only access and package the token for another machine",
+				Action: handleExportReadOnly(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "profile name used when the bundle is imported",
+						Value: "family",
+					},
+					&cli.StringFlag{
+						Name:  "out",
+						Usage: "bundle file to write (default: <name>.gcprofile.json)",
+					},
+				},
+			},
+			{
+				Name:      "import",
+				Usage:     "install a profile bundle; use it with --profile <name>",
+				ArgsUsage: "<bundle-file>",
+				Action:    handleProfileImport(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "install under a different name",
+					},
+				},
+			},
+		},
+	}
+}
+
+func handleProfileList(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		profiles, err := profile.List(cfg)
+		if err != nil {
+			return err
+		}
+		if len(profiles) == 0 {
+			fmt.Println("No profiles installed")
+			return nil
+		}
+
+		for _, p := range profiles {
+			marker := " "
+			if p.Name == cfg.Profile {
+				marker = "*"
+			}
+			access := "full access"
+			if p.ReadOnly {
+				access = "read-only"
+			}
+			fmt.Printf("%s %-20s %s\n", marker, p.Name, access)
+		}
+		return nil
+	}
+}
+
+func handleExportReadOnly(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		name := c.String("name")
+		if err := profile.ValidateName(name); err != nil {
+			return err
+		}
+		out := c.String("out")
+		if out == "" {
+			out = name + ".gcprofile.json"
+		}
+
+		authCfg := cfg.AuthConfig()
+		authCfg.Scopes = auth.ReadOnlyScopes
+
+		fmt.Println("Sign in to grant read-only access. The exported profile can view courses,")
+		fmt.Println("assignments, grades and announcements, but can never submit or change anything.")
+
+		token, err := auth.BrowserFlow(ctx, authCfg)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		bundle := &profile.Bundle{
+			Profile: profile.Profile{
+				Name:         name,
+				ReadOnly:     true,
+				Scopes:       auth.ReadOnlyScopes,
+				ClientID:     authCfg.ClientID,
+				ClientSecret: authCfg.ClientSecret,
+				Created:      time.Now(),
+			},
+			Token: token,
+		}
+		if err := profile.WriteBundle(out, bundle); err != nil {
+			return err
+		}
+
+		fmt.Printf("\n✓ Read-only profile written to %s\n", out)
+		fmt.Printf("On the other machine run: gc-cli profile import %s\n", out)
+		fmt.Println("Treat this file like a password. Revoke it any time at https://myaccount.google.com/permissions")
+		return nil
+	}
+}
+
+func handleProfileImport(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("bundle file required")
+		}
+
+		bundle, err := profile.ReadBundle(c.Args().First())
+		if err != nil {
+			return err
+		}
+		p := bundle.Profile
+		if name := c.String("name"); name != "" {
+			p.Name = name
+		}
+
+		if err := profile.Save(cfg, &p, bundle.Token); err != nil {
+			return err
+		}
+
+		access := "full access"
+		if p.ReadOnly {
+			access = "read-only"
+		}
+		fmt.Printf("✓ Installed %s profile %q\n", access, p.Name)
+		fmt.Printf("Use it with: gc-cli --profile %s grades list --course COURSE_ID\n", p.Name)
+		fmt.Printf("Or set `profile: %s` in %s\n", p.Name, cfg.ConfigPath)
+		return nil
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+	"github.com/timboy697/gc-cli/internal/report"
+	"github.com/urfave/cli/v2"
+)
+
+func ReportCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "report",
+		Usage: "produce printable records",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "grades",
+				Usage:  "write a grade report as PDF and/or HTML",
+				Action: handleGradeReport(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "only include this course (default: all active courses)",
+					},
+					&cli.StringFlag{
+						Name:  "pdf",
+						Usage: "write the report to this PDF file",
+					},
+					&cli.StringFlag{
+						Name:  "html",
+						Usage: "write the report to this HTML file",
+					},
+				},
+			},
+		},
+	}
+}
+
+func handleGradeReport(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		pdfPath, htmlPath := c.String("pdf"), c.String("html")
+		if pdfPath == "" && htmlPath == "" {
+			return fmt.Errorf("specify --pdf and/or --html")
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		var courses []api.Course
+		if c.IsSet("course") {
+			courseID, err := courseArg(c)
+			if err != nil {
+				return err
+			}
+			course, err := client.GetCourse(ctx, courseID)
+			if err != nil {
+				return fmt.Errorf("failed to get course: %w", err)
+			}
+			courses = append(courses, *course)
+		} else {
+			all, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive))
+			if err != nil {
+				return fmt.Errorf("failed to list courses: %w", err)
+			}
+			for _, course := range all {
+				if course.CourseState == api.CourseActive {
+					courses = append(courses, course)
+				}
+			}
+		}
+
+		scale := gradebook.NewScale(cfg.Grades.Scale)
+		rpt := &report.GradeReport{Generated: clk.Now()}
+		for _, course := range courses {
+			coursework, _, err := client.ListCourseWork(ctx, course.ID, 100)
+			if err != nil {
+				return fmt.Errorf("failed to list coursework for %s: %w", course.Name, err)
+			}
+			submissions, _, err := client.ListStudentSubmissions(ctx, course.ID, "-", 100, api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+			if err != nil {
+				return fmt.Errorf("failed to list submissions for %s: %w", course.Name, err)
+			}
+			rpt.Courses = append(rpt.Courses, report.CourseGradesFrom(course, coursework, submissions, scale))
+		}
+
+		if pdfPath != "" {
+			if err := writeReportFile(pdfPath, rpt.WritePDF); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s\n", pdfPath)
+		}
+		if htmlPath != "" {
+			if err := writeReportFile(htmlPath, rpt.WriteHTML); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %s\n", htmlPath)
+		}
+		return nil
+	}
+}
+
+func writeReportFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// Enrollment roles rarely change, so a cached role is trusted for a week.
+const roleCacheTTL = 7 * 24 * time.Hour
+
+// roleOf works out my role in a course. Its owner teaches it; anyone else
+// is looked up with teachers.get, which finds co-teachers and answers not
+// found for students.
+func roleOf(ctx context.Context, client *api.Client, course api.Course, me string) (string, error) {
+	if course.OwnedBy(me) {
+		return store.RoleTeacher, nil
+	}
+	if _, err := client.GetTeacher(ctx, course.ID, "me"); err != nil {
+		if api.IsNotFound(err) {
+			return store.RoleStudent, nil
+		}
+		return "", fmt.Errorf("failed to check whether you teach course %s: %w", course.ID, err)
+	}
+	return store.RoleTeacher, nil
+}
+
+// myUserID is the signed-in user's ID, which course owners are given by.
+// Without it only teachers.get can tell teachers apart.
+func myUserID(ctx context.Context, client *api.Client) string {
+	p, err := client.GetUserProfile(ctx, "me")
+	if err != nil {
+		return ""
+	}
+	return p.ID
+}
+
+// courseRole returns my role in a course, asking the API only when the
+// cached answer is missing or stale.
+func courseRole(ctx context.Context, cfg *config.Config, client *api.Client, courseID string) (string, error) {
+	st, err := cfg.Store()
+	if err != nil {
+		return "", err
+	}
+	roles, err := st.Roles()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if role, ok := roles.Get(courseID, roleCacheTTL, now); ok {
+		return role, nil
+	}
+
+	course, err := client.GetCourse(ctx, courseID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get course: %w", err)
+	}
+	role, err := roleOf(ctx, client, *course, myUserID(ctx, client))
+	if err != nil {
+		return "", err
+	}
+	roles.Set(courseID, role, now)
+	if err := st.SaveRoles(roles); err != nil {
+		return "", err
+	}
+	return role, nil
+}
+
+// rememberRoles caches roles for courses already fetched for other reasons,
+// looking up only those missing from the cache or stale in it. It's best
+// effort: a failure here shouldn't fail the command.
+func rememberRoles(ctx context.Context, cfg *config.Config, client *api.Client, courses []api.Course) {
+	st, err := cfg.Store()
+	if err != nil {
+		return
+	}
+	roles, err := st.Roles()
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	var me string
+	var asked bool
+	for _, course := range courses {
+		if _, ok := roles.Get(course.ID, roleCacheTTL, now); ok {
+			continue
+		}
+		if !asked {
+			me, asked = myUserID(ctx, client), true
+		}
+		role, err := roleOf(ctx, client, course, me)
+		if err != nil {
+			continue
+		}
+		roles.Set(course.ID, role, now)
+	}
+	_ = st.SaveRoles(roles)
+}
+
+// requireTeacher fails early, with a clear message, when a student tries a
+// teacher-only operation.
+func requireTeacher(ctx context.Context, cfg *config.Config, client *api.Client, courseID, action string) error {
+	role, err := courseRole(ctx, cfg, client, courseID)
+	if err != nil {
+		return err
+	}
+	if role != store.RoleTeacher {
+		return fmt.Errorf("you're a student in course %s; only its teachers can %s", courseID, action)
+	}
+	return nil
+}
+
+// teachesAnything decides whether teacher commands are worth showing. Until
+// roles have been cached it errs on the side of showing them.
+func teachesAnything(cfg *config.Config) bool {
+	st, err := cfg.Store()
+	if err != nil {
+		return true
+	}
+	roles, err := st.Roles()
+	if err != nil {
+		return true
+	}
+	teaches, known := roles.Teaches()
+	return teaches || !known
+}
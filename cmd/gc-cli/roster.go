@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/output"
+	"github.com/timboy697/gc-cli/internal/roster"
+	"github.com/urfave/cli/v2"
+)
+
+func RosterCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "roster",
+		Usage: "list a course's teachers and students",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "course",
+				Usage: "course ID, alias or link",
+			},
+			&cli.BoolFlag{
+				Name:  "teachers",
+				Usage: "only list teachers",
+			},
+			&cli.BoolFlag{
+				Name:  "students",
+				Usage: "only list students",
+			},
+		}, outputFlags()...),
+		Action: handleRoster(cfg),
+	}
+}
+
+type rosterEntry struct {
+	Role         string `json:"role"`
+	UserID       string `json:"userId"`
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+var rosterColumns = []output.Column[rosterEntry]{
+	{Name: "role", Value: func(e rosterEntry) string { return e.Role }},
+	{Name: "userId", Value: func(e rosterEntry) string { return e.UserID }},
+	{Name: "name", Value: func(e rosterEntry) string { return e.Name }},
+	{Name: "emailAddress", Value: func(e rosterEntry) string { return e.EmailAddress }},
+}
+
+func handleRoster(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+		format, err := outputFormat(c)
+		if err != nil {
+			return err
+		}
+		// Neither flag, or both, lists everyone.
+		wantTeachers := c.Bool("teachers") || !c.Bool("students")
+		wantStudents := c.Bool("students") || !c.Bool("teachers")
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		// Names from the roster save looking each person up later, e.g.
+		// for announcement authors.
+		ppl := newPeople(ctx, cfg, client)
+		defer ppl.save()
+
+		var entries []rosterEntry
+		if wantTeachers {
+			teachers, _, err := client.ListTeachers(ctx, courseID, 0)
+			if err != nil {
+				return rosterError(err)
+			}
+			roster.SortTeachers(teachers)
+			for _, t := range teachers {
+				ppl.remember(t.UserID, t.Profile)
+				entries = append(entries, rosterEntry{Role: "teacher", UserID: t.UserID, Name: t.Profile.Name.FullName, EmailAddress: t.Profile.EmailAddress})
+			}
+		}
+		if wantStudents {
+			students, _, err := client.ListStudents(ctx, courseID, 0)
+			if err != nil {
+				return rosterError(err)
+			}
+			roster.Sort(students)
+			for _, s := range students {
+				ppl.remember(s.UserID, s.Profile)
+				entries = append(entries, rosterEntry{Role: "student", UserID: s.UserID, Name: s.Profile.Name.FullName, EmailAddress: s.Profile.EmailAddress})
+			}
+		}
+
+		entries, err = filterOutput(entries)
+		if err != nil {
+			return err
+		}
+
+		if format != output.Table {
+			return writeOutput(format, entries, rosterColumns)
+		}
+		return outputRosterTable(entries)
+	}
+}
+
+// rosterError explains the usual reason a roster can't be read: a token
+// from before gc-cli asked for the roster scopes.
+func rosterError(err error) error {
+	if api.IsForbidden(err) {
+		return fmt.Errorf("reading the roster needs the roster scopes; run 'gc-cli auth login' again: %w", err)
+	}
+	return err
+}
+
+func outputRosterTable(entries []rosterEntry) error {
+	if len(entries) == 0 {
+		fmt.Println("No one found on this roster.")
+		return nil
+	}
+
+	roleWidth := 10
+	nameWidth := 20
+	emailWidth := 20
+	idWidth := 12
+	for _, e := range entries {
+		if len(e.Name)+2 > nameWidth {
+			nameWidth = len(e.Name) + 2
+		}
+		if len(e.EmailAddress)+2 > emailWidth {
+			emailWidth = len(e.EmailAddress) + 2
+		}
+		if len(displayID(e.UserID))+2 > idWidth {
+			idWidth = len(displayID(e.UserID)) + 2
+		}
+	}
+
+	header := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(roleWidth).Render("Role"),
+		headerStyle.Width(nameWidth).Render("Name"),
+		headerStyle.Width(emailWidth).Render("Email"),
+		headerStyle.Width(idWidth).Render("User ID"),
+	)
+	fmt.Println(header)
+	fmt.Println(separatorStyle.Render(strings.Repeat("─", roleWidth+nameWidth+emailWidth+idWidth)))
+
+	var teachers, students, hidden int
+	for _, e := range entries {
+		if e.Role == "teacher" {
+			teachers++
+		} else {
+			students++
+		}
+		email := e.EmailAddress
+		if email == "" {
+			email = "-"
+			hidden++
+		}
+		row := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(roleWidth).Render(e.Role),
+			cellStyle.Width(nameWidth).Render(e.Name),
+			cellStyle.Width(emailWidth).Render(email),
+			cellStyle.Width(idWidth).Render(displayID(e.UserID)),
+		)
+		fmt.Println(row)
+	}
+
+	fmt.Println()
+	fmt.Printf("Total: %d teacher(s), %d student(s)\n", teachers, students)
+	if hidden > 0 {
+		fmt.Printf("%d email address(es) aren't visible to you\n", hidden)
+	}
+	return nil
+}
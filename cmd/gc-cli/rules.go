@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/notify"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/urfave/cli/v2"
+)
+
+func notifyRulesCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "rules",
+		Usage: "manage grade alert rules",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "add",
+				Usage:  "alert when a grade or course average falls below a percentage",
+				Action: handleRulesAdd(cfg),
+				Flags: []cli.Flag{
+					&cli.Float64Flag{
+						Name:  "grade-below",
+						Usage: "alert on any returned grade under this percentage",
+					},
+					&cli.Float64Flag{
+						Name:  "average-below",
+						Usage: "alert when a course average drops under this percentage",
+					},
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "only apply to this course (default: all courses)",
+					},
+				},
+			},
+			{
+				Name:   "list",
+				Usage:  "list alert rules",
+				Action: handleRulesList(cfg),
+			},
+			{
+				Name:      "remove",
+				Usage:     "delete an alert rule",
+				ArgsUsage: "<rule-id>",
+				Action:    handleRulesRemove(cfg),
+			},
+		},
+	}
+}
+
+func handleRulesAdd(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		rule := store.AlertRule{Created: time.Now()}
+		switch {
+		case c.IsSet("grade-below") && c.IsSet("average-below"):
+			return fmt.Errorf("use either --grade-below or --average-below, not both")
+		case c.IsSet("grade-below"):
+			rule.Kind = store.RuleGradeBelow
+			rule.Threshold = c.Float64("grade-below")
+		case c.IsSet("average-below"):
+			rule.Kind = store.RuleAverageBelow
+			rule.Threshold = c.Float64("average-below")
+		default:
+			return fmt.Errorf("--grade-below or --average-below required")
+		}
+		if rule.Threshold <= 0 || rule.Threshold > 100 {
+			return fmt.Errorf("threshold must be a percentage between 0 and 100")
+		}
+		if c.String("course") != "" {
+			courseID, err := resolveCourseArg(c.String("course"))
+			if err != nil {
+				return err
+			}
+			rule.CourseID = courseID
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		rules, err := st.AlertRules()
+		if err != nil {
+			return err
+		}
+		rule = rules.Add(rule)
+		if err := st.SaveAlertRules(rules); err != nil {
+			return err
+		}
+
+		fmt.Printf("Added rule %s: %s\n", rule.ID, describeRule(rule))
+		return nil
+	}
+}
+
+func handleRulesList(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		rules, err := st.AlertRules()
+		if err != nil {
+			return err
+		}
+		if len(rules.Rules) == 0 {
+			fmt.Println("No alert rules. Add one with 'gc-cli notify rules add --grade-below 70'")
+			return nil
+		}
+		for _, rule := range rules.Rules {
+			fmt.Printf("%-4s %s\n", rule.ID, describeRule(rule))
+		}
+		return nil
+	}
+}
+
+func handleRulesRemove(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("rule ID required")
+		}
+		id := c.Args().First()
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		rules, err := st.AlertRules()
+		if err != nil {
+			return err
+		}
+		if !rules.Remove(id) {
+			return fmt.Errorf("no rule %s", id)
+		}
+		if err := st.SaveAlertRules(rules); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed rule %s\n", id)
+		return nil
+	}
+}
+
+func describeRule(rule store.AlertRule) string {
+	scope := "any course"
+	if rule.CourseID != "" {
+		scope = "course " + rule.CourseID
+	}
+	switch rule.Kind {
+	case store.RuleGradeBelow:
+		return fmt.Sprintf("a returned grade below %.0f%% in %s", rule.Threshold, scope)
+	case store.RuleAverageBelow:
+		return fmt.Sprintf("the average below %.0f%% in %s", rule.Threshold, scope)
+	}
+	return rule.Kind
+}
+
+// handleNotifyCheck evaluates the alert rules once and prints new alerts.
+// It's meant to run from cron until a long-running watcher exists.
+func handleNotifyCheck(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		rules, err := st.AlertRules()
+		if err != nil {
+			return err
+		}
+		if len(rules.Rules) == 0 {
+			fmt.Println("No alert rules configured")
+			return nil
+		}
+
+		gate, err := notifyGate(cfg)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		courses, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive))
+		if err != nil {
+			return fmt.Errorf("failed to list courses: %w", err)
+		}
+		var grades []notify.CourseGrades
+		for _, course := range courses {
+			if course.CourseState != api.CourseActive {
+				continue
+			}
+			coursework, _, err := client.ListCourseWork(ctx, course.ID, 100)
+			if err != nil {
+				return fmt.Errorf("failed to list coursework for %s: %w", course.Name, err)
+			}
+			submissions, _, err := client.ListStudentSubmissions(ctx, course.ID, "-", 100, api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+			if err != nil {
+				return fmt.Errorf("failed to list submissions for %s: %w", course.Name, err)
+			}
+			grades = append(grades, notify.CourseGrades{Course: course, Coursework: coursework, Submissions: submissions})
+		}
+
+		now := clk.Now()
+		alerts := notify.EvaluateRules(rules.Rules, grades)
+
+		// Forget alerts that no longer apply so a recovered average can fire
+		// again the next time it drops.
+		active := make(map[string]bool, len(alerts))
+		for _, alert := range alerts {
+			active[alert.Key] = true
+		}
+		for key := range rules.Fired {
+			if !active[key] {
+				delete(rules.Fired, key)
+			}
+		}
+
+		var sent, held int
+		for _, alert := range alerts {
+			if _, done := rules.Fired[alert.Key]; done {
+				continue
+			}
+			if !gate.Allow(alert.ItemID, now) {
+				held++
+				continue
+			}
+			fmt.Printf("⚠ [%s] %s\n", alert.RuleID, alert.Message)
+			rules.Fired[alert.Key] = now
+			sent++
+		}
+
+		if err := st.SaveAlertRules(rules); err != nil {
+			return err
+		}
+
+		if sent == 0 {
+			fmt.Println("No new alerts")
+		}
+		if held > 0 {
+			fmt.Printf("%d alert(s) held back by quiet hours or snoozes\n", held)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// commandNeeds are the permissions each command can't work without, keyed
+// by the command's path. A subcommand's entry replaces its parent's, and a
+// nil entry means it needs none, e.g. because it only reads local data.
+var commandNeeds = map[string][]auth.Need{
+	"courses":          {auth.NeedCourses},
+	"course":           {auth.NeedCourses},
+	"assignments":      {auth.NeedCourses, auth.NeedCourseWork},
+	"coursework":       {auth.NeedCourses, auth.NeedCourseWork},
+	"todo":             {auth.NeedCourses, auth.NeedCourseWork},
+	"grades":           {auth.NeedCourseWork},
+	"announcements":    {auth.NeedAnnouncements},
+	"feed":             {auth.NeedCourseWork, auth.NeedAnnouncements},
+	"roster":           {auth.NeedRosters},
+	"submit":           {auth.NeedSubmit, auth.NeedDrive},
+	"submit stage":     {auth.NeedSubmit, auth.NeedDrive},
+	"submit status":    {auth.NeedCourseWork},
+	"submit journal":   nil,
+	"submit finalize":  {auth.NeedSubmit},
+	"share":            {auth.NeedDrive},
+	"teach coursework": {auth.NeedTeach},
+	"teach roster":     {auth.NeedRosters},
+	"sync":             {auth.NeedCourses, auth.NeedCourseWork, auth.NeedAnnouncements},
+	"sync status":      nil,
+	"watch":            {auth.NeedCourses, auth.NeedCourseWork, auth.NeedAnnouncements},
+	"tui":              {auth.NeedCourses, auth.NeedCourseWork},
+}
+
+// allNeeds are what gc-cli as a whole uses, for 'auth status'.
+var allNeeds = []auth.Need{
+	auth.NeedCourses, auth.NeedCourseWork, auth.NeedSubmit, auth.NeedTeach,
+	auth.NeedAnnouncements, auth.NeedPostAnnouncements, auth.NeedRosters, auth.NeedDrive,
+}
+
+// neededScopes are the needs of the command being run, checked against the
+// token before the first request.
+var neededScopes []auth.Need
+
+// addScopePreflight makes each command in commandNeeds record its needs
+// when it starts.
+func addScopePreflight(cmds []*cli.Command, parent string) {
+	for _, cmd := range cmds {
+		path := strings.TrimSpace(parent + " " + cmd.Name)
+		if needs, ok := commandNeeds[path]; ok {
+			before := cmd.Before
+			cmd.Before = func(c *cli.Context) error {
+				neededScopes = needs
+				if before != nil {
+					return before(c)
+				}
+				return nil
+			}
+		}
+		addScopePreflight(cmd.Subcommands, path)
+	}
+}
+
+// checkScopes fails with the missing permission and how to get it, rather
+// than letting the API answer PERMISSION_DENIED partway through a command.
+// Tokens whose scopes aren't known yet are let through.
+func checkScopes(cfg *config.Config) error {
+	granted := auth.GrantedScopes(cfg.Auth.TokenFile)
+	if granted == nil {
+		granted = cfg.Auth.Scopes
+	}
+	if granted == nil {
+		return nil
+	}
+	missing := auth.Missing(granted, neededScopes...)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	what := make([]string, len(missing))
+	for i, n := range missing {
+		what[i] = fmt.Sprintf("%s (%s)", n.What, auth.ShortScope(n.Scopes[0]))
+	}
+	if cfg.ReadOnly {
+		return fmt.Errorf("the read-only profile %q can't %s", cfg.Profile, strings.Join(what, " or "))
+	}
+	return fmt.Errorf("this command needs permission to %s, which your sign-in doesn't include; run '%s' to grant it",
+		strings.Join(what, " and "), loginCommand(cfg))
+}
+
+func loginCommand(cfg *config.Config) string {
+	if cfg.Profile != "" {
+		return "gc-cli --profile " + cfg.Profile + " auth login"
+	}
+	return "gc-cli auth login"
+}
+
+// explainMissingScope is printed after the API refuses a request for want
+// of a scope that the preflight couldn't check.
+func explainMissingScope(cfg *config.Config) string {
+	return fmt.Sprintf("\nYour sign-in doesn't include a permission this needs, usually because it\n"+
+		"predates a gc-cli feature. Run '%s' to grant it; 'gc-cli auth status'\n"+
+		"shows what's missing.\n", loginCommand(cfg))
+}
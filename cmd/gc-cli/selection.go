@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// selectionTTL is how long the row numbers of the last listing can be used
+// in place of IDs. Past that the listing has likely scrolled away or changed.
+const selectionTTL = time.Hour
+
+// openStore opens the local state store for helpers that don't have the
+// config to hand. It is set once the config is final.
+var openStore func() (*store.Store, error)
+
+// saveSelection remembers the rows of a numbered listing so they can be
+// referred to as %1, %2, ... until the next one.
+func saveSelection(items []store.SelectedItem) error {
+	st, err := openStore()
+	if err != nil {
+		return err
+	}
+	return st.SaveSelection(&store.Selection{Saved: time.Now(), Items: items})
+}
+
+// selectionRef parses a row reference such as "%3".
+func selectionRef(s string) (int, bool) {
+	if !strings.HasPrefix(s, "%") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[1:])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// selectedItem returns row n (1-based) of the last listing.
+func selectedItem(n int) (store.SelectedItem, error) {
+	st, err := openStore()
+	if err != nil {
+		return store.SelectedItem{}, err
+	}
+	sel, err := st.Selection()
+	if err != nil {
+		return store.SelectedItem{}, err
+	}
+	switch {
+	case len(sel.Items) == 0:
+		return store.SelectedItem{}, fmt.Errorf("no numbered listing to refer to; run 'gc-cli coursework list' first")
+	case time.Since(sel.Saved) > selectionTTL:
+		return store.SelectedItem{}, fmt.Errorf("row numbers from the last listing have expired; run 'gc-cli coursework list' again")
+	case n < 1 || n > len(sel.Items):
+		return store.SelectedItem{}, fmt.Errorf("%%%d is out of range; the last listing had %d row(s)", n, len(sel.Items))
+	}
+	return sel.Items[n-1], nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/urfave/cli/v2"
+)
+
+func SelftestCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "selftest",
+		Usage:  "check which Classroom API capabilities this account has, using a sandbox course",
+		Action: handleSelftest(cfg),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "course",
+				Usage:    "ID of a course that is safe to test against",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "write",
+				Usage: "also create and delete a draft announcement (teachers only)",
+			},
+		},
+	}
+}
+
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkFail
+	checkSkip
+)
+
+type selftestCheck struct {
+	Name   string
+	Status checkStatus
+	Detail string
+}
+
+func handleSelftest(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+		courseID, err := optionalCourseArg(c)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		var checks []selftestCheck
+		record := func(name string, err error, detail string) bool {
+			if err != nil {
+				checks = append(checks, selftestCheck{Name: name, Status: checkFail, Detail: describeCheckError(err)})
+				return false
+			}
+			checks = append(checks, selftestCheck{Name: name, Status: checkPass, Detail: detail})
+			return true
+		}
+		skip := func(name, reason string) {
+			checks = append(checks, selftestCheck{Name: name, Status: checkSkip, Detail: reason})
+		}
+
+		courses, _, err := client.ListCourses(ctx, 0)
+		record("list courses", err, fmt.Sprintf("%d courses", len(courses)))
+
+		course, err := client.GetCourse(ctx, courseID)
+		teacher := false
+		if record("get course", err, "") {
+			role, err := courseRole(ctx, cfg, client, courseID)
+			if err != nil {
+				role = "unknown role"
+			}
+			teacher = role == store.RoleTeacher
+			checks[len(checks)-1].Detail = fmt.Sprintf("%s (as %s)", course.Name, role)
+		}
+
+		coursework, _, err := client.ListCourseWork(ctx, courseID, 0)
+		record("list coursework", err, fmt.Sprintf("%d items", len(coursework)))
+
+		if len(coursework) == 0 {
+			skip("list submissions", "no coursework to read submissions from")
+		} else {
+			subs, _, err := client.ListStudentSubmissions(ctx, courseID, coursework[0].ID, 0)
+			record("list submissions", err, fmt.Sprintf("%d for %q", len(subs), coursework[0].Title))
+		}
+
+		announcements, _, err := client.ListAnnouncements(ctx, courseID, 0)
+		record("list announcements", err, fmt.Sprintf("%d announcements", len(announcements)))
+
+		switch {
+		case !c.Bool("write"):
+			skip("create draft announcement", "pass --write to test")
+		case cfg.ReadOnly:
+			skip("create draft announcement", "profile is read-only")
+		case course == nil:
+			skip("create draft announcement", "course could not be read")
+		case !teacher:
+			skip("create draft announcement", "only teachers can post announcements")
+		default:
+			created, err := client.CreateAnnouncement(ctx, courseID, &api.AnnouncementCreate{
+				Text:  fmt.Sprintf("gc-cli selftest %s (safe to delete)", time.Now().Format(time.RFC3339)),
+				State: api.AnnouncementDraft,
+			})
+			if record("create draft announcement", err, "") {
+				checks[len(checks)-1].Detail = created.ID
+				err := client.DeleteAnnouncement(ctx, courseID, created.ID)
+				record("delete draft announcement", err, created.ID)
+			}
+		}
+
+		failed := printSelftest(checks)
+		if failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+		}
+		return nil
+	}
+}
+
+func describeCheckError(err error) string {
+	switch {
+	case errors.Is(err, api.ErrReadOnly):
+		return "refused: profile is read-only"
+	case api.IsAPIDisabled(err):
+		return "blocked by your school's admin"
+	case api.IsForbidden(err):
+		return "permission denied (missing scope or role)"
+	case api.IsNotFound(err):
+		return "not found (check the course ID)"
+	}
+	return err.Error()
+}
+
+func printSelftest(checks []selftestCheck) int {
+	passStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	failStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	failed := 0
+	for _, check := range checks {
+		var mark string
+		switch check.Status {
+		case checkPass:
+			mark = passStyle.Render("✓")
+		case checkFail:
+			mark = failStyle.Render("✗")
+			failed++
+		case checkSkip:
+			mark = separatorStyle.Render("-")
+		}
+		fmt.Printf("%s %-28s %s\n", mark, check.Name, check.Detail)
+	}
+	return failed
+}
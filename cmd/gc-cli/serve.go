@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/timboy697/gc-cli/internal/serve"
+	"github.com/urfave/cli/v2"
+)
+
+func ServeCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "serve synced data to other programs",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "http",
+				Usage: "serve a read-only JSON API (/courses, /upcoming, /grades) from synced data",
+				Description: "Data comes from 'gc-cli sync', so schedule a sync to keep it fresh.\n" +
+					"Clients send a key as 'Authorization: Bearer KEY', an X-API-Key header\n" +
+					"or an api_key query parameter.",
+				Action: handleServeHTTP(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "address to listen on (use :8787 to accept other devices on your network)",
+						Value: "127.0.0.1:8787",
+					},
+					apiKeyFlag(),
+				},
+			},
+			{
+				Name:  "grpc",
+				Usage: "serve the same data over gRPC, with submissions and a Watch stream of changes",
+				Description: "The service is defined in proto/gccli/serve/v1/serve.proto. It's plaintext\n" +
+					"HTTP/2, so keep it on localhost or behind a TLS proxy. Clients send a key as\n" +
+					"'authorization: Bearer KEY' metadata. Watch picks up a new sync within a few\n" +
+					"seconds.",
+				Action: handleServeGRPC(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "listen",
+						Usage: "address to listen on",
+						Value: "127.0.0.1:8789",
+					},
+					apiKeyFlag(),
+				},
+			},
+		},
+	}
+}
+
+func apiKeyFlag() cli.Flag {
+	return &cli.StringSliceFlag{
+		Name:    "api-key",
+		Usage:   "accepted API key, or KEY=scope,scope to allow only some of courses, upcoming and grades (repeatable)",
+		EnvVars: []string{"GC_CLI_API_KEY"},
+	}
+}
+
+func handleServeHTTP(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		server, keys, err := newServeServer(c, cfg)
+		if err != nil {
+			return err
+		}
+		addr := c.String("listen")
+		fmt.Printf("Serving read-only API on %s with %d key(s)\n", addr, keys)
+		return http.ListenAndServe(addr, server.Handler())
+	}
+}
+
+func handleServeGRPC(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		server, keys, err := newServeServer(c, cfg)
+		if err != nil {
+			return err
+		}
+		addr := c.String("listen")
+		fmt.Printf("Serving read-only gRPC API on %s with %d key(s)\n", addr, keys)
+		return serve.ListenAndServeGRPC(addr, server.GRPCHandler())
+	}
+}
+
+// newServeServer reads the --api-key flags and sets up a server over the
+// synced data, returning it with how many keys it accepts.
+func newServeServer(c *cli.Context, cfg *config.Config) (*serve.Server, int, error) {
+	var keys []serve.Key
+	for _, s := range c.StringSlice("api-key") {
+		key, err := serve.ParseKey(s)
+		if err != nil {
+			return nil, 0, err
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, 0, fmt.Errorf("at least one --api-key is required")
+	}
+
+	st, err := cfg.Store()
+	if err != nil {
+		return nil, 0, err
+	}
+	snap, err := offline.Load(st)
+	if err != nil {
+		return nil, 0, err
+	}
+	if snap.SyncedAt.IsZero() {
+		fmt.Fprintln(os.Stderr, "Warning: nothing synced yet; requests will fail until you run 'gc-cli sync'.")
+	}
+
+	server := serve.NewServer(st, keys, gradebook.NewScale(cfg.Grades.Scale), clk, courseorder.New(cfg.Courses))
+	return server, len(keys), nil
+}
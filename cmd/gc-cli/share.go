@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func ShareCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "share",
+		Usage: "share my submission's Drive files with teammates",
+		Description: "A normal sign-in only lets gc-cli reach Drive files it uploaded itself\n" +
+			"(with 'submit'). To share files you attached in Classroom in the\n" +
+			"browser, sign in with 'gc-cli auth login --drive' first.",
+		Action: handleShare(cfg),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "course",
+				Usage: "course ID",
+			},
+			&cli.StringFlag{
+				Name:     "assignment",
+				Usage:    "assignment ID",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "emails",
+				Usage:    "comma-separated teammate email addresses",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "role",
+				Usage: "access to grant: commenter or writer",
+				Value: "commenter",
+			},
+			&cli.BoolFlag{
+				Name:  "notify",
+				Usage: "have Drive email teammates about the share",
+			},
+		},
+	}
+}
+
+func handleShare(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		role := c.String("role")
+		if role != "commenter" && role != "writer" {
+			return fmt.Errorf("invalid role %q (want commenter or writer)", role)
+		}
+
+		var emails []string
+		for _, e := range strings.Split(c.String("emails"), ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				emails = append(emails, e)
+			}
+		}
+		if len(emails) == 0 {
+			return fmt.Errorf("at least one email address required")
+		}
+
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		submission, err := client.GetMySubmission(ctx, courseID, assignmentArg(c))
+		if err != nil {
+			return fmt.Errorf("failed to get submission: %w", err)
+		}
+
+		attachments, err := submission.Attachments()
+		if err != nil {
+			return err
+		}
+
+		var files []*api.DriveFile
+		for _, a := range attachments {
+			if a.DriveFile != nil && a.DriveFile.ID != "" {
+				files = append(files, a.DriveFile)
+			}
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("submission has no Drive files to share")
+		}
+
+		var shared, failed int
+		for _, f := range files {
+			for _, email := range emails {
+				_, err := client.CreatePermission(ctx, f.ID, api.Permission{
+					Type:         "user",
+					Role:         role,
+					EmailAddress: email,
+				}, c.Bool("notify"))
+				if err != nil {
+					failed++
+					if api.IsForbidden(err) || api.IsNotFound(err) {
+						err = fmt.Errorf("%w (%s)", err, shareHint(cfg))
+					}
+					fmt.Printf("✗ %s → %s: %v\n", f.Title, email, err)
+					continue
+				}
+				shared++
+			}
+			fmt.Printf("%s\n  %s\n", f.Title, f.AlternateLink)
+		}
+
+		fmt.Printf("\nGranted %s access %d time(s) across %d file(s)\n", role, shared, len(files))
+		if failed > 0 {
+			return fmt.Errorf("%d share(s) failed", failed)
+		}
+		return nil
+	}
+}
+
+// shareHint explains a file Drive wouldn't let me share: without the full
+// Drive scope gc-cli can only reach files it uploaded, which signing in
+// again with --drive fixes; with it, the file isn't mine to share.
+func shareHint(cfg *config.Config) string {
+	if granted := auth.GrantedScopes(cfg.Auth.TokenFile); granted != nil && len(auth.Missing(granted, auth.NeedAllDrive)) == 0 {
+		return "only files you own can be shared"
+	}
+	return fmt.Sprintf("gc-cli can only share files it uploaded; run '%s --drive' to share ones attached in Classroom", loginCommand(cfg))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+func ShortcutsCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "shortcuts",
+		Usage: "list the shortcuts defined in the config file",
+		Description: "A shortcut is a name for a command line, set under shortcuts: in the config\n" +
+			"file. 'gc-cli hw --json' runs the command hw stands for with --json added.",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list shortcuts and what they expand to",
+				Action: handleShortcutsList(cfg),
+			},
+		},
+	}
+}
+
+func handleShortcutsList(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if len(cfg.Shortcuts) == 0 {
+			fmt.Println("No shortcuts defined; add them under shortcuts: in " + cfg.ConfigPath)
+			return nil
+		}
+
+		names := make([]string, 0, len(cfg.Shortcuts))
+		for name := range cfg.Shortcuts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			line := fmt.Sprintf("%-15s gc-cli %s", name, cfg.Shortcuts[name])
+			if c.App.Command(name) != nil {
+				line += "  (unused: a built-in command has this name)"
+			}
+			fmt.Println(line)
+		}
+		return nil
+	}
+}
+
+// expandShortcut replaces a shortcut given where the command would be with
+// the words it stands for, keeping the global flags before it and the
+// arguments after it. Built-in commands win over shortcuts of the same name,
+// and an expansion isn't expanded again.
+func expandShortcut(app *cli.App, shortcuts map[string]string, args []string) ([]string, error) {
+	if len(shortcuts) == 0 {
+		return args, nil
+	}
+	app.Setup()
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			if !strings.Contains(arg, "=") && flagTakesValue(app.Flags, strings.TrimLeft(arg, "-")) {
+				i++
+			}
+			continue
+		}
+
+		expansion, ok := shortcuts[arg]
+		if !ok || app.Command(arg) != nil {
+			break
+		}
+		words, err := splitWords(expansion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shortcut %q: %w", arg, err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("shortcut %q is empty", arg)
+		}
+
+		expanded := append([]string{}, args[:i]...)
+		expanded = append(expanded, words...)
+		return append(expanded, args[i+1:]...), nil
+	}
+	return args, nil
+}
+
+// flagTakesValue reports whether the flag called name reads the next
+// argument as its value.
+func flagTakesValue(flags []cli.Flag, name string) bool {
+	for _, f := range flags {
+		for _, n := range f.Names() {
+			if n != name {
+				continue
+			}
+			_, isBool := f.(*cli.BoolFlag)
+			return !isBool
+		}
+	}
+	return false
+}
+
+// splitWords splits s on spaces like a shell would, keeping quoted text
+// together, so a shortcut can pass a course name such as "AP Physics".
+func splitWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unclosed %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
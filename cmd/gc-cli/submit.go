@@ -2,69 +2,241 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/timboy697/gc-cli/internal/api"
-	"github.com/timboy697/gc-cli/internal/auth"
 	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/drive"
+	"github.com/timboy697/gc-cli/internal/output"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/timboy697/gc-cli/internal/tui"
 	"github.com/urfave/cli/v2"
 )
 
 func SubmitCmd(cfg *config.Config) *cli.Command {
 	return &cli.Command{
 		Name:  "submit",
-		Usage: "submit an assignment for a course",
+		Usage: "attach a file to an assignment, and turn it in with --turn-in",
 		Action: func(c *cli.Context) error {
 			return handleSubmit(context.Background(), cfg, c)
 		},
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:     "course",
-				Usage:    "course ID",
-				Required: true,
+				Name:  "course",
+				Usage: "course ID",
 			},
 			&cli.StringFlag{
-				Name:     "assignment",
-				Usage:    "assignment (coursework) ID",
-				Required: true,
+				Name:  "assignment",
+				Usage: "assignment (coursework) ID",
 			},
 			&cli.StringFlag{
-				Name:     "file",
-				Usage:    "path to file to submit",
-				Required: true,
+				Name:  "file",
+				Usage: "path to file to submit",
+			},
+			&cli.BoolFlag{
+				Name:  "turn-in",
+				Usage: "turn the assignment in once the file is attached",
 			},
 			&cli.BoolFlag{
 				Name:  "json",
-				Usage: "output as JSON",
+				Usage: "output the submission as JSON (progress goes to stderr)",
+			},
+			&cli.StringFlag{
+				Name:  "resume-op",
+				Usage: "finish an interrupted submit (see 'submit journal')",
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:      "stage",
+				Usage:     "attach files to your submission without turning it in",
+				ArgsUsage: "<file> [file...]",
+				Flags:     submissionFlags(),
+				Action: func(c *cli.Context) error {
+					return handleSubmitStage(context.Background(), cfg, c)
+				},
 			},
+			{
+				Name:   "status",
+				Usage:  "show the state and staged attachments of your submission",
+				Flags:  append(submissionFlags(), outputFlags()...),
+				Action: handleSubmitStatus(cfg),
+			},
+			{
+				Name:   "journal",
+				Usage:  "list submits that were interrupted before turning in",
+				Action: handleSubmitJournal(cfg),
+			},
+			{
+				Name:  "finalize",
+				Usage: "turn in your submission with its staged attachments",
+				Flags: submissionFlags(),
+				Action: func(c *cli.Context) error {
+					return handleSubmitFinalize(context.Background(), cfg, c)
+				},
+			},
+		},
+	}
+}
+
+func submissionFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "course",
+			Usage: "course ID",
+		},
+		&cli.StringFlag{
+			Name:     "assignment",
+			Usage:    "assignment (coursework) ID",
+			Required: true,
 		},
 	}
 }
 
 func handleSubmit(ctx context.Context, cfg *config.Config, c *cli.Context) error {
-	courseID := c.String("course")
-	assignmentID := c.String("assignment")
+	if c.String("resume-op") != "" {
+		return handleSubmitResume(ctx, cfg, c)
+	}
+
+	courseID, err := courseArg(c)
+	if err != nil {
+		return err
+	}
+	assignmentID := assignmentArg(c)
 	filePath := c.String("file")
 
+	if courseID == "" || assignmentID == "" || filePath == "" {
+		return fmt.Errorf("--course, --assignment and --file are required (or use 'submit stage' / 'submit finalize')")
+	}
+
 	if err := validateFile(filePath); err != nil {
 		return err
 	}
 
-	fmt.Printf("Preparing to submit: %s\n", filePath)
-	fmt.Printf("Course: %s, Assignment: %s\n", courseID, assignmentID)
+	r := submitReporterFor(c)
+	r.Step(fmt.Sprintf("Preparing to submit: %s", filePath))
+	r.Step(fmt.Sprintf("Course: %s, Assignment: %s", displayID(courseID), displayID(assignmentID)))
+
+	client, err := newAPIClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	updatedSubmission, err := submitFile(ctx, cfg, client, courseID, assignmentID, filePath, c.Bool("turn-in"), r)
+	if err != nil {
+		return err
+	}
+	return printSubmitResult(c, updatedSubmission)
+}
+
+// submitReporterFor prints a submit's progress on stdout, or on stderr under
+// --json so that stdout holds nothing but the submission.
+func submitReporterFor(c *cli.Context) printReporter {
+	if c.Bool("json") {
+		return printReporter{w: os.Stderr}
+	}
+	return printReporter{}
+}
+
+// printSubmitResult shows the submission a submit or resume left behind.
+func printSubmitResult(c *cli.Context, submission *api.StudentSubmission) error {
+	if c.Bool("json") {
+		return outputSubmissionJSON(submission)
+	}
+	if submission.State.Done() {
+		fmt.Printf("\n✓ Submission successful!\n")
+	} else {
+		fmt.Printf("\n✓ Attached. Not turned in yet: run 'gc-cli submit finalize' when you're ready.\n")
+	}
+	fmt.Printf("Submission ID: %s\n", submission.ID)
+	fmt.Printf("State: %s\n", submission.State)
+	return nil
+}
+
+func handleSubmitResume(ctx context.Context, cfg *config.Config, c *cli.Context) error {
+	id := c.String("resume-op")
+
+	st, err := cfg.Store()
+	if err != nil {
+		return err
+	}
+	journal, err := st.SubmitJournal()
+	if err != nil {
+		return err
+	}
+	op, ok := journal.Ops[id]
+	if !ok {
+		return fmt.Errorf("no unfinished submit operation %s (see 'gc-cli submit journal')", id)
+	}
+
+	r := submitReporterFor(c)
+	r.Step(fmt.Sprintf("Resuming %s: %s for assignment %s (next step: %s)", op.ID, getFileName(op.FilePath), displayID(op.CourseWorkID), op.Step()))
+
+	client, err := newAPIClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
 
-	token, err := auth.GetValidToken(ctx, auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile))
+	updatedSubmission, err := runSubmitOp(ctx, st, journal, op, client, true, r)
 	if err != nil {
-		return fmt.Errorf("authentication required: %w", err)
+		return err
+	}
+	return printSubmitResult(c, updatedSubmission)
+}
+
+func handleSubmitJournal(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		journal, err := st.SubmitJournal()
+		if err != nil {
+			return err
+		}
+
+		pending := journal.Pending()
+		if len(pending) == 0 {
+			fmt.Println("No unfinished submissions")
+			return nil
+		}
+		for _, op := range pending {
+			fmt.Printf("%-6s %s → %s/%s (started %s, next: %s)\n",
+				op.ID, getFileName(op.FilePath), displayID(op.CourseID), displayID(op.CourseWorkID),
+				op.Started.Format("Jan 2 15:04"), op.Step())
+		}
+		fmt.Println("\nFinish one with: gc-cli submit --resume-op <id>")
+		return nil
 	}
+}
 
-	authCfg := auth.NewConfig(cfg.Auth.ClientID, cfg.Auth.ClientSecret, cfg.Auth.TokenFile)
-	client, err := api.NewClientFromToken(ctx, authCfg.OAuth2Config(), token)
+func handleSubmitStage(ctx context.Context, cfg *config.Config, c *cli.Context) error {
+	courseID, err := requireCourse(c)
 	if err != nil {
-		return fmt.Errorf("failed to create API client: %w", err)
+		return err
+	}
+	assignmentID := assignmentArg(c)
+
+	if c.Args().Len() < 1 {
+		return fmt.Errorf("at least one file to stage is required")
+	}
+	for _, filePath := range c.Args().Slice() {
+		if err := validateFile(filePath); err != nil {
+			return err
+		}
+	}
+
+	client, err := newAPIClient(ctx, cfg)
+	if err != nil {
+		return err
 	}
 
 	submission, err := client.GetMySubmission(ctx, courseID, assignmentID)
@@ -72,52 +244,398 @@ func handleSubmit(ctx context.Context, cfg *config.Config, c *cli.Context) error
 		return fmt.Errorf("failed to get your submission: %w", err)
 	}
 
-	fmt.Printf("Current submission state: %s\n", submission.State)
+	if submission.State.Done() {
+		return fmt.Errorf("submission is already %s; unsubmit it in Classroom before staging more files", submission.State)
+	}
+
+	for _, filePath := range c.Args().Slice() {
+		submission, err = attachFile(ctx, client, courseID, assignmentID, submission, filePath)
+		if err != nil {
+			return err
+		}
+		recordAudit(cfg, store.AuditAttach, courseID, assignmentID, submission.ID, filePath)
+		fmt.Printf("✓ Staged %s\n", getFileName(filePath))
+	}
+
+	fmt.Println("\nNot turned in yet. Run 'gc-cli submit finalize' when you're ready.")
+	return nil
+}
+
+func handleSubmitStatus(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+		format, err := outputFormat(c)
+		if err != nil {
+			return err
+		}
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
 
-	fileData, err := os.ReadFile(filePath)
+		submission, err := client.GetMySubmission(ctx, courseID, assignmentArg(c))
+		if err != nil {
+			return fmt.Errorf("failed to get your submission: %w", err)
+		}
+
+		switch format {
+		case output.JSON, output.YAML:
+			return output.WriteOne(os.Stdout, format, submission)
+		case output.CSV, output.TSV:
+			return writeOutput(format, []api.StudentSubmission{*submission}, submissionColumns)
+		}
+
+		attachments, err := submission.Attachments()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Submission ID: %s\n", submission.ID)
+		fmt.Printf("State: %s\n", submission.State)
+		if len(attachments) == 0 {
+			fmt.Println("No staged attachments")
+			return nil
+		}
+
+		fmt.Printf("\nAttachments (%d):\n", len(attachments))
+		for _, a := range attachments {
+			title, link := attachmentSummary(a)
+			fmt.Printf("  • %s\n    %s\n", title, link)
+		}
+		return nil
+	}
+}
+
+func handleSubmitFinalize(ctx context.Context, cfg *config.Config, c *cli.Context) error {
+	courseID, err := requireCourse(c)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return err
 	}
+	assignmentID := assignmentArg(c)
 
-	fileSize := len(fileData)
-	fmt.Printf("Uploading file (%d bytes)...\n", fileSize)
+	client, err := newAPIClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
 
-	attachment := api.Attachment{
-		DriveFile: &api.DriveFile{
-			Title:         getFileName(filePath),
-			AlternateLink: "https://drive.google.com/file/d placeholder",
-		},
+	submission, err := client.GetMySubmission(ctx, courseID, assignmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get your submission: %w", err)
+	}
+
+	if submission.State == api.SubmissionTurnedIn {
+		fmt.Println("Submission is already turned in")
+		return nil
+	}
+
+	attachments, err := submission.Attachments()
+	if err != nil {
+		return err
 	}
+	if len(attachments) == 0 {
+		fmt.Println("Warning: turning in without any attachments")
+	}
+
+	if err := client.TurnIn(ctx, courseID, assignmentID, submission.ID); err != nil {
+		return fmt.Errorf("turn in failed: %w", err)
+	}
+	recordAudit(cfg, store.AuditTurnIn, courseID, assignmentID, submission.ID, "")
 
-	attachments := []api.Attachment{attachment}
+	fmt.Printf("✓ Turned in %d attachment(s)\n", len(attachments))
+	return nil
+}
 
-	assignmentSub := api.AssignmentSubmission{
-		Attachments: attachments,
+// submitReporter is told how a submit is going. The CLI prints it; the TUI
+// shows it in its submit view.
+type submitReporter interface {
+	Step(msg string)
+	Uploaded(name string, sent, total int64)
+}
+
+// printReporter prints to w, or to stdout if w is nil.
+type printReporter struct {
+	w io.Writer
+}
+
+func (p printReporter) out() io.Writer {
+	if p.w == nil {
+		return os.Stdout
 	}
+	return p.w
+}
 
-	assignmentSubJSON, err := json.Marshal(assignmentSub)
+func (p printReporter) Step(msg string) {
+	fmt.Fprintln(p.out(), msg)
+}
+
+func (p printReporter) Uploaded(name string, sent, total int64) {
+	if total == 0 {
+		return
+	}
+	fmt.Fprintf(p.out(), "\rUploading %s: %d%%", name, sent*100/total)
+	if sent == total {
+		fmt.Fprintln(p.out())
+	}
+}
+
+// tuiReporter passes a submit's progress to the TUI's submit view.
+type tuiReporter func(tui.SubmitProgress)
+
+func (r tuiReporter) Step(msg string) {
+	r(tui.SubmitProgress{Step: msg})
+}
+
+func (r tuiReporter) Uploaded(name string, sent, total int64) {
+	r(tui.SubmitProgress{Sent: sent, Total: total})
+}
+
+// tuiSubmit submits from the TUI the same way 'gc-cli submit' does,
+// journal and audit log included.
+func tuiSubmit(cfg *config.Config) tui.SubmitFunc {
+	return func(ctx context.Context, courseID, courseWorkID, path string, progress func(tui.SubmitProgress)) error {
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		_, err = submitFile(ctx, cfg, client, courseID, courseWorkID, path, true, tuiReporter(progress))
+		return err
+	}
+}
+
+// submitFile attaches filePath to my submission and, if turnIn is set, turns
+// it in, journaling each step so an interrupted run can be finished with
+// --resume-op.
+func submitFile(ctx context.Context, cfg *config.Config, client *api.Client, courseID, assignmentID, filePath string, turnIn bool, r submitReporter) (*api.StudentSubmission, error) {
+	st, err := cfg.Store()
+	if err != nil {
+		return nil, err
+	}
+	journal, err := st.SubmitJournal()
 	if err != nil {
-		return fmt.Errorf("failed to marshal assignment submission: %w", err)
+		return nil, err
 	}
 
-	update := &api.SubmissionUpdate{
-		AssignmentSubmission: assignmentSubJSON,
+	op := journal.Begin(courseID, assignmentID, filePath, time.Now())
+	op.StageOnly = !turnIn
+	if err := st.SaveSubmitJournal(journal); err != nil {
+		return nil, err
 	}
+	r.Step(fmt.Sprintf("Operation %s (if interrupted, finish with: gc-cli submit --resume-op %s)", op.ID, op.ID))
 
-	updatedSubmission, err := client.PatchStudentSubmission(ctx, courseID, assignmentID, submission.ID, update)
+	return runSubmitOp(ctx, st, journal, op, client, false, r)
+}
+
+// runSubmitOp performs whichever steps of op are still outstanding, saving
+// the journal after each one. When resuming, the submission is inspected
+// first in case a step finished but wasn't recorded.
+func runSubmitOp(ctx context.Context, st *store.Store, journal *store.SubmitJournal, op *store.SubmitOp, client *api.Client, resuming bool, r submitReporter) (*api.StudentSubmission, error) {
+	save := func() error {
+		op.Updated = time.Now()
+		return st.SaveSubmitJournal(journal)
+	}
+
+	submission, err := client.GetMySubmission(ctx, op.CourseID, op.CourseWorkID)
 	if err != nil {
-		return fmt.Errorf("upload failed: %w", err)
+		return nil, fmt.Errorf("failed to get your submission: %w", err)
 	}
+	r.Step(fmt.Sprintf("Current submission state: %s", submission.State))
 
-	fmt.Printf("\n✓ Submission successful!\n")
-	fmt.Printf("Submission ID: %s\n", updatedSubmission.ID)
-	fmt.Printf("State: %s\n", updatedSubmission.State)
+	op.SubmissionID = submission.ID
+	turnedIn := submission.State.Done()
 
-	if c.Bool("json") {
-		return outputSubmissionJSON(updatedSubmission)
+	if !op.Attached && resuming {
+		attached, err := hasOpAttachment(submission, op)
+		if err != nil {
+			return nil, err
+		}
+		op.Attached = attached || turnedIn
+	}
+	if !op.Attached {
+		if op.DriveFileID == "" {
+			file, err := uploadFile(ctx, client, op.FilePath, op.UploadURL, r, func(sessionURL string) error {
+				op.UploadURL = sessionURL
+				return save()
+			})
+			if err != nil {
+				return nil, err
+			}
+			op.DriveFileID = file.ID
+			op.UploadURL = ""
+			if err := save(); err != nil {
+				return nil, err
+			}
+		}
+		if submission, err = attachDriveFile(ctx, client, op.CourseID, op.CourseWorkID, submission.ID, op.DriveFileID); err != nil {
+			return nil, err
+		}
+		op.Attached = true
+		auditTo(st, store.AuditAttach, op.CourseID, op.CourseWorkID, submission.ID, op.FilePath)
+		if err := save(); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	if op.StageOnly {
+		delete(journal.Ops, op.ID)
+		if err := st.SaveSubmitJournal(journal); err != nil {
+			return nil, err
+		}
+		return submission, nil
+	}
+
+	if !op.TurnedIn && !(resuming && turnedIn) {
+		r.Step("Turning in...")
+		if err := client.TurnIn(ctx, op.CourseID, op.CourseWorkID, submission.ID); err != nil {
+			return nil, fmt.Errorf("turn in failed: %w", err)
+		}
+		auditTo(st, store.AuditTurnIn, op.CourseID, op.CourseWorkID, submission.ID, "")
+		submission.State = api.SubmissionTurnedIn
+	}
+	op.TurnedIn = true
+
+	delete(journal.Ops, op.ID)
+	if err := st.SaveSubmitJournal(journal); err != nil {
+		return nil, err
+	}
+	return submission, nil
+}
+
+// hasOpAttachment checks whether the op's file is already on the submission,
+// by Drive file ID when known and otherwise by name.
+func hasOpAttachment(submission *api.StudentSubmission, op *store.SubmitOp) (bool, error) {
+	attachments, err := submission.Attachments()
+	if err != nil {
+		return false, err
+	}
+	name := getFileName(op.FilePath)
+	for _, a := range attachments {
+		if a.DriveFile == nil {
+			continue
+		}
+		if op.DriveFileID != "" && a.DriveFile.ID == op.DriveFileID {
+			return true, nil
+		}
+		if op.DriveFileID == "" && a.DriveFile.Title == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// attachFile uploads a local file to Drive and adds it to the submission,
+// returning the updated submission.
+func attachFile(ctx context.Context, client *api.Client, courseID, assignmentID string, submission *api.StudentSubmission, filePath string) (*api.StudentSubmission, error) {
+	file, err := uploadFile(ctx, client, filePath, "", printReporter{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return attachDriveFile(ctx, client, courseID, assignmentID, submission.ID, file.ID)
+}
+
+func attachDriveFile(ctx context.Context, client *api.Client, courseID, assignmentID, submissionID, fileID string) (*api.StudentSubmission, error) {
+	attachments := []api.Attachment{{DriveFile: &api.DriveFile{ID: fileID}}}
+	updatedSubmission, err := client.ModifyAttachments(ctx, courseID, assignmentID, submissionID, attachments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach uploaded file: %w", err)
+	}
+	return updatedSubmission, nil
+}
+
+// uploadFile uploads filePath to My Drive, reporting progress to r.
+// sessionURL continues an earlier upload; otherwise a new session is started
+// and passed to started, if set, so it can be saved for resuming. An expired
+// session is started over.
+func uploadFile(ctx context.Context, client *api.Client, filePath, sessionURL string, r submitReporter, started func(string) error) (*drive.File, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	name := getFileName(filePath)
+	up := drive.NewUploader(client.HTTPClient())
+	up.Progress = func(sent, total int64) {
+		r.Uploaded(name, sent, total)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if sessionURL == "" {
+			r.Step(fmt.Sprintf("Uploading %s (%d bytes)...", name, info.Size()))
+			if sessionURL, err = up.Start(ctx, name, info.Size()); err != nil {
+				return nil, err
+			}
+			if started != nil {
+				if err := started(sessionURL); err != nil {
+					return nil, err
+				}
+			}
+		}
+		file, err := up.Upload(ctx, sessionURL, f, info.Size())
+		if errors.Is(err, drive.ErrSessionExpired) && attempt == 0 {
+			sessionURL = ""
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return file, nil
+	}
+}
+
+// recordAudit notes a change to my submission in the audit log, which
+// 'gc-cli evidence' includes in its bundle. A failure to record is only
+// warned about: the submit itself has already happened.
+func recordAudit(cfg *config.Config, action, courseID, courseWorkID, submissionID, filePath string) {
+	st, err := cfg.Store()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write audit log: %v\n", err)
+		return
+	}
+	auditTo(st, action, courseID, courseWorkID, submissionID, filePath)
+}
+
+func auditTo(st *store.Store, action, courseID, courseWorkID, submissionID, filePath string) {
+	entry := store.AuditEntry{
+		Time:         time.Now(),
+		Action:       action,
+		CourseID:     courseID,
+		CourseWorkID: courseWorkID,
+		SubmissionID: submissionID,
+	}
+	if filePath != "" {
+		entry.File = getFileName(filePath)
+		if data, err := os.ReadFile(filePath); err == nil {
+			sum := sha256.Sum256(data)
+			entry.Size = int64(len(data))
+			entry.SHA256 = hex.EncodeToString(sum[:])
+		}
+	}
+	if err := st.Audit(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not write audit log: %v\n", err)
+	}
+}
+
+func attachmentSummary(a api.Attachment) (title, link string) {
+	switch {
+	case a.DriveFile != nil:
+		return a.DriveFile.Title, a.DriveFile.AlternateLink
+	case a.Link != nil:
+		return a.Link.Title, a.Link.URL
+	case a.YouTubeVideo != nil:
+		return "YouTube video", a.YouTubeVideo.AlternateLink
+	case a.Form != nil:
+		return a.Form.Title, a.Form.FormURL
+	}
+	return "(unknown attachment)", "-"
 }
 
 func validateFile(filePath string) error {
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/urfave/cli/v2"
+)
+
+func SyncCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:   "sync",
+		Usage:  "download course data for offline reading",
+		Action: handleSync(cfg),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "deep",
+				Usage: "also fetch full assignment descriptions, materials and announcements",
+			},
+			&cli.BoolFlag{
+				Name:  "attachments",
+				Usage: "with --deep, download Drive attachments too",
+			},
+			&cli.IntFlag{
+				Name:  "max-size",
+				Usage: "skip attachments larger than this many MB (0 for no limit)",
+				Value: 25,
+			},
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:   "status",
+				Usage:  "show what is available offline",
+				Action: handleSyncStatus(cfg),
+			},
+		},
+	}
+}
+
+func handleSync(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.Bool("attachments") && !c.Bool("deep") {
+			return fmt.Errorf("--attachments requires --deep")
+		}
+		if c.Int("max-size") < 0 {
+			return fmt.Errorf("--max-size must not be negative")
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+
+		previous, err := offline.Load(st)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		opts := offline.Options{
+			Deep:        c.Bool("deep"),
+			Attachments: c.Bool("attachments"),
+			MaxFileSize: int64(c.Int("max-size")) << 20,
+			Dir:         filepath.Join(cfg.DataDir, "offline"),
+		}
+		snap, err := offline.Sync(ctx, client, opts, func(name string) {
+			fmt.Fprintf(os.Stderr, "Syncing %s...\n", name)
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := offline.Save(st, snap); err != nil {
+			return fmt.Errorf("failed to save offline data: %w", err)
+		}
+		changes := offline.Changes(previous, snap)
+		if err := offline.RecordChanges(st, changes); err != nil {
+			return fmt.Errorf("failed to save assignment history: %w", err)
+		}
+
+		fmt.Println()
+		if err := outputSyncSummary(snap); err != nil {
+			return err
+		}
+		outputSyncChanges(changes)
+		return nil
+	}
+}
+
+func handleSyncStatus(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		snap, err := offline.Load(st)
+		if err != nil {
+			return err
+		}
+		if snap.SyncedAt.IsZero() {
+			fmt.Println("Nothing synced yet. Run 'gc-cli sync --deep' before going offline.")
+			return nil
+		}
+		return outputSyncSummary(snap)
+	}
+}
+
+func outputSyncSummary(snap *offline.Snapshot) error {
+	nameWidth := 36
+	countWidth := 16
+	filesWidth := 20
+
+	header := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(nameWidth).Render("Course"),
+		headerStyle.Width(countWidth).Render("Coursework"),
+		headerStyle.Width(countWidth).Render("Announcements"),
+		headerStyle.Width(filesWidth).Render("Files"),
+	)
+	separator := separatorStyle.Render("─")
+
+	fmt.Println(header)
+	fmt.Println(lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		separator+separator+separator+separator,
+	))
+
+	var downloaded, skipped int
+	var bytes int64
+	for _, course := range snap.Courses {
+		var courseFiles int
+		for _, f := range course.Files {
+			if f.Path != "" {
+				courseFiles++
+				bytes += f.Size
+			} else {
+				skipped++
+			}
+		}
+		downloaded += courseFiles
+
+		announcements := "-"
+		files := "-"
+		if snap.Deep {
+			announcements = fmt.Sprintf("%d", len(course.Announcements))
+		}
+		if len(course.Files) > 0 {
+			files = fmt.Sprintf("%d of %d", courseFiles, len(course.Files))
+		}
+
+		row := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(nameWidth).Render(truncate(course.Course.Name, nameWidth-2)),
+			cellStyle.Width(countWidth).Render(fmt.Sprintf("%d", len(course.Coursework))),
+			cellStyle.Width(countWidth).Render(announcements),
+			cellStyle.Width(filesWidth).Render(files),
+		)
+		fmt.Println(row)
+	}
+
+	kind := "metadata only"
+	if snap.Deep {
+		kind = "deep"
+	}
+	fmt.Printf("\nSynced %s (%s)\n", snap.SyncedAt.Format(time.RFC1123), kind)
+	if downloaded > 0 {
+		fmt.Printf("Attachments: %d downloaded (%s)\n", downloaded, formatBytes(bytes))
+	}
+	if skipped > 0 {
+		fmt.Printf("Attachments: %d skipped (no access, Google Docs, or over the size limit)\n", skipped)
+	}
+	return nil
+}
+
+func outputSyncChanges(changes []offline.Change) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Printf("\n%d assignment(s) changed since the last sync:\n", len(changes))
+	for _, ch := range changes {
+		fmt.Printf("  %s  %s %s\n", displayID(ch.CourseWorkID), truncate(ch.After.Title, 40),
+			separatorStyle.Render("("+strings.Join(ch.Fields, ", ")+")"))
+	}
+	fmt.Println("Run 'gc-cli diff show <coursework-id>' to see what changed.")
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/roster"
+	"github.com/urfave/cli/v2"
+)
+
+// hideTeachCmd decides whether TeachCmd shows in help. Roles are cached per
+// account, so it runs once the store is scoped to the signed-in account;
+// --help is printed before that, and shows teach.
+func hideTeachCmd(app *cli.App, cfg *config.Config) {
+	if teach := app.Command("teach"); teach != nil {
+		teach.Hidden = !teachesAnything(cfg)
+	}
+}
+
+// TeachCmd is hidden from help once cached roles show I don't teach any
+// course; it still runs, and fails early with a clear message.
+func TeachCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "teach",
+		Usage: "teacher-only operations",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "coursework",
+				Usage: "manage coursework you teach",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "publish-now",
+						Usage:     "publish a scheduled or draft coursework item immediately",
+						ArgsUsage: "<coursework-id>",
+						Action:    handlePublishNow(cfg),
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "course",
+								Usage: "course ID",
+							},
+						},
+					},
+				},
+			},
+			{
+				Name:  "roster",
+				Usage: "work with the students in a course you teach",
+				Subcommands: []*cli.Command{
+					{
+						Name:   "export",
+						Usage:  "export students' names and emails as CSV or vCard",
+						Action: handleRosterExport(cfg),
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "course",
+								Usage: "course ID",
+							},
+							&cli.StringFlag{
+								Name:  "format",
+								Usage: "csv or vcard",
+								Value: roster.FormatCSV,
+							},
+							&cli.StringFlag{
+								Name:  "output",
+								Usage: "write to this file instead of stdout",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func handlePublishNow(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("coursework ID required")
+		}
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+		courseWorkID := resolveItemID(c.Args().First())
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		if err := requireTeacher(ctx, cfg, client, courseID, "publish coursework"); err != nil {
+			return err
+		}
+
+		cw, err := client.GetCourseWork(ctx, courseID, courseWorkID)
+		if err != nil {
+			return fmt.Errorf("failed to get coursework: %w", err)
+		}
+		if cw.State == api.CourseWorkPublished {
+			fmt.Printf("%q is already published\n", cw.Title)
+			return nil
+		}
+
+		updated, err := client.PatchCourseWork(ctx, courseID, courseWorkID, &api.CourseWorkUpdate{State: api.CourseWorkPublished}, "state")
+		if err != nil {
+			if api.IsForbidden(err) {
+				return fmt.Errorf("only the course teacher can publish coursework, and only coursework created through the API by this app: %w", err)
+			}
+			return err
+		}
+
+		fmt.Printf("✓ Published %q (state: %s)\n", updated.Title, updated.State)
+		return nil
+	}
+}
+
+func handleRosterExport(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		format := strings.ToLower(c.String("format"))
+		if format != roster.FormatCSV && format != roster.FormatVCard {
+			return fmt.Errorf("unknown format %q (use csv or vcard)", format)
+		}
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		if err := requireTeacher(ctx, cfg, client, courseID, "export the roster"); err != nil {
+			return err
+		}
+
+		course, err := client.GetCourse(ctx, courseID)
+		if err != nil {
+			return err
+		}
+		students, _, err := client.ListStudents(ctx, courseID, 0)
+		if err != nil {
+			return rosterError(err)
+		}
+		roster.Sort(students)
+
+		write := func(w io.Writer) error {
+			return roster.Write(w, format, course.Name, students)
+		}
+		if c.String("output") == "" {
+			return write(os.Stdout)
+		}
+		if err := writeReportFile(c.String("output"), write); err != nil {
+			return err
+		}
+
+		var missing int
+		for _, s := range students {
+			if s.Profile.EmailAddress == "" {
+				missing++
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d student(s) to %s\n", len(students), c.String("output"))
+		if missing > 0 {
+			fmt.Fprintf(os.Stderr, "%d student(s) have no email address visible to you\n", missing)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// priorityBarWidth is how many cells a score of 100 fills.
+const priorityBarWidth = 10
+
+func TodoCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:    "todo",
+		Aliases: []string{"upcoming"},
+		Usage:   "list work I haven't turned in across all active courses",
+		Action:  handleTodo(cfg),
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "days",
+				Usage: "only show work due in the next N days",
+			},
+			&cli.BoolFlag{
+				Name:  "overdue",
+				Usage: "only show work past its due date (with --days, overdue work too)",
+			},
+			&cli.StringFlag{
+				Name:  "by",
+				Usage: "sort by due date (due) or priority score (priority)",
+				Value: "due",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "output as JSON",
+			},
+		},
+	}
+}
+
+func handleTodo(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		by := c.String("by")
+		if by != "due" && by != "priority" {
+			return fmt.Errorf("--by must be due or priority, not %q", by)
+		}
+		days := c.Int("days")
+		if c.IsSet("days") && days < 1 {
+			return fmt.Errorf("--days must be at least 1")
+		}
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		items, err := agenda.Collect(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		now := clk.Now()
+		var todo []agenda.Item
+		for _, item := range items {
+			if !item.Done() && todoWanted(item, now, days, c.Bool("overdue")) {
+				todo = append(todo, item)
+			}
+		}
+
+		scores, err := priorityScores(cfg, todo)
+		if err != nil {
+			return err
+		}
+		if by == "priority" {
+			agenda.SortByPriority(todo, scores)
+		}
+
+		if c.Bool("json") {
+			return outputTodoJSON(todo, scores)
+		}
+		if len(todo) == 0 {
+			fmt.Println("Nothing to do.")
+			return nil
+		}
+		outputTodoTable(todo, scores)
+		return nil
+	}
+}
+
+// todoWanted applies --days and --overdue. With neither, everything not
+// turned in is shown, including undated work.
+func todoWanted(item agenda.Item, now time.Time, days int, overdue bool) bool {
+	if days == 0 && !overdue {
+		return true
+	}
+	due := item.Due()
+	if due.IsZero() {
+		return false
+	}
+	if due.Before(now) {
+		return overdue
+	}
+	return days > 0 && due.Before(now.AddDate(0, 0, days))
+}
+
+// priorityScores scores each item by coursework ID, counting the subtasks
+// I've checked off in notes as progress.
+func priorityScores(cfg *config.Config, items []agenda.Item) (map[string]float64, error) {
+	st, err := cfg.Store()
+	if err != nil {
+		return nil, err
+	}
+	notes, err := st.Notes()
+	if err != nil {
+		return nil, err
+	}
+
+	var maxPoints int64
+	for _, item := range items {
+		if item.Work.MaxPoints > maxPoints {
+			maxPoints = item.Work.MaxPoints
+		}
+	}
+	w := agenda.Weights{
+		Points:   cfg.Priority.PointsWeight,
+		Urgency:  cfg.Priority.UrgencyWeight,
+		Progress: cfg.Priority.ProgressWeight,
+		HalfLife: cfg.Priority.HalfLife,
+	}
+
+	now := clk.Now()
+	scores := make(map[string]float64, len(items))
+	for _, item := range items {
+		var progress float64
+		if done, total := notes.Get(item.Work.ID).Progress(); total > 0 {
+			progress = float64(done) / float64(total)
+		}
+		scores[item.Work.ID] = agenda.Priority(item, maxPoints, progress, now, w)
+	}
+	return scores, nil
+}
+
+// priorityBar draws a score as a short bar, e.g. "██████▌    72".
+func priorityBar(score float64) string {
+	halves := int(score*priorityBarWidth*2/100 + 0.5)
+	bar := strings.Repeat("█", halves/2)
+	if halves%2 == 1 {
+		bar += "▌"
+	}
+	return fmt.Sprintf("%-*s %3.0f", priorityBarWidth, bar, score)
+}
+
+func outputTodoTable(items []agenda.Item, scores map[string]float64) {
+	courseWidth := 20
+	titleWidth := 36
+	dueWidth := 18
+	pointsWidth := 8
+	priorityWidth := priorityBarWidth + 6
+
+	header := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		headerStyle.Width(courseWidth).Render("Course"),
+		headerStyle.Width(titleWidth).Render("Title"),
+		headerStyle.Width(dueWidth).Render("Due"),
+		headerStyle.Width(pointsWidth).Render("Points"),
+		headerStyle.Width(priorityWidth).Render("Priority"),
+	)
+	fmt.Println(header)
+	fmt.Println(separatorStyle.Render(strings.Repeat("─", courseWidth+titleWidth+dueWidth+pointsWidth+priorityWidth)))
+
+	for _, item := range items {
+		points := "-"
+		if item.Work.MaxPoints > 0 {
+			points = fmt.Sprint(item.Work.MaxPoints)
+		}
+		fmt.Println(lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			cellStyle.Width(courseWidth).Render(truncate(item.Course.Name, courseWidth-2)),
+			cellStyle.Width(titleWidth).Render(truncate(item.Work.Title, titleWidth-2)),
+			cellStyle.Width(dueWidth).Render(formatDueDate(item.Work)),
+			cellStyle.Width(pointsWidth).Render(points),
+			cellStyle.Width(priorityWidth).Render(priorityBar(scores[item.Work.ID])),
+		))
+	}
+}
+
+type todoJSON struct {
+	CourseID     string  `json:"courseId"`
+	CourseName   string  `json:"courseName"`
+	CourseWorkID string  `json:"courseWorkId"`
+	Title        string  `json:"title"`
+	Due          string  `json:"due,omitempty"`
+	MaxPoints    int64   `json:"maxPoints,omitempty"`
+	Priority     float64 `json:"priority"`
+	Link         string  `json:"link,omitempty"`
+}
+
+func outputTodoJSON(items []agenda.Item, scores map[string]float64) error {
+	out := make([]todoJSON, len(items))
+	for i, item := range items {
+		out[i] = todoJSON{
+			CourseID:     item.Course.ID,
+			CourseName:   item.Course.Name,
+			CourseWorkID: item.Work.ID,
+			Title:        item.Work.Title,
+			MaxPoints:    item.Work.MaxPoints,
+			Priority:     scores[item.Work.ID],
+			Link:         item.Work.AlternateLink,
+		}
+		if due := item.Due(); !due.IsZero() {
+			out[i].Due = due.Format("2006-01-02T15:04:05Z07:00")
+		}
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
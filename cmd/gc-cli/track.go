@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/urfave/cli/v2"
+)
+
+func TrackCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "track",
+		Usage: "track time spent on coursework",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "log",
+				Usage:     "record time spent on an assignment",
+				ArgsUsage: "<duration>",
+				Action:    handleTrackLog(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "course",
+						Usage: "course ID",
+					},
+					&cli.StringFlag{
+						Name:     "assignment",
+						Usage:    "assignment ID",
+						Required: true,
+					},
+				},
+			},
+			{
+				Name:   "list",
+				Usage:  "list tracked time",
+				Action: handleTrackList(cfg),
+			},
+			{
+				Name:      "remove",
+				Usage:     "delete a time entry (it goes to the trash)",
+				ArgsUsage: "<number>",
+				Action:    handleTrackRemove(cfg),
+			},
+		},
+	}
+}
+
+func handleTrackLog(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx := context.Background()
+
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("duration required (e.g. 45m or 1h30m)")
+		}
+		duration, err := time.ParseDuration(c.Args().First())
+		if err != nil || duration <= 0 {
+			return fmt.Errorf("invalid duration %q (e.g. 45m or 1h30m)", c.Args().First())
+		}
+		courseID, err := requireCourse(c)
+		if err != nil {
+			return err
+		}
+		assignmentID := assignmentArg(c)
+
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		cw, err := client.GetCourseWork(ctx, courseID, assignmentID)
+		if err != nil {
+			return fmt.Errorf("failed to get coursework: %w", err)
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		log, err := st.TrackLog()
+		if err != nil {
+			return err
+		}
+		log.Add(store.TimeEntry{
+			CourseID:     courseID,
+			CourseWorkID: cw.ID,
+			Title:        cw.Title,
+			Points:       float64(cw.MaxPoints),
+			Duration:     duration,
+			LoggedAt:     time.Now(),
+		})
+		if err := st.SaveTrackLog(log); err != nil {
+			return err
+		}
+
+		fmt.Printf("Logged %s on %q\n", duration, cw.Title)
+		return nil
+	}
+}
+
+func handleTrackList(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		log, err := st.TrackLog()
+		if err != nil {
+			return err
+		}
+		if len(log.Entries) == 0 {
+			fmt.Println("No time tracked yet")
+			return nil
+		}
+
+		numWidth := 5
+		dateWidth := 14
+		titleWidth := 40
+		durationWidth := 10
+		pointsWidth := 8
+
+		header := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			headerStyle.Width(numWidth).Render("#"),
+			headerStyle.Width(dateWidth).Render("Logged"),
+			headerStyle.Width(titleWidth).Render("Assignment"),
+			headerStyle.Width(durationWidth).Render("Time"),
+			headerStyle.Width(pointsWidth).Render("Points"),
+		)
+		separator := separatorStyle.Render("─")
+
+		fmt.Println(header)
+		fmt.Println(lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			separator+separator+separator+separator,
+		))
+
+		var total time.Duration
+		for i, e := range log.Entries {
+			total += e.Duration
+			points := "-"
+			if e.Points > 0 {
+				points = fmt.Sprintf("%.0f", e.Points)
+			}
+			row := lipgloss.JoinHorizontal(
+				lipgloss.Left,
+				cellStyle.Width(numWidth).Render(strconv.Itoa(i+1)),
+				cellStyle.Width(dateWidth).Render(e.LoggedAt.Local().Format("01/02 15:04")),
+				cellStyle.Width(titleWidth).Render(truncate(e.Title, titleWidth)),
+				cellStyle.Width(durationWidth).Render(e.Duration.String()),
+				cellStyle.Width(pointsWidth).Render(points),
+			)
+			fmt.Println(row)
+		}
+
+		fmt.Printf("\nTotal: %s\n", total)
+		return nil
+	}
+}
+
+func handleTrackRemove(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		n, err := strconv.Atoi(c.Args().First())
+		if err != nil || n < 1 {
+			return fmt.Errorf("entry number required, as shown by 'gc-cli track list'")
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		log, err := st.TrackLog()
+		if err != nil {
+			return err
+		}
+		removed, ok := log.Remove(n - 1)
+		if !ok {
+			return fmt.Errorf("no time entry %d", n)
+		}
+		trashID, err := moveToTrash(st, store.TrashItem{Kind: store.TrashTimeEntry, Entry: &removed})
+		if err != nil {
+			return err
+		}
+		if err := st.SaveTrackLog(log); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed %s on %q; 'gc-cli note trash restore %s' brings it back\n", removed.Duration, removed.Title, trashID)
+		return nil
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+func trashCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "trash",
+		Usage: "bring back deleted subtasks and time entries",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "list what has been deleted",
+				Action: handleTrashList(cfg),
+			},
+			{
+				Name:      "restore",
+				Usage:     "put deleted items back where they were",
+				ArgsUsage: "<trash-id>...",
+				Action:    handleTrashRestore(cfg),
+			},
+			{
+				Name:   "purge",
+				Usage:  "delete what's in the trash for good",
+				Action: handleTrashPurge(cfg),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "older-than",
+						Usage: "only items deleted longer ago than this (e.g. 30d)",
+					},
+				},
+			},
+		},
+	}
+}
+
+// moveToTrash keeps a deleted item so 'note trash restore' can bring it
+// back, returning its trash ID.
+func moveToTrash(st *store.Store, item store.TrashItem) (string, error) {
+	trash, err := st.Trash()
+	if err != nil {
+		return "", err
+	}
+	item.Deleted = time.Now()
+	item = trash.Add(item)
+	if err := st.SaveTrash(trash); err != nil {
+		return "", err
+	}
+	return item.ID, nil
+}
+
+func handleTrashList(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		trash, err := st.Trash()
+		if err != nil {
+			return err
+		}
+		if len(trash.Items) == 0 {
+			fmt.Println("The trash is empty")
+			return nil
+		}
+
+		idWidth := 6
+		kindWidth := 10
+		itemWidth := 50
+		dateWidth := 14
+
+		header := lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			headerStyle.Width(idWidth).Render("ID"),
+			headerStyle.Width(kindWidth).Render("Kind"),
+			headerStyle.Width(itemWidth).Render("Item"),
+			headerStyle.Width(dateWidth).Render("Deleted"),
+		)
+		fmt.Println(header)
+		fmt.Println(separatorStyle.Render(strings.Repeat("─", idWidth+kindWidth+itemWidth+dateWidth)))
+
+		for _, item := range trash.Items {
+			row := lipgloss.JoinHorizontal(
+				lipgloss.Left,
+				cellStyle.Width(idWidth).Render(item.ID),
+				cellStyle.Width(kindWidth).Render(item.Kind),
+				cellStyle.Width(itemWidth).Render(truncate(describeTrashItem(item), itemWidth-2)),
+				cellStyle.Width(dateWidth).Render(item.Deleted.Local().Format("01/02 15:04")),
+			)
+			fmt.Println(row)
+		}
+
+		fmt.Printf("\n%d item(s); 'gc-cli note trash restore <id>' brings one back\n", len(trash.Items))
+		return nil
+	}
+}
+
+func describeTrashItem(item store.TrashItem) string {
+	switch {
+	case item.Subtask != nil:
+		return fmt.Sprintf("%s (on %s)", item.Subtask.Text, displayID(item.CourseWorkID))
+	case item.Entry != nil:
+		title := item.Entry.Title
+		if title == "" {
+			title = displayID(item.Entry.CourseWorkID)
+		}
+		return fmt.Sprintf("%s on %s", item.Entry.Duration, title)
+	}
+	return "-"
+}
+
+func handleTrashRestore(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		if c.Args().Len() < 1 {
+			return fmt.Errorf("trash ID required (see 'gc-cli note trash list')")
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		trash, err := st.Trash()
+		if err != nil {
+			return err
+		}
+		notes, err := st.Notes()
+		if err != nil {
+			return err
+		}
+		log, err := st.TrackLog()
+		if err != nil {
+			return err
+		}
+
+		var restored []store.TrashItem
+		for _, id := range c.Args().Slice() {
+			item, ok := trash.Take(id)
+			if !ok {
+				return fmt.Errorf("nothing in the trash with ID %s", id)
+			}
+			restored = append(restored, item)
+		}
+		// Undoing the latest deletion first puts subtasks back in their
+		// places.
+		sort.SliceStable(restored, func(i, j int) bool {
+			return restored[i].Deleted.After(restored[j].Deleted)
+		})
+		for _, item := range restored {
+			switch {
+			case item.Subtask != nil:
+				notes.RestoreSubtask(item.CourseWorkID, item.Index, *item.Subtask)
+			case item.Entry != nil:
+				log.Restore(*item.Entry)
+			}
+		}
+
+		if err := st.SaveNotes(notes); err != nil {
+			return err
+		}
+		if err := st.SaveTrackLog(log); err != nil {
+			return err
+		}
+		if err := st.SaveTrash(trash); err != nil {
+			return err
+		}
+		for _, item := range restored {
+			fmt.Printf("Restored %s: %s\n", item.Kind, describeTrashItem(item))
+		}
+		return nil
+	}
+}
+
+func handleTrashPurge(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		var cutoff time.Time
+		if s := c.String("older-than"); s != "" {
+			d, err := parseSnoozeDuration(s)
+			if err != nil {
+				return err
+			}
+			cutoff = time.Now().Add(-d)
+		}
+
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+		trash, err := st.Trash()
+		if err != nil {
+			return err
+		}
+		if len(trash.Items) == 0 {
+			fmt.Println("The trash is empty")
+			return nil
+		}
+
+		// Emptying the whole trash can't be undone, so ask first when
+		// someone is there to answer.
+		if cutoff.IsZero() && term.IsTerminal(int(os.Stdin.Fd())) {
+			answer, err := prompt(fmt.Sprintf("Delete all %d item(s) in the trash for good? [y/N] ", len(trash.Items)))
+			if err != nil {
+				return err
+			}
+			if a := strings.ToLower(answer); a != "y" && a != "yes" {
+				fmt.Println("Nothing deleted")
+				return nil
+			}
+		}
+
+		purged := trash.Purge(cutoff)
+		if err := st.SaveTrash(trash); err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %d item(s) for good\n", purged)
+		return nil
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/notify"
+	"github.com/timboy697/gc-cli/internal/watch"
+	"github.com/urfave/cli/v2"
+)
+
+const minWatchInterval = time.Minute
+
+func WatchCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "watch",
+		Usage: "keep checking for new assignments, edits, announcements, grades and deadlines",
+		Description: "The first check only notes what is already there; after that each change is\n" +
+			"printed as it is found. Changes made while watch wasn't running are reported\n" +
+			"on the next check. Work you haven't turned in is reported once when it comes\n" +
+			"within --due-soon of its deadline. With --format ndjson each change is one JSON\n" +
+			"object per line, for piping into jq, a log collector or your own scripts.\n\n" +
+			"--notify also shows each change as a desktop notification (notify-send on\n" +
+			"Linux, osascript on macOS, a toast on Windows), except during quiet hours or\n" +
+			"for snoozed items; see 'gc-cli notify'.",
+		Action: handleWatch(cfg),
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "time between checks",
+				Value: 5 * time.Minute,
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "output format: text or ndjson",
+				Value: "text",
+			},
+			&cli.BoolFlag{
+				Name:  "once",
+				Usage: "check once and exit, e.g. from cron",
+			},
+			&cli.DurationFlag{
+				Name:  "due-soon",
+				Usage: "report work not turned in when it's due within this long (0 to turn off)",
+				Value: 24 * time.Hour,
+			},
+			&cli.BoolFlag{
+				Name:  "notify",
+				Usage: "also show changes as desktop notifications",
+			},
+		},
+	}
+}
+
+func handleWatch(cfg *config.Config) func(*cli.Context) error {
+	return func(c *cli.Context) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		interval := c.Duration("interval")
+		if interval < minWatchInterval {
+			return fmt.Errorf("--interval must be at least %s", minWatchInterval)
+		}
+		var emit func(io.Writer, watch.Event) error
+		switch c.String("format") {
+		case "text":
+			emit = printWatchEvent
+		case "ndjson":
+			emit = writeWatchEvent
+		default:
+			return fmt.Errorf("--format must be text or ndjson, not %q", c.String("format"))
+		}
+		if c.Duration("due-soon") < 0 {
+			return fmt.Errorf("--due-soon must not be negative")
+		}
+
+		// Each check has to see the API as it is now, not a cached response,
+		// and the token must not lapse between checks.
+		noCache = true
+		keepTokenFresh = true
+		backOffPatiently()
+		client, err := newAPIClient(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		st, err := cfg.Store()
+		if err != nil {
+			return err
+		}
+
+		var notifyFailed bool
+		for {
+			courses, err := watch.Fetch(ctx, client)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				if c.Bool("once") {
+					return err
+				}
+				// Keep watching through network blips and API hiccups.
+				fmt.Fprintf(os.Stderr, "%s check failed: %v\n", clk.Now().Format("15:04"), err)
+			} else {
+				ws, err := st.WatchState()
+				if err != nil {
+					return err
+				}
+				if ws.Recovered {
+					msg := "Warning: the saved watch state was damaged, so watching starts over; changes made meanwhile won't be reported"
+					if ws.SetAside != "" {
+						msg += " (a copy of the damaged state is at " + filepath.Join(st.Dir(), ws.SetAside+".json") + ")"
+					}
+					fmt.Fprintln(os.Stderr, msg)
+				}
+				var gate *notify.Gate
+				if c.Bool("notify") {
+					// Loaded each check, so snoozes made meanwhile apply.
+					if gate, err = notifyGate(cfg); err != nil {
+						return err
+					}
+				}
+				now := clk.Now()
+				for _, e := range watch.Diff(ws, courses, now, c.Duration("due-soon")) {
+					if err := emit(os.Stdout, e); err != nil {
+						return err
+					}
+					if gate != nil && gate.Allow(e.ItemID, now) {
+						if err := notify.Desktop(e.Course, describeWatchEvent(e)); err != nil && !notifyFailed {
+							// Say so once rather than on every change.
+							fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+							notifyFailed = true
+						}
+					}
+				}
+				if err := st.SaveWatchState(ws); err != nil {
+					return err
+				}
+			}
+
+			if c.Bool("once") {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	}
+}
+
+// writeWatchEvent writes e as a single line of JSON.
+func writeWatchEvent(w io.Writer, e watch.Event) error {
+	return json.NewEncoder(w).Encode(e)
+}
+
+func printWatchEvent(w io.Writer, e watch.Event) error {
+	_, err := fmt.Fprintf(w, "%s %s %s\n", e.Detected.Format("15:04"), headerStyle.Render("["+e.Course+"]"), describeWatchEvent(e))
+	return err
+}
+
+// describeWatchEvent says what happened, without the course.
+func describeWatchEvent(e watch.Event) string {
+	var what string
+	switch e.Type {
+	case watch.CourseworkPosted:
+		what = fmt.Sprintf("New assignment %q", e.Title)
+		if e.Due != nil {
+			what += ", due " + e.Due.Format("Mon Jan 2 15:04")
+		}
+	case watch.CourseworkEdited:
+		what = fmt.Sprintf("Teacher changed the %s of %q", strings.Join(e.Fields, ", "), e.Title)
+	case watch.AnnouncementPosted:
+		what = "Announcement: " + e.Title
+	case watch.GradeReturned:
+		what = fmt.Sprintf("%q returned", e.Title)
+		if e.Grade != nil && e.MaxPoints > 0 {
+			what += fmt.Sprintf(" with %g/%d", *e.Grade, e.MaxPoints)
+		}
+	case watch.DeadlineSoon:
+		what = fmt.Sprintf("%q is due", e.Title)
+		if e.Due != nil {
+			what += fmt.Sprintf(" in %s (%s)", agenda.Until(e.Detected, *e.Due), e.Due.Format("Mon 15:04"))
+		}
+	default:
+		what = e.Title
+	}
+	return what
+}
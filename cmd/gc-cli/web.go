@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+	"github.com/timboy697/gc-cli/internal/web"
+	"github.com/urfave/cli/v2"
+)
+
+func WebCmd(cfg *config.Config) *cli.Command {
+	return &cli.Command{
+		Name:  "web",
+		Usage: "serve a read-only dashboard in your browser",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "listen",
+				Usage: "address to listen on",
+				Value: "127.0.0.1:7070",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx := context.Background()
+
+			client, err := newAPIClient(ctx, cfg)
+			if err != nil {
+				return err
+			}
+
+			server := web.NewServer(client, gradebook.NewScale(cfg.Grades.Scale), clk, courseorder.New(cfg.Courses))
+			addr := c.String("listen")
+			fmt.Printf("Dashboard available at http://%s/\n", addr)
+			return http.ListenAndServe(addr, server.Handler())
+		},
+	}
+}
@@ -0,0 +1,112 @@
+// Package account keeps each Google account's local state in its own
+// directory, so signing in as someone else never mixes their read markers,
+// notes or synced data with yours.
+//
+// A data root (the default data directory, or a profile's) holds
+// account.json naming the account last signed in there, and one
+// accounts/<id> directory per account. State written before accounts were
+// tracked sits directly in the root until the first account claims it.
+package account
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	markerFile  = "account.json"
+	accountsDir = "accounts"
+)
+
+// Marker records which account a data root currently belongs to.
+type Marker struct {
+	ID    string    `json:"id"`
+	Email string    `json:"email,omitempty"`
+	Since time.Time `json:"since"`
+}
+
+// Read returns the root's marker, or nil if no account has been recorded.
+func Read(root string) (*Marker, error) {
+	data, err := os.ReadFile(filepath.Join(root, markerFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account marker: %w", err)
+	}
+	var m Marker
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse account marker: %w", err)
+	}
+	if m.ID == "" {
+		return nil, nil
+	}
+	return &m, nil
+}
+
+// Dir is where the account's state lives under root.
+func Dir(root, id string) string {
+	return filepath.Join(root, accountsDir, id)
+}
+
+// Adopt records id as the root's account and returns its state directory.
+// The first account recorded in a root takes over any state left there from
+// before accounts were tracked, and migrated reports whether that happened;
+// later accounts start empty.
+func Adopt(root, id, email string, now time.Time) (dir string, migrated bool, err error) {
+	prev, err := Read(root)
+	if err != nil {
+		return "", false, err
+	}
+	dir = Dir(root, id)
+	if prev == nil {
+		if migrated, err = migrateLegacy(root, dir); err != nil {
+			return "", false, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, fmt.Errorf("failed to create account directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(Marker{ID: id, Email: email, Since: now}, "", "  ")
+	if err != nil {
+		return "", false, err
+	}
+	if err := os.WriteFile(filepath.Join(root, markerFile), data, 0600); err != nil {
+		return "", false, fmt.Errorf("failed to write account marker: %w", err)
+	}
+	return dir, migrated, nil
+}
+
+// migrateLegacy moves everything in root except the account bookkeeping into
+// dir, unless dir already has state of its own.
+func migrateLegacy(root, dir string) (bool, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return false, nil
+	}
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	var moved bool
+	for _, e := range entries {
+		if e.Name() == accountsDir || e.Name() == markerFile {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return moved, fmt.Errorf("failed to create account directory: %w", err)
+		}
+		if err := os.Rename(filepath.Join(root, e.Name()), filepath.Join(dir, e.Name())); err != nil {
+			return moved, fmt.Errorf("failed to move %s into account directory: %w", e.Name(), err)
+		}
+		moved = true
+	}
+	return moved, nil
+}
@@ -0,0 +1,146 @@
+package agenda
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+// Item is a published coursework item paired with my submission for it.
+type Item struct {
+	Course     api.Course
+	Work       api.CourseWork
+	Submission *api.StudentSubmission
+}
+
+// Due returns the due time in local time, or zero if the item has no due
+// date. Items without a due time are due at the end of the day.
+func (i Item) Due() time.Time {
+	return DueTime(i.Work)
+}
+
+// Done reports whether I've turned the item in (or it has been returned).
+func (i Item) Done() bool {
+	if i.Submission == nil {
+		return false
+	}
+	return i.Submission.State.Done()
+}
+
+func DueTime(cw api.CourseWork) time.Time {
+	if cw.DueDate == nil {
+		return time.Time{}
+	}
+	hour, min := 23, 59
+	if cw.DueTime != nil {
+		hour, min = cw.DueTime.Hours, cw.DueTime.Minutes
+	}
+	return time.Date(cw.DueDate.Year, time.Month(cw.DueDate.Month), cw.DueDate.Day, hour, min, 0, 0, time.UTC).Local()
+}
+
+// maxCourseFetches bounds how many courses Collect fetches at once.
+const maxCourseFetches = 4
+
+// Collect gathers published coursework and my submissions across all active
+// courses, fetching courses concurrently, sorted by due date with undated
+// items last.
+func Collect(ctx context.Context, client api.ClassroomService) ([]Item, error) {
+	courses, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list courses: %w", err)
+	}
+
+	// The first failure cancels the other fetches.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]Item, len(courses))
+	sem := make(chan struct{}, maxCourseFetches)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i, course := range courses {
+		if course.CourseState != api.CourseActive {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, course api.Course) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			courseItems, err := CollectCourse(ctx, client, course)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = courseItems
+		}(i, course)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var items []Item
+	for _, courseItems := range results {
+		items = append(items, courseItems...)
+	}
+	Sort(items)
+	return items, nil
+}
+
+// CollectCourse fetches a course's coursework and my submissions side by
+// side and pairs them up.
+func CollectCourse(ctx context.Context, client api.ClassroomService, course api.Course) ([]Item, error) {
+	var (
+		submissions []api.StudentSubmission
+		subErr      error
+		done        = make(chan struct{})
+	)
+	go func() {
+		defer close(done)
+		submissions, _, subErr = client.ListStudentSubmissions(ctx, course.ID, "-", 100, api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+	}()
+
+	coursework, _, err := client.ListCourseWork(ctx, course.ID, 100)
+	<-done
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coursework for %s: %w", course.Name, err)
+	}
+	if subErr != nil {
+		return nil, fmt.Errorf("failed to list submissions for %s: %w", course.Name, subErr)
+	}
+
+	byWork := make(map[string]*api.StudentSubmission, len(submissions))
+	for i := range submissions {
+		byWork[submissions[i].CourseWorkID] = &submissions[i]
+	}
+
+	var items []Item
+	for _, cw := range coursework {
+		if cw.State != api.CourseWorkPublished {
+			continue
+		}
+		items = append(items, Item{Course: course, Work: cw, Submission: byWork[cw.ID]})
+	}
+	return items, nil
+}
+
+func Sort(items []Item) {
+	sort.SliceStable(items, func(i, j int) bool {
+		di, dj := items[i].Due(), items[j].Due()
+		if di.IsZero() != dj.IsZero() {
+			return dj.IsZero()
+		}
+		return di.Before(dj)
+	})
+}
@@ -0,0 +1,65 @@
+package agenda
+
+import "github.com/timboy697/gc-cli/internal/api"
+
+// Expects is what a coursework item asks of the student.
+type Expects string
+
+const (
+	// ExpectsFile is an assignment handed in as files, or turned in as is.
+	ExpectsFile Expects = "file"
+	// ExpectsAnswer is a short-answer or multiple-choice question.
+	ExpectsAnswer Expects = "answer"
+	// ExpectsQuiz is an assignment built around a Google Form.
+	ExpectsQuiz Expects = "quiz"
+	// ExpectsAddOn is done inside an add-on such as Kami or Edpuzzle.
+	ExpectsAddOn Expects = "add-on"
+	// ExpectsMaterial has nothing graded or due, only materials to read.
+	ExpectsMaterial Expects = "material"
+)
+
+// ExpectsOf works out what cw asks for from its work type and materials.
+func ExpectsOf(cw api.CourseWork) Expects {
+	switch {
+	case cw.WorkType == api.WorkTypeShortAnswer || cw.WorkType == api.WorkTypeMultipleChoice:
+		return ExpectsAnswer
+	case len(cw.AddOnAttachments) > 0:
+		return ExpectsAddOn
+	case IsQuiz(cw):
+		return ExpectsQuiz
+	case cw.MaxPoints == 0 && cw.DueDate == nil && len(cw.Materials) > 0:
+		return ExpectsMaterial
+	}
+	return ExpectsFile
+}
+
+// InCLI reports whether gc-cli can do the work itself, with 'gc-cli submit',
+// rather than it needing the browser. The API doesn't let students answer
+// questions, fill in forms or use add-ons.
+func (e Expects) InCLI() bool {
+	return e == ExpectsFile || e == ExpectsMaterial
+}
+
+// Icon is a one-character picture of e for listings.
+func (e Expects) Icon() string {
+	switch e {
+	case ExpectsAnswer:
+		return "✎"
+	case ExpectsQuiz:
+		return "☷"
+	case ExpectsAddOn:
+		return "⧉"
+	case ExpectsMaterial:
+		return "❏"
+	}
+	return "⇪"
+}
+
+// Label is e with its icon, and ↗ for work done in the browser.
+func (e Expects) Label() string {
+	label := e.Icon() + " " + string(e)
+	if !e.InCLI() {
+		label += " ↗"
+	}
+	return label
+}
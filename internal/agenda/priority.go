@@ -0,0 +1,56 @@
+package agenda
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Weights tunes the priority score. Points and Urgency are the relative
+// pull of an item's worth and of its deadline; Progress is how much a fully
+// checked-off subtask list lowers the score (0 ignores subtasks, 1 drops a
+// finished list to zero). HalfLife is how far out a due date counts as half
+// urgent.
+type Weights struct {
+	Points   float64
+	Urgency  float64
+	Progress float64
+	HalfLife time.Duration
+}
+
+// Priority scores an item from 0 to 100 for triage. maxPoints is the largest
+// point value among the items being compared, so worth is relative to the
+// rest of the list; progress is the fraction of the item's subtasks done.
+// Overdue work is fully urgent and undated work not urgent at all.
+func Priority(item Item, maxPoints int64, progress float64, now time.Time, w Weights) float64 {
+	if w.Points+w.Urgency <= 0 {
+		return 0
+	}
+
+	var worth float64
+	if maxPoints > 0 {
+		worth = float64(item.Work.MaxPoints) / float64(maxPoints)
+	}
+
+	var urgency float64
+	if due := item.Due(); !due.IsZero() {
+		left := due.Sub(now)
+		if left <= 0 || w.HalfLife <= 0 {
+			urgency = 1
+		} else {
+			urgency = math.Pow(0.5, float64(left)/float64(w.HalfLife))
+		}
+	}
+
+	score := (w.Points*worth + w.Urgency*urgency) / (w.Points + w.Urgency)
+	score *= 1 - math.Min(math.Max(w.Progress, 0), 1)*progress
+	return math.Round(score * 100)
+}
+
+// SortByPriority orders items by score, highest first, keeping due-date
+// order among equal scores.
+func SortByPriority(items []Item, scores map[string]float64) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return scores[items[i].Work.ID] > scores[items[j].Work.ID]
+	})
+}
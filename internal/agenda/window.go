@@ -0,0 +1,62 @@
+package agenda
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+// closingSoon is how close to its due time a quiz starts showing "closes in".
+const closingSoon = 24 * time.Hour
+
+// OpensAt returns when scheduled coursework becomes visible to students, or
+// zero if it isn't scheduled or has already opened.
+func OpensAt(cw api.CourseWork, now time.Time) time.Time {
+	if !cw.IsScheduled() || !cw.ScheduledTime.After(now) {
+		return time.Time{}
+	}
+	return cw.ScheduledTime.Local()
+}
+
+// IsQuiz reports whether the coursework is answered in a time window rather
+// than worked on: a question, or an assignment built around a Google Form.
+func IsQuiz(cw api.CourseWork) bool {
+	if cw.WorkType == api.WorkTypeShortAnswer || cw.WorkType == api.WorkTypeMultipleChoice {
+		return true
+	}
+	for _, m := range cw.Materials {
+		if m.Form != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Window describes when coursework can be done, e.g. "opens in 2h" for
+// scheduled work or "closes in 40m" for a quiz due soon. It returns "" when
+// there is nothing to say.
+func Window(cw api.CourseWork, now time.Time) string {
+	if opens := OpensAt(cw, now); !opens.IsZero() {
+		return "opens in " + Until(now, opens)
+	}
+	due := DueTime(cw)
+	if IsQuiz(cw) && !due.IsZero() && due.After(now) && due.Sub(now) <= closingSoon {
+		return "closes in " + Until(now, due)
+	}
+	return ""
+}
+
+// Until formats the time from now to t coarsely: "45m", "2h", "3d".
+func Until(now, t time.Time) string {
+	d := t.Sub(now)
+	switch {
+	case d < time.Minute:
+		return "under a minute"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
@@ -0,0 +1,82 @@
+// Package anonymize replaces identifying course, people and grade data with
+// stable fake values so output can be shared in screenshots and bug reports.
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+var (
+	adjectives = []string{"Amber", "Cobalt", "Crimson", "Golden", "Ivory", "Jade", "Onyx", "Scarlet", "Silver", "Violet", "Azure", "Copper"}
+	subjects   = []string{"Studies", "Seminar", "Workshop", "Lab", "Foundations", "Topics", "Methods", "Practicum"}
+	firstNames = []string{"Alex", "Jordan", "Sam", "Taylor", "Morgan", "Casey", "Riley", "Jamie", "Avery", "Quinn", "Drew", "Rowan"}
+	lastNames  = []string{"Lee", "Patel", "Garcia", "Kim", "Nguyen", "Smith", "Cohen", "Okafor", "Silva", "Novak", "Haddad", "Berg"}
+)
+
+// Anonymizer maps real values to fake ones. The same input always yields the
+// same output for a given salt, so relationships between rows survive.
+type Anonymizer struct {
+	salt []byte
+}
+
+func New(salt []byte) *Anonymizer {
+	return &Anonymizer{salt: salt}
+}
+
+func (a *Anonymizer) hash(kind, value string) uint64 {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return binary.BigEndian.Uint64(mac.Sum(nil))
+}
+
+func (a *Anonymizer) Course(name string) string {
+	if name == "" {
+		return ""
+	}
+	h := a.hash("course", name)
+	return fmt.Sprintf("%s %s %d", adjectives[h%uint64(len(adjectives))], subjects[(h>>8)%uint64(len(subjects))], 100+(h>>16)%400)
+}
+
+// Section and room values are short and often identifying on their own, so
+// they are replaced with generic labels.
+func (a *Anonymizer) Section(section string) string {
+	if section == "" {
+		return ""
+	}
+	return fmt.Sprintf("Section %d", 1+a.hash("section", section)%9)
+}
+
+func (a *Anonymizer) Room(room string) string {
+	if room == "" {
+		return ""
+	}
+	return fmt.Sprintf("Room %d", 100+a.hash("room", room)%300)
+}
+
+func (a *Anonymizer) Person(name string) string {
+	if name == "" {
+		return ""
+	}
+	h := a.hash("person", name)
+	return firstNames[h%uint64(len(firstNames))] + " " + lastNames[(h>>8)%uint64(len(lastNames))]
+}
+
+func (a *Anonymizer) Email(email string) string {
+	if email == "" {
+		return ""
+	}
+	return fmt.Sprintf("user%04d@example.com", a.hash("email", email)%10000)
+}
+
+// Grade scales a grade by a stable factor between 0.7 and 1.0, keyed by the
+// submission, so it stays within the original maximum and looks plausible.
+func (a *Anonymizer) Grade(key string, grade float64) float64 {
+	factor := 0.7 + float64(a.hash("grade", key)%301)/1000
+	return math.Round(grade*factor*10) / 10
+}
@@ -9,18 +9,18 @@ import (
 )
 
 type Announcement struct {
-	ID                 string          `json:"id"`
-	CourseID           string          `json:"courseId"`
-	Text               string          `json:"text"`
-	State              string          `json:"state"`
-	AlternateLink      string          `json:"alternateLink"`
-	CreationTime       time.Time       `json:"creationTime"`
-	UpdateTime         time.Time       `json:"updateTime"`
-	ScheduledTime      time.Time       `json:"scheduledTime,omitempty"`
-	AssigneeMode       string          `json:"assigneeMode,omitempty"`
-	CourseWorkMaterial json.RawMessage `json:"courseWorkMaterial,omitempty"`
-	TopicID            string          `json:"topicId,omitempty"`
-	CreatorUserID      string          `json:"creatorUserId,omitempty"`
+	ID                 string            `json:"id"`
+	CourseID           string            `json:"courseId"`
+	Text               string            `json:"text"`
+	State              AnnouncementState `json:"state"`
+	AlternateLink      string            `json:"alternateLink"`
+	CreationTime       time.Time         `json:"creationTime"`
+	UpdateTime         time.Time         `json:"updateTime"`
+	ScheduledTime      time.Time         `json:"scheduledTime,omitempty"`
+	AssigneeMode       string            `json:"assigneeMode,omitempty"`
+	CourseWorkMaterial json.RawMessage   `json:"courseWorkMaterial,omitempty"`
+	TopicID            string            `json:"topicId,omitempty"`
+	CreatorUserID      string            `json:"creatorUserId,omitempty"`
 }
 
 type AnnouncementList struct {
@@ -28,12 +28,20 @@ type AnnouncementList struct {
 	NextPageToken string         `json:"nextPageToken,omitempty"`
 }
 
-func (c *Client) ListAnnouncements(ctx context.Context, courseID string, pageSize int) ([]Announcement, string, error) {
+// ListAnnouncements lists a course's announcements, most recently updated
+// first. With WithLimit it stops once it has that many and returns the token
+// for the next page.
+func (c *Client) ListAnnouncements(ctx context.Context, courseID string, pageSize int, opts ...CallOption) ([]Announcement, string, error) {
+	o := collectCallOptions(opts)
+	if o.limit > 0 && (pageSize <= 0 || o.limit < pageSize) {
+		pageSize = o.limit
+	}
+
 	var allAnnouncements []Announcement
 	var pageToken string
 
 	for {
-		params := buildListParams(pageSize, pageToken)
+		params := applyFields(buildListParams(pageSize, pageToken), "announcements", opts)
 		endpoint := fmt.Sprintf("/courses/%s/announcements", url.PathEscape(courseID))
 		resp, err := c.get(ctx, endpoint, params)
 		if err != nil {
@@ -46,19 +54,23 @@ func (c *Client) ListAnnouncements(ctx context.Context, courseID string, pageSiz
 		}
 
 		allAnnouncements = append(allAnnouncements, result.Announcements...)
+		pageToken = result.NextPageToken
 
-		if result.NextPageToken == "" {
+		if pageToken == "" {
+			break
+		}
+		if o.limit > 0 && len(allAnnouncements) >= o.limit {
+			allAnnouncements = allAnnouncements[:o.limit]
 			break
 		}
-		pageToken = result.NextPageToken
 	}
 
 	return allAnnouncements, pageToken, nil
 }
 
-func (c *Client) GetAnnouncement(ctx context.Context, courseID, announcementID string) (*Announcement, error) {
+func (c *Client) GetAnnouncement(ctx context.Context, courseID, announcementID string, opts ...CallOption) (*Announcement, error) {
 	endpoint := fmt.Sprintf("/courses/%s/announcements/%s", url.PathEscape(courseID), url.PathEscape(announcementID))
-	resp, err := c.get(ctx, endpoint, nil)
+	resp, err := c.get(ctx, endpoint, applyFields(nil, "", opts))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get announcement %s in course %s: %w", announcementID, courseID, err)
 	}
@@ -70,3 +82,42 @@ func (c *Client) GetAnnouncement(ctx context.Context, courseID, announcementID s
 
 	return &announcement, nil
 }
+
+// AnnouncementCreate is the body for CreateAnnouncement. A scheduled
+// announcement must be created as a DRAFT.
+type AnnouncementCreate struct {
+	Text          string            `json:"text"`
+	State         AnnouncementState `json:"state,omitempty"`
+	ScheduledTime *time.Time        `json:"scheduledTime,omitempty"`
+	Materials     []Material        `json:"materials,omitempty"`
+}
+
+// CreateAnnouncement posts a new announcement. Only course teachers may do
+// this, and only with the classroom.announcements scope.
+func (c *Client) CreateAnnouncement(ctx context.Context, courseID string, announcement *AnnouncementCreate) (*Announcement, error) {
+	body, err := json.Marshal(announcement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal announcement: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/courses/%s/announcements", url.PathEscape(courseID))
+	resp, err := c.post(ctx, endpoint, nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create announcement in course %s: %w", courseID, err)
+	}
+
+	var created Announcement
+	if err := json.Unmarshal(resp, &created); err != nil {
+		return nil, fmt.Errorf("failed to parse announcement: %w", err)
+	}
+
+	return &created, nil
+}
+
+func (c *Client) DeleteAnnouncement(ctx context.Context, courseID, announcementID string) error {
+	endpoint := fmt.Sprintf("/courses/%s/announcements/%s", url.PathEscape(courseID), url.PathEscape(announcementID))
+	if _, err := c.delete(ctx, endpoint); err != nil {
+		return fmt.Errorf("failed to delete announcement %s in course %s: %w", announcementID, courseID, err)
+	}
+	return nil
+}
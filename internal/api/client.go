@@ -1,6 +1,7 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,8 +11,11 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/timboy697/gc-cli/internal/logging"
+	"github.com/timboy697/gc-cli/internal/useragent"
 	"golang.org/x/oauth2"
 	"google.golang.org/api/googleapi"
 )
@@ -26,32 +30,96 @@ const (
 type Client struct {
 	httpClient  *http.Client
 	tokenSource oauth2.TokenSource
-	retries     int
-	backoff     time.Duration
+	retry       RetryPolicy
+	stats       *Stats
+	readOnly    bool
+	filter      func([]byte) []byte
+	cache       ResponseCache
+	log         *logging.Logger
+
+	// courses memoizes full course objects seen by this client, so
+	// checking a course again costs nothing.
+	courseMu sync.Mutex
+	courses  map[string]Course
+
+	// profiles memoizes user profiles the same way, mostly for showing
+	// who wrote something.
+	profileMu sync.Mutex
+	profiles  map[string]UserProfile
 }
 
+// ResponseCache keeps GET responses between runs. Endpoints it doesn't
+// cache simply never hit.
+type ResponseCache interface {
+	Get(endpoint, url string) ([]byte, bool)
+	Put(endpoint, url string, body []byte)
+	// Invalidate is called after a successful change to endpoint.
+	Invalidate(endpoint string)
+}
+
+// ErrReadOnly is returned for any modifying request made by a read-only
+// client.
+var ErrReadOnly = errors.New("this profile is read-only")
+
 type Option func(*Client)
 
 func WithRetries(n int) Option {
 	return func(c *Client) {
-		c.retries = n
+		c.retry.Retries = n
 	}
 }
 
 func WithBackoff(d time.Duration) Option {
 	return func(c *Client) {
-		c.backoff = d
+		c.retry.Backoff = d
+	}
+}
+
+// WithReadOnly makes the client refuse every request other than GET, so
+// read-only profiles fail fast with a clear error.
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.readOnly = true
+	}
+}
+
+// WithHTTPClient sends requests through hc instead of an OAuth client, e.g.
+// to answer them from local data.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithResponseFilter rewrites every successful GET response body before it
+// is decoded, e.g. to anonymize data for screenshots.
+func WithResponseFilter(fn func([]byte) []byte) Option {
+	return func(c *Client) {
+		c.filter = fn
+	}
+}
+
+// WithCache answers repeated reads from rc while they are fresh.
+func WithCache(rc ResponseCache) Option {
+	return func(c *Client) {
+		c.cache = rc
+	}
+}
+
+// WithLogger traces requests, retries and cache use to l.
+func WithLogger(l *logging.Logger) Option {
+	return func(c *Client) {
+		c.log = l
 	}
 }
 
 func NewClient(ctx context.Context, ts oauth2.TokenSource, opts ...Option) (*Client, error) {
-	httpClient := oauth2.NewClient(ctx, ts)
+	httpClient := oauth2.NewClient(useragent.Context(ctx), ts)
 
 	client := &Client{
 		httpClient:  httpClient,
 		tokenSource: ts,
-		retries:     defaultRetry,
-		backoff:     initialDelay,
+		retry:       DefaultRetryPolicy(),
 	}
 
 	for _, opt := range opts {
@@ -62,10 +130,16 @@ func NewClient(ctx context.Context, ts oauth2.TokenSource, opts ...Option) (*Cli
 }
 
 func NewClientFromToken(ctx context.Context, cfg *oauth2.Config, token *oauth2.Token, opts ...Option) (*Client, error) {
-	ts := cfg.TokenSource(ctx, token)
+	ts := cfg.TokenSource(useragent.Context(ctx), token)
 	return NewClient(ctx, ts, opts...)
 }
 
+// HTTPClient returns the authorized HTTP client, for requests the JSON
+// helpers can't make, such as Drive uploads.
+func (c *Client) HTTPClient() *http.Client {
+	return c.httpClient
+}
+
 type APIError struct {
 	Code    int    `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
@@ -112,6 +186,40 @@ func IsRateLimited(err error) bool {
 	return false
 }
 
+// IsInsufficientScope reports whether the token wasn't granted a scope the
+// request needs.
+func IsInsufficientScope(err error) bool {
+	const msg = "insufficient authentication scopes"
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 403 && strings.Contains(apiErr.Message, msg)
+	}
+	var gae *googleapi.Error
+	if errors.As(err, &gae) {
+		return gae.Code == http.StatusForbidden && strings.Contains(gae.Message, msg)
+	}
+	return false
+}
+
+// IsAPIDisabled reports whether a Google Workspace admin has blocked this
+// account from the Classroom API, either by turning off Classroom's API
+// access for users or by not trusting gc-cli's OAuth client in the domain.
+func IsAPIDisabled(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 403 && strings.Contains(apiErr.Message, "@ClassroomApiDisabled")
+	}
+	var gae *googleapi.Error
+	if errors.As(err, &gae) {
+		return gae.Code == http.StatusForbidden && strings.Contains(gae.Message, "@ClassroomApiDisabled")
+	}
+	var re *oauth2.RetrieveError
+	if errors.As(err, &re) {
+		return re.ErrorCode == "admin_policy_enforced"
+	}
+	return false
+}
+
 type GoogleAPIErrorResponse struct {
 	Error GoogleAPIError `json:"error"`
 }
@@ -135,6 +243,9 @@ func (c *Client) parseError(resp *http.Response) error {
 	if err != nil {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
+	if resp.Request != nil {
+		c.log.Body(resp.Request.Method+" "+logging.SanitizeURL(resp.Request.URL.String())+" error response", body)
+	}
 
 	var wrappedErr GoogleAPIErrorResponse
 	if err := json.Unmarshal(body, &wrappedErr); err == nil && wrappedErr.Error.Code != 0 {
@@ -178,99 +289,142 @@ func (c *Client) doRequest(ctx context.Context, method, url string, body io.Read
 	return resp, nil
 }
 
-func (c *Client) doRequestWithRetry(ctx context.Context, method, url string, body io.Reader) (*http.Response, error) {
-	var lastErr error
-	backoff := c.backoff
+// closeWithError reads the error out of a failed response and closes it.
+func (c *Client) closeWithError(resp *http.Response) error {
+	defer resp.Body.Close()
+	return c.parseError(resp)
+}
+
+// doRequestWithRetry sends the request, retrying as c.retry says. The body
+// is a byte slice so each attempt can send it again.
+func (c *Client) doRequestWithRetry(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	backoff := c.retry.Backoff
+	logURL := logging.SanitizeURL(url)
 
-	for i := 0; i <= c.retries; i++ {
-		resp, err := c.doRequest(ctx, method, url, body)
+	for i := 0; ; i++ {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		c.log.Debug("sending request", "method", method, "url", logURL, "attempt", i+1)
+		start := time.Now()
+		resp, err := c.doRequest(ctx, method, url, reader)
 		if err != nil {
+			c.log.Info("request failed", "method", method, "url", logURL, "duration", time.Since(start), "err", err)
 			return nil, err
 		}
-
-		if resp.StatusCode == 429 {
-			resp.Body.Close()
-			if i < c.retries {
-				select {
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				case <-time.After(backoff):
-					backoff *= 2
-					if backoff > maxDelay {
-						backoff = maxDelay
-					}
-					continue
-				}
-			}
-			return nil, c.parseError(resp)
-		}
+		c.log.Info("request", "method", method, "url", logURL, "status", resp.StatusCode, "duration", time.Since(start), "attempt", i+1)
 
 		if resp.StatusCode < 400 {
 			return resp, nil
 		}
-
-		if resp.StatusCode == 404 || resp.StatusCode == 403 {
-			return resp, c.parseError(resp)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || i >= c.retry.Retries {
+			return resp, c.closeWithError(resp)
 		}
 
-		if resp.StatusCode >= 500 {
-			resp.Body.Close()
-			if i < c.retries {
-				select {
-				case <-ctx.Done():
-					return nil, ctx.Err()
-				case <-time.After(backoff):
-					backoff *= 2
-					if backoff > maxDelay {
-						backoff = maxDelay
-					}
-					continue
-				}
-			}
+		wait, ok := c.retry.wait(resp, backoff, time.Now())
+		if !ok {
+			c.log.Info("not retrying", "method", method, "url", logURL, "status", resp.StatusCode, "retry_after", wait)
+			return resp, c.closeWithError(resp)
 		}
-
-		return resp, c.parseError(resp)
+		resp.Body.Close()
+		c.log.Info("retrying", "method", method, "url", logURL, "status", resp.StatusCode, "wait", wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		c.stats.recordRetry()
+		backoff = c.retry.next(backoff)
 	}
-
-	return nil, lastErr
 }
 
 func (c *Client) get(ctx context.Context, endpoint string, params url.Values) ([]byte, error) {
-	url := baseURL + endpoint
-	if len(params) > 0 {
-		url += "?" + params.Encode()
-	}
+	return c.send(ctx, http.MethodGet, baseURL, endpoint, params, nil)
+}
 
-	resp, err := c.doRequestWithRetry(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+func (c *Client) patch(ctx context.Context, endpoint string, params url.Values, body []byte) ([]byte, error) {
+	return c.send(ctx, http.MethodPatch, baseURL, endpoint, params, body)
+}
 
-	if resp.StatusCode >= 400 {
-		return nil, c.parseError(resp)
-	}
+func (c *Client) post(ctx context.Context, endpoint string, params url.Values, body []byte) ([]byte, error) {
+	return c.send(ctx, http.MethodPost, baseURL, endpoint, params, body)
+}
 
-	return io.ReadAll(resp.Body)
+func (c *Client) delete(ctx context.Context, endpoint string) ([]byte, error) {
+	return c.send(ctx, http.MethodDelete, baseURL, endpoint, nil, nil)
 }
 
-func (c *Client) patch(ctx context.Context, endpoint string, params url.Values, body []byte) ([]byte, error) {
-	url := baseURL + endpoint
+// send issues a request against base+endpoint. The base is a parameter so
+// the same retry and stats handling covers companion APIs such as Drive.
+func (c *Client) send(ctx context.Context, method, base, endpoint string, params url.Values, body []byte) ([]byte, error) {
+	return c.sendCached(ctx, method, base, endpoint, params, body, true)
+}
+
+// sendCached is send with the response cache optional for reads. Writes
+// invalidate it either way.
+func (c *Client) sendCached(ctx context.Context, method, base, endpoint string, params url.Values, body []byte, useCache bool) ([]byte, error) {
+	if c.readOnly && method != http.MethodGet {
+		return nil, ErrReadOnly
+	}
+
+	url := base + endpoint
 	if len(params) > 0 {
 		url += "?" + params.Encode()
 	}
 
-	resp, err := c.doRequestWithRetry(ctx, http.MethodPatch, url, strings.NewReader(string(body)))
+	// Only Classroom reads are cached; file downloads and companion APIs
+	// always go to the network.
+	cacheable := useCache && c.cache != nil && method == http.MethodGet && base == baseURL && params.Get("alt") != "media"
+	if cacheable {
+		if data, ok := c.cache.Get(endpoint, url); ok {
+			c.stats.recordCacheHit()
+			c.log.Debug("cache hit", "url", logging.SanitizeURL(url), "bytes", len(data))
+			if c.filter != nil {
+				data = c.filter(data)
+			}
+			return data, nil
+		}
+	}
+
+	if body != nil {
+		c.log.Body(method+" "+logging.SanitizeURL(url)+" request", body)
+	}
+
+	resp, err := c.doRequestWithRetry(ctx, method, url, body)
 	if err != nil {
+		c.stats.recordCall(method, endpoint, len(body))
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
+		c.stats.recordCall(method, endpoint, len(body))
 		return nil, c.parseError(resp)
 	}
 
-	return io.ReadAll(resp.Body)
+	data, err := io.ReadAll(resp.Body)
+	c.stats.recordCall(method, endpoint, len(body)+len(data))
+	// File downloads are left out of dumps; they're rarely the problem.
+	if params.Get("alt") != "media" {
+		c.log.Body(method+" "+logging.SanitizeURL(url)+" response", data)
+	}
+	if err == nil && c.cache != nil {
+		if cacheable {
+			c.cache.Put(endpoint, url, data)
+		} else if method != http.MethodGet && base == baseURL {
+			c.log.Debug("invalidating cache", "endpoint", endpoint)
+			c.cache.Invalidate(endpoint)
+		}
+	}
+	// File downloads (alt=media) are passed through untouched.
+	if err == nil && c.filter != nil && method == http.MethodGet && params.Get("alt") != "media" {
+		data = c.filter(data)
+	}
+	return data, err
 }
 
 type ListResponse struct {
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sync"
+	"time"
 )
 
 type Course struct {
@@ -14,27 +16,102 @@ type Course struct {
 	Description       string          `json:"descriptionHeading"`
 	Room              string          `json:"room"`
 	OwnerID           string          `json:"ownerId"`
-	CourseState       string          `json:"courseState"`
+	CourseState       CourseState     `json:"courseState"`
 	EnrollmentCode    string          `json:"enrollmentCode"`
 	CourseTheme       string          `json:"courseTheme"`
 	AlternateLink     string          `json:"alternateLink"`
 	TeacherGroupEmail string          `json:"teacherGroupEmail"`
 	CourseGroupEmail  string          `json:"courseGroupEmail"`
+	CreationTime      time.Time       `json:"creationTime"`
 	TeacherFolder     json.RawMessage `json:"teacherFolder,omitempty"`
 	CloningOptions    json.RawMessage `json:"cloningOptions,omitempty"`
 }
 
+// OwnedBy reports whether userID owns the course. Its owner always teaches
+// it, but co-teachers don't own it; GetTeacher finds them.
+func (c Course) OwnedBy(userID string) bool {
+	return userID != "" && c.OwnerID == userID
+}
+
 type CourseList struct {
 	Courses       []Course `json:"courses"`
 	NextPageToken string   `json:"nextPageToken,omitempty"`
 }
 
-func (c *Client) ListCourses(ctx context.Context, pageSize int) ([]Course, string, error) {
+// maxCourseStateFetches bounds how many course states ListCourses pages
+// through at once.
+const maxCourseStateFetches = 3
+
+// ListCourses fetches courses page by page, checking ctx between pages. Page
+// tokens only come with the previous page, so a single listing can't be
+// fetched ahead; with WithCourseStates each state is paged concurrently
+// instead, and the results are returned in the order the states were given.
+func (c *Client) ListCourses(ctx context.Context, pageSize int, opts ...CallOption) ([]Course, string, error) {
+	o := collectCallOptions(opts)
+	if len(o.courseStates) <= 1 {
+		return c.listCoursePages(ctx, pageSize, o.courseStates, o.limit, opts)
+	}
+
+	// The first failure cancels the other fetches.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([][]Course, len(o.courseStates))
+	sem := make(chan struct{}, maxCourseStateFetches)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i, state := range o.courseStates {
+		wg.Add(1)
+		go func(i int, state CourseState) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			courses, _, err := c.listCoursePages(ctx, pageSize, []CourseState{state}, o.limit, opts)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = courses
+		}(i, state)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, "", firstErr
+	}
+
+	var allCourses []Course
+	for _, courses := range results {
+		allCourses = append(allCourses, courses...)
+	}
+	if o.limit > 0 && len(allCourses) > o.limit {
+		allCourses = allCourses[:o.limit]
+	}
+	return allCourses, "", nil
+}
+
+func (c *Client) listCoursePages(ctx context.Context, pageSize int, states []CourseState, limit int, opts []CallOption) ([]Course, string, error) {
+	if limit > 0 && (pageSize <= 0 || limit < pageSize) {
+		pageSize = limit
+	}
+
 	var allCourses []Course
 	var pageToken string
 
 	for {
-		params := buildListParams(pageSize, pageToken)
+		if err := ctx.Err(); err != nil {
+			return nil, "", fmt.Errorf("failed to list courses: %w", err)
+		}
+
+		params := applyFields(buildListParams(pageSize, pageToken), "courses", opts)
+		for _, state := range states {
+			params.Add("courseStates", string(state))
+		}
 		resp, err := c.get(ctx, "/courses", params)
 		if err != nil {
 			return nil, "", fmt.Errorf("failed to list courses: %w", err)
@@ -46,19 +123,35 @@ func (c *Client) ListCourses(ctx context.Context, pageSize int) ([]Course, strin
 		}
 
 		allCourses = append(allCourses, result.Courses...)
+		if len(collectCallOptions(opts).fields) == 0 {
+			c.rememberCourses(result.Courses...)
+		}
+		pageToken = result.NextPageToken
 
-		if result.NextPageToken == "" {
+		if pageToken == "" {
+			break
+		}
+		if limit > 0 && len(allCourses) >= limit {
+			allCourses = allCourses[:limit]
 			break
 		}
-		pageToken = result.NextPageToken
 	}
 
 	return allCourses, pageToken, nil
 }
 
-func (c *Client) GetCourse(ctx context.Context, courseID string) (*Course, error) {
+// GetCourse fetches a course, or returns it straight away if this client
+// has already seen it in full.
+func (c *Client) GetCourse(ctx context.Context, courseID string, opts ...CallOption) (*Course, error) {
+	full := len(collectCallOptions(opts).fields) == 0
+	if full {
+		if course, ok := c.memoizedCourse(courseID); ok {
+			return course, nil
+		}
+	}
+
 	endpoint := fmt.Sprintf("/courses/%s", url.PathEscape(courseID))
-	resp, err := c.get(ctx, endpoint, nil)
+	resp, err := c.get(ctx, endpoint, applyFields(nil, "", opts))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get course %s: %w", courseID, err)
 	}
@@ -67,6 +160,79 @@ func (c *Client) GetCourse(ctx context.Context, courseID string) (*Course, error
 	if err := json.Unmarshal(resp, &course); err != nil {
 		return nil, fmt.Errorf("failed to parse course: %w", err)
 	}
+	if full {
+		c.rememberCourses(course)
+	}
 
 	return &course, nil
 }
+
+// KnownCourse returns the course if it is known without a request: seen
+// earlier by this client or in a still-fresh cached response.
+func (c *Client) KnownCourse(courseID string) (*Course, bool) {
+	if course, ok := c.memoizedCourse(courseID); ok {
+		return course, true
+	}
+	if c.cache == nil {
+		return nil, false
+	}
+	endpoint := fmt.Sprintf("/courses/%s", url.PathEscape(courseID))
+	data, ok := c.cache.Get(endpoint, baseURL+endpoint)
+	if !ok {
+		return nil, false
+	}
+	if c.filter != nil {
+		data = c.filter(data)
+	}
+	var course Course
+	if json.Unmarshal(data, &course) != nil || course.ID == "" {
+		return nil, false
+	}
+	c.rememberCourses(course)
+	return &course, true
+}
+
+func (c *Client) memoizedCourse(courseID string) (*Course, bool) {
+	c.courseMu.Lock()
+	defer c.courseMu.Unlock()
+	course, ok := c.courses[courseID]
+	return &course, ok
+}
+
+func (c *Client) rememberCourses(courses ...Course) {
+	c.courseMu.Lock()
+	defer c.courseMu.Unlock()
+	if c.courses == nil {
+		c.courses = make(map[string]Course)
+	}
+	for _, course := range courses {
+		c.courses[course.ID] = course
+	}
+}
+
+type GradingPeriod struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	StartDate *Date  `json:"startDate,omitempty"`
+	EndDate   *Date  `json:"endDate,omitempty"`
+}
+
+type GradingPeriodSettings struct {
+	GradingPeriods            []GradingPeriod `json:"gradingPeriods"`
+	ApplyToExistingCoursework bool            `json:"applyToExistingCoursework,omitempty"`
+}
+
+func (c *Client) GetGradingPeriodSettings(ctx context.Context, courseID string) (*GradingPeriodSettings, error) {
+	endpoint := fmt.Sprintf("/courses/%s/gradingPeriodSettings", url.PathEscape(courseID))
+	resp, err := c.get(ctx, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grading periods for course %s: %w", courseID, err)
+	}
+
+	var settings GradingPeriodSettings
+	if err := json.Unmarshal(resp, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse grading period settings: %w", err)
+	}
+
+	return &settings, nil
+}
@@ -5,34 +5,99 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 )
 
 type CourseWork struct {
-	ID                         string          `json:"id"`
-	CourseID                   string          `json:"courseId"`
-	Title                      string          `json:"title"`
-	Description                string          `json:"description"`
-	State                      string          `json:"state"`
-	WorkType                   string          `json:"workType"`
-	MaxPoints                  int64           `json:"maxPoints,omitempty"`
-	DueDate                    *Date           `json:"dueDate,omitempty"`
-	DueTime                    *TimeOfDay      `json:"dueTime,omitempty"`
-	ScheduledDate              *Date           `json:"scheduledDate,omitempty"`
-	ScheduledTime              *TimeOfDay      `json:"scheduledTime,omitempty"`
-	AllowLateSubmission        bool            `json:"allowLateSubmission"`
-	SubmissionModificationTime time.Time       `json:"submissionModificationTime,omitempty"`
-	CreateTime                 time.Time       `json:"createTime,omitempty"`
-	UpdateTime                 time.Time       `json:"updateTime,omitempty"`
-	DraftGrade                 json.RawMessage `json:"draftGrade,omitempty"`
-	AssignedGrade              json.RawMessage `json:"assignedGrade,omitempty"`
-	CourseWorkMaterial         json.RawMessage `json:"courseWorkMaterial,omitempty"`
-	Assignment                 json.RawMessage `json:"assignment,omitempty"`
-	MultipleChoiceQuestion     json.RawMessage `json:"multipleChoiceQuestion,omitempty"`
-	AlternateLink              string          `json:"alternateLink,omitempty"`
-	TeacherFolder              json.RawMessage `json:"teacherFolder,omitempty"`
-	TopicID                    string          `json:"topicId,omitempty"`
-	GradeCategory              json.RawMessage `json:"gradeCategory,omitempty"`
+	ID                         string            `json:"id"`
+	CourseID                   string            `json:"courseId"`
+	Title                      string            `json:"title"`
+	Description                string            `json:"description"`
+	State                      CourseWorkState   `json:"state"`
+	WorkType                   WorkType          `json:"workType"`
+	MaxPoints                  int64             `json:"maxPoints,omitempty"`
+	DueDate                    *Date             `json:"dueDate,omitempty"`
+	DueTime                    *TimeOfDay        `json:"dueTime,omitempty"`
+	ScheduledDate              *Date             `json:"scheduledDate,omitempty"`
+	ScheduledTime              *time.Time        `json:"scheduledTime,omitempty"`
+	AllowLateSubmission        bool              `json:"allowLateSubmission"`
+	SubmissionModificationTime time.Time         `json:"submissionModificationTime,omitempty"`
+	CreateTime                 time.Time         `json:"createTime,omitempty"`
+	UpdateTime                 time.Time         `json:"updateTime,omitempty"`
+	DraftGrade                 json.RawMessage   `json:"draftGrade,omitempty"`
+	AssignedGrade              json.RawMessage   `json:"assignedGrade,omitempty"`
+	CourseWorkMaterial         json.RawMessage   `json:"courseWorkMaterial,omitempty"`
+	Assignment                 json.RawMessage   `json:"assignment,omitempty"`
+	MultipleChoiceQuestion     json.RawMessage   `json:"multipleChoiceQuestion,omitempty"`
+	AlternateLink              string            `json:"alternateLink,omitempty"`
+	TeacherFolder              json.RawMessage   `json:"teacherFolder,omitempty"`
+	TopicID                    string            `json:"topicId,omitempty"`
+	GradeCategory              json.RawMessage   `json:"gradeCategory,omitempty"`
+	Materials                  []Material        `json:"materials,omitempty"`
+	AddOnAttachments           []AddOnAttachment `json:"addOnAttachments,omitempty"`
+}
+
+// Material is a resource attached to coursework by the teacher.
+type Material struct {
+	DriveFile    *SharedDriveFile `json:"driveFile,omitempty"`
+	YouTubeVideo *YouTubeVideo    `json:"youtubeVideo,omitempty"`
+	Link         *Link            `json:"link,omitempty"`
+	Form         *Form            `json:"form,omitempty"`
+}
+
+// AddOnAttachment is content provided by a Classroom add-on such as Kami or
+// Edpuzzle. Assignments built entirely from add-ons have no other materials.
+type AddOnAttachment struct {
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	StudentViewURI *EmbedURI `json:"studentViewUri,omitempty"`
+	TeacherViewURI *EmbedURI `json:"teacherViewUri,omitempty"`
+}
+
+type EmbedURI struct {
+	URI string `json:"uri"`
+}
+
+// OpenURL is where a student opens the attachment, or "" if the add-on
+// only works from inside Classroom.
+func (a AddOnAttachment) OpenURL() string {
+	if a.StudentViewURI != nil && a.StudentViewURI.URI != "" {
+		return a.StudentViewURI.URI
+	}
+	if a.TeacherViewURI != nil {
+		return a.TeacherViewURI.URI
+	}
+	return ""
+}
+
+// SharedDriveFile is a Drive file material along with how it is shared with
+// students (VIEW, EDIT or STUDENT_COPY).
+type SharedDriveFile struct {
+	DriveFile DriveFile `json:"driveFile"`
+	ShareMode string    `json:"shareMode,omitempty"`
+}
+
+// GradeCategory is a course's grade category, such as Homework or Tests.
+// Weight is the category's share of the overall grade in millionths
+// (123400 is 12.34%); zero when the course doesn't weight categories.
+type GradeCategory struct {
+	ID                      string `json:"id"`
+	Name                    string `json:"name"`
+	Weight                  int    `json:"weight,omitempty"`
+	DefaultGradeDenominator int    `json:"defaultGradeDenominator,omitempty"`
+}
+
+// Category decodes GradeCategory, returning nil for uncategorised work.
+func (cw CourseWork) Category() *GradeCategory {
+	if len(cw.GradeCategory) == 0 {
+		return nil
+	}
+	var category GradeCategory
+	if err := json.Unmarshal(cw.GradeCategory, &category); err != nil || category.Name == "" {
+		return nil
+	}
+	return &category
 }
 
 type Date struct {
@@ -52,12 +117,12 @@ type CourseWorkList struct {
 	NextPageToken string       `json:"nextPageToken,omitempty"`
 }
 
-func (c *Client) ListCourseWork(ctx context.Context, courseID string, pageSize int) ([]CourseWork, string, error) {
+func (c *Client) ListCourseWork(ctx context.Context, courseID string, pageSize int, opts ...CallOption) ([]CourseWork, string, error) {
 	var allCourseWork []CourseWork
 	var pageToken string
 
 	for {
-		params := buildListParams(pageSize, pageToken)
+		params := applyFields(buildListParams(pageSize, pageToken), "courseWork", opts)
 		endpoint := fmt.Sprintf("/courses/%s/courseWork", url.PathEscape(courseID))
 		resp, err := c.get(ctx, endpoint, params)
 		if err != nil {
@@ -80,9 +145,9 @@ func (c *Client) ListCourseWork(ctx context.Context, courseID string, pageSize i
 	return allCourseWork, pageToken, nil
 }
 
-func (c *Client) GetCourseWork(ctx context.Context, courseID, courseWorkID string) (*CourseWork, error) {
+func (c *Client) GetCourseWork(ctx context.Context, courseID, courseWorkID string, opts ...CallOption) (*CourseWork, error) {
 	endpoint := fmt.Sprintf("/courses/%s/courseWork/%s", url.PathEscape(courseID), url.PathEscape(courseWorkID))
-	resp, err := c.get(ctx, endpoint, nil)
+	resp, err := c.get(ctx, endpoint, applyFields(nil, "", opts))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get coursework %s in course %s: %w", courseWorkID, courseID, err)
 	}
@@ -94,3 +159,43 @@ func (c *Client) GetCourseWork(ctx context.Context, courseID, courseWorkID strin
 
 	return &cw, nil
 }
+
+// IsScheduled reports whether a draft is set to publish automatically.
+func (cw *CourseWork) IsScheduled() bool {
+	return cw.State == CourseWorkDraft && cw.ScheduledTime != nil && !cw.ScheduledTime.IsZero()
+}
+
+type CourseWorkUpdate struct {
+	Title         string          `json:"title,omitempty"`
+	Description   string          `json:"description,omitempty"`
+	State         CourseWorkState `json:"state,omitempty"`
+	MaxPoints     int64           `json:"maxPoints,omitempty"`
+	DueDate       *Date           `json:"dueDate,omitempty"`
+	DueTime       *TimeOfDay      `json:"dueTime,omitempty"`
+	ScheduledTime *time.Time      `json:"scheduledTime,omitempty"`
+}
+
+// PatchCourseWork updates the fields named in updateMask. Only the teacher who
+// owns the course (and the developer project that created the coursework)
+// may patch it.
+func (c *Client) PatchCourseWork(ctx context.Context, courseID, courseWorkID string, update *CourseWorkUpdate, updateMask ...string) (*CourseWork, error) {
+	endpoint := fmt.Sprintf("/courses/%s/courseWork/%s", url.PathEscape(courseID), url.PathEscape(courseWorkID))
+
+	body, err := json.Marshal(update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal coursework update: %w", err)
+	}
+
+	params := buildParams("updateMask", strings.Join(updateMask, ","))
+	resp, err := c.patch(ctx, endpoint, params, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch coursework %s in course %s: %w", courseWorkID, courseID, err)
+	}
+
+	var cw CourseWork
+	if err := json.Unmarshal(resp, &cw); err != nil {
+		return nil, fmt.Errorf("failed to parse coursework: %w", err)
+	}
+
+	return &cw, nil
+}
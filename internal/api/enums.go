@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CourseState is a course's lifecycle state.
+type CourseState string
+
+const (
+	CourseActive      CourseState = "ACTIVE"
+	CourseArchived    CourseState = "ARCHIVED"
+	CourseProvisioned CourseState = "PROVISIONED"
+	CourseDeclined    CourseState = "DECLINED"
+	CourseSuspended   CourseState = "SUSPENDED"
+)
+
+var courseStates = []string{string(CourseActive), string(CourseArchived), string(CourseProvisioned), string(CourseDeclined), string(CourseSuspended)}
+
+func ParseCourseState(s string) (CourseState, error) {
+	v, err := parseEnum("course state", s, courseStates)
+	return CourseState(v), err
+}
+
+// CourseWorkState is whether coursework is visible to students.
+type CourseWorkState string
+
+const (
+	CourseWorkPublished CourseWorkState = "PUBLISHED"
+	CourseWorkDraft     CourseWorkState = "DRAFT"
+	CourseWorkDeleted   CourseWorkState = "DELETED"
+)
+
+var courseWorkStates = []string{string(CourseWorkPublished), string(CourseWorkDraft), string(CourseWorkDeleted)}
+
+func ParseCourseWorkState(s string) (CourseWorkState, error) {
+	v, err := parseEnum("coursework state", s, courseWorkStates)
+	return CourseWorkState(v), err
+}
+
+// AnnouncementState is whether an announcement is visible to students.
+type AnnouncementState string
+
+const (
+	AnnouncementPublished AnnouncementState = "PUBLISHED"
+	AnnouncementDraft     AnnouncementState = "DRAFT"
+	AnnouncementDeleted   AnnouncementState = "DELETED"
+)
+
+// WorkType is the kind of coursework.
+type WorkType string
+
+const (
+	WorkTypeAssignment     WorkType = "ASSIGNMENT"
+	WorkTypeShortAnswer    WorkType = "SHORT_ANSWER_QUESTION"
+	WorkTypeMultipleChoice WorkType = "MULTIPLE_CHOICE_QUESTION"
+)
+
+var workTypes = []string{string(WorkTypeAssignment), string(WorkTypeShortAnswer), string(WorkTypeMultipleChoice)}
+
+func ParseWorkType(s string) (WorkType, error) {
+	v, err := parseEnum("work type", s, workTypes)
+	return WorkType(v), err
+}
+
+// SubmissionState is where a student submission is in the turn-in cycle.
+type SubmissionState string
+
+const (
+	SubmissionNew       SubmissionState = "NEW"
+	SubmissionCreated   SubmissionState = "CREATED"
+	SubmissionTurnedIn  SubmissionState = "TURNED_IN"
+	SubmissionReturned  SubmissionState = "RETURNED"
+	SubmissionReclaimed SubmissionState = "RECLAIMED_BY_STUDENT"
+)
+
+var submissionStates = []string{string(SubmissionNew), string(SubmissionCreated), string(SubmissionTurnedIn), string(SubmissionReturned), string(SubmissionReclaimed)}
+
+func ParseSubmissionState(s string) (SubmissionState, error) {
+	v, err := parseEnum("submission state", s, submissionStates)
+	return SubmissionState(v), err
+}
+
+// Done reports whether the work has been handed in, whether or not it has
+// been graded yet.
+func (s SubmissionState) Done() bool {
+	return s == SubmissionTurnedIn || s == SubmissionReturned
+}
+
+// parseEnum matches user input such as "turned-in" or "Published" against
+// the API's values, suggesting the closest one when nothing matches.
+func parseEnum(kind, s string, valid []string) (string, error) {
+	norm := strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(strings.TrimSpace(s)))
+	for _, v := range valid {
+		if norm == v {
+			return v, nil
+		}
+	}
+
+	lower := make([]string, len(valid))
+	for i, v := range valid {
+		lower[i] = strings.ToLower(v)
+	}
+	msg := fmt.Sprintf("unknown %s %q (want one of %s)", kind, s, strings.Join(lower, ", "))
+	if guess := closest(norm, valid); guess != "" {
+		msg = fmt.Sprintf("unknown %s %q; did you mean %q?", kind, s, strings.ToLower(guess))
+	}
+	return "", fmt.Errorf("%s", msg)
+}
+
+// closest returns the valid value nearest to s, or "" when none is close
+// enough to be a plausible typo.
+func closest(s string, valid []string) string {
+	best, bestDist := "", -1
+	for _, v := range valid {
+		d := editDistance(s, v)
+		if strings.HasPrefix(v, s) && s != "" {
+			d = 0
+		}
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = v, d
+		}
+	}
+	if bestDist > len(best)/3+1 {
+		return ""
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j] + 1
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+			if prev[j-1]+cost < cur[j] {
+				cur[j] = prev[j-1] + cost
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
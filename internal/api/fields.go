@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CallOption customizes a single API call, as opposed to Option which
+// configures the whole client.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	fields       []string
+	limit        int
+	courseStates []CourseState
+	submissions  SubmissionFilter
+}
+
+// WithFields restricts the response to the given fields via the API's
+// partial response mask, e.g. WithFields("id", "title", "dueDate"). For list
+// calls the fields apply to each item; the page token is always kept.
+func WithFields(fields ...string) CallOption {
+	return func(o *callOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+// WithLimit stops a list call once it has n items, rather than fetching
+// every page. The returned page token then resumes after the last page read.
+func WithLimit(n int) CallOption {
+	return func(o *callOptions) {
+		o.limit = n
+	}
+}
+
+// WithCourseStates restricts ListCourses to courses in the given states,
+// e.g. WithCourseStates(CourseActive). Several states are fetched
+// concurrently.
+func WithCourseStates(states ...CourseState) CallOption {
+	return func(o *callOptions) {
+		o.courseStates = append(o.courseStates, states...)
+	}
+}
+
+// WithSubmissionFilter has ListStudentSubmissions return only the
+// submissions f matches, filtered by the API rather than after download.
+func WithSubmissionFilter(f SubmissionFilter) CallOption {
+	return func(o *callOptions) {
+		o.submissions = f
+	}
+}
+
+func collectCallOptions(opts []CallOption) callOptions {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// applyFields adds the fields mask to params. collection is the JSON key of
+// the item array for list calls and empty for single-resource calls.
+func applyFields(params url.Values, collection string, opts []CallOption) url.Values {
+	o := collectCallOptions(opts)
+	if len(o.fields) == 0 {
+		return params
+	}
+	if params == nil {
+		params = url.Values{}
+	}
+
+	mask := strings.Join(o.fields, ",")
+	if collection != "" {
+		mask = "nextPageToken," + collection + "(" + mask + ")"
+	}
+	params.Set("fields", mask)
+	return params
+}
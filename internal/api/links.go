@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
+
+// ClassroomLink is the result of decoding a classroom.google.com URL.
+type ClassroomLink struct {
+	CourseID string
+	ItemID   string
+}
+
+// ParseClassroomLink decodes the base64 course (and coursework or post)
+// tokens embedded in links copied from the Classroom web UI, e.g.
+// https://classroom.google.com/c/NjAxMjM0NTY3ODkw/a/NzAxMjM0NTY3ODkw/details.
+func ParseClassroomLink(s string) (ClassroomLink, bool) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return ClassroomLink{}, false
+	}
+	if host := strings.ToLower(u.Hostname()); host != "classroom.google.com" && !strings.HasSuffix(host, ".classroom.google.com") {
+		return ClassroomLink{}, false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	var link ClassroomLink
+	for i := 0; i+1 < len(parts); i++ {
+		switch parts[i] {
+		case "c", "w", "r":
+			if link.CourseID == "" {
+				link.CourseID = decodeLinkToken(parts[i+1])
+			}
+		case "a", "p", "m", "sa":
+			if link.ItemID == "" {
+				link.ItemID = decodeLinkToken(parts[i+1])
+			}
+		}
+	}
+
+	return link, link.CourseID != ""
+}
+
+// decodeLinkToken decodes a numeric ID from its URL token, returning the
+// token unchanged if it isn't base64-encoded digits.
+func decodeLinkToken(token string) string {
+	for _, enc := range []*base64.Encoding{base64.RawURLEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.StdEncoding} {
+		decoded, err := enc.DecodeString(token)
+		if err == nil && len(decoded) > 0 && isDigits(string(decoded)) {
+			return string(decoded)
+		}
+	}
+	return token
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
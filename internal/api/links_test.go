@@ -0,0 +1,29 @@
+package api
+
+import "testing"
+
+func TestParseClassroomLink(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		in   string
+		want ClassroomLink
+		ok   bool
+	}{
+		{"course", "https://classroom.google.com/c/NjAxMjM0NTY3ODkw", ClassroomLink{CourseID: "601234567890"}, true},
+		{"coursework", " https://classroom.google.com/c/NjAxMjM0NTY3ODkw/a/NzAxMjM0NTY3ODkw/details\n", ClassroomLink{CourseID: "601234567890", ItemID: "701234567890"}, true},
+		{"account path", "https://classroom.google.com/u/1/c/NjAxMjM0NTY3ODkw", ClassroomLink{CourseID: "601234567890"}, true},
+		{"numeric token", "https://classroom.google.com/c/601234567890", ClassroomLink{CourseID: "601234567890"}, true},
+		{"bad base64", "https://classroom.google.com/c/not*base64", ClassroomLink{CourseID: "not*base64"}, true},
+		{"lookalike host", "https://evilclassroom.google.com/c/NjAxMjM0NTY3ODkw", ClassroomLink{}, false},
+		{"host as subdomain", "https://classroom.google.com.evil.com/c/NjAxMjM0NTY3ODkw", ClassroomLink{}, false},
+		{"no course", "https://classroom.google.com/h", ClassroomLink{}, false},
+		{"plain ID", "601234567890", ClassroomLink{}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseClassroomLink(tt.in)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseClassroomLink(%q) = %+v, %v; want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Raw sends an arbitrary request with the client's credentials, retries and
+// stats, for endpoints gc-cli doesn't wrap yet. path is relative to the
+// Classroom API (e.g. "/courses/123/courseWork") or a full googleapis.com
+// URL for companion APIs such as Drive. Reads bypass the response cache, so
+// the answer is always the API's current one.
+func (c *Client) Raw(ctx context.Context, method, path string, params url.Values, body []byte) ([]byte, error) {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+	default:
+		return nil, fmt.Errorf("unsupported method %s", method)
+	}
+
+	base := baseURL
+	if strings.HasPrefix(path, "https://") {
+		u, err := url.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %q: %w", path, err)
+		}
+		// Never send the token anywhere but Google's APIs.
+		if u.Host != "googleapis.com" && !strings.HasSuffix(u.Host, ".googleapis.com") {
+			return nil, fmt.Errorf("refusing to send credentials to %s; only googleapis.com URLs are allowed", u.Host)
+		}
+		if params == nil {
+			params = url.Values{}
+		}
+		for k, vs := range u.Query() {
+			for _, v := range vs {
+				params.Add(k, v)
+			}
+		}
+		base = u.Scheme + "://" + u.Host
+		path = u.Path
+	} else if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return c.sendCached(ctx, method, base, path, params, body, false)
+}
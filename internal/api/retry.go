@@ -0,0 +1,107 @@
+package api
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy says how requests that were rate limited (429) or failed on
+// the server (5xx) are retried.
+type RetryPolicy struct {
+	// Retries is how many times a request is retried after the first try.
+	Retries int
+	// Backoff is the first wait, doubled after each retry up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Jitter randomizes each wait by up to this fraction either way, so
+	// clients limited at the same moment don't all come back at once.
+	Jitter float64
+	// MaxRetryAfter is the longest a Retry-After header may make a request
+	// wait. Asking for longer fails the request instead.
+	MaxRetryAfter time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Retries:       defaultRetry,
+		Backoff:       initialDelay,
+		MaxBackoff:    maxDelay,
+		Jitter:        0.2,
+		MaxRetryAfter: time.Minute,
+	}
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func (p RetryPolicy) jittered(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+	jitterMu.Lock()
+	f := 1 + p.Jitter*(2*jitterRand.Float64()-1)
+	jitterMu.Unlock()
+	return time.Duration(float64(d) * f)
+}
+
+// wait returns how long to wait before retrying resp, and false if the
+// server asked for a longer wait than the policy allows. Retry-After, when
+// given, is a minimum: it is never shortened by jitter.
+func (p RetryPolicy) wait(resp *http.Response, backoff time.Duration, now time.Time) (time.Duration, bool) {
+	d := p.jittered(backoff)
+	after, ok := retryAfter(resp, now)
+	if !ok {
+		return d, true
+	}
+	if p.MaxRetryAfter > 0 && after > p.MaxRetryAfter {
+		return after, false
+	}
+	if after > d {
+		d = after
+	}
+	return d, true
+}
+
+func (p RetryPolicy) next(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// retryAfter reads the Retry-After header of a 429 or 503 response, which
+// is either a number of seconds or an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
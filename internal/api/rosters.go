@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+type Student struct {
+	CourseID string      `json:"courseId"`
+	UserID   string      `json:"userId"`
+	Profile  UserProfile `json:"profile"`
+}
+
+type StudentList struct {
+	Students      []Student `json:"students"`
+	NextPageToken string    `json:"nextPageToken,omitempty"`
+}
+
+// ListStudents returns a course's students. Email addresses are only filled
+// in with the classroom.profile.emails scope.
+func (c *Client) ListStudents(ctx context.Context, courseID string, pageSize int, opts ...CallOption) ([]Student, string, error) {
+	var allStudents []Student
+	var pageToken string
+
+	for {
+		params := applyFields(buildListParams(pageSize, pageToken), "students", opts)
+		endpoint := fmt.Sprintf("/courses/%s/students", url.PathEscape(courseID))
+		resp, err := c.get(ctx, endpoint, params)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list students for course %s: %w", courseID, err)
+		}
+
+		var result StudentList
+		if err := json.Unmarshal(resp, &result); err != nil {
+			return nil, "", fmt.Errorf("failed to parse student list: %w", err)
+		}
+
+		for _, s := range result.Students {
+			c.rememberProfiles(s.Profile)
+		}
+		allStudents = append(allStudents, result.Students...)
+
+		if result.NextPageToken == "" {
+			break
+		}
+		pageToken = result.NextPageToken
+	}
+
+	return allStudents, pageToken, nil
+}
+
+type Teacher struct {
+	CourseID string      `json:"courseId"`
+	UserID   string      `json:"userId"`
+	Profile  UserProfile `json:"profile"`
+}
+
+type TeacherList struct {
+	Teachers      []Teacher `json:"teachers"`
+	NextPageToken string    `json:"nextPageToken,omitempty"`
+}
+
+// ListTeachers returns a course's teachers, including co-teachers. Like
+// ListStudents, email addresses need the classroom.profile.emails scope.
+func (c *Client) ListTeachers(ctx context.Context, courseID string, pageSize int, opts ...CallOption) ([]Teacher, string, error) {
+	var allTeachers []Teacher
+	var pageToken string
+
+	for {
+		params := applyFields(buildListParams(pageSize, pageToken), "teachers", opts)
+		endpoint := fmt.Sprintf("/courses/%s/teachers", url.PathEscape(courseID))
+		resp, err := c.get(ctx, endpoint, params)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list teachers for course %s: %w", courseID, err)
+		}
+
+		var result TeacherList
+		if err := json.Unmarshal(resp, &result); err != nil {
+			return nil, "", fmt.Errorf("failed to parse teacher list: %w", err)
+		}
+
+		for _, t := range result.Teachers {
+			c.rememberProfiles(t.Profile)
+		}
+		allTeachers = append(allTeachers, result.Teachers...)
+
+		if result.NextPageToken == "" {
+			break
+		}
+		pageToken = result.NextPageToken
+	}
+
+	return allTeachers, pageToken, nil
+}
+
+// GetTeacher returns one of a course's teachers, by ID, email or "me". It
+// fails with not found for anyone who doesn't teach the course, so "me"
+// tells a co-teacher from a student.
+func (c *Client) GetTeacher(ctx context.Context, courseID, userID string) (*Teacher, error) {
+	endpoint := fmt.Sprintf("/courses/%s/teachers/%s", url.PathEscape(courseID), url.PathEscape(userID))
+	resp, err := c.get(ctx, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teacher %s of course %s: %w", userID, courseID, err)
+	}
+
+	var teacher Teacher
+	if err := json.Unmarshal(resp, &teacher); err != nil {
+		return nil, fmt.Errorf("failed to parse teacher: %w", err)
+	}
+	c.rememberProfiles(teacher.Profile)
+	return &teacher, nil
+}
@@ -0,0 +1,35 @@
+package api
+
+import "context"
+
+// ClassroomService is the part of Client that reads and changes Classroom
+// data. Code that takes it instead of a *Client can run against a fake,
+// such as the demo courses, in tests and previews.
+type ClassroomService interface {
+	ListCourses(ctx context.Context, pageSize int, opts ...CallOption) ([]Course, string, error)
+	GetCourse(ctx context.Context, courseID string, opts ...CallOption) (*Course, error)
+	GetGradingPeriodSettings(ctx context.Context, courseID string) (*GradingPeriodSettings, error)
+
+	ListCourseWork(ctx context.Context, courseID string, pageSize int, opts ...CallOption) ([]CourseWork, string, error)
+	GetCourseWork(ctx context.Context, courseID, courseWorkID string, opts ...CallOption) (*CourseWork, error)
+	PatchCourseWork(ctx context.Context, courseID, courseWorkID string, update *CourseWorkUpdate, updateMask ...string) (*CourseWork, error)
+
+	ListStudentSubmissions(ctx context.Context, courseID, courseWorkID string, pageSize int, opts ...CallOption) ([]StudentSubmission, string, error)
+	GetStudentSubmission(ctx context.Context, courseID, courseWorkID, submissionID string, opts ...CallOption) (*StudentSubmission, error)
+	GetMySubmission(ctx context.Context, courseID, courseWorkID string, opts ...CallOption) (*StudentSubmission, error)
+	PatchStudentSubmission(ctx context.Context, courseID, courseWorkID, submissionID string, update *SubmissionUpdate) (*StudentSubmission, error)
+	ModifyAttachments(ctx context.Context, courseID, courseWorkID, submissionID string, add []Attachment) (*StudentSubmission, error)
+	TurnIn(ctx context.Context, courseID, courseWorkID, submissionID string) error
+
+	ListAnnouncements(ctx context.Context, courseID string, pageSize int, opts ...CallOption) ([]Announcement, string, error)
+	GetAnnouncement(ctx context.Context, courseID, announcementID string, opts ...CallOption) (*Announcement, error)
+	CreateAnnouncement(ctx context.Context, courseID string, announcement *AnnouncementCreate) (*Announcement, error)
+	DeleteAnnouncement(ctx context.Context, courseID, announcementID string) error
+
+	ListStudents(ctx context.Context, courseID string, pageSize int, opts ...CallOption) ([]Student, string, error)
+	ListTeachers(ctx context.Context, courseID string, pageSize int, opts ...CallOption) ([]Teacher, string, error)
+	GetUserProfile(ctx context.Context, userID string) (*UserProfile, error)
+	UserNames(ctx context.Context, userIDs []string) map[string]string
+}
+
+var _ ClassroomService = (*Client)(nil)
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Stats accumulates request metrics for a single command invocation so the
+// CLI can print a summary footer when --verbose is set.
+type Stats struct {
+	mu        sync.Mutex
+	calls     map[string]int
+	cacheHits int
+	bytes     int64
+	retries   int
+}
+
+type EndpointCount struct {
+	Endpoint string
+	Calls    int
+}
+
+func NewStats() *Stats {
+	return &Stats{calls: make(map[string]int)}
+}
+
+func WithStats(s *Stats) Option {
+	return func(c *Client) {
+		c.stats = s
+	}
+}
+
+func (s *Stats) recordCall(method, endpoint string, n int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls[method+" "+normalizeEndpoint(endpoint)]++
+	s.bytes += int64(n)
+}
+
+func (s *Stats) recordRetry() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries++
+}
+
+func (s *Stats) recordCacheHit() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cacheHits++
+}
+
+// Calls returns the call count per endpoint, busiest first.
+func (s *Stats) Calls() []EndpointCount {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := make([]EndpointCount, 0, len(s.calls))
+	for endpoint, n := range s.calls {
+		counts = append(counts, EndpointCount{Endpoint: endpoint, Calls: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Calls != counts[j].Calls {
+			return counts[i].Calls > counts[j].Calls
+		}
+		return counts[i].Endpoint < counts[j].Endpoint
+	})
+	return counts
+}
+
+func (s *Stats) TotalCalls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, n := range s.calls {
+		total += n
+	}
+	return total
+}
+
+func (s *Stats) CacheHits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cacheHits
+}
+
+func (s *Stats) Bytes() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bytes
+}
+
+func (s *Stats) Retries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.retries
+}
+
+// normalizeEndpoint replaces resource IDs with a placeholder so calls against
+// different courses are grouped together, e.g. /courses/{id}/courseWork.
+func normalizeEndpoint(endpoint string) string {
+	path := endpoint
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(parts); i += 2 {
+		method := ""
+		if j := strings.Index(parts[i], ":"); j >= 0 {
+			method = parts[i][j:]
+		}
+		parts[i] = "{id}" + method
+	}
+	return "/" + strings.Join(parts, "/")
+}
@@ -13,7 +13,8 @@ type StudentSubmission struct {
 	CourseID              string          `json:"courseId"`
 	CourseWorkID          string          `json:"courseWorkId"`
 	UserID                string          `json:"userId"`
-	State                 string          `json:"state"`
+	State                 SubmissionState `json:"state"`
+	Late                  bool            `json:"late,omitempty"`
 	AssignedGrade         float64         `json:"assignedGrade,omitempty"`
 	DraftGrade            float64         `json:"draftGrade,omitempty"`
 	SubmittedTimestamp    time.Time       `json:"submittedTimestamp,omitempty"`
@@ -24,21 +25,89 @@ type StudentSubmission struct {
 	ShortAnswerSubmission json.RawMessage `json:"shortAnswerSubmission,omitempty"`
 	Attachment            json.RawMessage `json:"attachment,omitempty"`
 	AlternateLink         string          `json:"alternateLink,omitempty"`
-	CourseWorkType        string          `json:"courseWorkType,omitempty"`
+	CourseWorkType        WorkType        `json:"courseWorkType,omitempty"`
 	SubmissionHistory     json.RawMessage `json:"submissionHistory,omitempty"`
 }
 
+// SubmissionHistory is one entry of a submission's history: either a state
+// change or a grade change.
+type SubmissionHistory struct {
+	StateHistory *StateHistory   `json:"stateHistory,omitempty"`
+	GradeHistory json.RawMessage `json:"gradeHistory,omitempty"`
+}
+
+type StateHistory struct {
+	State          SubmissionState `json:"state"`
+	StateTimestamp time.Time       `json:"stateTimestamp"`
+	ActorUserID    string          `json:"actorUserId,omitempty"`
+}
+
+// History decodes SubmissionHistory, oldest entry first.
+func (s StudentSubmission) History() ([]SubmissionHistory, error) {
+	if len(s.SubmissionHistory) == 0 {
+		return nil, nil
+	}
+	var history []SubmissionHistory
+	if err := json.Unmarshal(s.SubmissionHistory, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse submission history: %w", err)
+	}
+	return history, nil
+}
+
 type StudentSubmissionList struct {
 	StudentSubmissions []StudentSubmission `json:"studentSubmissions"`
 	NextPageToken      string              `json:"nextPageToken,omitempty"`
 }
 
-func (c *Client) ListStudentSubmissions(ctx context.Context, courseID, courseWorkID string, pageSize int) ([]StudentSubmission, string, error) {
+// SubmissionFilter narrows a submission listing. UserID is a user ID, email
+// address or "me"; States keeps submissions in any of the given states; Late
+// keeps only late (LateOnly) or only on-time (NotLateOnly) work. Zero fields
+// don't filter.
+type SubmissionFilter struct {
+	UserID string
+	States []SubmissionState
+	Late   LateFilter
+}
+
+// LateFilter values, as the API's late query parameter takes them.
+type LateFilter string
+
+const (
+	LateOnly    LateFilter = "LATE_ONLY"
+	NotLateOnly LateFilter = "NOT_LATE_ONLY"
+)
+
+func (f SubmissionFilter) apply(params url.Values) {
+	if f.UserID != "" {
+		params.Set("userId", f.UserID)
+	}
+	for _, state := range f.States {
+		params.Add("states", string(state))
+	}
+	if f.Late != "" {
+		params.Set("late", string(f.Late))
+	}
+}
+
+// ListStudentSubmissions lists the submissions for a coursework item, or for
+// every item in the course with courseWorkID "-". With WithLimit it stops
+// once it has that many and returns the token for the next page.
+func (c *Client) ListStudentSubmissions(ctx context.Context, courseID, courseWorkID string, pageSize int, opts ...CallOption) ([]StudentSubmission, string, error) {
+	o := collectCallOptions(opts)
+	if o.limit > 0 && (pageSize <= 0 || o.limit < pageSize) {
+		pageSize = o.limit
+	}
+
 	var allSubmissions []StudentSubmission
 	var pageToken string
 
 	for {
-		params := buildListParams(pageSize, pageToken)
+		if err := ctx.Err(); err != nil {
+			return nil, "", fmt.Errorf("failed to list submissions for coursework %s in course %s: %w", courseWorkID, courseID, err)
+		}
+
+		params := applyFields(buildListParams(pageSize, pageToken), "studentSubmissions", opts)
+		o.submissions.apply(params)
 		endpoint := fmt.Sprintf("/courses/%s/courseWork/%s/studentSubmissions", url.PathEscape(courseID), url.PathEscape(courseWorkID))
 		resp, err := c.get(ctx, endpoint, params)
 		if err != nil {
@@ -51,20 +120,24 @@ func (c *Client) ListStudentSubmissions(ctx context.Context, courseID, courseWor
 		}
 
 		allSubmissions = append(allSubmissions, result.StudentSubmissions...)
+		pageToken = result.NextPageToken
 
-		if result.NextPageToken == "" {
+		if pageToken == "" {
+			break
+		}
+		if o.limit > 0 && len(allSubmissions) >= o.limit {
+			allSubmissions = allSubmissions[:o.limit]
 			break
 		}
-		pageToken = result.NextPageToken
 	}
 
 	return allSubmissions, pageToken, nil
 }
 
-func (c *Client) GetStudentSubmission(ctx context.Context, courseID, courseWorkID, submissionID string) (*StudentSubmission, error) {
+func (c *Client) GetStudentSubmission(ctx context.Context, courseID, courseWorkID, submissionID string, opts ...CallOption) (*StudentSubmission, error) {
 	endpoint := fmt.Sprintf("/courses/%s/courseWork/%s/studentSubmissions/%s",
 		url.PathEscape(courseID), url.PathEscape(courseWorkID), url.PathEscape(submissionID))
-	resp, err := c.get(ctx, endpoint, nil)
+	resp, err := c.get(ctx, endpoint, applyFields(nil, "", opts))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get submission %s for coursework %s in course %s: %w", submissionID, courseWorkID, courseID, err)
 	}
@@ -107,10 +180,10 @@ func (c *Client) PatchStudentSubmission(ctx context.Context, courseID, courseWor
 	return &sub, nil
 }
 
-func (c *Client) GetMySubmission(ctx context.Context, courseID, courseWorkID string) (*StudentSubmission, error) {
+func (c *Client) GetMySubmission(ctx context.Context, courseID, courseWorkID string, opts ...CallOption) (*StudentSubmission, error) {
 	endpoint := fmt.Sprintf("/courses/%s/courseWork/%s/studentSubmissions/me",
 		url.PathEscape(courseID), url.PathEscape(courseWorkID))
-	resp, err := c.get(ctx, endpoint, nil)
+	resp, err := c.get(ctx, endpoint, applyFields(nil, "", opts))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get my submission for coursework %s in course %s: %w", courseWorkID, courseID, err)
 	}
@@ -123,6 +196,55 @@ func (c *Client) GetMySubmission(ctx context.Context, courseID, courseWorkID str
 	return &sub, nil
 }
 
+func (c *Client) ModifyAttachments(ctx context.Context, courseID, courseWorkID, submissionID string, add []Attachment) (*StudentSubmission, error) {
+	endpoint := fmt.Sprintf("/courses/%s/courseWork/%s/studentSubmissions/%s:modifyAttachments",
+		url.PathEscape(courseID), url.PathEscape(courseWorkID), url.PathEscape(submissionID))
+
+	body, err := json.Marshal(struct {
+		AddAttachments []Attachment `json:"addAttachments"`
+	}{add})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal attachments: %w", err)
+	}
+
+	resp, err := c.post(ctx, endpoint, nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify attachments on submission %s for coursework %s in course %s: %w", submissionID, courseWorkID, courseID, err)
+	}
+
+	var sub StudentSubmission
+	if err := json.Unmarshal(resp, &sub); err != nil {
+		return nil, fmt.Errorf("failed to parse submission response: %w", err)
+	}
+
+	return &sub, nil
+}
+
+func (c *Client) TurnIn(ctx context.Context, courseID, courseWorkID, submissionID string) error {
+	endpoint := fmt.Sprintf("/courses/%s/courseWork/%s/studentSubmissions/%s:turnIn",
+		url.PathEscape(courseID), url.PathEscape(courseWorkID), url.PathEscape(submissionID))
+
+	if _, err := c.post(ctx, endpoint, nil, []byte("{}")); err != nil {
+		return fmt.Errorf("failed to turn in submission %s for coursework %s in course %s: %w", submissionID, courseWorkID, courseID, err)
+	}
+
+	return nil
+}
+
+// Attachments decodes the attachments currently on an assignment submission.
+func (s *StudentSubmission) Attachments() ([]Attachment, error) {
+	if len(s.AssignmentSubmission) == 0 {
+		return nil, nil
+	}
+
+	var as AssignmentSubmission
+	if err := json.Unmarshal(s.AssignmentSubmission, &as); err != nil {
+		return nil, fmt.Errorf("failed to parse submission attachments: %w", err)
+	}
+
+	return as.Attachments, nil
+}
+
 type Attachment struct {
 	DriveFile    *DriveFile    `json:"driveFile,omitempty"`
 	YouTubeVideo *YouTubeVideo `json:"youtubeVideo,omitempty"`
@@ -134,6 +256,7 @@ type DriveFile struct {
 	ID            string              `json:"id,omitempty"`
 	Title         string              `json:"title,omitempty"`
 	AlternateLink string              `json:"alternateLink,omitempty"`
+	ThumbnailURL  string              `json:"thumbnailUrl,omitempty"`
 	FileRef       *DriveFileReference `json:"driveFile,omitempty"`
 }
 
@@ -143,7 +266,9 @@ type DriveFileReference struct {
 
 type YouTubeVideo struct {
 	ID            string `json:"id"`
+	Title         string `json:"title,omitempty"`
 	AlternateLink string `json:"alternateLink,omitempty"`
+	ThumbnailURL  string `json:"thumbnailUrl,omitempty"`
 }
 
 type Link struct {
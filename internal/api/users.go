@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type UserName struct {
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+	FullName   string `json:"fullName"`
+}
+
+type UserProfile struct {
+	ID           string   `json:"id"`
+	Name         UserName `json:"name"`
+	EmailAddress string   `json:"emailAddress,omitempty"`
+	PhotoURL     string   `json:"photoUrl,omitempty"`
+}
+
+// Photo returns an absolute URL for the profile photo. The API returns them
+// protocol-relative ("//lh3.googleusercontent.com/...").
+func (p UserProfile) Photo() string {
+	if strings.HasPrefix(p.PhotoURL, "//") {
+		return "https:" + p.PhotoURL
+	}
+	return p.PhotoURL
+}
+
+// GetUserProfile looks up a user by ID, email or "me". Other users are only
+// visible to members of a shared course, with a roster scope. Profiles are
+// remembered for the life of the client, including those that came with a
+// roster.
+func (c *Client) GetUserProfile(ctx context.Context, userID string) (*UserProfile, error) {
+	if profile, ok := c.memoizedProfile(userID); ok {
+		return profile, nil
+	}
+	endpoint := fmt.Sprintf("/userProfiles/%s", url.PathEscape(userID))
+	resp, err := c.get(ctx, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user profile %s: %w", userID, err)
+	}
+
+	var profile UserProfile
+	if err := json.Unmarshal(resp, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse user profile: %w", err)
+	}
+
+	c.rememberProfiles(profile)
+	return &profile, nil
+}
+
+// UserNames returns the full names of the given users, looking each one up
+// at most once. People the caller can't see are left out, so callers fall
+// back to whatever they showed before.
+func (c *Client) UserNames(ctx context.Context, userIDs []string) map[string]string {
+	names := make(map[string]string)
+	tried := make(map[string]bool)
+	for _, id := range userIDs {
+		if id == "" || tried[id] {
+			continue
+		}
+		tried[id] = true
+		if profile, err := c.GetUserProfile(ctx, id); err == nil && profile.Name.FullName != "" {
+			names[id] = profile.Name.FullName
+		}
+	}
+	return names
+}
+
+func (c *Client) memoizedProfile(userID string) (*UserProfile, bool) {
+	c.profileMu.Lock()
+	defer c.profileMu.Unlock()
+	profile, ok := c.profiles[userID]
+	return &profile, ok
+}
+
+func (c *Client) rememberProfiles(profiles ...UserProfile) {
+	c.profileMu.Lock()
+	defer c.profileMu.Unlock()
+	if c.profiles == nil {
+		c.profiles = make(map[string]UserProfile)
+	}
+	for _, profile := range profiles {
+		// Partial responses (see WithFields) aren't worth keeping.
+		if profile.ID != "" && profile.Name.FullName != "" {
+			c.profiles[profile.ID] = profile
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/timboy697/gc-cli/internal/useragent"
 	"golang.org/x/oauth2"
 )
 
@@ -20,7 +21,21 @@ var Scopes = []string{
 	"https://www.googleapis.com/auth/classroom.courses.readonly",
 	"https://www.googleapis.com/auth/classroom.coursework.me",
 	"https://www.googleapis.com/auth/classroom.coursework.students",
+	"https://www.googleapis.com/auth/classroom.announcements",
+	"https://www.googleapis.com/auth/classroom.rosters.readonly",
+	"https://www.googleapis.com/auth/classroom.profile.photos",
+	"https://www.googleapis.com/auth/classroom.profile.emails",
+	"https://www.googleapis.com/auth/drive.file",
+}
+
+// ReadOnlyScopes can view courses, coursework, grades and announcements but
+// never submit or modify anything.
+var ReadOnlyScopes = []string{
+	"https://www.googleapis.com/auth/classroom.courses.readonly",
+	"https://www.googleapis.com/auth/classroom.coursework.me.readonly",
 	"https://www.googleapis.com/auth/classroom.announcements.readonly",
+	"https://www.googleapis.com/auth/classroom.rosters.readonly",
+	"https://www.googleapis.com/auth/classroom.profile.photos",
 }
 
 const (
@@ -36,13 +51,19 @@ type Config struct {
 	ClientSecret string
 	RedirectURL  string
 	TokenFile    string
+	// Scopes overrides the default Scopes when set.
+	Scopes []string
 }
 
 func (c *Config) OAuth2Config() *oauth2.Config {
+	scopes := Scopes
+	if len(c.Scopes) > 0 {
+		scopes = c.Scopes
+	}
 	return &oauth2.Config{
 		ClientID:     c.ClientID,
 		ClientSecret: c.ClientSecret,
-		Scopes:       Scopes,
+		Scopes:       scopes,
 		Endpoint: oauth2.Endpoint{
 			AuthURL:  "https://accounts.google.com/o/oauth2/auth",
 			TokenURL: "https://oauth2.googleapis.com/token",
@@ -69,14 +90,26 @@ func BrowserFlow(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
 	return manualFlow(ctx, cfg)
 }
 
-func tryAutoCallback(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
+// CallbackFlow is an OAuth flow waiting for Google to redirect the browser
+// back to a local loopback listener.
+type CallbackFlow struct {
+	AuthURL string
+
+	oauthCfg *oauth2.Config
+	server   *http.Server
+	codeChan chan string
+	errChan  chan error
+}
+
+// StartCallbackFlow starts the loopback listener and returns the URL the user
+// must visit. Callers must Close the flow once Wait returns.
+func StartCallbackFlow(cfg *Config) (*CallbackFlow, error) {
 	oauthCfg := cfg.OAuth2Config()
 
 	listener, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
 		return nil, fmt.Errorf("listen: %w", err)
 	}
-	defer listener.Close()
 
 	port := listener.Addr().(*net.TCPAddr).Port
 	redirectURL := fmt.Sprintf("http://localhost:%d", port)
@@ -84,57 +117,86 @@ func tryAutoCallback(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
 	oauthCfg.RedirectURL = redirectURL
 
 	state := fmt.Sprintf("gc-cli-%d", time.Now().UnixNano())
-	authURL := oauthCfg.AuthCodeURL(state, oauth2.AccessTypeOffline)
 
-	codeChan := make(chan string, 1)
-	errChan := make(chan error, 1)
+	flow := &CallbackFlow{
+		AuthURL:  oauthCfg.AuthCodeURL(state, oauth2.AccessTypeOffline),
+		oauthCfg: oauthCfg,
+		codeChan: make(chan string, 1),
+		errChan:  make(chan error, 1),
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		q := r.URL.Query()
 		if q.Get("error") != "" {
-			errChan <- fmt.Errorf("oauth error: %s", q.Get("error_description"))
+			flow.errChan <- fmt.Errorf("oauth error: %s", q.Get("error_description"))
 			return
 		}
 		if q.Get("state") != state {
-			errChan <- fmt.Errorf("state mismatch")
+			flow.errChan <- fmt.Errorf("state mismatch")
 			return
 		}
 		code := q.Get("code")
 		if code == "" {
-			errChan <- fmt.Errorf("no code")
+			flow.errChan <- fmt.Errorf("no code")
 			return
 		}
-		codeChan <- code
+		flow.codeChan <- code
 		io.WriteString(w, "<html><body><h1>✓ Success! You can close this window.</h1></body></html>")
 	})
 
-	server := &http.Server{Addr: redirectURL, Handler: mux}
-	go server.Serve(listener)
+	flow.server = &http.Server{Addr: redirectURL, Handler: mux}
+	go flow.server.Serve(listener)
 
-	fmt.Println("🌐 Opening browser...")
-	_ = openBrowser(authURL)
-	fmt.Printf("📋 Or visit: %s\n", authURL)
-	fmt.Println("⏳ Waiting...")
+	return flow, nil
+}
 
+// Wait blocks until the browser redirect arrives, then exchanges the code
+// for a token.
+func (f *CallbackFlow) Wait(ctx context.Context, timeout time.Duration) (*oauth2.Token, error) {
 	select {
-	case code := <-codeChan:
-		server.Close()
-		token, err := oauthCfg.Exchange(ctx, code)
+	case code := <-f.codeChan:
+		f.Close()
+		token, err := f.oauthCfg.Exchange(useragent.Context(ctx), code)
 		if err != nil {
 			return nil, fmt.Errorf("exchange: %w", err)
 		}
-		fmt.Println("✓ Logged in!")
 		return token, nil
-	case err := <-errChan:
-		server.Close()
+	case err := <-f.errChan:
+		f.Close()
 		return nil, err
-	case <-time.After(60 * time.Second):
-		server.Close()
+	case <-ctx.Done():
+		f.Close()
+		return nil, ctx.Err()
+	case <-time.After(timeout):
+		f.Close()
 		return nil, fmt.Errorf("timeout")
 	}
 }
 
+func (f *CallbackFlow) Close() {
+	f.server.Close()
+}
+
+func tryAutoCallback(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
+	flow, err := StartCallbackFlow(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("🌐 Opening browser...")
+	_ = openBrowser(flow.AuthURL)
+	fmt.Printf("📋 Or visit: %s\n", flow.AuthURL)
+	fmt.Println("⏳ Waiting...")
+
+	token, err := flow.Wait(ctx, 60*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println("✓ Logged in!")
+	return token, nil
+}
+
 func manualFlow(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
 	oauthCfg := cfg.OAuth2Config()
 
@@ -168,7 +230,7 @@ func manualFlow(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
 		return nil, fmt.Errorf("no code in URL")
 	}
 
-	token, err := oauthCfg.Exchange(ctx, code)
+	token, err := oauthCfg.Exchange(useragent.Context(ctx), code)
 	if err != nil {
 		return nil, fmt.Errorf("exchange failed: %w", err)
 	}
@@ -205,6 +267,11 @@ func openBrowser(url string) error {
 	return nil
 }
 
+// OpenBrowser opens url in the user's default browser.
+func OpenBrowser(url string) error {
+	return openBrowser(url)
+}
+
 func isWindows() bool {
 	return os.PathSeparator == '\\'
 }
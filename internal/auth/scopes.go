@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+const scopePrefix = "https://www.googleapis.com/auth/"
+
+// DriveScope reaches every file in the user's Drive, not just those gc-cli
+// created or opened (drive.file). It's only asked for by 'auth login
+// --drive', for sharing files attached in the Classroom web UI.
+const DriveScope = scopePrefix + "drive"
+
+// Need is a permission a command can't work without. Any one of its scopes
+// grants it, e.g. a read-only scope or the full one.
+type Need struct {
+	// What completes "needs permission to", e.g. "see class rosters".
+	What   string
+	Scopes []string
+}
+
+var (
+	NeedCourses = Need{"see your courses", []string{
+		scopePrefix + "classroom.courses.readonly",
+		scopePrefix + "classroom.courses",
+	}}
+	NeedCourseWork = Need{"see coursework and grades", []string{
+		scopePrefix + "classroom.coursework.me.readonly",
+		scopePrefix + "classroom.coursework.me",
+		scopePrefix + "classroom.coursework.students.readonly",
+		scopePrefix + "classroom.coursework.students",
+	}}
+	NeedSubmit = Need{"turn in your work", []string{
+		scopePrefix + "classroom.coursework.me",
+	}}
+	NeedTeach = Need{"change your students' coursework", []string{
+		scopePrefix + "classroom.coursework.students",
+	}}
+	NeedAnnouncements = Need{"see announcements", []string{
+		scopePrefix + "classroom.announcements.readonly",
+		scopePrefix + "classroom.announcements",
+	}}
+	NeedPostAnnouncements = Need{"post announcements", []string{
+		scopePrefix + "classroom.announcements",
+	}}
+	NeedRosters = Need{"see class rosters", []string{
+		scopePrefix + "classroom.rosters.readonly",
+		scopePrefix + "classroom.rosters",
+	}}
+	NeedDrive = Need{"use files you add through gc-cli in Google Drive", []string{
+		scopePrefix + "drive.file",
+		DriveScope,
+	}}
+	NeedAllDrive = Need{"use any of your files in Google Drive", []string{
+		DriveScope,
+	}}
+)
+
+// ShortScope drops the common prefix, e.g. "classroom.rosters.readonly".
+func ShortScope(scope string) string {
+	return strings.TrimPrefix(scope, scopePrefix)
+}
+
+// Missing returns the needs that none of granted meets.
+func Missing(granted []string, needs ...Need) []Need {
+	have := make(map[string]bool, len(granted))
+	for _, s := range granted {
+		have[s] = true
+	}
+	var missing []Need
+	for _, n := range needs {
+		met := false
+		for _, s := range n.Scopes {
+			if have[s] {
+				met = true
+				break
+			}
+		}
+		if !met {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
+
+// GrantedScopes returns the scopes the saved token was granted, or nil if
+// the file doesn't say, as with tokens saved by older versions until their
+// next refresh.
+func GrantedScopes(tokenFile string) []string {
+	data, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil
+	}
+	var saved savedToken
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil
+	}
+	return strings.Fields(saved.Scope)
+}
+
+// savedToken is the token file: the token plus the scopes Google granted,
+// which oauth2.Token only holds in fields it doesn't marshal.
+type savedToken struct {
+	*oauth2.Token
+	Scope string `json:"scope,omitempty"`
+}
+
+// grantedScope is the space-separated scope Google returned with token.
+func grantedScope(token *oauth2.Token) string {
+	s, _ := token.Extra("scope").(string)
+	return s
+}
@@ -3,11 +3,17 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/timboy697/gc-cli/internal/useragent"
 	"golang.org/x/oauth2"
 )
 
@@ -38,7 +44,13 @@ func TokenToFile(tokenFile string, token *oauth2.Token) error {
 		return err
 	}
 
-	data, err := json.Marshal(token)
+	// Refreshes don't always repeat the scope, and then the earlier one
+	// still holds.
+	saved := savedToken{Token: token, Scope: grantedScope(token)}
+	if saved.Scope == "" {
+		saved.Scope = strings.Join(GrantedScopes(tokenFile), " ")
+	}
+	data, err := json.Marshal(saved)
 	if err != nil {
 		return fmt.Errorf("failed to marshal token: %w", err)
 	}
@@ -50,6 +62,59 @@ func TokenToFile(tokenFile string, token *oauth2.Token) error {
 	return nil
 }
 
+// RevokeURL is Google's OAuth token revocation endpoint.
+const RevokeURL = "https://oauth2.googleapis.com/revoke"
+
+// ErrTokenInvalid means Google no longer recognises the token, e.g. because
+// it was already revoked or has lapsed.
+var ErrTokenInvalid = errors.New("token is no longer valid")
+
+// Revoke asks Google to revoke token. Revoking the refresh token ends the
+// whole grant, so it is preferred over the access token when both are set.
+func Revoke(ctx context.Context, token *oauth2.Token) error {
+	value := token.RefreshToken
+	if value == "" {
+		value = token.AccessToken
+	}
+	if value == "" {
+		return ErrTokenInvalid
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, RevokeURL, strings.NewReader(url.Values{"token": {value}}.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create revoke request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Transport: &useragent.Transport{}, Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusBadRequest:
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error == "invalid_token" {
+			return ErrTokenInvalid
+		}
+	}
+	return fmt.Errorf("failed to revoke token: %s", resp.Status)
+}
+
+// RemoveToken deletes the token file. A missing file is not an error.
+func RemoveToken(tokenFile string) error {
+	if err := os.Remove(tokenFile); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove token file: %w", err)
+	}
+	return nil
+}
+
 func TokenExists(tokenFile string) bool {
 	_, err := os.Stat(tokenFile)
 	return err == nil
@@ -73,7 +138,7 @@ func ValidateToken(ctx context.Context, cfg *Config, token *oauth2.Token) bool {
 
 func GetTokenSource(ctx context.Context, cfg *Config, token *oauth2.Token) oauth2.TokenSource {
 	oauthCfg := cfg.OAuth2Config()
-	return oauthCfg.TokenSource(ctx, token)
+	return oauthCfg.TokenSource(useragent.Context(ctx), token)
 }
 
 func RefreshToken(ctx context.Context, cfg *Config, token *oauth2.Token) (*oauth2.Token, error) {
@@ -82,7 +147,7 @@ func RefreshToken(ctx context.Context, cfg *Config, token *oauth2.Token) (*oauth
 	}
 
 	oauthCfg := cfg.OAuth2Config()
-	ts := oauthCfg.TokenSource(ctx, token)
+	ts := oauthCfg.TokenSource(useragent.Context(ctx), token)
 
 	newToken, err := ts.Token()
 	if err != nil {
@@ -103,7 +168,7 @@ func GetValidToken(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
 	}
 
 	if token.RefreshToken != "" {
-		fmt.Println("Token expired, refreshing...")
+		fmt.Fprintln(os.Stderr, "Token expired, refreshing...")
 		newToken, err := RefreshToken(ctx, cfg, token)
 		if err == nil {
 			if err := TokenToFile(cfg.TokenFile, newToken); err != nil {
@@ -111,8 +176,80 @@ func GetValidToken(ctx context.Context, cfg *Config) (*oauth2.Token, error) {
 			}
 			return newToken, nil
 		}
-		fmt.Printf("Token refresh failed: %v\n", err)
+		return nil, fmt.Errorf("token expired and could not be refreshed, please run 'gc-cli auth login': %w", err)
 	}
 
 	return nil, fmt.Errorf("token expired, please run 'gc-cli auth login'")
 }
+
+// ExpiresWithin reports whether token expires within margin of now. Tokens
+// without an expiry never do.
+func ExpiresWithin(token *oauth2.Token, margin time.Duration, now time.Time) bool {
+	return !token.Expiry.IsZero() && !token.Expiry.After(now.Add(margin))
+}
+
+// ForceRefresh gets a new access token even if token is still valid.
+func ForceRefresh(ctx context.Context, cfg *Config, token *oauth2.Token) (*oauth2.Token, error) {
+	stale := *token
+	stale.AccessToken = ""
+	return RefreshToken(ctx, cfg, &stale)
+}
+
+// RefreshIfNeeded refreshes the saved token if it expires within margin and
+// saves the new one. refreshed reports whether that happened.
+func RefreshIfNeeded(ctx context.Context, cfg *Config, margin time.Duration) (token *oauth2.Token, refreshed bool, err error) {
+	token, err = TokenFromFile(cfg.TokenFile)
+	if err != nil {
+		return nil, false, fmt.Errorf("no valid token found, please run 'gc-cli auth login': %w", err)
+	}
+	if !ExpiresWithin(token, margin, time.Now()) {
+		return token, false, nil
+	}
+	newToken, err := ForceRefresh(ctx, cfg, token)
+	if err != nil {
+		return nil, false, fmt.Errorf("token could not be refreshed, please run 'gc-cli auth login': %w", err)
+	}
+	if err := TokenToFile(cfg.TokenFile, newToken); err != nil {
+		return nil, false, fmt.Errorf("failed to save refreshed token: %w", err)
+	}
+	return newToken, true, nil
+}
+
+// RefreshingTokenSource serves token to long-running commands, refreshing
+// it margin before it expires rather than on the first failed request, and
+// saving each new token so other gc-cli runs start with a fresh one.
+func RefreshingTokenSource(ctx context.Context, cfg *Config, token *oauth2.Token, margin time.Duration) oauth2.TokenSource {
+	return &refreshingSource{ctx: ctx, cfg: cfg, token: token, margin: margin}
+}
+
+type refreshingSource struct {
+	ctx    context.Context
+	cfg    *Config
+	margin time.Duration
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func (s *refreshingSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !ExpiresWithin(s.token, s.margin, time.Now()) {
+		return s.token, nil
+	}
+	newToken, err := ForceRefresh(s.ctx, s.cfg, s.token)
+	if err != nil {
+		// Keep using the current token while it lasts; the next request
+		// tries again.
+		if s.token.Valid() {
+			return s.token, nil
+		}
+		return nil, err
+	}
+	if err := TokenToFile(s.cfg.TokenFile, newToken); err != nil {
+		return nil, fmt.Errorf("failed to save refreshed token: %w", err)
+	}
+	s.token = newToken
+	return newToken, nil
+}
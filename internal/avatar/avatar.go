@@ -0,0 +1,117 @@
+// Package avatar keeps a local copy of profile photos and draws them as
+// tiny terminal avatars, falling back to initials.
+package avatar
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/timboy697/gc-cli/internal/termimg"
+)
+
+// Photos are stored downscaled; a 2×1 cell avatar only needs 2×2 pixels,
+// but a little extra keeps them usable for larger renders.
+const storedSize = 32
+
+var unsafeID = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+type Cache struct {
+	dir string
+}
+
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+func (c *Cache) path(userID string) string {
+	return filepath.Join(c.dir, unsafeID.ReplaceAllString(userID, "_")+".png")
+}
+
+// Cached returns a previously fetched photo without touching the network.
+func (c *Cache) Cached(userID string) (image.Image, bool) {
+	f, err := os.Open(c.path(userID))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, false
+	}
+	return img, true
+}
+
+// Fetch returns the user's photo, downloading and storing it on first use.
+func (c *Cache) Fetch(ctx context.Context, userID, url string) (image.Image, error) {
+	if img, ok := c.Cached(userID); ok {
+		return img, nil
+	}
+	if url == "" {
+		return nil, fmt.Errorf("no photo for user %s", userID)
+	}
+
+	img, err := termimg.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	img = downscale(img, storedSize)
+
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return img, nil
+	}
+	if f, err := os.Create(c.path(userID)); err == nil {
+		_ = png.Encode(f, img)
+		f.Close()
+	}
+	return img, nil
+}
+
+func downscale(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= size && bounds.Dy() <= size {
+		return img
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dst.Set(x, y, img.At(bounds.Min.X+x*bounds.Dx()/size, bounds.Min.Y+y*bounds.Dy()/size))
+		}
+	}
+	return dst
+}
+
+// Initials returns up to two uppercase initials, e.g. "Ada Lovelace" → "AL".
+func Initials(name string) string {
+	var initials []rune
+	for _, word := range strings.Fields(name) {
+		r := []rune(word)[0]
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		initials = append(initials, unicode.ToUpper(r))
+	}
+	switch len(initials) {
+	case 0:
+		return "?"
+	case 1:
+		return string(initials)
+	}
+	return string([]rune{initials[0], initials[len(initials)-1]})
+}
+
+// Badge is a two-cell avatar: the photo in half blocks when there is one
+// and the terminal can show it, otherwise the initials.
+func Badge(img image.Image, name string, images bool) string {
+	if img != nil && images {
+		return termimg.Blocks(img, 2, 1)
+	}
+	return fmt.Sprintf("%-2s", Initials(name))
+}
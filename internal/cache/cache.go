@@ -0,0 +1,186 @@
+// Package cache keeps recent Classroom API responses on disk so listings
+// that are opened again within a few minutes don't refetch everything.
+//
+// Responses are grouped into buckets by course, one file per request URL,
+// and each kind of resource has its own time to live. A change made through
+// the API drops the bucket of the course it touched.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Resources whose responses are cached.
+const (
+	Courses       = "courses"
+	Coursework    = "courseWork"
+	Announcements = "announcements"
+	Submissions   = "studentSubmissions"
+)
+
+// TTLs is how long each resource's responses stay fresh. A zero TTL turns
+// caching off for that resource.
+type TTLs map[string]time.Duration
+
+type entry struct {
+	URL     string    `json:"url"`
+	Fetched time.Time `json:"fetched"`
+	Body    []byte    `json:"body"`
+}
+
+type Cache struct {
+	dir  string
+	ttls TTLs
+	now  func() time.Time
+}
+
+// Open uses dir for cached responses, creating it on first write.
+func Open(dir string, ttls TTLs, now func() time.Time) *Cache {
+	return &Cache{dir: dir, ttls: ttls, now: now}
+}
+
+// Dir is where c keeps its files.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get returns the cached body for url if endpoint is a cached resource and
+// the response is still fresh.
+func (c *Cache) Get(endpoint, url string) ([]byte, bool) {
+	ttl := c.ttls[Resource(endpoint)]
+	if ttl <= 0 {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(endpoint, url))
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if json.Unmarshal(data, &e) != nil || e.URL != url || c.now().Sub(e.Fetched) > ttl {
+		return nil, false
+	}
+	return e.Body, true
+}
+
+// Put saves body as the response for url. Failing to write the cache only
+// costs a refetch later, so errors are dropped.
+func (c *Cache) Put(endpoint, url string, body []byte) {
+	if c.ttls[Resource(endpoint)] <= 0 {
+		return
+	}
+	data, err := json.Marshal(entry{URL: url, Fetched: c.now(), Body: body})
+	if err != nil {
+		return
+	}
+	path := c.path(endpoint, url)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil || os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Invalidate drops what a change to endpoint may have made stale: the
+// course's bucket, and the course list.
+func (c *Cache) Invalidate(endpoint string) {
+	os.RemoveAll(filepath.Join(c.dir, bucket(endpoint)))
+	os.RemoveAll(filepath.Join(c.dir, Courses))
+}
+
+// Clear removes every cached response and returns how many there were.
+func (c *Cache) Clear() (int, error) {
+	n, _, _, err := c.Usage()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.RemoveAll(c.dir); err != nil {
+		return 0, fmt.Errorf("failed to clear response cache: %w", err)
+	}
+	return n, nil
+}
+
+// Usage reports how many responses are cached, their total size and when
+// the oldest was fetched.
+func (c *Cache) Usage() (n int, size int64, oldest time.Time, err error) {
+	err = filepath.Walk(c.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".tmp-") {
+			return nil
+		}
+		n++
+		size += info.Size()
+		if oldest.IsZero() || info.ModTime().Before(oldest) {
+			oldest = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, time.Time{}, fmt.Errorf("failed to read response cache: %w", err)
+	}
+	return n, size, oldest, nil
+}
+
+func (c *Cache) path(endpoint, url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, bucket(endpoint), hex.EncodeToString(sum[:16])+".json")
+}
+
+// Resource names the cached resource an endpoint returns, e.g.
+// "/courses/1/courseWork/2" is courseWork, or "" if it isn't cached.
+func Resource(endpoint string) string {
+	parts := strings.Split(strings.Trim(endpoint, "/"), "/")
+	if parts[0] != Courses {
+		return ""
+	}
+	switch len(parts) {
+	case 1, 2:
+		return Courses
+	case 3, 4:
+		if parts[2] == Coursework || parts[2] == Announcements {
+			return parts[2]
+		}
+	case 5, 6:
+		if parts[2] == Coursework && parts[4] == Submissions {
+			return Submissions
+		}
+	}
+	return ""
+}
+
+// bucket groups everything under one course, e.g. "courses-123"; the
+// course list itself is "courses".
+func bucket(endpoint string) string {
+	parts := strings.Split(strings.Trim(endpoint, "/"), "/")
+	if len(parts) < 2 {
+		return Courses
+	}
+	// Actions such as "123:archive" belong to the course.
+	id, _, _ := strings.Cut(parts[1], ":")
+	return Courses + "-" + strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == '.' {
+			return '_'
+		}
+		return r
+	}, id)
+}
@@ -0,0 +1,53 @@
+// Package checklist finds the steps in an assignment's instructions: lines
+// teachers write as a numbered list or as checkboxes.
+package checklist
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// "1. Read chapter 4", "2) Answer questions", "Step 3: Submit"
+	numbered = regexp.MustCompile(`^(?:[Ss]tep\s+)?\d{1,2}\s*[.):]\s+(.+)$`)
+	// "- [ ] Outline", "* [x] Draft", "[ ] Proofread", "☐ Cite sources"
+	checkbox = regexp.MustCompile(`^(?:[-*•]\s*)?(?:\[[ xX]?\]|☐|☑|✅)\s*(.+)$`)
+)
+
+// Extract returns the text of each step in description, in order. A single
+// numbered line is more likely a heading or a date than a list, so numbered
+// steps only count when there are at least two; checkboxes always do.
+func Extract(description string) []string {
+	type step struct {
+		text     string
+		numbered bool
+	}
+	var found []step
+	numberedSteps := 0
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(line)
+		if m := checkbox.FindStringSubmatch(line); m != nil {
+			found = append(found, step{text: clean(m[1])})
+		} else if m := numbered.FindStringSubmatch(line); m != nil {
+			found = append(found, step{text: clean(m[1]), numbered: true})
+			numberedSteps++
+		}
+	}
+
+	// Drop empty and repeated steps, e.g. a list restated at the end.
+	var steps []string
+	seen := make(map[string]bool, len(found))
+	for _, s := range found {
+		key := strings.ToLower(s.text)
+		if s.text == "" || seen[key] || (s.numbered && numberedSteps < 2) {
+			continue
+		}
+		seen[key] = true
+		steps = append(steps, s.text)
+	}
+	return steps
+}
+
+func clean(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
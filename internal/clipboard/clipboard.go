@@ -0,0 +1,25 @@
+// Package clipboard copies text to the system clipboard, falling back to the
+// OSC 52 terminal escape when no clipboard utility is available (e.g. over
+// SSH).
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/atotto/clipboard"
+	"golang.org/x/term"
+)
+
+func Write(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stderr.Fd())) {
+		return fmt.Errorf("no clipboard available")
+	}
+	fmt.Fprintf(os.Stderr, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+	return nil
+}
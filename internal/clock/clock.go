@@ -0,0 +1,40 @@
+// Package clock abstracts the current time so due-date logic can be pinned
+// to a fixed instant for tests and what-if reports.
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the real wall clock.
+type System struct{}
+
+func (System) Now() time.Time { return time.Now() }
+
+// Fixed always reports the same instant.
+type Fixed time.Time
+
+func (f Fixed) Now() time.Time { return time.Time(f) }
+
+var layouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Parse reads a --now value. Times without a zone are local.
+func Parse(s string) (Fixed, error) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return Fixed(t), nil
+		}
+	}
+	return Fixed{}, fmt.Errorf("invalid time %q (want e.g. 2024-05-01T00:00)", s)
+}
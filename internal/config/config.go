@@ -1,28 +1,171 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/store"
 )
 
 type Config struct {
-	ConfigPath      string          `mapstructure:"-"`
+	ConfigPath string `mapstructure:"-"`
+	// DataDir is where local state is kept: storage.dir, or a profile's or
+	// account's own directory once one is in use.
+	DataDir         string          `mapstructure:"-"`
+	Profile         string          `mapstructure:"profile"`
+	ReadOnly        bool            `mapstructure:"-"`
+	Anonymize       bool            `mapstructure:"-"`
 	Auth            AuthConfig      `mapstructure:"auth"`
 	GoogleClassroom ClassroomConfig `mapstructure:"google_classroom"`
+	Grades          GradesConfig    `mapstructure:"grades"`
+	Forecast        ForecastConfig  `mapstructure:"forecast"`
+	Plan            PlanConfig      `mapstructure:"plan"`
+	Priority        PriorityConfig  `mapstructure:"priority"`
+	Calendar        CalendarConfig  `mapstructure:"calendar"`
+	Notify          NotifyConfig    `mapstructure:"notify"`
+	Storage         StorageConfig   `mapstructure:"storage"`
+	Cache           CacheConfig     `mapstructure:"cache"`
+	TUI             TUIConfig       `mapstructure:"tui"`
+	Display         DisplayConfig   `mapstructure:"display"`
+	Courses         []CourseSlot    `mapstructure:"courses"`
+	// Shortcuts maps a name to the command line it stands for, e.g.
+	// hw: "coursework list --course phys".
+	Shortcuts map[string]string `mapstructure:"shortcuts"`
+	// OutputFilters maps a command, e.g. "courses list", to a shell command
+	// its results are passed through as JSON before they're printed.
+	OutputFilters map[string]string `mapstructure:"output_filters"`
+	// UserAgentSuffix is appended to the User-Agent, e.g. to tag a school's
+	// deployment.
+	UserAgentSuffix string `mapstructure:"user_agent_suffix"`
+	// Migration is how the config file was brought up to SchemaVersion when
+	// it was loaded; it's nil without a file. Load doesn't write the result.
+	Migration *Migration `mapstructure:"-"`
+
+	store *store.Store
 }
 
 type AuthConfig struct {
 	ClientID     string `mapstructure:"client_id"`
 	ClientSecret string `mapstructure:"client_secret"`
 	TokenFile    string `mapstructure:"token_file"`
+	// Scopes is set by read-only profiles; empty means the full default set.
+	Scopes []string `mapstructure:"-"`
+	// RefreshMargin is how long before expiry long-running commands and
+	// `auth status --refresh-if-needed` renew the access token.
+	RefreshMargin time.Duration `mapstructure:"refresh_margin"`
 }
 
+// ClassroomConfig holds course shortcuts: CourseID is used when a command's
+// --course is left out, and Aliases names courses, e.g. math: 123456789, so
+// --course math works anywhere a course ID or link does.
 type ClassroomConfig struct {
-	CourseID string `mapstructure:"course_id"`
+	CourseID string            `mapstructure:"course_id"`
+	Aliases  map[string]string `mapstructure:"aliases"`
+}
+
+type GradesConfig struct {
+	Periods []PeriodConfig `mapstructure:"periods"`
+	// Scale maps letter grades to the minimum percentage that earns them,
+	// e.g. {A: 90, B: 80}.
+	Scale map[string]float64 `mapstructure:"scale"`
+}
+
+// PeriodConfig describes a grading period for courses that don't publish
+// grading period settings. Dates are YYYY-MM-DD and inclusive.
+type PeriodConfig struct {
+	Name  string `mapstructure:"name"`
+	Start string `mapstructure:"start"`
+	End   string `mapstructure:"end"`
+}
+
+// ForecastConfig tunes workload estimates. MinutesPerPoint is only used until
+// enough time has been tracked to derive a personal rate.
+type ForecastConfig struct {
+	MinutesPerPoint  float64 `mapstructure:"minutes_per_point"`
+	DefaultMinutes   float64 `mapstructure:"default_minutes"`
+	WeeklyLimitHours float64 `mapstructure:"weekly_limit_hours"`
+}
+
+// PlanConfig says how much time I have for schoolwork on each weekday, keyed
+// by day name (mon, tue, ...).
+type PlanConfig struct {
+	Availability map[string]time.Duration `mapstructure:"availability"`
+}
+
+// PriorityConfig weighs what makes an item urgent in `todo --by priority`;
+// see agenda.Weights.
+type PriorityConfig struct {
+	PointsWeight   float64       `mapstructure:"points_weight"`
+	UrgencyWeight  float64       `mapstructure:"urgency_weight"`
+	ProgressWeight float64       `mapstructure:"progress_weight"`
+	HalfLife       time.Duration `mapstructure:"half_life"`
+}
+
+// CalendarConfig describes the school year. WeekStart is the first day of
+// the week (default monday) and SchoolDays the days with classes (default
+// mon-fri). A holiday's End may be left out for a single day.
+type CalendarConfig struct {
+	WeekStart  string         `mapstructure:"week_start"`
+	SchoolDays []string       `mapstructure:"school_days"`
+	Terms      []PeriodConfig `mapstructure:"terms"`
+	Holidays   []PeriodConfig `mapstructure:"holidays"`
+}
+
+// CourseSlot places a course in the display order. Course matches a course
+// ID or name; Period is an optional label such as "Period 1" or "Block A".
+// Courses are shown in the order they're listed, unlisted ones last.
+type CourseSlot struct {
+	Course string `mapstructure:"course"`
+	Period string `mapstructure:"period"`
+}
+
+// TUIConfig controls the deadline banner: anything due within
+// DeadlineWarning is announced, with a terminal bell if Bell is set.
+// RestoreState reopens the TUI where the last session left off.
+type TUIConfig struct {
+	DeadlineWarning time.Duration `mapstructure:"deadline_warning"`
+	Bell            bool          `mapstructure:"bell"`
+	RestoreState    bool          `mapstructure:"restore_state"`
+}
+
+// DisplayConfig controls table output. RedactIDs is "hash" or "truncate" to
+// hide course and user IDs when screen-sharing; empty shows them in full.
+type DisplayConfig struct {
+	RedactIDs string `mapstructure:"redact_ids"`
+}
+
+// StorageConfig selects where local state lives: "json" files in Dir (the
+// default) or "memory", which keeps nothing between runs.
+type StorageConfig struct {
+	Backend string `mapstructure:"backend"`
+	Dir     string `mapstructure:"dir"`
+}
+
+// CacheConfig is how long API responses are reused before being fetched
+// again, per resource. Zero turns caching off for that resource.
+type CacheConfig struct {
+	Courses       time.Duration `mapstructure:"courses"`
+	Coursework    time.Duration `mapstructure:"coursework"`
+	Announcements time.Duration `mapstructure:"announcements"`
+	Submissions   time.Duration `mapstructure:"submissions"`
+}
+
+type NotifyConfig struct {
+	QuietHours []QuietHoursConfig `mapstructure:"quiet_hours"`
+}
+
+// QuietHoursConfig is a do-not-disturb window such as overnight or class
+// time. Start and End are HH:MM; a window may cross midnight. Days limits it
+// to specific weekdays (mon, tue, ...); empty means every day.
+type QuietHoursConfig struct {
+	Start string   `mapstructure:"start"`
+	End   string   `mapstructure:"end"`
+	Days  []string `mapstructure:"days"`
 }
 
 func Default() *Config {
@@ -32,12 +175,35 @@ func Default() *Config {
 
 	return &Config{
 		ConfigPath: filepath.Join(configDir, "config.yaml"),
+		DataDir:    filepath.Join(configDir, "data"),
 		Auth: AuthConfig{
-			ClientID:     defaultAuth.ClientID,
-			ClientSecret: defaultAuth.ClientSecret,
-			TokenFile:    filepath.Join(configDir, "token.json"),
+			ClientID:      defaultAuth.ClientID,
+			ClientSecret:  defaultAuth.ClientSecret,
+			TokenFile:     filepath.Join(configDir, "token.json"),
+			RefreshMargin: 10 * time.Minute,
 		},
 		GoogleClassroom: ClassroomConfig{},
+		Forecast: ForecastConfig{
+			MinutesPerPoint:  1.5,
+			DefaultMinutes:   30,
+			WeeklyLimitHours: 10,
+		},
+		Priority: PriorityConfig{
+			PointsWeight:   1,
+			UrgencyWeight:  2,
+			ProgressWeight: 0.5,
+			HalfLife:       48 * time.Hour,
+		},
+		Cache: CacheConfig{
+			Courses:       time.Hour,
+			Coursework:    10 * time.Minute,
+			Announcements: 5 * time.Minute,
+			Submissions:   2 * time.Minute,
+		},
+		TUI: TUIConfig{
+			DeadlineWarning: 2 * time.Hour,
+			RestoreState:    true,
+		},
 	}
 }
 
@@ -50,8 +216,35 @@ func Load() (*Config, error) {
 	viper.SetDefault("auth.client_id", cfg.Auth.ClientID)
 	viper.SetDefault("auth.client_secret", cfg.Auth.ClientSecret)
 	viper.SetDefault("auth.token_file", cfg.Auth.TokenFile)
+	viper.SetDefault("auth.refresh_margin", cfg.Auth.RefreshMargin)
+	viper.SetDefault("storage.dir", cfg.DataDir)
+	viper.SetDefault("forecast.minutes_per_point", cfg.Forecast.MinutesPerPoint)
+	viper.SetDefault("forecast.default_minutes", cfg.Forecast.DefaultMinutes)
+	viper.SetDefault("forecast.weekly_limit_hours", cfg.Forecast.WeeklyLimitHours)
+	viper.SetDefault("priority.points_weight", cfg.Priority.PointsWeight)
+	viper.SetDefault("priority.urgency_weight", cfg.Priority.UrgencyWeight)
+	viper.SetDefault("priority.progress_weight", cfg.Priority.ProgressWeight)
+	viper.SetDefault("priority.half_life", cfg.Priority.HalfLife)
+	viper.SetDefault("cache.courses", cfg.Cache.Courses)
+	viper.SetDefault("cache.coursework", cfg.Cache.Coursework)
+	viper.SetDefault("cache.announcements", cfg.Cache.Announcements)
+	viper.SetDefault("cache.submissions", cfg.Cache.Submissions)
+	viper.SetDefault("tui.deadline_warning", cfg.TUI.DeadlineWarning)
+	viper.SetDefault("tui.restore_state", cfg.TUI.RestoreState)
 
-	if err := viper.ReadInConfig(); err != nil {
+	// Older files are read as migrated; writing them back is up to the
+	// caller, so that 'config migrate --dry-run' can still show the change.
+	m, err := PlanMigration(cfg.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		err = viper.ReadInConfig()
+	} else {
+		cfg.Migration = m
+		err = viper.ReadConfig(bytes.NewReader(m.After))
+	}
+	if err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			return cfg, nil
 		}
@@ -63,10 +256,49 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	cfg.DataDir = cfg.Storage.Dir
 
 	return cfg, nil
 }
 
+// AuthConfig returns the OAuth settings for the active profile.
+func (c *Config) AuthConfig() *auth.Config {
+	authCfg := auth.NewConfig(c.Auth.ClientID, c.Auth.ClientSecret, c.Auth.TokenFile)
+	authCfg.Scopes = c.Auth.Scopes
+	return authCfg
+}
+
+// Store opens the local state store for the configured backend. The store is
+// shared for the life of the config so in-memory state isn't lost between
+// callers.
+func (c *Config) Store() (*store.Store, error) {
+	if c.store == nil {
+		st, err := store.Open(c.Storage.Backend, c.DataDir)
+		if err != nil {
+			return nil, err
+		}
+		c.store = st
+	}
+	return c.store, nil
+}
+
+// UseDataDir points local state at dir, dropping any store already opened on
+// the previous directory.
+func (c *Config) UseDataDir(dir string) {
+	c.DataDir = dir
+	c.store = nil
+}
+
+// StoreOpened reports whether Store has been called since the data
+// directory was last set.
+func (c *Config) StoreOpened() bool {
+	return c.store != nil
+}
+
+func (c *Config) Dir() string {
+	return filepath.Dir(c.ConfigPath)
+}
+
 func EnsureConfigDir(cfg *Config) error {
 	configDir := filepath.Dir(cfg.ConfigPath)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
@@ -89,3 +321,32 @@ func Save(cfg *Config) error {
 	}
 	return nil
 }
+
+// SaveSettings writes the given settings, keyed like "notify.quiet_hours",
+// to the config file alongside what is already there. Unlike Save, defaults
+// aren't written out.
+func SaveSettings(cfg *Config, settings map[string]interface{}) error {
+	if err := EnsureConfigDir(cfg); err != nil {
+		return err
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigFile(cfg.ConfigPath)
+	if _, err := os.Stat(cfg.ConfigPath); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+	}
+	if _, err := os.Stat(cfg.ConfigPath); os.IsNotExist(err) {
+		v.Set("version", SchemaVersion)
+	}
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+
+	if err := v.WriteConfig(); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the layout of config files this build reads and writes,
+// kept in the file's version key. Files without one are version 1.
+const SchemaVersion = 2
+
+// migration rewrites a config file from the version before to to, returning
+// a line describing each change it made.
+type migration struct {
+	to    int
+	apply func(root *yaml.Node) []string
+}
+
+var migrations = []migration{
+	{to: 2, apply: moveDataDir},
+}
+
+// Migration is what it takes to bring a config file up to SchemaVersion.
+// Before and After are the file's contents; comments and key order are kept.
+type Migration struct {
+	Path    string
+	From    int
+	To      int
+	Changes []string
+	Before  []byte
+	After   []byte
+	// Backup is where Apply saved the old file.
+	Backup string
+}
+
+// Needed reports whether the file is older than this build's schema.
+func (m *Migration) Needed() bool {
+	return m.From < m.To
+}
+
+// Newer reports whether the file was written by a newer gc-cli, whose
+// settings this build may not understand.
+func (m *Migration) Newer() bool {
+	return m.From > SchemaVersion
+}
+
+// PlanMigration works out how the config file at path would be migrated
+// without changing it. It returns nil if there is no file.
+func PlanMigration(path string) (*Migration, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	m := &Migration{Path: path, From: 1, To: SchemaVersion, Before: data, After: data}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		// An empty file has nothing to migrate.
+		m.From = SchemaVersion
+		return m, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("failed to read config: %s is not a mapping of settings", path)
+	}
+	if _, v := mappingEntry(root, "version"); v != nil {
+		m.From, err = strconv.Atoi(v.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config: version %q is not a number", v.Value)
+		}
+	}
+	if m.From >= SchemaVersion {
+		m.To = m.From
+		return m, nil
+	}
+
+	for _, mig := range migrations {
+		if mig.to > m.From {
+			m.Changes = append(m.Changes, mig.apply(root)...)
+		}
+	}
+	setVersion(root, SchemaVersion)
+	m.Changes = append(m.Changes, fmt.Sprintf("version set to %d", SchemaVersion))
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	m.After = buf.Bytes()
+	return m, nil
+}
+
+// Apply saves the old file next to it as e.g. config.yaml.v1.bak and writes
+// the migrated one in its place.
+func (m *Migration) Apply() error {
+	if !m.Needed() {
+		return nil
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(m.Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", m.Path, m.From)
+	if err := os.WriteFile(backup, m.Before, mode); err != nil {
+		return fmt.Errorf("failed to back up config: %w", err)
+	}
+	tmp := m.Path + ".tmp"
+	if err := os.WriteFile(tmp, m.After, mode); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Rename(tmp, m.Path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	m.Backup = backup
+	return nil
+}
+
+// moveDataDir moves data_dir under storage as storage.dir (version 2), next
+// to the backend it's used by.
+func moveDataDir(root *yaml.Node) []string {
+	i, value := mappingEntry(root, "data_dir")
+	if value == nil {
+		return nil
+	}
+	key := root.Content[i]
+	root.Content = append(root.Content[:i], root.Content[i+2:]...)
+
+	j, storage := mappingEntry(root, "storage")
+	if storage == nil || storage.Kind != yaml.MappingNode {
+		storage = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if j >= 0 {
+			root.Content[j+1] = storage
+		} else {
+			root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "storage"}, storage)
+		}
+	}
+	if _, existing := mappingEntry(storage, "dir"); existing != nil {
+		return []string{"data_dir removed (storage.dir already replaces it)"}
+	}
+	key.Value = "dir"
+	storage.Content = append(storage.Content, key, value)
+	return []string{"data_dir moved to storage.dir"}
+}
+
+// mappingEntry finds key in a mapping node, returning the index of the key
+// node and the value node, or nil if it isn't there.
+func mappingEntry(n *yaml.Node, key string) (int, *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return i, n.Content[i+1]
+		}
+	}
+	return -1, nil
+}
+
+func setVersion(root *yaml.Node, version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if i, _ := mappingEntry(root, "version"); i >= 0 {
+		root.Content[i+1] = value
+		return
+	}
+	key := &yaml.Node{
+		Kind:        yaml.ScalarNode,
+		Tag:         "!!str",
+		Value:       "version",
+		HeadComment: "# Config layout; gc-cli updates it (see 'gc-cli config migrate')",
+	}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
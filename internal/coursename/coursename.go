@@ -0,0 +1,121 @@
+// Package coursename tells apart courses that share a name, such as a class
+// taken again or two sections of it, so a name always picks out one course.
+package coursename
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+// Labels returns a label for each course ID that no other course has: the
+// name alone if it's unique, otherwise the name followed by whichever of the
+// section, the teacher and the year the course was created tell the courses
+// of that name apart, e.g. "Biology (Period 5, Maria Gomez)". teacher
+// turns a user ID into a name; it may be nil, and is only called for
+// courses whose names clash.
+func Labels(courses []api.Course, teacher func(userID string) string) map[string]string {
+	groups := make(map[string][]api.Course)
+	for _, c := range courses {
+		key := strings.ToLower(c.Name)
+		groups[key] = append(groups[key], c)
+	}
+
+	labels := make(map[string]string, len(courses))
+	for _, group := range groups {
+		if len(group) == 1 {
+			labels[group[0].ID] = group[0].Name
+			continue
+		}
+		qualifiers := []func(api.Course) string{
+			func(c api.Course) string { return c.Section },
+			func(c api.Course) string {
+				if teacher == nil || c.OwnerID == "" {
+					return ""
+				}
+				return teacher(c.OwnerID)
+			},
+			func(c api.Course) string {
+				if c.CreationTime.IsZero() {
+					return ""
+				}
+				return strconv.Itoa(c.CreationTime.Year())
+			},
+		}
+		details := make([][]string, len(group))
+		for _, q := range qualifiers {
+			if unique(group, details) {
+				break
+			}
+			values := make([]string, len(group))
+			for i, c := range group {
+				values[i] = q(c)
+			}
+			if allSame(values) {
+				continue
+			}
+			for i, v := range values {
+				if v != "" {
+					details[i] = append(details[i], v)
+				}
+			}
+		}
+		if !unique(group, details) {
+			// Nothing else tells them apart, e.g. two copies of a course.
+			for i, c := range group {
+				details[i] = append(details[i], "ID "+c.ID)
+			}
+		}
+		for i, c := range group {
+			labels[c.ID] = label(c.Name, details[i])
+		}
+	}
+	return labels
+}
+
+// Match returns the courses s names: by name, ignoring case, or by the label
+// Labels gave them.
+func Match(courses []api.Course, s string, labels map[string]string) []api.Course {
+	var byName, byLabel []api.Course
+	for _, c := range courses {
+		if strings.EqualFold(c.Name, s) {
+			byName = append(byName, c)
+		}
+		if l, ok := labels[c.ID]; ok && strings.EqualFold(l, s) {
+			byLabel = append(byLabel, c)
+		}
+	}
+	if len(byLabel) == 1 {
+		return byLabel
+	}
+	return byName
+}
+
+func label(name string, details []string) string {
+	if len(details) == 0 {
+		return name
+	}
+	return name + " (" + strings.Join(details, ", ") + ")"
+}
+
+func unique(group []api.Course, details [][]string) bool {
+	seen := make(map[string]bool, len(group))
+	for i, c := range group {
+		l := strings.ToLower(label(c.Name, details[i]))
+		if seen[l] {
+			return false
+		}
+		seen[l] = true
+	}
+	return true
+}
+
+func allSame(values []string) bool {
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return false
+		}
+	}
+	return true
+}
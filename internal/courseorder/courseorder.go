@@ -0,0 +1,81 @@
+// Package courseorder arranges courses in the order of a student's day
+// instead of the order the API returns them.
+package courseorder
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+)
+
+// Order ranks courses by their position in the courses config. A nil Order
+// leaves everything as it is.
+type Order struct {
+	slots []config.CourseSlot
+}
+
+func New(slots []config.CourseSlot) *Order {
+	return &Order{slots: slots}
+}
+
+func (o *Order) slot(id, name string) (int, bool) {
+	if o == nil {
+		return 0, false
+	}
+	for i, s := range o.slots {
+		if s.Course == id || strings.EqualFold(s.Course, name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Rank is the course's position in the configured order. Unlisted courses
+// share the rank after the last listed one.
+func (o *Order) Rank(id, name string) int {
+	if i, ok := o.slot(id, name); ok {
+		return i
+	}
+	if o == nil {
+		return 0
+	}
+	return len(o.slots)
+}
+
+func (o *Order) Period(id, name string) string {
+	if i, ok := o.slot(id, name); ok {
+		return o.slots[i].Period
+	}
+	return ""
+}
+
+// Label prefixes the course name with its period, e.g. "Period 1: Calc".
+func (o *Order) Label(id, name string) string {
+	if period := o.Period(id, name); period != "" {
+		return period + ": " + name
+	}
+	return name
+}
+
+// HasPeriods reports whether any course has a period label configured.
+func (o *Order) HasPeriods() bool {
+	if o == nil {
+		return false
+	}
+	for _, s := range o.slots {
+		if s.Period != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SortCourses orders courses by rank, keeping the API order among unlisted
+// courses.
+func (o *Order) SortCourses(courses []api.Course) {
+	sort.SliceStable(courses, func(i, j int) bool {
+		return o.Rank(courses[i].ID, courses[i].Name) < o.Rank(courses[j].ID, courses[j].Name)
+	})
+}
@@ -0,0 +1,351 @@
+// Package demo serves made-up Classroom data, so gc-cli can be tried,
+// recorded and developed without a Google account.
+//
+// The courses live in classroom.json with dates given in days relative to
+// today, so the demo always has work due this week, something overdue and
+// recent grades, whenever it is run.
+package demo
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/offline"
+)
+
+//go:embed classroom.json
+var fixtureJSON []byte
+
+type fixture struct {
+	Me       string          `json:"me"`
+	Profiles []fixtureUser   `json:"profiles"`
+	Courses  []fixtureCourse `json:"courses"`
+}
+
+type fixtureUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type fixtureCourse struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Section string `json:"section"`
+	Room    string `json:"room"`
+	Heading string `json:"heading"`
+	Teacher string `json:"teacher"`
+	// Classmates are the course's other students; the demo student is in
+	// every course.
+	Classmates    []string              `json:"classmates"`
+	Categories    []fixtureCategory     `json:"categories"`
+	Coursework    []fixtureWork         `json:"coursework"`
+	Announcements []fixtureAnnouncement `json:"announcements"`
+}
+
+// fixtureCategory is a grade category; Weight is its percentage of the
+// overall grade.
+type fixtureCategory struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// fixtureWork dates are days from today: Posted is when it was assigned
+// and Due, if set, when it is due at At o'clock.
+type fixtureWork struct {
+	ID          string        `json:"id"`
+	Title       string        `json:"title"`
+	Type        api.WorkType  `json:"type"`
+	Points      int64         `json:"points"`
+	Posted      int           `json:"posted"`
+	Due         *int          `json:"due"`
+	At          string        `json:"at"`
+	Description string        `json:"description"`
+	Category    string        `json:"category"`
+	Links       []fixtureLink `json:"links"`
+	// Submission is my submission's state; empty means not turned in.
+	Submission api.SubmissionState `json:"submission"`
+	Grade      float64             `json:"grade"`
+}
+
+type fixtureLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type fixtureAnnouncement struct {
+	ID     string `json:"id"`
+	Posted int    `json:"posted"`
+	At     string `json:"at"`
+	Author string `json:"author"`
+	Text   string `json:"text"`
+}
+
+// Data is the demo as of a moment: a snapshot in the shape 'gc-cli sync'
+// saves, and the people in it.
+type Data struct {
+	Snapshot *offline.Snapshot
+	Profiles map[string]api.UserProfile
+	// Teachers and Students are user IDs by course ID.
+	Teachers map[string][]string
+	Students map[string][]string
+	// Me is the ID of the demo student.
+	Me string
+}
+
+// Load builds the built-in demo data with dates relative to now.
+func Load(now time.Time) (*Data, error) {
+	return Parse(fixtureJSON, now)
+}
+
+// Parse builds demo data from fixtures laid out like classroom.json, so
+// tests and demos can be seeded with courses of their own.
+func Parse(data []byte, now time.Time) (*Data, error) {
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse demo data: %w", err)
+	}
+
+	d := &Data{
+		Snapshot: &offline.Snapshot{SyncedAt: now, Deep: true},
+		Profiles: make(map[string]api.UserProfile),
+		Teachers: make(map[string][]string),
+		Students: make(map[string][]string),
+		Me:       f.Me,
+	}
+	for _, u := range f.Profiles {
+		given, family, _ := strings.Cut(u.Name, " ")
+		d.Profiles[u.ID] = api.UserProfile{
+			ID:           u.ID,
+			Name:         api.UserName{GivenName: given, FamilyName: family, FullName: u.Name},
+			EmailAddress: u.Email,
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	at := func(days int, clock string) time.Time {
+		t := today.AddDate(0, 0, days)
+		if hm, err := time.Parse("15:04", clock); err == nil {
+			t = t.Add(time.Duration(hm.Hour())*time.Hour + time.Duration(hm.Minute())*time.Minute)
+		} else {
+			t = t.Add(9 * time.Hour)
+		}
+		return t
+	}
+
+	for _, fc := range f.Courses {
+		link := "https://classroom.google.com/c/" + fc.ID
+		d.Teachers[fc.ID] = []string{fc.Teacher}
+		d.Students[fc.ID] = append([]string{f.Me}, fc.Classmates...)
+		c := offline.Course{
+			SyncedAt: now,
+			Course: api.Course{
+				ID:            fc.ID,
+				Name:          fc.Name,
+				Section:       fc.Section,
+				Description:   fc.Heading,
+				Room:          fc.Room,
+				OwnerID:       fc.Teacher,
+				CourseState:   api.CourseActive,
+				AlternateLink: link,
+				// The demo is a couple of months into the school year.
+				CreationTime: today.AddDate(0, -2, 0).UTC(),
+			},
+		}
+
+		categories := make(map[string]json.RawMessage)
+		for i, fcat := range fc.Categories {
+			raw, err := json.Marshal(api.GradeCategory{
+				ID:     fmt.Sprintf("%s-cat-%d", fc.ID, i+1),
+				Name:   fcat.Name,
+				Weight: int(fcat.Weight * 10000),
+			})
+			if err != nil {
+				return nil, err
+			}
+			categories[fcat.Name] = raw
+		}
+
+		for _, fw := range fc.Coursework {
+			posted := at(fw.Posted, "07:30").UTC()
+			cw := api.CourseWork{
+				ID:            fw.ID,
+				CourseID:      fc.ID,
+				Title:         fw.Title,
+				Description:   fw.Description,
+				State:         api.CourseWorkPublished,
+				WorkType:      fw.Type,
+				MaxPoints:     fw.Points,
+				CreateTime:    posted,
+				UpdateTime:    posted,
+				AlternateLink: link + "/a/" + fw.ID + "/details",
+				GradeCategory: categories[fw.Category],
+			}
+			if fw.Due != nil {
+				// Classroom keeps due dates in UTC.
+				due := at(*fw.Due, fw.At).UTC()
+				cw.DueDate = &api.Date{Year: due.Year(), Month: int(due.Month()), Day: due.Day()}
+				cw.DueTime = &api.TimeOfDay{Hours: due.Hour(), Minutes: due.Minute()}
+			}
+			for _, l := range fw.Links {
+				cw.Materials = append(cw.Materials, api.Material{Link: &api.Link{URL: l.URL, Title: l.Title}})
+			}
+			c.Coursework = append(c.Coursework, cw)
+
+			sub := api.StudentSubmission{
+				ID:             "sub-" + fw.ID,
+				CourseID:       fc.ID,
+				CourseWorkID:   fw.ID,
+				UserID:         f.Me,
+				State:          api.SubmissionCreated,
+				CourseWorkType: fw.Type,
+				AlternateLink:  cw.AlternateLink,
+			}
+			if fw.Submission != "" {
+				sub.State = fw.Submission
+			}
+			if !sub.State.Done() && cw.DueDate != nil && at(*fw.Due, fw.At).Before(now) {
+				sub.Late = true
+			}
+			if sub.State.Done() {
+				submitted := posted.Add(48 * time.Hour)
+				if cw.DueDate != nil {
+					submitted = at(*fw.Due, fw.At).Add(-3 * time.Hour).UTC()
+				}
+				sub.SubmittedTimestamp = submitted
+				c.Done = append(c.Done, fw.ID)
+			}
+			if sub.State == api.SubmissionReturned {
+				sub.AssignedGrade = fw.Grade
+				sub.ReturnTimestamp = sub.SubmittedTimestamp.Add(72 * time.Hour)
+			}
+			c.Submissions = append(c.Submissions, sub)
+		}
+
+		for _, fa := range fc.Announcements {
+			posted := at(fa.Posted, fa.At).UTC()
+			c.Announcements = append(c.Announcements, api.Announcement{
+				ID:            fa.ID,
+				CourseID:      fc.ID,
+				Text:          fa.Text,
+				State:         api.AnnouncementPublished,
+				AlternateLink: link + "/p/" + fa.ID,
+				CreationTime:  posted,
+				UpdateTime:    posted,
+				CreatorUserID: fa.Author,
+			})
+		}
+		d.Snapshot.Courses = append(d.Snapshot.Courses, c)
+	}
+	return d, nil
+}
+
+// NewClient returns a client that answers from d, in memory and without
+// signing in. It satisfies api.ClassroomService like a real client.
+func NewClient(ctx context.Context, d *Data, opts ...api.Option) (*api.Client, error) {
+	hc := &http.Client{Transport: Transport(d)}
+	return api.NewClient(ctx, nil, append([]api.Option{api.WithHTTPClient(hc)}, opts...)...)
+}
+
+// Transport answers Classroom API requests from d the way the offline
+// transport answers them from a sync, plus user profiles and rosters.
+// Changes are refused: there is nothing behind the demo to change.
+func Transport(d *Data) http.RoundTripper {
+	return &transport{data: d, classroom: offline.Transport(d.Snapshot)}
+}
+
+type transport struct {
+	data      *Data
+	classroom http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.URL.Host != "classroom.googleapis.com" {
+		return errorResponse(req, http.StatusBadRequest, "FAILED_PRECONDITION", "demo mode: nothing is sent to Google, so changes and uploads aren't available"), nil
+	}
+
+	const profiles = "/v1/userProfiles/"
+	if strings.HasPrefix(req.URL.Path, profiles) {
+		id := strings.TrimPrefix(req.URL.Path, profiles)
+		if id == "me" {
+			id = t.data.Me
+		}
+		profile, ok := t.data.Profiles[id]
+		if !ok {
+			return errorResponse(req, http.StatusNotFound, "NOT_FOUND", "no demo user "+id), nil
+		}
+		return reply(req, http.StatusOK, profile)
+	}
+
+	// /v1/courses/{id}/teachers and /v1/courses/{id}/students
+	if parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/v1/"), "/"); len(parts) == 3 && parts[0] == "courses" {
+		switch parts[2] {
+		case "teachers":
+			var list api.TeacherList
+			for _, id := range t.data.Teachers[parts[1]] {
+				list.Teachers = append(list.Teachers, api.Teacher{CourseID: parts[1], UserID: id, Profile: t.data.Profiles[id]})
+			}
+			return t.roster(req, parts[1], list)
+		case "students":
+			var list api.StudentList
+			for _, id := range t.data.Students[parts[1]] {
+				list.Students = append(list.Students, api.Student{CourseID: parts[1], UserID: id, Profile: t.data.Profiles[id]})
+			}
+			return t.roster(req, parts[1], list)
+		}
+	}
+	// /v1/courses/{id}/teachers/{userId}
+	if parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/v1/"), "/"); len(parts) == 4 && parts[0] == "courses" && parts[2] == "teachers" {
+		id := parts[3]
+		if id == "me" {
+			id = t.data.Me
+		}
+		for _, teacher := range t.data.Teachers[parts[1]] {
+			if teacher == id {
+				return reply(req, http.StatusOK, api.Teacher{CourseID: parts[1], UserID: id, Profile: t.data.Profiles[id]})
+			}
+		}
+		return errorResponse(req, http.StatusNotFound, "NOT_FOUND", id+" doesn't teach demo course "+parts[1]), nil
+	}
+	return t.classroom.RoundTrip(req)
+}
+
+func (t *transport) roster(req *http.Request, courseID string, list interface{}) (*http.Response, error) {
+	if _, ok := t.data.Teachers[courseID]; !ok {
+		return errorResponse(req, http.StatusNotFound, "NOT_FOUND", "no demo course "+courseID), nil
+	}
+	return reply(req, http.StatusOK, list)
+}
+
+func errorResponse(req *http.Request, status int, code, msg string) *http.Response {
+	resp, _ := reply(req, status, map[string]interface{}{
+		"error": map[string]interface{}{"code": status, "message": msg, "status": code},
+	})
+	return resp
+}
+
+func reply(req *http.Request, status int, v interface{}) (*http.Response, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}, nil
+}
@@ -0,0 +1,207 @@
+// Package drive uploads local files to Google Drive with the resumable
+// upload protocol, so large files survive flaky connections and an
+// interrupted upload can be picked up later from its session URL.
+package drive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	uploadURL = "https://www.googleapis.com/upload/drive/v3/files"
+	// chunkSize must be a multiple of 256 KiB.
+	chunkSize  = 8 << 20
+	maxRetries = 3
+)
+
+// ErrSessionExpired means the upload session is gone (they last about a
+// week) and the upload has to start over.
+var ErrSessionExpired = errors.New("upload session expired")
+
+// File is the uploaded Drive file.
+type File struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	MimeType    string `json:"mimeType"`
+	WebViewLink string `json:"webViewLink"`
+}
+
+type Uploader struct {
+	hc        *http.Client
+	chunkSize int64
+	backoff   time.Duration
+	// Progress, if set, is called after each chunk with the bytes Drive has
+	// received so far.
+	Progress func(sent, total int64)
+}
+
+// NewUploader uploads with hc, which must be authorized for the drive.file
+// scope.
+func NewUploader(hc *http.Client) *Uploader {
+	return &Uploader{hc: hc, chunkSize: chunkSize, backoff: time.Second}
+}
+
+// Start opens an upload session for a file called name of the given size
+// and returns the session URL to pass to Upload.
+func (u *Uploader) Start(ctx context.Context, name string, size int64) (string, error) {
+	mimeType := mime.TypeByExtension(filepath.Ext(name))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	meta, err := json.Marshal(map[string]string{"name": name, "mimeType": mimeType})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL+"?uploadType=resumable&fields=id,name,mimeType,webViewLink", bytes.NewReader(meta))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	req.Header.Set("X-Upload-Content-Type", mimeType)
+	req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+
+	resp, err := u.hc.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to start upload: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to start upload: %w", responseError(resp))
+	}
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("failed to start upload: no session URL in response")
+	}
+	return location, nil
+}
+
+// Upload sends r to the session at sessionURL in chunks, continuing from
+// whatever Drive already has, and retries chunks that fail in transit.
+func (u *Uploader) Upload(ctx context.Context, sessionURL string, r io.ReaderAt, size int64) (*File, error) {
+	offset, file, err := u.status(ctx, sessionURL, size)
+	if err != nil {
+		return nil, err
+	}
+
+	retries := 0
+	for file == nil {
+		end := offset + u.chunkSize
+		if end > size {
+			end = size
+		}
+		next, f, err := u.sendChunk(ctx, sessionURL, r, offset, end, size)
+		if err != nil {
+			if errors.Is(err, ErrSessionExpired) || ctx.Err() != nil || retries >= maxRetries {
+				return nil, err
+			}
+			retries++
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(u.backoff << (retries - 1)):
+			}
+			// The chunk may have partly arrived; ask where to carry on.
+			if next, f, err = u.status(ctx, sessionURL, size); err != nil {
+				return nil, err
+			}
+		} else {
+			retries = 0
+		}
+		offset, file = next, f
+		if u.Progress != nil {
+			sent := offset
+			if file != nil {
+				sent = size
+			}
+			u.Progress(sent, size)
+		}
+	}
+	return file, nil
+}
+
+func (u *Uploader) sendChunk(ctx context.Context, sessionURL string, r io.ReaderAt, start, end, size int64) (int64, *File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, io.NewSectionReader(r, start, end-start))
+	if err != nil {
+		return start, nil, err
+	}
+	req.ContentLength = end - start
+	if size > 0 {
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, size))
+	} else {
+		req.Header.Set("Content-Range", "bytes */0")
+	}
+	return u.do(req, start)
+}
+
+// status asks Drive how much of the upload it has received.
+func (u *Uploader) status(ctx context.Context, sessionURL string, size int64) (int64, *File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+	return u.do(req, 0)
+}
+
+// do sends an upload request and interprets the reply: the finished file,
+// or 308 with the offset to continue from.
+func (u *Uploader) do(req *http.Request, offset int64) (int64, *File, error) {
+	resp, err := u.hc.Do(req)
+	if err != nil {
+		return offset, nil, fmt.Errorf("upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated:
+		var f File
+		if err := json.NewDecoder(resp.Body).Decode(&f); err != nil {
+			return offset, nil, fmt.Errorf("failed to parse uploaded file: %w", err)
+		}
+		return offset, &f, nil
+	case resp.StatusCode == http.StatusPermanentRedirect:
+		return received(resp.Header.Get("Range")), nil, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return offset, nil, ErrSessionExpired
+	}
+	return offset, nil, fmt.Errorf("upload failed: %w", responseError(resp))
+}
+
+// received parses a Range header such as "bytes=0-8388607" into the offset
+// of the next byte to send. No header means nothing has arrived yet.
+func received(rng string) int64 {
+	_, last, ok := strings.Cut(strings.TrimPrefix(rng, "bytes="), "-")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseInt(last, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n + 1
+}
+
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	var wrapped struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &wrapped) == nil && wrapped.Error.Message != "" {
+		return fmt.Errorf("%s: %s", resp.Status, wrapped.Error.Message)
+	}
+	return fmt.Errorf("%s", resp.Status)
+}
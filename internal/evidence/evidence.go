@@ -0,0 +1,211 @@
+// Package evidence builds a zip of everything that shows when and what I
+// turned in, for contesting a late penalty or a missing submission.
+package evidence
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// Attachment is a file on my submission with what Drive reports about it.
+// Err is set when Drive couldn't be asked, e.g. for files I can't read.
+type Attachment struct {
+	Title        string    `json:"title"`
+	DriveFileID  string    `json:"driveFileId,omitempty"`
+	Link         string    `json:"link,omitempty"`
+	MimeType     string    `json:"mimeType,omitempty"`
+	Size         int64     `json:"size,omitempty"`
+	MD5Checksum  string    `json:"md5Checksum,omitempty"`
+	ModifiedTime time.Time `json:"modifiedTime,omitempty"`
+	Err          string    `json:"error,omitempty"`
+}
+
+type Bundle struct {
+	Generated   time.Time
+	Version     string
+	Course      api.Course
+	Work        api.CourseWork
+	Submission  *api.StudentSubmission
+	Attachments []Attachment
+	Audit       []store.AuditEntry
+}
+
+// TurnedIn returns the state changes to TURNED_IN, oldest first.
+func (b *Bundle) TurnedIn() ([]api.StateHistory, error) {
+	history, err := b.Submission.History()
+	if err != nil {
+		return nil, err
+	}
+	var turnedIn []api.StateHistory
+	for _, h := range history {
+		if h.StateHistory != nil && h.StateHistory.State == api.SubmissionTurnedIn {
+			turnedIn = append(turnedIn, *h.StateHistory)
+		}
+	}
+	return turnedIn, nil
+}
+
+// Write writes the bundle as a zip with a SHA256SUMS file covering every
+// other entry, so recipients can check nothing was edited afterwards.
+func (b *Bundle) Write(w io.Writer) error {
+	history, err := b.Submission.History()
+	if err != nil {
+		return err
+	}
+	summary, err := b.summary()
+	if err != nil {
+		return err
+	}
+
+	files := map[string]any{
+		"coursework.json":  b.Work,
+		"submission.json":  b.Submission,
+		"history.json":     history,
+		"attachments.json": b.Attachments,
+		"audit.json":       b.Audit,
+	}
+	contents := map[string][]byte{"summary.txt": []byte(summary)}
+	for name, v := range files {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		contents[name] = append(data, '\n')
+	}
+
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sums bytes.Buffer
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		sum := sha256.Sum256(contents[name])
+		fmt.Fprintf(&sums, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+		if err := b.add(zw, name, contents[name]); err != nil {
+			return err
+		}
+	}
+	if err := b.add(zw, "SHA256SUMS", sums.Bytes()); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write zip: %w", err)
+	}
+	return nil
+}
+
+func (b *Bundle) add(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: b.Generated})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return nil
+}
+
+const stampLayout = "Mon Jan 2 2006 15:04:05 MST"
+
+func stamp(t time.Time) string {
+	return fmt.Sprintf("%s (%s)", t.Local().Format(stampLayout), t.UTC().Format(time.RFC3339))
+}
+
+func (b *Bundle) summary() (string, error) {
+	turnedIn, err := b.TurnedIn()
+	if err != nil {
+		return "", err
+	}
+	history, err := b.Submission.History()
+	if err != nil {
+		return "", err
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "Submission evidence generated %s by gc-cli %s\n\n", stamp(b.Generated), b.Version)
+	fmt.Fprintf(&s, "Course:      %s (%s)\n", b.Course.Name, b.Course.ID)
+	fmt.Fprintf(&s, "Assignment:  %s (%s)\n", b.Work.Title, b.Work.ID)
+	due := agenda.DueTime(b.Work)
+	if due.IsZero() {
+		fmt.Fprintln(&s, "Due:         no due date")
+	} else {
+		fmt.Fprintf(&s, "Due:         %s\n", stamp(due))
+	}
+	fmt.Fprintf(&s, "Submission:  %s\n", b.Submission.ID)
+	fmt.Fprintf(&s, "State:       %s\n", b.Submission.State)
+	fmt.Fprintf(&s, "Marked late: %t\n", b.Submission.Late)
+	if b.Submission.AlternateLink != "" {
+		fmt.Fprintf(&s, "Link:        %s\n", b.Submission.AlternateLink)
+	}
+
+	if len(turnedIn) > 0 {
+		first := turnedIn[0].StateTimestamp
+		fmt.Fprintf(&s, "\nFirst turned in %s", stamp(first))
+		if !due.IsZero() {
+			if first.After(due) {
+				fmt.Fprintf(&s, ", %s after the due time", first.Sub(due).Round(time.Minute))
+			} else {
+				fmt.Fprintf(&s, ", %s before the due time", due.Sub(first).Round(time.Minute))
+			}
+		}
+		fmt.Fprintln(&s)
+	}
+
+	fmt.Fprintln(&s, "\nHistory (from Google Classroom):")
+	if len(history) == 0 {
+		fmt.Fprintln(&s, "  none recorded")
+	}
+	for _, h := range history {
+		switch {
+		case h.StateHistory != nil:
+			fmt.Fprintf(&s, "  %s  %s\n", stamp(h.StateHistory.StateTimestamp), h.StateHistory.State)
+		case len(h.GradeHistory) > 0:
+			fmt.Fprintf(&s, "  grade change (see history.json)\n")
+		}
+	}
+
+	fmt.Fprintln(&s, "\nAttachments:")
+	if len(b.Attachments) == 0 {
+		fmt.Fprintln(&s, "  none")
+	}
+	for _, a := range b.Attachments {
+		fmt.Fprintf(&s, "  %s\n", a.Title)
+		if a.MD5Checksum != "" {
+			fmt.Fprintf(&s, "    md5 %s, %d bytes\n", a.MD5Checksum, a.Size)
+		}
+		if !a.ModifiedTime.IsZero() {
+			fmt.Fprintf(&s, "    last modified %s\n", stamp(a.ModifiedTime))
+		}
+		if a.Err != "" {
+			fmt.Fprintf(&s, "    (%s)\n", a.Err)
+		}
+	}
+
+	fmt.Fprintln(&s, "\nActions taken with gc-cli on this device:")
+	if len(b.Audit) == 0 {
+		fmt.Fprintln(&s, "  none recorded")
+	}
+	for _, e := range b.Audit {
+		fmt.Fprintf(&s, "  %s  %s", stamp(e.Time), e.Action)
+		if e.File != "" {
+			fmt.Fprintf(&s, " %s (sha256 %s)", e.File, e.SHA256)
+		}
+		fmt.Fprintln(&s)
+	}
+	return s.String(), nil
+}
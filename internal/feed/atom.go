@@ -0,0 +1,141 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+const atomNS = "http://www.w3.org/2005/Atom"
+
+type Feed struct {
+	XMLName xml.Name `xml:"feed"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Link    *Link    `xml:"link,omitempty"`
+	Entries []Entry  `xml:"entry"`
+}
+
+type Entry struct {
+	ID        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Updated   string    `xml:"updated"`
+	Published string    `xml:"published,omitempty"`
+	Link      *Link     `xml:"link,omitempty"`
+	Category  *Category `xml:"category,omitempty"`
+	Content   *Content  `xml:"content,omitempty"`
+
+	updated time.Time
+}
+
+type Link struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+type Content struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// Build assembles an Atom feed of a course's announcements and coursework,
+// newest first.
+func Build(course *api.Course, announcements []api.Announcement, coursework []api.CourseWork) *Feed {
+	var entries []Entry
+
+	for _, a := range announcements {
+		entries = append(entries, Entry{
+			ID:        entryID(course.ID, "announcement", a.ID),
+			Title:     announcementTitle(a.Text),
+			Updated:   a.UpdateTime.Format(time.RFC3339),
+			Published: a.CreationTime.Format(time.RFC3339),
+			Link:      link(a.AlternateLink),
+			Category:  &Category{Term: "announcement"},
+			Content:   &Content{Type: "text", Body: a.Text},
+			updated:   a.UpdateTime,
+		})
+	}
+
+	for _, cw := range coursework {
+		if cw.State != api.CourseWorkPublished {
+			continue
+		}
+		body := cw.Description
+		if cw.DueDate != nil {
+			body = fmt.Sprintf("Due %d-%02d-%02d\n\n%s", cw.DueDate.Year, cw.DueDate.Month, cw.DueDate.Day, body)
+		}
+		entries = append(entries, Entry{
+			ID:        entryID(course.ID, "coursework", cw.ID),
+			Title:     "New " + strings.ToLower(strings.ReplaceAll(string(cw.WorkType), "_", " ")) + ": " + cw.Title,
+			Updated:   cw.UpdateTime.Format(time.RFC3339),
+			Published: cw.CreateTime.Format(time.RFC3339),
+			Link:      link(cw.AlternateLink),
+			Category:  &Category{Term: "coursework"},
+			Content:   &Content{Type: "text", Body: body},
+			updated:   cw.UpdateTime,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].updated.After(entries[j].updated)
+	})
+
+	updated := time.Now()
+	if len(entries) > 0 {
+		updated = entries[0].updated
+	}
+
+	return &Feed{
+		XMLNS:   atomNS,
+		ID:      entryID(course.ID, "course", course.ID),
+		Title:   course.Name,
+		Updated: updated.Format(time.RFC3339),
+		Link:    link(course.AlternateLink),
+		Entries: entries,
+	}
+}
+
+func (f *Feed) Marshal() ([]byte, error) {
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+func entryID(courseID, kind, id string) string {
+	return fmt.Sprintf("tag:gc-cli,2024:course/%s/%s/%s", courseID, kind, id)
+}
+
+func link(href string) *Link {
+	if href == "" {
+		return nil
+	}
+	return &Link{Href: href, Rel: "alternate"}
+}
+
+// announcementTitle uses the first line of an announcement as its title,
+// since announcements have no title of their own.
+func announcementTitle(text string) string {
+	title := strings.TrimSpace(text)
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = title[:i]
+	}
+	if r := []rune(title); len(r) > 80 {
+		title = string(r[:77]) + "..."
+	}
+	if title == "" {
+		title = "Announcement"
+	}
+	return title
+}
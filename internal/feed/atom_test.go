@@ -0,0 +1,24 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestAnnouncementTitle(t *testing.T) {
+	long := strings.Repeat("é", 100)
+	for in, want := range map[string]string{
+		"  Field trip\nBring lunch.": "Field trip",
+		"":                           "Announcement",
+		long:                         strings.Repeat("é", 77) + "...",
+	} {
+		got := announcementTitle(in)
+		if got != want {
+			t.Errorf("announcementTitle(%q) = %q, want %q", in, got, want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("announcementTitle(%q) cut a character in half", in)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package feed
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+)
+
+// WriteICS writes a calendar of coursework due dates for calendar apps to
+// import or subscribe to. Each dated item is a VEVENT at its due time, or
+// with todos a VTODO due then, for task apps. Work with a due date but no
+// time is an all-day entry. UIDs are stable, so a refreshed subscription
+// moves changed deadlines instead of duplicating them. name is the title
+// calendar apps give the calendar.
+func WriteICS(w io.Writer, name string, items []agenda.Item, todos bool, stamp time.Time) error {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//gc-cli//due dates//EN\r\n")
+	fmt.Fprintf(&b, "X-WR-CALNAME:%s\r\n", icsEscaper.Replace(name))
+	for _, item := range items {
+		cw := item.Work
+		if cw.DueDate == nil {
+			continue
+		}
+		component := "VEVENT"
+		if todos {
+			component = "VTODO"
+		}
+
+		fmt.Fprintf(&b, "BEGIN:%s\r\n", component)
+		fmt.Fprintf(&b, "UID:due-%s-%s@gc-cli\r\n", cw.CourseID, cw.ID)
+		fmt.Fprintf(&b, "DTSTAMP:%s\r\n", stamp.UTC().Format("20060102T150405Z"))
+		if todos {
+			b.WriteString("DUE" + icsTime(item) + "\r\n")
+			if item.Done() {
+				b.WriteString("STATUS:COMPLETED\r\n")
+			} else {
+				b.WriteString("STATUS:NEEDS-ACTION\r\n")
+			}
+		} else {
+			b.WriteString("DTSTART" + icsTime(item) + "\r\n")
+			b.WriteString("TRANSP:TRANSPARENT\r\n")
+		}
+		fmt.Fprintf(&b, "SUMMARY:%s\r\n", icsEscaper.Replace(cw.Title))
+		desc := item.Course.Name
+		if cw.MaxPoints > 0 {
+			desc += fmt.Sprintf(", %d points", cw.MaxPoints)
+		}
+		fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", icsEscaper.Replace(desc))
+		fmt.Fprintf(&b, "CATEGORIES:%s\r\n", icsEscaper.Replace(item.Course.Name))
+		if cw.AlternateLink != "" {
+			fmt.Fprintf(&b, "URL:%s\r\n", cw.AlternateLink)
+		}
+		fmt.Fprintf(&b, "END:%s\r\n", component)
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+// icsTime is the value part of a DTSTART or DUE line: a UTC date-time, or
+// a date for work due "by the end of the day".
+func icsTime(item agenda.Item) string {
+	if item.Work.DueTime == nil {
+		d := item.Work.DueDate
+		return fmt.Sprintf(";VALUE=DATE:%04d%02d%02d", d.Year, d.Month, d.Day)
+	}
+	return ":" + item.Due().UTC().Format("20060102T150405Z")
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
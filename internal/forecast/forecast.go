@@ -0,0 +1,62 @@
+package forecast
+
+import (
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/schoolcal"
+)
+
+// Estimator converts an assignment into expected working time.
+type Estimator struct {
+	// MinutesPerPoint is applied to point-bearing work.
+	MinutesPerPoint float64
+	// DefaultMinutes is used for ungraded work.
+	DefaultMinutes float64
+}
+
+func (e Estimator) Estimate(item agenda.Item) time.Duration {
+	minutes := e.DefaultMinutes
+	if item.Work.MaxPoints > 0 {
+		minutes = float64(item.Work.MaxPoints) * e.MinutesPerPoint
+	}
+	return time.Duration(minutes * float64(time.Minute))
+}
+
+// Week is the outstanding work due in the seven days starting at Start.
+type Week struct {
+	Start    time.Time
+	Items    []agenda.Item
+	Points   int64
+	Estimate time.Duration
+}
+
+func (w Week) Over(limit time.Duration) bool {
+	return limit > 0 && w.Estimate > limit
+}
+
+// Build buckets outstanding items into the next n weeks, starting with the
+// first day of the week containing now. Completed, undated and overdue items
+// are ignored.
+func Build(items []agenda.Item, est Estimator, cal *schoolcal.Calendar, now time.Time, n int) []Week {
+	start := cal.WeekStart(now)
+	weeks := make([]Week, n)
+	for i := range weeks {
+		weeks[i].Start = start.AddDate(0, 0, 7*i)
+	}
+
+	for _, item := range items {
+		due := item.Due()
+		if item.Done() || due.IsZero() || due.Before(now) {
+			continue
+		}
+		idx := int(due.Sub(start).Hours() / (24 * 7))
+		if idx < 0 || idx >= n {
+			continue
+		}
+		weeks[idx].Items = append(weeks[idx].Items, item)
+		weeks[idx].Points += item.Work.MaxPoints
+		weeks[idx].Estimate += est.Estimate(item)
+	}
+	return weeks
+}
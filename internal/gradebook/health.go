@@ -0,0 +1,161 @@
+package gradebook
+
+import (
+	"sort"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+// Uncategorized groups work the teacher hasn't put in a grade category.
+const Uncategorized = "Uncategorized"
+
+// CategoryAverage is my standing in one grade category. Class is the mean of
+// every student's percentage in it, known only when other students'
+// submissions are visible, as they are to teachers.
+type CategoryAverage struct {
+	Name string
+	// Weight is the category's percentage of the overall grade, or 0 when
+	// the course doesn't weight categories.
+	Weight float64
+	Summary
+	Class    float64
+	HasClass bool
+}
+
+// Scored is a returned piece of work with its grade as a percentage.
+type Scored struct {
+	Work    api.CourseWork
+	Grade   float64
+	Percent float64
+}
+
+// Health is a quick check of how a course is going: averages overall and
+// per category, what is missing, and the best and worst grades.
+type Health struct {
+	Overall    Summary
+	Categories []CategoryAverage
+	// Weighted is the overall percentage using the category weights, set
+	// when the course weights its categories.
+	Weighted    float64
+	HasWeighted bool
+	Missing     []api.CourseWork
+	Best        []Scored
+	Worst       []Scored
+}
+
+// CourseHealth summarises a course's published coursework for the student
+// me. Submissions by other students only count towards the class averages;
+// with me empty every submission is taken to be mine. Best and Worst hold
+// up to n grades each and never the same one twice.
+func CourseHealth(coursework []api.CourseWork, submissions []api.StudentSubmission, me string, n int) Health {
+	var mine []api.StudentSubmission
+	for _, sub := range submissions {
+		if me == "" || sub.UserID == me {
+			mine = append(mine, sub)
+		}
+	}
+	h := Health{Overall: Summarize(coursework, mine)}
+
+	works := make(map[string]api.CourseWork, len(coursework))
+	categoryOf := make(map[string]string, len(coursework))
+	var order []string
+	seen := make(map[string]bool)
+	weights := make(map[string]float64)
+	for _, cw := range coursework {
+		if cw.State != api.CourseWorkPublished {
+			continue
+		}
+		works[cw.ID] = cw
+		name := Uncategorized
+		if category := cw.Category(); category != nil {
+			name = category.Name
+			weights[name] = float64(category.Weight) / 10000
+		}
+		if !seen[name] {
+			seen[name] = true
+			order = append(order, name)
+		}
+		categoryOf[cw.ID] = name
+	}
+
+	mineByWork := make(map[string]api.StudentSubmission, len(mine))
+	for _, sub := range mine {
+		mineByWork[sub.CourseWorkID] = sub
+	}
+	for _, cw := range coursework {
+		sub, ok := mineByWork[cw.ID]
+		if _, published := works[cw.ID]; ok && published && sub.Late && !sub.State.Done() {
+			h.Missing = append(h.Missing, cw)
+		}
+	}
+
+	// Per category and student: points earned and possible.
+	type points struct{ earned, possible float64 }
+	byStudent := make(map[string]map[string]*points)
+	var scored []Scored
+	for _, sub := range submissions {
+		cw, ok := works[sub.CourseWorkID]
+		if !ok || cw.MaxPoints == 0 || sub.State != api.SubmissionReturned {
+			continue
+		}
+		category := categoryOf[cw.ID]
+		if byStudent[category] == nil {
+			byStudent[category] = make(map[string]*points)
+		}
+		p := byStudent[category][sub.UserID]
+		if p == nil {
+			p = &points{}
+			byStudent[category][sub.UserID] = p
+		}
+		p.earned += sub.AssignedGrade
+		p.possible += float64(cw.MaxPoints)
+
+		if me == "" || sub.UserID == me {
+			scored = append(scored, Scored{Work: cw, Grade: sub.AssignedGrade, Percent: sub.AssignedGrade / float64(cw.MaxPoints) * 100})
+		}
+	}
+
+	var weightedSum, weightTotal float64
+	for _, name := range order {
+		var cws []api.CourseWork
+		for _, cw := range coursework {
+			if categoryOf[cw.ID] == name {
+				cws = append(cws, cw)
+			}
+		}
+		avg := CategoryAverage{Name: name, Weight: weights[name], Summary: Summarize(cws, mine)}
+
+		students := byStudent[name]
+		if len(students) > 1 || (len(students) == 1 && me != "" && students[me] == nil) {
+			var total float64
+			for _, p := range students {
+				total += p.earned / p.possible * 100
+			}
+			avg.Class, avg.HasClass = total/float64(len(students)), true
+		}
+
+		if pct, ok := avg.Percent(); ok && avg.Weight > 0 {
+			weightedSum += pct * avg.Weight
+			weightTotal += avg.Weight
+		}
+		h.Categories = append(h.Categories, avg)
+	}
+	if weightTotal > 0 {
+		h.Weighted, h.HasWeighted = weightedSum/weightTotal, true
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Percent > scored[j].Percent
+	})
+	// With few grades, split them so the best and worst lists are both
+	// shown.
+	best := n
+	if half := (len(scored) + 1) / 2; best > half {
+		best = half
+	}
+	h.Best = scored[:best]
+	for i := len(scored) - 1; i >= best && len(h.Worst) < n; i-- {
+		h.Worst = append(h.Worst, scored[i])
+	}
+	return h
+}
@@ -0,0 +1,94 @@
+package gradebook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/config"
+)
+
+const dateLayout = "2006-01-02"
+
+// Period is an inclusive date range grades are bucketed into, such as a
+// quarter or term.
+type Period struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+func (p Period) Contains(t time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	return !t.Before(p.Start) && t.Before(p.End.AddDate(0, 0, 1))
+}
+
+func (p Period) String() string {
+	return fmt.Sprintf("%s (%s – %s)", p.Name, p.Start.Format(dateLayout), p.End.Format(dateLayout))
+}
+
+func PeriodsFromConfig(periods []config.PeriodConfig) ([]Period, error) {
+	var result []Period
+	for _, pc := range periods {
+		start, err := time.Parse(dateLayout, pc.Start)
+		if err != nil {
+			return nil, fmt.Errorf("grading period %q: invalid start date %q", pc.Name, pc.Start)
+		}
+		end, err := time.Parse(dateLayout, pc.End)
+		if err != nil {
+			return nil, fmt.Errorf("grading period %q: invalid end date %q", pc.Name, pc.End)
+		}
+		result = append(result, Period{Name: pc.Name, Start: start, End: end})
+	}
+	return result, nil
+}
+
+func PeriodsFromAPI(periods []api.GradingPeriod) []Period {
+	var result []Period
+	for _, gp := range periods {
+		if gp.StartDate == nil || gp.EndDate == nil {
+			continue
+		}
+		result = append(result, Period{
+			Name:  gp.Title,
+			Start: time.Date(gp.StartDate.Year, time.Month(gp.StartDate.Month), gp.StartDate.Day, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(gp.EndDate.Year, time.Month(gp.EndDate.Month), gp.EndDate.Day, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	return result
+}
+
+// ParsePeriod resolves a --period value: either the name of a known period
+// (case-insensitive) or an explicit "YYYY-MM-DD..YYYY-MM-DD" range.
+func ParsePeriod(spec string, known []Period) (Period, error) {
+	if from, to, ok := strings.Cut(spec, ".."); ok {
+		start, err := time.Parse(dateLayout, strings.TrimSpace(from))
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid period start %q (want YYYY-MM-DD)", from)
+		}
+		end, err := time.Parse(dateLayout, strings.TrimSpace(to))
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid period end %q (want YYYY-MM-DD)", to)
+		}
+		if end.Before(start) {
+			return Period{}, fmt.Errorf("period end %s is before start %s", to, from)
+		}
+		return Period{Name: spec, Start: start, End: end}, nil
+	}
+
+	var names []string
+	for _, p := range known {
+		if strings.EqualFold(p.Name, spec) {
+			return p, nil
+		}
+		names = append(names, p.Name)
+	}
+
+	if len(names) == 0 {
+		return Period{}, fmt.Errorf("unknown period %q: this course has no grading periods, configure grades.periods or use a YYYY-MM-DD..YYYY-MM-DD range", spec)
+	}
+	return Period{}, fmt.Errorf("unknown period %q (available: %s)", spec, strings.Join(names, ", "))
+}
@@ -0,0 +1,55 @@
+package gradebook
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultScale is used when grades.scale is not configured.
+var DefaultScale = Scale{
+	{Letter: "A", Min: 90},
+	{Letter: "B", Min: 80},
+	{Letter: "C", Min: 70},
+	{Letter: "D", Min: 60},
+	{Letter: "F", Min: 0},
+}
+
+type Threshold struct {
+	Letter string
+	Min    float64
+}
+
+// Scale maps percentages to letter grades, ordered from the highest
+// threshold to the lowest.
+type Scale []Threshold
+
+// NewScale builds a scale from the grades.scale config map. Config keys are
+// case-folded by the loader, so letters are upper-cased again here.
+func NewScale(thresholds map[string]float64) Scale {
+	if len(thresholds) == 0 {
+		return DefaultScale
+	}
+
+	scale := make(Scale, 0, len(thresholds))
+	for letter, min := range thresholds {
+		scale = append(scale, Threshold{Letter: strings.ToUpper(letter), Min: min})
+	}
+	sort.Slice(scale, func(i, j int) bool {
+		return scale[i].Min > scale[j].Min
+	})
+	return scale
+}
+
+// Letter returns the letter for a percentage in the 0-100 range. Anything
+// below the lowest threshold gets the lowest letter.
+func (s Scale) Letter(percent float64) string {
+	if len(s) == 0 {
+		return ""
+	}
+	for _, t := range s {
+		if percent >= t.Min {
+			return t.Letter
+		}
+	}
+	return s[len(s)-1].Letter
+}
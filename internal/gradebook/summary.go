@@ -0,0 +1,48 @@
+package gradebook
+
+import "github.com/timboy697/gc-cli/internal/api"
+
+// Summary aggregates my points across a course's published coursework.
+type Summary struct {
+	Earned   float64
+	Possible float64
+	Graded   int
+	Missing  int
+}
+
+func (s Summary) Percent() (float64, bool) {
+	if s.Possible == 0 {
+		return 0, false
+	}
+	return s.Earned / s.Possible * 100, true
+}
+
+// Summarize matches my submissions to coursework and totals the graded ones.
+// Ungraded and excused work does not count towards the possible points.
+func Summarize(coursework []api.CourseWork, submissions []api.StudentSubmission) Summary {
+	byWork := make(map[string]api.StudentSubmission, len(submissions))
+	for _, sub := range submissions {
+		byWork[sub.CourseWorkID] = sub
+	}
+
+	var s Summary
+	for _, cw := range coursework {
+		if cw.State != api.CourseWorkPublished {
+			continue
+		}
+		sub, ok := byWork[cw.ID]
+		if !ok {
+			continue
+		}
+		if sub.Late && !sub.State.Done() {
+			s.Missing++
+		}
+		if cw.MaxPoints == 0 || sub.State != api.SubmissionReturned {
+			continue
+		}
+		s.Graded++
+		s.Earned += sub.AssignedGrade
+		s.Possible += float64(cw.MaxPoints)
+	}
+	return s
+}
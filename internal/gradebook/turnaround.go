@@ -0,0 +1,64 @@
+package gradebook
+
+import (
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+// ReturnDelay is how long a submission waited between its last turn-in and
+// being returned. It reads the submission history and falls back to the
+// submitted and returned timestamps.
+func ReturnDelay(sub api.StudentSubmission) (time.Duration, bool) {
+	var turnedIn, returned time.Time
+	if history, err := sub.History(); err == nil {
+		for _, h := range history {
+			if h.StateHistory == nil {
+				continue
+			}
+			switch h.StateHistory.State {
+			case api.SubmissionTurnedIn:
+				turnedIn = h.StateHistory.StateTimestamp
+				returned = time.Time{}
+			case api.SubmissionReturned:
+				if !turnedIn.IsZero() && returned.IsZero() {
+					returned = h.StateHistory.StateTimestamp
+				}
+			}
+		}
+	}
+	if turnedIn.IsZero() || returned.IsZero() {
+		turnedIn, returned = sub.SubmittedTimestamp, sub.ReturnTimestamp
+	}
+	if turnedIn.IsZero() || returned.IsZero() || returned.Before(turnedIn) {
+		return 0, false
+	}
+	return returned.Sub(turnedIn), true
+}
+
+// Turnaround summarizes how quickly a course returns work.
+type Turnaround struct {
+	Returned int
+	Average  time.Duration
+	Slowest  time.Duration
+}
+
+func MeasureTurnaround(submissions []api.StudentSubmission) Turnaround {
+	var t Turnaround
+	var total time.Duration
+	for _, sub := range submissions {
+		delay, ok := ReturnDelay(sub)
+		if !ok {
+			continue
+		}
+		t.Returned++
+		total += delay
+		if delay > t.Slowest {
+			t.Slowest = delay
+		}
+	}
+	if t.Returned > 0 {
+		t.Average = total / time.Duration(t.Returned)
+	}
+	return t
+}
@@ -0,0 +1,116 @@
+// Package logging writes leveled diagnostics as key=value lines, in the
+// format of log/slog's text handler, plus optional dumps of HTTP bodies.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelDebug
+)
+
+func (l Level) String() string {
+	if l == LevelDebug {
+		return "DEBUG"
+	}
+	return "INFO"
+}
+
+// Logger is safe for concurrent use. A nil *Logger logs nothing, so callers
+// don't have to check whether logging is on.
+type Logger struct {
+	mu     sync.Mutex
+	w      io.Writer
+	level  Level
+	bodies io.Writer
+	now    func() time.Time
+}
+
+// New logs messages at level or below to w.
+func New(w io.Writer, level Level) *Logger {
+	return &Logger{w: w, level: level, now: time.Now}
+}
+
+// DumpBodies also writes sanitized request and response bodies to w.
+func (l *Logger) DumpBodies(w io.Writer) {
+	l.bodies = w
+}
+
+func (l *Logger) Enabled(level Level) bool {
+	return l != nil && l.w != nil && level <= l.level
+}
+
+// Info logs msg with alternating keys and values, like slog.
+func (l *Logger) Info(msg string, args ...interface{}) {
+	l.log(LevelInfo, msg, args)
+}
+
+func (l *Logger) Debug(msg string, args ...interface{}) {
+	l.log(LevelDebug, msg, args)
+}
+
+func (l *Logger) log(level Level, msg string, args []interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+	var b strings.Builder
+	b.WriteString("time=")
+	b.WriteString(l.now().Format("15:04:05.000"))
+	b.WriteString(" level=")
+	b.WriteString(level.String())
+	b.WriteString(" msg=")
+	b.WriteString(quote(msg))
+	for i := 0; i < len(args); i += 2 {
+		key := fmt.Sprint(args[i])
+		var value interface{} = "!MISSING"
+		if i+1 < len(args) {
+			value = args[i+1]
+		}
+		b.WriteByte(' ')
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(quote(formatValue(value)))
+	}
+	b.WriteByte('\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.w, b.String())
+}
+
+// Body records an HTTP body, with secrets and email addresses blanked out.
+// label says which request it belongs to, e.g. "GET https://... response".
+func (l *Logger) Body(label string, body []byte) {
+	if l == nil || l.bodies == nil || len(body) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.bodies, "--- %s %s\n%s\n\n", l.now().Format(time.RFC3339), label, Sanitize(body))
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.Round(time.Millisecond).String()
+	case error:
+		return v.Error()
+	}
+	return fmt.Sprint(v)
+}
+
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
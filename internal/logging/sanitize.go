@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// secretFields are JSON fields and query parameters whose values are
+// credentials.
+var secretFields = []string{"access_token", "refresh_token", "id_token", "client_secret", "key", "code", "password"}
+
+var (
+	secretPattern = regexp.MustCompile(`("(?i:` + strings.Join(secretFields, "|") + `)"\s*:\s*)"[^"]*"`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// Sanitize blanks out credentials and email addresses in a body so dumps
+// can be shared in bug reports.
+func Sanitize(body []byte) []byte {
+	body = secretPattern.ReplaceAll(body, []byte(`$1"REDACTED"`))
+	return emailPattern.ReplaceAll(body, []byte("REDACTED@example.com"))
+}
+
+// SanitizeURL blanks out credentials passed as query parameters.
+func SanitizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	q := u.Query()
+	changed := false
+	for _, f := range secretFields {
+		if q.Has(f) {
+			q.Set(f, "REDACTED")
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Desktop shows a desktop notification with notify-send on Linux and BSD,
+// osascript on macOS and a PowerShell toast on Windows.
+func Desktop(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToast(title, body))
+	default:
+		cmd = exec.Command("notify-send", "--app-name=gc-cli", title, body)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("failed to show notification: %w: %s", err, msg)
+		}
+		return fmt.Errorf("failed to show notification: %w", err)
+	}
+	return nil
+}
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// windowsToast is a PowerShell script raising a toast through the Windows
+// Runtime, which needs no module installed.
+func windowsToast(title, body string) string {
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+	return `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode(` + quote(title) + `)) | Out-Null
+$text.Item(1).AppendChild($xml.CreateTextNode(` + quote(body) + `)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('gc-cli').Show($toast)`
+}
@@ -0,0 +1,35 @@
+// Package notify decides whether a notification may be delivered right now.
+package notify
+
+import (
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// Gate combines per-item snoozes with do-not-disturb windows. Anything that
+// sends notifications should check Allow first.
+type Gate struct {
+	Quiet   []QuietWindow
+	Snoozes *store.Snoozes
+}
+
+func (g *Gate) Quieted(now time.Time) bool {
+	for _, w := range g.Quiet {
+		if w.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow reports whether a notification about id may be sent at now.
+func (g *Gate) Allow(id string, now time.Time) bool {
+	if g.Quieted(now) {
+		return false
+	}
+	if g.Snoozes != nil && g.Snoozes.IsSnoozed(id, now) {
+		return false
+	}
+	return true
+}
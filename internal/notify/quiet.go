@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/config"
+)
+
+// QuietWindow is a recurring do-not-disturb period. Times are minutes after
+// midnight; End < Start means the window crosses midnight.
+type QuietWindow struct {
+	Start int
+	End   int
+	Days  map[time.Weekday]bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func QuietWindowsFromConfig(windows []config.QuietHoursConfig) ([]QuietWindow, error) {
+	var result []QuietWindow
+	for _, wc := range windows {
+		start, err := parseClock(wc.Start)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours: invalid start %q (want HH:MM)", wc.Start)
+		}
+		end, err := parseClock(wc.End)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours: invalid end %q (want HH:MM)", wc.End)
+		}
+
+		w := QuietWindow{Start: start, End: end}
+		for _, d := range wc.Days {
+			name := strings.ToLower(d)
+			if len(name) > 3 {
+				name = name[:3]
+			}
+			day, ok := weekdays[name]
+			if !ok {
+				return nil, fmt.Errorf("quiet hours: unknown day %q", d)
+			}
+			if w.Days == nil {
+				w.Days = make(map[time.Weekday]bool)
+			}
+			w.Days[day] = true
+		}
+		result = append(result, w)
+	}
+	return result, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether t falls inside the window. For windows crossing
+// midnight, Days refers to the day the window starts.
+func (w QuietWindow) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+
+	if w.Start <= w.End {
+		return w.onDay(t.Weekday()) && minute >= w.Start && minute < w.End
+	}
+	if minute >= w.Start {
+		return w.onDay(t.Weekday())
+	}
+	if minute < w.End {
+		return w.onDay(t.AddDate(0, 0, -1).Weekday())
+	}
+	return false
+}
+
+func (w QuietWindow) onDay(d time.Weekday) bool {
+	return len(w.Days) == 0 || w.Days[d]
+}
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"fmt"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// CourseGrades is what the alert rules are evaluated against.
+type CourseGrades struct {
+	Course      api.Course
+	Coursework  []api.CourseWork
+	Submissions []api.StudentSubmission
+}
+
+// Alert is a triggered rule. Key identifies the rule and the grade or course
+// it fired for; ItemID is what snoozes apply to.
+type Alert struct {
+	RuleID  string
+	Key     string
+	ItemID  string
+	Message string
+}
+
+func EvaluateRules(rules []store.AlertRule, courses []CourseGrades) []Alert {
+	var alerts []Alert
+	for _, rule := range rules {
+		for _, cg := range courses {
+			if rule.CourseID != "" && rule.CourseID != cg.Course.ID {
+				continue
+			}
+			switch rule.Kind {
+			case store.RuleGradeBelow:
+				alerts = append(alerts, gradeBelow(rule, cg)...)
+			case store.RuleAverageBelow:
+				if alert, ok := averageBelow(rule, cg); ok {
+					alerts = append(alerts, alert)
+				}
+			}
+		}
+	}
+	return alerts
+}
+
+func gradeBelow(rule store.AlertRule, cg CourseGrades) []Alert {
+	work := make(map[string]api.CourseWork, len(cg.Coursework))
+	for _, cw := range cg.Coursework {
+		work[cw.ID] = cw
+	}
+
+	var alerts []Alert
+	for _, sub := range cg.Submissions {
+		cw, ok := work[sub.CourseWorkID]
+		if !ok || cw.MaxPoints == 0 || sub.State != api.SubmissionReturned {
+			continue
+		}
+		percent := sub.AssignedGrade / float64(cw.MaxPoints) * 100
+		if percent >= rule.Threshold {
+			continue
+		}
+		alerts = append(alerts, Alert{
+			RuleID:  rule.ID,
+			Key:     rule.ID + ":" + sub.ID,
+			ItemID:  cw.ID,
+			Message: fmt.Sprintf("%s: %q graded %.0f/%d (%.1f%%, below %.0f%%)", cg.Course.Name, cw.Title, sub.AssignedGrade, cw.MaxPoints, percent, rule.Threshold),
+		})
+	}
+	return alerts
+}
+
+func averageBelow(rule store.AlertRule, cg CourseGrades) (Alert, bool) {
+	percent, ok := gradebook.Summarize(cg.Coursework, cg.Submissions).Percent()
+	if !ok || percent >= rule.Threshold {
+		return Alert{}, false
+	}
+	return Alert{
+		RuleID:  rule.ID,
+		Key:     rule.ID + ":" + cg.Course.ID,
+		ItemID:  cg.Course.ID,
+		Message: fmt.Sprintf("%s: average is %.1f%%, below %.0f%%", cg.Course.Name, percent, rule.Threshold),
+	}, true
+}
@@ -0,0 +1,99 @@
+package offline
+
+import (
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// Fields a Change can report.
+const (
+	FieldTitle       = "title"
+	FieldDue         = "due"
+	FieldPoints      = "points"
+	FieldDescription = "description"
+)
+
+// Change is an assignment that was edited between two syncs.
+type Change struct {
+	CourseWorkID string
+	Before       store.CourseWorkVersion
+	After        store.CourseWorkVersion
+	Fields       []string
+}
+
+// Version records cw as seen in snap. Descriptions are only kept from deep
+// snapshots, since shallow ones never fetch them.
+func Version(snap *Snapshot, c Course, cw api.CourseWork) store.CourseWorkVersion {
+	v := store.CourseWorkVersion{
+		Seen:      snap.Age(c),
+		CourseID:  cw.CourseID,
+		Title:     cw.Title,
+		Due:       agenda.DueTime(cw),
+		MaxPoints: cw.MaxPoints,
+	}
+	if snap.Deep {
+		desc := cw.Description
+		v.Description = &desc
+	}
+	return v
+}
+
+// Changes compares the coursework in two snapshots. New and deleted
+// assignments aren't changes; descriptions are compared only when both
+// snapshots have them.
+func Changes(old, cur *Snapshot) []Change {
+	before := make(map[string]store.CourseWorkVersion)
+	for _, c := range old.Courses {
+		for _, cw := range c.Coursework {
+			before[cw.ID] = Version(old, c, cw)
+		}
+	}
+
+	var changes []Change
+	for _, c := range cur.Courses {
+		for _, cw := range c.Coursework {
+			prev, ok := before[cw.ID]
+			if !ok {
+				continue
+			}
+			next := Version(cur, c, cw)
+			if fields := diffFields(prev, next); len(fields) > 0 {
+				changes = append(changes, Change{CourseWorkID: cw.ID, Before: prev, After: next, Fields: fields})
+			}
+		}
+	}
+	return changes
+}
+
+func diffFields(a, b store.CourseWorkVersion) []string {
+	var fields []string
+	if a.Title != b.Title {
+		fields = append(fields, FieldTitle)
+	}
+	if !a.Due.Equal(b.Due) {
+		fields = append(fields, FieldDue)
+	}
+	if a.MaxPoints != b.MaxPoints {
+		fields = append(fields, FieldPoints)
+	}
+	if a.Description != nil && b.Description != nil && *a.Description != *b.Description {
+		fields = append(fields, FieldDescription)
+	}
+	return fields
+}
+
+// RecordChanges adds changes to the saved revision history.
+func RecordChanges(st *store.Store, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	rev, err := st.Revisions()
+	if err != nil {
+		return err
+	}
+	for _, ch := range changes {
+		rev.Record(ch.CourseWorkID, ch.Before, ch.After)
+	}
+	return st.SaveRevisions(rev)
+}
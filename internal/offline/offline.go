@@ -0,0 +1,292 @@
+// Package offline keeps a local snapshot of Classroom data so it can be read
+// without a network connection.
+package offline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+const snapshotName = "offline"
+
+// metadataFields is what a shallow sync keeps for each coursework item:
+// enough to list and plan, without descriptions or materials.
+var metadataFields = []string{"id", "courseId", "title", "state", "workType", "maxPoints", "dueDate", "dueTime", "scheduledTime", "alternateLink"}
+
+type Snapshot struct {
+	SyncedAt time.Time `json:"syncedAt"`
+	Deep     bool      `json:"deep"`
+	Courses  []Course  `json:"courses"`
+}
+
+type Course struct {
+	// SyncedAt is when this course was last fetched; older snapshots only
+	// have the snapshot-wide time.
+	SyncedAt      time.Time          `json:"syncedAt,omitempty"`
+	Course        api.Course         `json:"course"`
+	Coursework    []api.CourseWork   `json:"coursework"`
+	Announcements []api.Announcement `json:"announcements,omitempty"`
+	Files         []File             `json:"files,omitempty"`
+	// Done lists coursework I've turned in or had returned, so offline
+	// readers can tell what is still outstanding.
+	Done []string `json:"done,omitempty"`
+	// Submissions keeps the state and grade of each of my submissions, for
+	// grade summaries. Snapshots from before it was added don't have it.
+	Submissions []api.StudentSubmission `json:"submissions,omitempty"`
+}
+
+// File is a Drive material considered for download. Path is empty when it
+// was skipped, and Skipped says why.
+type File struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Path    string `json:"path,omitempty"`
+	Size    int64  `json:"size,omitempty"`
+	Skipped string `json:"skipped,omitempty"`
+}
+
+type Options struct {
+	// Deep fetches full coursework, announcements and, with Attachments,
+	// Drive materials.
+	Deep        bool
+	Attachments bool
+	// MaxFileSize skips larger attachments; zero means no limit.
+	MaxFileSize int64
+	// Dir is where attachments are written, one subdirectory per course.
+	Dir string
+}
+
+func Load(st *store.Store) (*Snapshot, error) {
+	snap := &Snapshot{}
+	if err := st.Load(snapshotName, snap); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+func Save(st *store.Store, snap *Snapshot) error {
+	return st.Save(snapshotName, snap)
+}
+
+// Relocate rewrites downloaded file paths after the data directory moved
+// from oldDir to newDir.
+func Relocate(st *store.Store, oldDir, newDir string) error {
+	snap, err := Load(st)
+	if err != nil {
+		return err
+	}
+	prefix := oldDir + string(filepath.Separator)
+	var changed bool
+	for i := range snap.Courses {
+		for j := range snap.Courses[i].Files {
+			f := &snap.Courses[i].Files[j]
+			if strings.HasPrefix(f.Path, prefix) {
+				f.Path = filepath.Join(newDir, strings.TrimPrefix(f.Path, prefix))
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return Save(st, snap)
+}
+
+// Sync downloads every active course. progress, if set, is called as each
+// course starts.
+func Sync(ctx context.Context, client *api.Client, opts Options, progress func(name string)) (*Snapshot, error) {
+	courses, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list courses: %w", err)
+	}
+
+	snap := &Snapshot{SyncedAt: time.Now(), Deep: opts.Deep}
+	for _, course := range courses {
+		if course.CourseState != api.CourseActive {
+			continue
+		}
+		if progress != nil {
+			progress(course.Name)
+		}
+
+		oc, err := syncCourse(ctx, client, course, opts)
+		if err != nil {
+			return nil, err
+		}
+		snap.Courses = append(snap.Courses, *oc)
+	}
+	return snap, nil
+}
+
+func syncCourse(ctx context.Context, client *api.Client, course api.Course, opts Options) (*Course, error) {
+	var callOpts []api.CallOption
+	if !opts.Deep {
+		callOpts = append(callOpts, api.WithFields(metadataFields...))
+	}
+
+	coursework, _, err := client.ListCourseWork(ctx, course.ID, 100, callOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coursework for %s: %w", course.Name, err)
+	}
+
+	submissions, _, err := client.ListStudentSubmissions(ctx, course.ID, "-", 100,
+		api.WithFields("courseWorkId", "state", "late", "assignedGrade"), api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submissions for %s: %w", course.Name, err)
+	}
+
+	oc := &Course{SyncedAt: time.Now(), Course: course, Coursework: coursework, Submissions: submissions}
+	for _, sub := range submissions {
+		if sub.State.Done() {
+			oc.Done = append(oc.Done, sub.CourseWorkID)
+		}
+	}
+	if !opts.Deep {
+		return oc, nil
+	}
+
+	oc.Announcements, _, err = client.ListAnnouncements(ctx, course.ID, 100)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list announcements for %s: %w", course.Name, err)
+	}
+
+	if opts.Attachments {
+		dir := filepath.Join(opts.Dir, course.ID)
+		for _, cw := range coursework {
+			for _, m := range cw.Materials {
+				if m.DriveFile == nil {
+					continue
+				}
+				oc.Files = append(oc.Files, downloadFile(ctx, client, m.DriveFile.DriveFile, dir, opts.MaxFileSize))
+			}
+		}
+	}
+	return oc, nil
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// downloadFile fetches one Drive material. Failures are recorded on the File
+// rather than aborting the sync: many teacher files aren't downloadable with
+// the scopes gc-cli asks for.
+func downloadFile(ctx context.Context, client *api.Client, df api.DriveFile, dir string, maxSize int64) File {
+	f := File{ID: df.ID, Title: df.Title}
+
+	info, err := client.GetDriveFile(ctx, df.ID)
+	switch {
+	case api.IsForbidden(err) || api.IsNotFound(err):
+		f.Skipped = "no access"
+		return f
+	case err != nil:
+		f.Skipped = err.Error()
+		return f
+	case info.IsGoogleDoc():
+		f.Skipped = "Google Docs file (open online)"
+		return f
+	case maxSize > 0 && info.Size > maxSize:
+		f.Skipped = fmt.Sprintf("larger than %d MB", maxSize>>20)
+		return f
+	}
+
+	data, err := client.DownloadDriveFile(ctx, df.ID)
+	if err != nil {
+		f.Skipped = err.Error()
+		return f
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		f.Skipped = err.Error()
+		return f
+	}
+	path := filepath.Join(dir, df.ID+"-"+unsafeName.ReplaceAllString(info.Name, "_"))
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		f.Skipped = err.Error()
+		return f
+	}
+
+	f.Path = path
+	f.Size = int64(len(data))
+	return f
+}
+
+// What can be dropped from a snapshot with Forget.
+const (
+	Coursework    = "coursework"
+	Announcements = "announcements"
+	Files         = "files"
+)
+
+// Age returns when the course was last synced.
+func (s *Snapshot) Age(c Course) time.Time {
+	if !c.SyncedAt.IsZero() {
+		return c.SyncedAt
+	}
+	return s.SyncedAt
+}
+
+// Forget drops one kind of data for a course, or for every course when
+// courseID is empty, and returns how many items went. Downloaded files are
+// deleted from disk too.
+func (s *Snapshot) Forget(courseID, what string) (int, error) {
+	var removed int
+	for i := range s.Courses {
+		c := &s.Courses[i]
+		if courseID != "" && c.Course.ID != courseID {
+			continue
+		}
+		switch what {
+		case Coursework:
+			removed += len(c.Coursework)
+			c.Coursework = nil
+		case Announcements:
+			removed += len(c.Announcements)
+			c.Announcements = nil
+		case Files:
+			for _, f := range c.Files {
+				if f.Path == "" {
+					continue
+				}
+				if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+					return removed, fmt.Errorf("failed to delete %s: %w", f.Path, err)
+				}
+			}
+			removed += len(c.Files)
+			c.Files = nil
+		default:
+			return 0, fmt.Errorf("unknown offline data %q", what)
+		}
+	}
+	return removed, nil
+}
+
+// DueOn returns published coursework due on the given day that I haven't
+// turned in, across all synced courses.
+func (s *Snapshot) DueOn(day time.Time) []api.CourseWork {
+	y, m, d := day.Date()
+	var due []api.CourseWork
+	for _, c := range s.Courses {
+		done := make(map[string]bool, len(c.Done))
+		for _, id := range c.Done {
+			done[id] = true
+		}
+		for _, cw := range c.Coursework {
+			if cw.State != api.CourseWorkPublished || cw.DueDate == nil || done[cw.ID] {
+				continue
+			}
+			dy, dm, dd := agenda.DueTime(cw).In(day.Location()).Date()
+			if dy == y && dm == m && dd == d {
+				due = append(due, cw)
+			}
+		}
+	}
+	return due
+}
@@ -0,0 +1,175 @@
+package offline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+// Transport answers Classroom API reads from snap, so commands keep working
+// from the last sync when the API can't be used, e.g. because a school admin
+// has disabled it. Changes, and reads of anything sync doesn't keep, fail
+// with FAILED_PRECONDITION.
+func Transport(snap *Snapshot) http.RoundTripper {
+	return &transport{snap: snap}
+}
+
+type transport struct {
+	snap *Snapshot
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return errorResponse(req, http.StatusBadRequest, "FAILED_PRECONDITION", "changes need the Classroom API; only data from the last sync can be read"), nil
+	}
+	if req.URL.Host != "classroom.googleapis.com" {
+		return errorResponse(req, http.StatusBadRequest, "FAILED_PRECONDITION", req.URL.Host+" isn't kept by gc-cli sync"), nil
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/v1"), "/"), "/")
+	v, status, msg := t.answer(parts, req)
+	if status != http.StatusOK {
+		code := "FAILED_PRECONDITION"
+		if status == http.StatusNotFound {
+			code = "NOT_FOUND"
+		}
+		return errorResponse(req, status, code, msg), nil
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return response(req, http.StatusOK, body), nil
+}
+
+// answer resolves /courses[/{id}[/courseWork|announcements[/{id}[/studentSubmissions[/{id}]]]]].
+func (t *transport) answer(parts []string, req *http.Request) (interface{}, int, string) {
+	if len(parts) == 0 || parts[0] != "courses" {
+		return nil, http.StatusBadRequest, "/" + strings.Join(parts, "/") + " isn't kept by gc-cli sync"
+	}
+	if len(parts) == 1 {
+		states := req.URL.Query()["courseStates"]
+		var courses []api.Course
+		for _, c := range t.snap.Courses {
+			if len(states) == 0 || contains(states, string(c.Course.CourseState)) {
+				courses = append(courses, c.Course)
+			}
+		}
+		return map[string]interface{}{"courses": courses}, http.StatusOK, ""
+	}
+
+	course := t.course(parts[1])
+	if course == nil {
+		return nil, http.StatusNotFound, fmt.Sprintf("course %s wasn't synced", parts[1])
+	}
+	if len(parts) == 2 {
+		return course.Course, http.StatusOK, ""
+	}
+
+	switch parts[2] {
+	case "courseWork":
+		if len(parts) == 3 {
+			return map[string]interface{}{"courseWork": course.Coursework}, http.StatusOK, ""
+		}
+		if len(parts) == 4 {
+			for _, cw := range course.Coursework {
+				if cw.ID == parts[3] {
+					return cw, http.StatusOK, ""
+				}
+			}
+			return nil, http.StatusNotFound, fmt.Sprintf("coursework %s wasn't synced", parts[3])
+		}
+		if parts[4] == "studentSubmissions" {
+			return submissions(course, parts[3], parts[5:], req.URL.Query())
+		}
+	case "announcements":
+		if len(parts) == 3 {
+			return map[string]interface{}{"announcements": course.Announcements}, http.StatusOK, ""
+		}
+		if len(parts) == 4 {
+			for _, a := range course.Announcements {
+				if a.ID == parts[3] {
+					return a, http.StatusOK, ""
+				}
+			}
+			return nil, http.StatusNotFound, fmt.Sprintf("announcement %s wasn't synced", parts[3])
+		}
+	}
+	return nil, http.StatusBadRequest, "/" + strings.Join(parts, "/") + " isn't kept by gc-cli sync"
+}
+
+// submissions answers for my own submissions, the only ones sync keeps, so
+// a userId filter changes nothing. courseWorkID may be "-" for every
+// assignment in the course.
+func submissions(course *Course, courseWorkID string, rest []string, query url.Values) (interface{}, int, string) {
+	states := query["states"]
+	late := query.Get("late")
+	var subs []api.StudentSubmission
+	for _, s := range course.Submissions {
+		if courseWorkID != "-" && s.CourseWorkID != courseWorkID {
+			continue
+		}
+		if len(states) > 0 && !contains(states, string(s.State)) {
+			continue
+		}
+		if (late == string(api.LateOnly) && !s.Late) || (late == string(api.NotLateOnly) && s.Late) {
+			continue
+		}
+		subs = append(subs, s)
+	}
+	if len(rest) == 0 {
+		return map[string]interface{}{"studentSubmissions": subs}, http.StatusOK, ""
+	}
+	for _, s := range subs {
+		if rest[0] == "me" || s.ID == rest[0] {
+			return s, http.StatusOK, ""
+		}
+	}
+	return nil, http.StatusNotFound, "submission wasn't synced"
+}
+
+func (t *transport) course(id string) *Course {
+	for i := range t.snap.Courses {
+		if t.snap.Courses[i].Course.ID == id {
+			return &t.snap.Courses[i]
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func errorResponse(req *http.Request, status int, code, msg string) *http.Response {
+	body, _ := json.Marshal(map[string]interface{}{
+		"error": map[string]interface{}{"code": status, "message": msg, "status": code},
+	})
+	return response(req, status, body)
+}
+
+func response(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}
+}
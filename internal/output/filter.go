@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Filter passes items through command, a shell command line that reads them
+// as a JSON array on stdin and writes the array to print on stdout, e.g. to
+// translate text or convert grades. It may change, drop or reorder items,
+// but what it writes must still decode as items. The command's stderr is
+// passed through so it can explain a failure.
+//
+// Items the command passes through unchanged keep the fields JSON leaves out,
+// such as the points behind a table's totals. When it rewrites items but
+// keeps their number, each is decoded over the item in its place, so those
+// fields survive a map too.
+func Filter[T any](ctx context.Context, command string, items []T) ([]T, error) {
+	if items == nil {
+		items = []T{}
+	}
+	in, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output for filter: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var out bytes.Buffer
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("output filter %q failed: %w", command, err)
+	}
+
+	// A filter that prints nothing leaves nothing to print, rather than
+	// failing to decode.
+	if strings.TrimSpace(out.String()) == "" {
+		return []T{}, nil
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(out.Bytes(), &raw); err != nil {
+		return nil, fmt.Errorf("output filter %q didn't print a JSON array of the same items: %w", command, err)
+	}
+
+	var original []json.RawMessage
+	if err := json.Unmarshal(in, &original); err != nil {
+		return nil, fmt.Errorf("failed to encode output for filter: %w", err)
+	}
+	unchanged := make(map[string][]int, len(items))
+	for i, r := range original {
+		key := canonicalJSON(r)
+		unchanged[key] = append(unchanged[key], i)
+	}
+	filtered := make([]T, len(raw))
+	for i, r := range raw {
+		key := canonicalJSON(r)
+		if same := unchanged[key]; len(same) > 0 {
+			filtered[i] = items[same[0]]
+			unchanged[key] = same[1:]
+			continue
+		}
+		if len(raw) == len(items) {
+			filtered[i] = items[i]
+		}
+		if err := json.Unmarshal(r, &filtered[i]); err != nil {
+			return nil, fmt.Errorf("output filter %q didn't print a JSON array of the same items: %w", command, err)
+		}
+	}
+	return filtered, nil
+}
+
+// canonicalJSON re-encodes a JSON value with sorted keys and the same
+// escaping and number formatting, so an item a filter printed in its own
+// style still matches the one it was given.
+func canonicalJSON(r json.RawMessage) string {
+	var v any
+	if err := json.Unmarshal(r, &v); err != nil {
+		return ""
+	}
+	enc, _ := json.Marshal(v)
+	return string(enc)
+}
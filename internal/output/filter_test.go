@@ -0,0 +1,59 @@
+package output
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+type filterItem struct {
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+
+	rank int
+}
+
+func TestFilterKeepsHiddenFields(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("filters run through sh")
+	}
+	items := []filterItem{{"Lab <1>", 8, 1}, {"Quiz", 5, 2}, {"Essay", 9.5, 3}}
+
+	for _, tt := range []struct {
+		name, command string
+		want          []filterItem
+	}{
+		{"drop and reorder", `printf '[{"points": 9.50, "name": "Essay"}, {"name": "Lab <1>", "points": 8}]'`,
+			[]filterItem{{"Essay", 9.5, 3}, {"Lab <1>", 8, 1}}},
+		{"rewrite in place", `printf '[{"name":"Lab <1>","points":80},{"name":"Quiz","points":50},{"name":"Essay","points":95}]'`,
+			[]filterItem{{"Lab <1>", 80, 1}, {"Quiz", 50, 2}, {"Essay", 95, 3}}},
+		{"rewrite and drop", `printf '[{"name":"Quiz","points":50}]'`,
+			[]filterItem{{"Quiz", 50, 0}}},
+		{"print nothing", `true`, []filterItem{}},
+	} {
+		got, err := Filter(context.Background(), tt.command, items)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: item %d = %+v, want %+v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestFilterRejectsOtherOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("filters run through sh")
+	}
+	if _, err := Filter(context.Background(), `echo '{"name": "x"}'`, []filterItem{{Name: "x"}}); err == nil {
+		t.Error("want an error for output that isn't an array")
+	}
+	if _, err := Filter(context.Background(), `exit 3`, []filterItem{{Name: "x"}}); err == nil {
+		t.Error("want an error for a failing filter")
+	}
+}
@@ -0,0 +1,146 @@
+// Package output writes list results for scripts and spreadsheets: JSON,
+// YAML, CSV or TSV. Tables for people stay with each command, which knows
+// how to lay its own out.
+//
+// Field names are the same in every format: JSON and YAML keep the JSON
+// names of the objects written, and CSV and TSV columns are named after the
+// JSON fields they come from.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Format string
+
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+	CSV   Format = "csv"
+	TSV   Format = "tsv"
+)
+
+// Formats lists the formats in the order they're offered.
+var Formats = []Format{Table, JSON, YAML, CSV, TSV}
+
+// Parse reads a format name such as "csv"; empty means Table.
+func Parse(s string) (Format, error) {
+	if s == "" {
+		return Table, nil
+	}
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("unknown output format %q (use %s)", s, strings.Join(names, ", "))
+}
+
+// Column is one CSV/TSV column: a JSON field name and how to read it from
+// an item.
+type Column[T any] struct {
+	Name  string
+	Value func(T) string
+}
+
+// Write writes items in format f: whole objects for JSON and YAML, and one
+// row of cols per item for CSV and TSV. Table isn't handled here.
+func Write[T any](w io.Writer, f Format, items []T, cols []Column[T]) error {
+	switch f {
+	case JSON, YAML:
+		return writeDocument(w, f, items)
+	case CSV, TSV:
+		records := make([][]string, 0, len(items)+1)
+		header := make([]string, len(cols))
+		for i, col := range cols {
+			header[i] = col.Name
+		}
+		records = append(records, header)
+		for _, item := range items {
+			row := make([]string, len(cols))
+			for i, col := range cols {
+				row[i] = col.Value(item)
+			}
+			records = append(records, row)
+		}
+		return writeDelimited(w, f, records)
+	}
+	return fmt.Errorf("output format %q isn't supported here", f)
+}
+
+// WriteOne writes a single object as JSON or YAML.
+func WriteOne(w io.Writer, f Format, v interface{}) error {
+	if f != JSON && f != YAML {
+		return fmt.Errorf("output format %q isn't supported here (use json or yaml)", f)
+	}
+	return writeDocument(w, f, v)
+}
+
+func writeDocument(w io.Writer, f Format, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	if f == JSON {
+		_, err := w.Write(append(data, '\n'))
+		return err
+	}
+
+	// JSON is YAML, so decoding it into a node keeps the JSON field names
+	// and their order.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to convert output to YAML: %w", err)
+	}
+	plainStyle(&doc)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// plainStyle drops the flow style and quoting that nodes decoded from JSON
+// carry, so the YAML reads as block YAML.
+func plainStyle(n *yaml.Node) {
+	n.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, child := range n.Content {
+		plainStyle(child)
+	}
+}
+
+func writeDelimited(w io.Writer, f Format, records [][]string) error {
+	cw := csv.NewWriter(w)
+	if f == TSV {
+		cw.Comma = '\t'
+		// Tabs and newlines would break TSV rows; spreadsheets don't
+		// expect quoting there.
+		for _, row := range records {
+			for i, v := range row {
+				row[i] = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(v)
+			}
+		}
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f, err)
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+// Package picker is a small inline arrow-key menu for CLI commands that
+// want a quick choice without launching the full TUI.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrCancelled is returned when the user backs out with esc, q or ctrl+c.
+var ErrCancelled = errors.New("cancelled")
+
+const maxVisible = 12
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86"))
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	optionStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+	hintStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+// Shortcut is a key that picks the highlighted option and reports which key
+// was used, so callers can skip a follow-up menu.
+type Shortcut struct {
+	Key   string
+	Label string
+}
+
+type model struct {
+	title     string
+	options   []string
+	shortcuts []Shortcut
+	cursor    int
+	offset    int
+	chosen    bool
+	pressed   string
+	quitting  bool
+
+	// filtering puts a search box above the list; matches holds the indexes
+	// of the options it lets through, best first.
+	filtering bool
+	input     textinput.Model
+	keywords  []string
+	matches   []int
+}
+
+func newModel(title string, options []string, shortcuts []Shortcut) model {
+	m := model{title: title, options: options, shortcuts: shortcuts}
+	m.matches = make([]int, len(options))
+	for i := range options {
+		m.matches[i] = i
+	}
+	return m
+}
+
+func (m model) Init() tea.Cmd {
+	if m.filtering {
+		return textinput.Blink
+	}
+	return nil
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.filtering {
+		return m.updateFiltering(msg)
+	}
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	for _, sc := range m.shortcuts {
+		if key.String() == sc.Key {
+			m.chosen = true
+			m.pressed = sc.Key
+			return m, tea.Quit
+		}
+	}
+
+	switch key.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.options)-1 {
+			m.cursor++
+		}
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = len(m.options) - 1
+	case "enter":
+		m.chosen = true
+		return m, tea.Quit
+	case "esc", "q", "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	}
+
+	m.scroll()
+	return m, nil
+}
+
+// updateFiltering handles keys while the search box has focus. Letters go to
+// the search, so shortcuts need alt held down.
+func (m model) updateFiltering(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	for _, sc := range m.shortcuts {
+		if key.String() == "alt+"+sc.Key && len(m.matches) > 0 {
+			m.chosen = true
+			m.pressed = sc.Key
+			return m, tea.Quit
+		}
+	}
+
+	switch key.String() {
+	case "up", "ctrl+p":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "ctrl+n":
+		if m.cursor < len(m.matches)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if len(m.matches) > 0 {
+			m.chosen = true
+			return m, tea.Quit
+		}
+	case "esc":
+		if m.input.Value() == "" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		m.input.SetValue("")
+		m.refilter()
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	default:
+		before := m.input.Value()
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		if m.input.Value() != before {
+			m.refilter()
+		}
+		return m, cmd
+	}
+
+	m.scroll()
+	return m, nil
+}
+
+// refilter recomputes the matches for the current search and moves the
+// cursor back to the top one.
+func (m *model) refilter() {
+	m.matches = match(m.input.Value(), m.options, m.keywords)
+	m.cursor = 0
+	m.offset = 0
+}
+
+func (m *model) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+maxVisible {
+		m.offset = m.cursor - maxVisible + 1
+	}
+}
+
+// match returns the indexes of options containing every word of query,
+// case-insensitively, in the option or its keywords. Options where each word
+// starts a word come first, so "bio" ranks "Biology" above "Microbiology".
+func match(query string, options, keywords []string) []int {
+	words := strings.Fields(strings.ToLower(query))
+	var prefixed, inner []int
+	for i, option := range options {
+		text := strings.ToLower(option)
+		if i < len(keywords) {
+			text += " " + strings.ToLower(keywords[i])
+		}
+		all, starts := true, true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				all = false
+				break
+			}
+			if !startsWord(text, w) {
+				starts = false
+			}
+		}
+		switch {
+		case !all:
+		case starts:
+			prefixed = append(prefixed, i)
+		default:
+			inner = append(inner, i)
+		}
+	}
+	return append(prefixed, inner...)
+}
+
+func startsWord(text, w string) bool {
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	}) {
+		if strings.HasPrefix(field, w) {
+			return true
+		}
+	}
+	return strings.HasPrefix(text, w)
+}
+
+func (m model) View() string {
+	if m.chosen || m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(m.title) + "\n")
+	if m.filtering {
+		b.WriteString(m.input.View() + "\n")
+	}
+
+	end := m.offset + maxVisible
+	if end > len(m.matches) {
+		end = len(m.matches)
+	}
+	for i := m.offset; i < end; i++ {
+		option := m.options[m.matches[i]]
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render("> "+option) + "\n")
+		} else {
+			b.WriteString(optionStyle.Render("  "+option) + "\n")
+		}
+	}
+	if len(m.matches) == 0 {
+		b.WriteString(hintStyle.Render("  no matches") + "\n")
+	} else if len(m.matches) > maxVisible || len(m.matches) < len(m.options) {
+		b.WriteString(hintStyle.Render(fmt.Sprintf("  (%d/%d of %d)", m.cursor+1, len(m.matches), len(m.options))) + "\n")
+	}
+
+	hint := "↑/↓ move • enter select"
+	prefix := ""
+	if m.filtering {
+		hint = "type to search • " + hint
+		prefix = "alt+"
+	}
+	for _, sc := range m.shortcuts {
+		hint += " • " + prefix + sc.Key + " " + sc.Label
+	}
+	if m.filtering && m.input.Value() != "" {
+		hint += " • esc clear"
+	} else {
+		hint += " • esc cancel"
+	}
+	b.WriteString(hintStyle.Render(hint) + "\n")
+	return b.String()
+}
+
+// Pick shows options on stderr and returns the index chosen.
+func Pick(title string, options []string) (int, error) {
+	i, _, err := PickWithShortcuts(title, options, nil)
+	return i, err
+}
+
+// PickWithShortcuts is Pick with extra keys. It also returns the shortcut
+// key that made the choice, or "" if it was enter.
+func PickWithShortcuts(title string, options []string, shortcuts []Shortcut) (int, string, error) {
+	if len(options) == 0 {
+		return 0, "", fmt.Errorf("nothing to choose from")
+	}
+
+	return run(newModel(title, options, shortcuts))
+}
+
+// PickFiltered is PickWithShortcuts with a search box: typing narrows the
+// list to options matching the text, and enter picks the top match.
+// keywords, if given, holds extra text to match per option, such as an ID
+// or nickname that isn't part of its label. Shortcuts are pressed with alt.
+func PickFiltered(title string, options, keywords []string, shortcuts []Shortcut) (int, string, error) {
+	if len(options) == 0 {
+		return 0, "", fmt.Errorf("nothing to choose from")
+	}
+
+	m := newModel(title, options, shortcuts)
+	m.filtering = true
+	m.keywords = keywords
+	m.input = textinput.New()
+	m.input.Prompt = "Search: "
+	m.input.Placeholder = "type to filter"
+	m.input.Focus()
+	return run(m)
+}
+
+func run(m model) (int, string, error) {
+	p := tea.NewProgram(m, tea.WithOutput(os.Stderr))
+	result, err := p.Run()
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to run picker: %w", err)
+	}
+
+	m = result.(model)
+	if !m.chosen {
+		return 0, "", ErrCancelled
+	}
+	return m.matches[m.cursor], m.pressed, nil
+}
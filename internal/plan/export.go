@@ -0,0 +1,87 @@
+package plan
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteICS writes one all-day event per block, so the plan shows up next to
+// the rest of my calendar without claiming specific hours.
+func WriteICS(w io.Writer, p *Plan, stamp time.Time) error {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\n")
+	b.WriteString("VERSION:2.0\r\n")
+	b.WriteString("PRODID:-//gc-cli//plan//EN\r\n")
+	for _, day := range p.Days {
+		for _, blk := range day.Blocks {
+			date := day.Date.Format("20060102")
+			b.WriteString("BEGIN:VEVENT\r\n")
+			fmt.Fprintf(&b, "UID:plan-%s-%s@gc-cli\r\n", date, blk.Item.Work.ID)
+			fmt.Fprintf(&b, "DTSTAMP:%s\r\n", stamp.UTC().Format("20060102T150405Z"))
+			fmt.Fprintf(&b, "DTSTART;VALUE=DATE:%s\r\n", date)
+			fmt.Fprintf(&b, "DTEND;VALUE=DATE:%s\r\n", day.Date.AddDate(0, 0, 1).Format("20060102"))
+			fmt.Fprintf(&b, "SUMMARY:%s\r\n", escapeText(fmt.Sprintf("%s: %s", FormatDuration(blk.Duration), blk.Item.Work.Title)))
+			fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", escapeText(fmt.Sprintf("%s, due %s", blk.Item.Course.Name, blk.Item.Due().Format("Mon Jan 2 15:04"))))
+			if link := blk.Item.Work.AlternateLink; link != "" {
+				fmt.Fprintf(&b, "URL:%s\r\n", link)
+			}
+			b.WriteString("TRANSP:TRANSPARENT\r\n")
+			b.WriteString("END:VEVENT\r\n")
+		}
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes one row per block, for importing into task managers.
+func WriteCSV(w io.Writer, p *Plan) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"date", "task", "minutes", "course", "due", "link"}); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, day := range p.Days {
+		for _, blk := range day.Blocks {
+			row := []string{
+				day.Date.Format("2006-01-02"),
+				blk.Item.Work.Title,
+				strconv.Itoa(int(blk.Duration.Minutes())),
+				blk.Item.Course.Name,
+				blk.Item.Due().Format("2006-01-02 15:04"),
+				blk.Item.Work.AlternateLink,
+			}
+			if err := cw.Write(row); err != nil {
+				return fmt.Errorf("failed to write CSV: %w", err)
+			}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// FormatDuration renders durations as "45m", "2h" or "1h30m".
+func FormatDuration(d time.Duration) string {
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func escapeText(s string) string {
+	return icsEscaper.Replace(s)
+}
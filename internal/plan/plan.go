@@ -0,0 +1,170 @@
+// Package plan spreads upcoming work over the days before it is due, within
+// the time I have each day.
+package plan
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/forecast"
+	"github.com/timboy697/gc-cli/internal/schoolcal"
+)
+
+// slot is the smallest block of time the planner hands out.
+const slot = 15 * time.Minute
+
+const defaultDaily = 2 * time.Hour
+
+// Availability is how much time I can spend on schoolwork each weekday.
+type Availability map[time.Weekday]time.Duration
+
+// AvailabilityFromConfig reads plan.availability. Days that aren't listed
+// get two hours; an empty config means two hours every day.
+func AvailabilityFromConfig(pc config.PlanConfig) (Availability, error) {
+	avail := make(Availability, 7)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		avail[d] = defaultDaily
+	}
+	for name, d := range pc.Availability {
+		day, ok := schoolcal.ParseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("plan availability: unknown day %q", name)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("plan availability: negative time for %s", name)
+		}
+		avail[day] = d
+	}
+	return avail, nil
+}
+
+// Block is time set aside on one day for one item.
+type Block struct {
+	Item     agenda.Item
+	Duration time.Duration
+}
+
+type Day struct {
+	Date      time.Time
+	Available time.Duration
+	// Break names the holiday the day falls in, if any.
+	Break  string
+	Blocks []Block
+}
+
+func (d Day) Planned() time.Duration {
+	var total time.Duration
+	for _, b := range d.Blocks {
+		total += b.Duration
+	}
+	return total
+}
+
+func (d Day) free() time.Duration {
+	return d.Available - d.Planned()
+}
+
+// Shortfall is work that didn't fit before its due date.
+type Shortfall struct {
+	Item    agenda.Item
+	Missing time.Duration
+}
+
+type Plan struct {
+	Days      []Day
+	Shortfall []Shortfall
+}
+
+// Build plans outstanding items due within the n days starting today. Items
+// are taken in due order and spread evenly over the days before they're
+// due, so each gets a little time every day rather than one long session.
+func Build(items []agenda.Item, est forecast.Estimator, avail Availability, cal *schoolcal.Calendar, now time.Time, n int) *Plan {
+	today := startOfDay(now)
+	p := &Plan{Days: make([]Day, n)}
+	for i := range p.Days {
+		date := today.AddDate(0, 0, i)
+		p.Days[i] = Day{Date: date, Available: avail[date.Weekday()]}
+		p.Days[i].Break, _ = cal.Break(date)
+	}
+	horizon := today.AddDate(0, 0, n)
+
+	sorted := append([]agenda.Item(nil), items...)
+	agenda.Sort(sorted)
+	for _, item := range sorted {
+		due := item.Due()
+		if item.Done() || due.IsZero() || due.Before(now) || !due.Before(horizon) {
+			continue
+		}
+		if missing := p.schedule(item, est.Estimate(item), lastWorkDay(today, due)); missing > 0 {
+			p.Shortfall = append(p.Shortfall, Shortfall{Item: item, Missing: missing})
+		}
+	}
+	return p
+}
+
+// lastWorkDay is the index of the last day to work on something due at due:
+// the day before, unless it's due today.
+func lastWorkDay(today, due time.Time) int {
+	idx := int(startOfDay(due).Sub(today).Hours()/24+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return idx
+}
+
+// schedule spreads need over days 0..last and returns what didn't fit.
+func (p *Plan) schedule(item agenda.Item, need time.Duration, last int) time.Duration {
+	need = roundUp(need)
+	for need > 0 {
+		var open []int
+		for i := 0; i <= last && i < len(p.Days); i++ {
+			if p.Days[i].free() > 0 {
+				open = append(open, i)
+			}
+		}
+		if len(open) == 0 {
+			break
+		}
+
+		share := roundUp(need / time.Duration(len(open)))
+		for _, i := range open {
+			take := share
+			if free := p.Days[i].free(); take > free {
+				take = free
+			}
+			if take > need {
+				take = need
+			}
+			if take <= 0 {
+				break
+			}
+			p.Days[i].add(item, take)
+			need -= take
+		}
+	}
+	return need
+}
+
+func (d *Day) add(item agenda.Item, dur time.Duration) {
+	for i := range d.Blocks {
+		if d.Blocks[i].Item.Work.ID == item.Work.ID {
+			d.Blocks[i].Duration += dur
+			return
+		}
+	}
+	d.Blocks = append(d.Blocks, Block{Item: item, Duration: dur})
+}
+
+func roundUp(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return ((d + slot - 1) / slot) * slot
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,356 @@
+// Package preset bundles the parts of a setup worth sharing with classmates
+// who take the same courses: course order and periods, the school calendar,
+// quiet hours, grade alert rules, pinned items and active mutes. Nothing in
+// a preset identifies the person who exported it.
+package preset
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// Version is bumped when the file layout changes incompatibly.
+const Version = 1
+
+type Preset struct {
+	Version    int                       `mapstructure:"version"`
+	Name       string                    `mapstructure:"name"`
+	Courses    []config.CourseSlot       `mapstructure:"courses"`
+	Calendar   config.CalendarConfig     `mapstructure:"calendar"`
+	QuietHours []config.QuietHoursConfig `mapstructure:"quiet_hours"`
+	Rules      []Rule                    `mapstructure:"rules"`
+	Pins       []Pin                     `mapstructure:"pins"`
+	Mutes      []Mute                    `mapstructure:"mutes"`
+}
+
+// Rule is a grade alert rule; see store.AlertRule.
+type Rule struct {
+	Kind      string  `mapstructure:"kind"`
+	Threshold float64 `mapstructure:"threshold"`
+	CourseID  string  `mapstructure:"course"`
+}
+
+// Pin is a TUI favorite; see store.Visit.
+type Pin struct {
+	Kind   string `mapstructure:"kind"`
+	ID     string `mapstructure:"id"`
+	Label  string `mapstructure:"label"`
+	Parent string `mapstructure:"parent"`
+}
+
+// Mute silences notifications about an item until an RFC 3339 time.
+type Mute struct {
+	ID    string `mapstructure:"id"`
+	Until string `mapstructure:"until"`
+}
+
+// HasCalendar reports whether the preset describes the school year.
+func (p *Preset) HasCalendar() bool {
+	return !emptyCalendar(p.Calendar)
+}
+
+// Build collects the shareable parts of cfg and st. Expired mutes are left
+// out.
+func Build(cfg *config.Config, st *store.Store, name string, now time.Time) (*Preset, error) {
+	p := &Preset{
+		Version:    Version,
+		Name:       name,
+		Courses:    cfg.Courses,
+		Calendar:   cfg.Calendar,
+		QuietHours: cfg.Notify.QuietHours,
+	}
+
+	rules, err := st.AlertRules()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rules.Rules {
+		p.Rules = append(p.Rules, Rule{Kind: r.Kind, Threshold: r.Threshold, CourseID: r.CourseID})
+	}
+
+	history, err := st.History()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range history.Favorites {
+		p.Pins = append(p.Pins, Pin{Kind: v.Kind, ID: v.ID, Label: v.Label, Parent: v.Parent})
+	}
+
+	snoozes, err := st.Snoozes()
+	if err != nil {
+		return nil, err
+	}
+	for id, until := range snoozes.Until {
+		if until.After(now) {
+			p.Mutes = append(p.Mutes, Mute{ID: id, Until: until.UTC().Format(time.RFC3339)})
+		}
+	}
+	return p, nil
+}
+
+// Write saves p as YAML.
+func Write(path string, p *Preset) error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.Set("version", p.Version)
+	if p.Name != "" {
+		v.Set("name", p.Name)
+	}
+	if len(p.Courses) > 0 {
+		v.Set("courses", courseSettings(p.Courses))
+	}
+	if !emptyCalendar(p.Calendar) {
+		v.Set("calendar", calendarSettings(p.Calendar))
+	}
+	if len(p.QuietHours) > 0 {
+		v.Set("quiet_hours", quietHoursSettings(p.QuietHours))
+	}
+	if len(p.Rules) > 0 {
+		rules := make([]map[string]interface{}, len(p.Rules))
+		for i, r := range p.Rules {
+			rules[i] = map[string]interface{}{"kind": r.Kind, "threshold": r.Threshold}
+			if r.CourseID != "" {
+				rules[i]["course"] = r.CourseID
+			}
+		}
+		v.Set("rules", rules)
+	}
+	if len(p.Pins) > 0 {
+		pins := make([]map[string]interface{}, len(p.Pins))
+		for i, pin := range p.Pins {
+			pins[i] = map[string]interface{}{"kind": pin.Kind, "id": pin.ID, "label": pin.Label}
+			if pin.Parent != "" {
+				pins[i]["parent"] = pin.Parent
+			}
+		}
+		v.Set("pins", pins)
+	}
+	if len(p.Mutes) > 0 {
+		mutes := make([]map[string]interface{}, len(p.Mutes))
+		for i, m := range p.Mutes {
+			mutes[i] = map[string]interface{}{"id": m.ID, "until": m.Until}
+		}
+		v.Set("mutes", mutes)
+	}
+
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to write preset: %w", err)
+	}
+	return nil
+}
+
+// Read loads a preset written by Write.
+func Read(path string) (*Preset, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read preset: %w", err)
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read preset: %w", err)
+	}
+	var p Preset
+	if err := v.Unmarshal(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse preset: %w", err)
+	}
+	if p.Version > Version {
+		return nil, fmt.Errorf("preset version %d is newer than this gc-cli supports (%d); upgrade to import it", p.Version, Version)
+	}
+	return &p, nil
+}
+
+// Result says what Apply changed. Skipped lists config sections left alone
+// because they were already set locally.
+type Result struct {
+	Courses    int
+	Calendar   bool
+	QuietHours int
+	Rules      int
+	Pins       int
+	Mutes      int
+	Skipped    []string
+}
+
+// Apply merges p into the local setup. Rules, pins and mutes are added
+// alongside existing ones; course order, calendar and quiet hours replace
+// local settings only when those are unset or overwrite is true.
+func Apply(cfg *config.Config, st *store.Store, p *Preset, overwrite bool, now time.Time) (*Result, error) {
+	res := &Result{}
+	settings := map[string]interface{}{}
+
+	if len(p.Courses) > 0 && !reflect.DeepEqual(p.Courses, cfg.Courses) {
+		if len(cfg.Courses) == 0 || overwrite {
+			cfg.Courses = p.Courses
+			settings["courses"] = courseSettings(p.Courses)
+			res.Courses = len(p.Courses)
+		} else {
+			res.Skipped = append(res.Skipped, "courses")
+		}
+	}
+	if !emptyCalendar(p.Calendar) && !reflect.DeepEqual(p.Calendar, cfg.Calendar) {
+		if emptyCalendar(cfg.Calendar) || overwrite {
+			cfg.Calendar = p.Calendar
+			settings["calendar"] = calendarSettings(p.Calendar)
+			res.Calendar = true
+		} else {
+			res.Skipped = append(res.Skipped, "calendar")
+		}
+	}
+	if len(p.QuietHours) > 0 && !reflect.DeepEqual(p.QuietHours, cfg.Notify.QuietHours) {
+		if len(cfg.Notify.QuietHours) == 0 || overwrite {
+			cfg.Notify.QuietHours = p.QuietHours
+			settings["notify.quiet_hours"] = quietHoursSettings(p.QuietHours)
+			res.QuietHours = len(p.QuietHours)
+		} else {
+			res.Skipped = append(res.Skipped, "quiet_hours")
+		}
+	}
+	if len(settings) > 0 {
+		if err := config.SaveSettings(cfg, settings); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applyRules(st, p.Rules, res, now); err != nil {
+		return nil, err
+	}
+	if err := applyPins(st, p.Pins, res, now); err != nil {
+		return nil, err
+	}
+	if err := applyMutes(st, p.Mutes, res, now); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func applyRules(st *store.Store, rules []Rule, res *Result, now time.Time) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	existing, err := st.AlertRules()
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if r.Kind != store.RuleGradeBelow && r.Kind != store.RuleAverageBelow {
+			return fmt.Errorf("preset has an unknown rule kind %q", r.Kind)
+		}
+		if hasRule(existing, r) {
+			continue
+		}
+		existing.Add(store.AlertRule{Kind: r.Kind, Threshold: r.Threshold, CourseID: r.CourseID, Created: now})
+		res.Rules++
+	}
+	return st.SaveAlertRules(existing)
+}
+
+func hasRule(rules *store.AlertRules, r Rule) bool {
+	for _, e := range rules.Rules {
+		if e.Kind == r.Kind && e.Threshold == r.Threshold && e.CourseID == r.CourseID {
+			return true
+		}
+	}
+	return false
+}
+
+func applyPins(st *store.Store, pins []Pin, res *Result, now time.Time) error {
+	if len(pins) == 0 {
+		return nil
+	}
+	history, err := st.History()
+	if err != nil {
+		return err
+	}
+	for _, pin := range pins {
+		v := store.Visit{Kind: pin.Kind, ID: pin.ID, Label: pin.Label, Parent: pin.Parent, LastSeen: now}
+		if !history.IsFavorite(v) {
+			history.ToggleFavorite(v)
+			res.Pins++
+		}
+	}
+	return st.SaveHistory(history)
+}
+
+func applyMutes(st *store.Store, mutes []Mute, res *Result, now time.Time) error {
+	if len(mutes) == 0 {
+		return nil
+	}
+	snoozes, err := st.Snoozes()
+	if err != nil {
+		return err
+	}
+	for _, m := range mutes {
+		until, err := time.Parse(time.RFC3339, m.Until)
+		if err != nil {
+			return fmt.Errorf("preset mute for %s has an invalid time %q", m.ID, m.Until)
+		}
+		// Keep a local mute that already lasts longer.
+		if !until.After(now) || !snoozes.Until[m.ID].Before(until) {
+			continue
+		}
+		snoozes.Snooze(m.ID, until)
+		res.Mutes++
+	}
+	return st.SaveSnoozes(snoozes)
+}
+
+func emptyCalendar(c config.CalendarConfig) bool {
+	return c.WeekStart == "" && len(c.SchoolDays) == 0 && len(c.Terms) == 0 && len(c.Holidays) == 0
+}
+
+func courseSettings(slots []config.CourseSlot) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(slots))
+	for i, s := range slots {
+		out[i] = map[string]interface{}{"course": s.Course}
+		if s.Period != "" {
+			out[i]["period"] = s.Period
+		}
+	}
+	return out
+}
+
+func calendarSettings(c config.CalendarConfig) map[string]interface{} {
+	out := map[string]interface{}{}
+	if c.WeekStart != "" {
+		out["week_start"] = c.WeekStart
+	}
+	if len(c.SchoolDays) > 0 {
+		out["school_days"] = c.SchoolDays
+	}
+	if len(c.Terms) > 0 {
+		out["terms"] = periodSettings(c.Terms)
+	}
+	if len(c.Holidays) > 0 {
+		out["holidays"] = periodSettings(c.Holidays)
+	}
+	return out
+}
+
+func periodSettings(periods []config.PeriodConfig) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(periods))
+	for i, p := range periods {
+		out[i] = map[string]interface{}{"name": p.Name, "start": p.Start}
+		if p.End != "" {
+			out[i]["end"] = p.End
+		}
+	}
+	return out
+}
+
+func quietHoursSettings(windows []config.QuietHoursConfig) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(windows))
+	for i, w := range windows {
+		out[i] = map[string]interface{}{"start": w.Start, "end": w.End}
+		if len(w.Days) > 0 {
+			out[i]["days"] = w.Days
+		}
+	}
+	return out
+}
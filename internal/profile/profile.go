@@ -0,0 +1,162 @@
+// Package profile manages named credential sets kept alongside the main
+// config, such as a read-only profile imported on a guardian's machine.
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/config"
+	"golang.org/x/oauth2"
+)
+
+const bundleVersion = 1
+
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+type Profile struct {
+	Name         string    `json:"name"`
+	ReadOnly     bool      `json:"read_only"`
+	Scopes       []string  `json:"scopes,omitempty"`
+	ClientID     string    `json:"client_id"`
+	ClientSecret string    `json:"client_secret"`
+	Created      time.Time `json:"created"`
+}
+
+// Bundle is the portable form of a profile, including its token. Anyone
+// holding a bundle can act with the profile's scopes.
+type Bundle struct {
+	Version int           `json:"version"`
+	Profile Profile       `json:"profile"`
+	Token   *oauth2.Token `json:"token"`
+}
+
+func root(cfg *config.Config) string {
+	return filepath.Join(cfg.Dir(), "profiles")
+}
+
+func dir(cfg *config.Config, name string) string {
+	return filepath.Join(root(cfg), name)
+}
+
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (letters, digits, - and _ only)", name)
+	}
+	return nil
+}
+
+func Load(cfg *config.Config, name string) (*Profile, error) {
+	data, err := os.ReadFile(filepath.Join(dir(cfg, name), "profile.json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile %q: %w", name, err)
+	}
+
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %q: %w", name, err)
+	}
+	return &p, nil
+}
+
+func Save(cfg *config.Config, p *Profile, token *oauth2.Token) error {
+	if err := ValidateName(p.Name); err != nil {
+		return err
+	}
+	d := dir(cfg, p.Name)
+	if err := os.MkdirAll(d, 0700); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal profile: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(d, "profile.json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write profile: %w", err)
+	}
+
+	return auth.TokenToFile(filepath.Join(d, "token.json"), token)
+}
+
+func List(cfg *config.Config) ([]*Profile, error) {
+	entries, err := os.ReadDir(root(cfg))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var profiles []*Profile
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if p, err := Load(cfg, e.Name()); err == nil {
+			profiles = append(profiles, p)
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// Apply switches cfg to use the named profile's credentials and its own
+// data directory, so profiles never share local state.
+func Apply(cfg *config.Config, name string) error {
+	p, err := Load(cfg, name)
+	if err != nil {
+		return err
+	}
+
+	cfg.Profile = p.Name
+	cfg.ReadOnly = p.ReadOnly
+	cfg.Auth.TokenFile = filepath.Join(dir(cfg, name), "token.json")
+	cfg.UseDataDir(filepath.Join(dir(cfg, name), "data"))
+	cfg.Auth.Scopes = p.Scopes
+	if p.ClientID != "" {
+		cfg.Auth.ClientID = p.ClientID
+		cfg.Auth.ClientSecret = p.ClientSecret
+	}
+	return nil
+}
+
+func WriteBundle(path string, b *Bundle) error {
+	b.Version = bundleVersion
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+	if b.Version != bundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+	if b.Token == nil || b.Token.RefreshToken == "" {
+		return nil, fmt.Errorf("bundle has no refresh token")
+	}
+	return &b, nil
+}
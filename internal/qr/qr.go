@@ -0,0 +1,527 @@
+// Package qr draws QR codes in the terminal, e.g. a sign-in link to scan
+// with a phone. It encodes bytes at the lowest error correction level, the
+// smallest code for a given text, since a screen doesn't get smudged.
+package qr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code is an encoded QR code, a square of dark and light modules.
+type Code struct {
+	size     int
+	modules  [][]bool
+	function [][]bool
+}
+
+// Error correction codewords per block and number of blocks, by version, at
+// level L (ISO/IEC 18004 table 9).
+var (
+	eccPerBlock = [41]int{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30}
+	eccBlocks   = [41]int{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25}
+)
+
+// eccLevelL is level L's format information bits.
+const eccLevelL = 1
+
+// Encode makes the smallest QR code holding text.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	version := 1
+	for ; version <= 40; version++ {
+		if bitLength(version, len(data)) <= dataCodewords(version)*8 {
+			break
+		}
+	}
+	if version > 40 {
+		return nil, fmt.Errorf("text too long for a QR code (%d bytes)", len(data))
+	}
+
+	// Byte mode: the mode, the length, the bytes, then a terminator and
+	// padding up to the version's capacity.
+	var bits bitBuffer
+	bits.append(0x4, 4)
+	bits.append(len(data), countBits(version))
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := dataCodewords(version) * 8
+	terminator := capacity - len(bits)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bits.append(0, terminator)
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	c := newCode(version)
+	c.drawFunctionPatterns(version)
+	c.drawCodewords(addECCAndInterleave(version, bits.bytes()))
+
+	// Keep the mask that leaves the fewest patterns a scanner could trip on.
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask)
+	}
+	c.applyMask(best)
+	c.drawFormatBits(best)
+	return c, nil
+}
+
+// Size is the number of modules along a side, without a quiet zone.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark reports whether the module at column x, row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y][x]
+}
+
+// Lines draws the code with two rows of modules per line of half blocks,
+// dark modules as spaces and light ones as blocks, inside a quiet zone of
+// quiet modules. It reads correctly when printed light on dark.
+func (c *Code) Lines(quiet int) []string {
+	size := c.size + 2*quiet
+	dark := func(x, y int) bool {
+		x, y = x-quiet, y-quiet
+		if x < 0 || y < 0 || x >= c.size || y >= c.size {
+			return false
+		}
+		return c.modules[y][x]
+	}
+	var lines []string
+	for y := 0; y < size; y += 2 {
+		var line strings.Builder
+		for x := 0; x < size; x++ {
+			top, bottom := !dark(x, y), y+1 < size && !dark(x, y+1)
+			switch {
+			case top && bottom:
+				line.WriteString("█")
+			case top:
+				line.WriteString("▀")
+			case bottom:
+				line.WriteString("▄")
+			default:
+				line.WriteString(" ")
+			}
+		}
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+func newCode(version int) *Code {
+	size := version*4 + 17
+	c := &Code{size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range c.modules {
+		c.modules[i] = make([]bool, size)
+		c.function[i] = make([]bool, size)
+	}
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.function[y][x] = true
+}
+
+func (c *Code) drawFunctionPatterns(version int) {
+	for i := 0; i < c.size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+	c.drawFinder(3, 3)
+	c.drawFinder(c.size-4, 3)
+	c.drawFinder(3, c.size-4)
+
+	pos := alignmentPositions(version)
+	n := len(pos)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			// The corners with finder patterns get no alignment pattern.
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			c.drawAlignment(pos[i], pos[j])
+		}
+	}
+
+	// Reserve the format areas now; the real bits go in once the mask is
+	// chosen.
+	c.drawFormatBits(0)
+	c.drawVersion(version)
+}
+
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.size || yy >= c.size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// formatBits is the 15-bit format information for level L and mask: the
+// data, its BCH code, and the standard XOR mask.
+func formatBits(mask int) int {
+	data := eccLevelL<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	// Around the top left finder.
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	// Split between the other two finders.
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.size-15+i, bit(i))
+	}
+	c.setFunction(8, c.size-8, true)
+}
+
+// versionBits is the 18-bit version information of versions 7 and up.
+func versionBits(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+func (c *Code) drawVersion(version int) {
+	if version < 7 {
+		return
+	}
+	bits := versionBits(version)
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 != 0
+		a, b := c.size-11+i%3, i/3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords fills the data area in the standard zigzag: two columns at
+// a time from the right, alternately up and down, skipping the timing
+// column.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.size - 1 - vert
+				}
+				if c.function[y][x] || i >= len(data)*8 {
+					continue
+				}
+				c.modules[y][x] = data[i>>3]>>(7-i&7)&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules picked by mask; applying it twice undoes
+// it.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if !c.function[y][x] && maskBit(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores how hard the code is to scan, by the four rules of the
+// standard: long runs, 2×2 blocks, finder-like patterns and imbalance
+// between dark and light.
+func (c *Code) penalty() int {
+	score := 0
+	line := make([]bool, c.size)
+	for _, vertical := range []bool{false, true} {
+		for i := 0; i < c.size; i++ {
+			for j := 0; j < c.size; j++ {
+				if vertical {
+					line[j] = c.modules[j][i]
+				} else {
+					line[j] = c.modules[i][j]
+				}
+			}
+			score += linePenalty(line)
+		}
+	}
+
+	dark := 0
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < c.size && y+1 < c.size {
+				m := c.modules[y][x]
+				if c.modules[y][x+1] == m && c.modules[y+1][x] == m && c.modules[y+1][x+1] == m {
+					score += 3
+				}
+			}
+		}
+	}
+	total := c.size * c.size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return score + k*10
+}
+
+var finderLike = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+func linePenalty(line []bool) int {
+	score := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += run - 2
+		}
+		run = 1
+	}
+	for i := 0; i+11 <= len(line); i++ {
+		for _, pattern := range finderLike {
+			match := true
+			for j, dark := range pattern {
+				if line[i+j] != dark {
+					match = false
+					break
+				}
+			}
+			if match {
+				score += 40
+			}
+		}
+	}
+	return score
+}
+
+// rawModules is how many modules of a version hold data and error
+// correction, after the function patterns.
+func rawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+func dataCodewords(version int) int {
+	return rawModules(version)/8 - eccPerBlock[version]*eccBlocks[version]
+}
+
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func bitLength(version, n int) int {
+	return 4 + countBits(version) + 8*n
+}
+
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, version*4+17-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// addECCAndInterleave splits data into the version's blocks, adds each
+// block's error correction, and interleaves them. Short blocks come first
+// and are one data codeword shorter.
+func addECCAndInterleave(version int, data []byte) []byte {
+	numBlocks, eccLen := eccBlocks[version], eccPerBlock[version]
+	raw := rawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := range blocks {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, divisor)
+		if i < numShort {
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	var result []byte
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			// Skip the padding of short blocks.
+			if i != shortLen-eccLen || j >= numShort {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// rsDivisor is the Reed-Solomon generator polynomial of degree n over
+// GF(2⁸), highest coefficient first without the leading 1.
+func rsDivisor(n int) []byte {
+	result := make([]byte, n)
+	result[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMul multiplies in GF(2⁸) modulo x⁸+x⁴+x³+x²+1.
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+type bitBuffer []bool
+
+func (b *bitBuffer) append(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, v>>i&1 != 0)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return out
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
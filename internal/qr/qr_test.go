@@ -0,0 +1,176 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Values from the tables in ISO/IEC 18004 annexes C and D.
+func TestFormatAndVersionBits(t *testing.T) {
+	if got := formatBits(0); got != 0x77C4 {
+		t.Errorf("format bits for L, mask 0 = %015b, want 111011111000100", got)
+	}
+	if got := formatBits(7); got != 0x6976 {
+		t.Errorf("format bits for L, mask 7 = %015b, want 110100101110110", got)
+	}
+	if got := versionBits(7); got != 0x07C94 {
+		t.Errorf("version bits for 7 = %018b, want 000111110010010100", got)
+	}
+	if got := versionBits(40); got != 0x28C69 {
+		t.Errorf("version bits for 40 = %018b, want 101000110001101001", got)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	// Data codewords at level L for a few versions (table 7).
+	for version, want := range map[int]int{1: 19, 2: 34, 7: 156, 10: 274, 25: 1276, 40: 2956} {
+		if got := dataCodewords(version); got != want {
+			t.Errorf("version %d holds %d data codewords, want %d", version, got, want)
+		}
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"https://example.com",
+		"https://accounts.google.com/o/oauth2/auth?access_type=offline&client_id=1234567890-abcdefghijklmnop.apps.googleusercontent.com&redirect_uri=http%3A%2F%2Flocalhost%3A41234&response_type=code&scope=" + strings.Repeat("https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fclassroom.courses.readonly+", 8) + "&state=gc-cli-1700000000000000000",
+	} {
+		c, err := Encode(text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decode(t, c); got != text {
+			t.Errorf("decoded %q, want %q", got, text)
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("x", 3000)); err == nil {
+		t.Error("want an error for text beyond version 40")
+	}
+}
+
+func TestLines(t *testing.T) {
+	c, err := Encode("hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := c.Lines(2)
+	if want := (c.Size() + 4 + 1) / 2; len(lines) != want {
+		t.Errorf("%d lines, want %d", len(lines), want)
+	}
+	// The quiet zone is light all round.
+	if lines[0] != strings.Repeat("█", c.Size()+4) {
+		t.Errorf("first line isn't quiet: %q", lines[0])
+	}
+}
+
+// decode reads c back the way a scanner would once it has found the grid:
+// format, unmasking, codewords, blocks, error correction and the segment.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	version := (c.Size() - 17) / 4
+
+	var format int
+	for i := 0; i <= 5; i++ {
+		format |= b2i(c.Dark(8, i)) << i
+	}
+	format |= b2i(c.Dark(8, 7))<<6 | b2i(c.Dark(8, 8))<<7 | b2i(c.Dark(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		format |= b2i(c.Dark(14-i, 8)) << i
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatBits(m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format bits %015b match no mask at level L", format)
+	}
+
+	// Read the data modules in zigzag order, unmasked.
+	layout := newCode(version)
+	layout.drawFunctionPatterns(version)
+	var bits bitBuffer
+	for right := c.Size() - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size(); vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size() - 1 - vert
+				}
+				if !layout.function[y][x] {
+					bits = append(bits, c.Dark(x, y) != maskBit(mask, x, y))
+				}
+			}
+		}
+	}
+	codewords := bits[:len(bits)/8*8].bytes()
+
+	// Undo the interleaving and check every block's error correction.
+	numBlocks, eccLen := eccBlocks[version], eccPerBlock[version]
+	raw := rawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortData := raw/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortData; i++ {
+		for j := range blocks {
+			if i < shortData || j >= numShort {
+				blocks[j] = append(blocks[j], codewords[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+	for j, block := range blocks {
+		n := len(block) - eccLen
+		if got := rsRemainder(block[:n], rsDivisor(eccLen)); !bytes.Equal(got, block[n:]) {
+			t.Fatalf("block %d has wrong error correction", j)
+		}
+		data = append(data, block[:n]...)
+	}
+
+	// One byte-mode segment.
+	var dataBits bitBuffer
+	for _, b := range data {
+		dataBits.append(int(b), 8)
+	}
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v = v<<1 | b2i(dataBits[i])
+		}
+		dataBits = dataBits[n:]
+		return v
+	}
+	if mode := read(4); mode != 0x4 {
+		t.Fatalf("mode %04b, want byte mode", mode)
+	}
+	n := read(countBits(version))
+	text := make([]byte, n)
+	for i := range text {
+		text[i] = byte(read(8))
+	}
+	return string(text)
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
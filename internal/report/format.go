@@ -0,0 +1,42 @@
+package report
+
+import (
+	"fmt"
+	"time"
+)
+
+const timestampLayout = "2006-01-02 15:04"
+
+func percent(earned, possible float64) string {
+	if possible == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", earned/possible*100)
+}
+
+func formatPoints(earned, possible float64) string {
+	return fmt.Sprintf("%.1f/%.0f", earned, possible)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format(timestampLayout)
+}
+
+func (r GradeRow) Score() string {
+	return formatPoints(r.Grade, float64(r.MaxPoints))
+}
+
+func (r GradeRow) SubmittedAt() string {
+	return formatTime(r.Submitted)
+}
+
+func (r GradeRow) ReturnedAt() string {
+	return formatTime(r.Returned)
+}
+
+func (r *GradeReport) GeneratedAt() string {
+	return formatTime(r.Generated)
+}
@@ -0,0 +1,69 @@
+// Package report builds printable records such as grade reports.
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+)
+
+type GradeReport struct {
+	Generated time.Time
+	Courses   []CourseGrades
+}
+
+type CourseGrades struct {
+	Name    string
+	Section string
+	Rows    []GradeRow
+	Percent string
+	Letter  string
+	Points  string
+}
+
+type GradeRow struct {
+	Assignment string
+	Grade      float64
+	MaxPoints  int64
+	Percent    string
+	Submitted  time.Time
+	Returned   time.Time
+}
+
+// CourseGradesFrom lists returned, graded coursework for one course in
+// return order, along with the course total.
+func CourseGradesFrom(course api.Course, coursework []api.CourseWork, submissions []api.StudentSubmission, scale gradebook.Scale) CourseGrades {
+	byWork := make(map[string]api.StudentSubmission, len(submissions))
+	for _, sub := range submissions {
+		byWork[sub.CourseWorkID] = sub
+	}
+
+	cg := CourseGrades{Name: course.Name, Section: course.Section, Percent: "-", Points: "-"}
+	for _, cw := range coursework {
+		sub, ok := byWork[cw.ID]
+		if !ok || cw.State != api.CourseWorkPublished || cw.MaxPoints == 0 || sub.State != api.SubmissionReturned {
+			continue
+		}
+		cg.Rows = append(cg.Rows, GradeRow{
+			Assignment: cw.Title,
+			Grade:      sub.AssignedGrade,
+			MaxPoints:  cw.MaxPoints,
+			Percent:    percent(sub.AssignedGrade, float64(cw.MaxPoints)),
+			Submitted:  sub.SubmittedTimestamp,
+			Returned:   sub.ReturnTimestamp,
+		})
+	}
+	sort.SliceStable(cg.Rows, func(i, j int) bool {
+		return cg.Rows[i].Returned.Before(cg.Rows[j].Returned)
+	})
+
+	summary := gradebook.Summarize(coursework, submissions)
+	if pct, ok := summary.Percent(); ok {
+		cg.Percent = percent(summary.Earned, summary.Possible)
+		cg.Points = formatPoints(summary.Earned, summary.Possible)
+		cg.Letter = scale.Letter(pct)
+	}
+	return cg
+}
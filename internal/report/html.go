@@ -0,0 +1,20 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+func (r *GradeReport) WriteHTML(w io.Writer) error {
+	if err := templates.ExecuteTemplate(w, "grades.html", r); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// US Letter in points, with 54pt (0.75in) margins.
+const (
+	pageWidth  = 612.0
+	pageHeight = 792.0
+	margin     = 54.0
+)
+
+// pdfWriter lays out text-only pages using the standard Helvetica fonts, which
+// every PDF reader provides, so no font embedding is needed.
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+func newPDFWriter() *pdfWriter {
+	p := &pdfWriter{}
+	p.newPage()
+	return p
+}
+
+func (p *pdfWriter) newPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+	p.y = pageHeight - margin
+}
+
+func (p *pdfWriter) page() *bytes.Buffer {
+	return p.pages[len(p.pages)-1]
+}
+
+// ensure starts a new page unless height points remain above the margin.
+func (p *pdfWriter) ensure(height float64) {
+	if p.y-height < margin {
+		p.newPage()
+	}
+}
+
+func (p *pdfWriter) text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+func (p *pdfWriter) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(p.page(), "0.8 G %.2f %.2f m %.2f %.2f l S 0 G\n", x1, y1, x2, y2)
+}
+
+// fit truncates s to roughly fit width at the given size. Helvetica averages
+// about half an em per character, which is close enough for table cells.
+func fit(s string, width, size float64) string {
+	limit := int(width / (size * 0.5))
+	r := []rune(s)
+	if len(r) <= limit {
+		return s
+	}
+	if limit < 2 {
+		return ""
+	}
+	return string(r[:limit-1]) + "…"
+}
+
+// wrap breaks s into lines that roughly fit width at the given size, using
+// the same estimate as fit. A word too long for a line of its own is cut
+// down by fit.
+func wrap(s string, width, size float64) []string {
+	limit := int(width / (size * 0.5))
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(s) {
+		w := []rune(word)
+		if len(line) > 0 && len(line)+1+len(w) > limit {
+			lines = append(lines, string(line))
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, w...)
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, string(line))
+	}
+	for i := range lines {
+		lines[i] = fit(lines[i], width, size)
+	}
+	return lines
+}
+
+// winAnsi holds the characters WinAnsiEncoding puts in 0x80-0x9F, where
+// Latin-1 has control codes.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// pdfString escapes s for a literal string in WinAnsiEncoding. Characters
+// the encoding lacks are replaced with a question mark.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		c, ok := winAnsi[r]
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case ok:
+			fmt.Fprintf(&b, `\%03o`, c)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r >= 160 && r < 256:
+			fmt.Fprintf(&b, `\%03o`, r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func (p *pdfWriter) writeTo(w io.Writer) error {
+	var out bytes.Buffer
+	var offsets []int
+
+	obj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objects 1-4 are fixed; each page then takes a page and a content object.
+	var kids []string
+	for i := range p.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, content := range p.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// columnWidths fits the grade table's five columns between the margins.
+// Tables with any other number of columns share the width evenly.
+var columnWidths = []float64{190, 70, 55, 94, 94}
+
+func (p *pdfWriter) row(cells []string, bold bool) {
+	widths := columnWidths
+	if len(cells) != len(widths) {
+		widths = make([]float64, len(cells))
+		for i := range widths {
+			widths[i] = (pageWidth - 2*margin) / float64(len(cells))
+		}
+	}
+	p.ensure(16)
+	p.y -= 14
+	x := margin
+	for i, cell := range cells {
+		p.text(x, p.y, 9, bold, fit(cell, widths[i]-6, 9))
+		x += widths[i]
+	}
+	p.line(margin, p.y-4, pageWidth-margin, p.y-4)
+}
+
+// block is one piece of the rendered HTML report: a heading, a paragraph or
+// a table row, with its text.
+type block struct {
+	kind  string // h1, h2, p, meta, header, row or total
+	cells []string
+}
+
+// htmlBlocks reads the blocks of an HTML report in the order they appear,
+// skipping the head. The report's own template is regular enough for the
+// XML decoder's HTML mode.
+func htmlBlocks(r io.Reader) ([]block, error) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	var blocks []block
+	var text strings.Builder
+	var kind string
+	var cells []string
+	inHead := false
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return blocks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "head":
+				inHead = true
+			case "h1", "h2", "p":
+				kind = t.Name.Local
+				if class(t) == "meta" {
+					kind = "meta"
+				}
+				text.Reset()
+			case "tr":
+				kind, cells = "row", nil
+				if class(t) == "total" {
+					kind = "total"
+				}
+			case "th":
+				kind = "header"
+				text.Reset()
+			case "td":
+				text.Reset()
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "head":
+				inHead = false
+			case "h1", "h2", "p":
+				blocks = append(blocks, block{kind: kind, cells: []string{collapse(text.String())}})
+			case "th", "td":
+				cells = append(cells, collapse(text.String()))
+			case "tr":
+				blocks = append(blocks, block{kind: kind, cells: cells})
+			}
+		case xml.CharData:
+			if !inHead {
+				text.Write(t)
+			}
+		}
+	}
+}
+
+func class(t xml.StartElement) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == "class" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// WritePDF renders the HTML report as a paginated PDF, so both formats come
+// from the one template. Table headers repeat at the top of each new page.
+func (r *GradeReport) WritePDF(w io.Writer) error {
+	var html bytes.Buffer
+	if err := r.WriteHTML(&html); err != nil {
+		return err
+	}
+	blocks, err := htmlBlocks(&html)
+	if err != nil {
+		return fmt.Errorf("failed to lay out report: %w", err)
+	}
+
+	p := newPDFWriter()
+	var header []string
+	for _, b := range blocks {
+		switch b.kind {
+		case "h1":
+			p.text(margin, p.y-20, 20, true, fit(b.cells[0], pageWidth-2*margin, 20))
+			p.y -= 38
+		case "meta":
+			for _, line := range wrap(b.cells[0], pageWidth-2*margin, 10) {
+				p.ensure(14)
+				p.text(margin, p.y, 10, false, line)
+				p.y -= 14
+			}
+			p.y -= 6
+		case "h2":
+			p.ensure(70)
+			p.y -= 24
+			p.text(margin, p.y, 14, true, fit(b.cells[0], pageWidth-2*margin, 14))
+			p.y -= 6
+		case "p":
+			for _, line := range wrap(b.cells[0], pageWidth-2*margin, 10) {
+				p.ensure(14)
+				p.y -= 14
+				p.text(margin, p.y, 10, false, line)
+			}
+		case "header":
+			header = b.cells
+			p.row(header, true)
+		case "row", "total":
+			if p.y-16 < margin && header != nil {
+				p.newPage()
+				p.row(header, true)
+			}
+			p.row(b.cells, b.kind == "total")
+		}
+	}
+	return p.writeTo(w)
+}
@@ -0,0 +1,187 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleReport() *GradeReport {
+	returned := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC)
+	chem := CourseGrades{Name: "Chemistry", Section: "Period 2", Points: "57.0/60", Percent: "95.0%", Letter: "A"}
+	for i := 1; i <= 60; i++ {
+		chem.Rows = append(chem.Rows, GradeRow{
+			Assignment: fmt.Sprintf("Lab (%d) & report", i),
+			Grade:      19,
+			MaxPoints:  20,
+			Percent:    "95.0%",
+			Returned:   returned,
+		})
+	}
+	return &GradeReport{
+		Generated: returned,
+		Courses:   []CourseGrades{chem, {Name: "Français", Percent: "-", Points: "-"}},
+	}
+}
+
+// pdfObjects checks data is a PDF whose cross-reference table and stream
+// lengths hold, and returns its objects by number.
+func pdfObjects(t *testing.T, data []byte) map[int]string {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.")) {
+		t.Fatalf("no PDF header: %q", data[:min(len(data), 16)])
+	}
+	if !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("no end-of-file marker")
+	}
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if xref >= len(data) || !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d doesn't point at the xref table", xref)
+	}
+	lines := strings.Split(string(data[xref:]), "\n")
+	var first, count int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil || first != 0 {
+		t.Fatalf("xref subsection %q: %v", lines[1], err)
+	}
+	if size := regexp.MustCompile(`/Size (\d+)`).FindStringSubmatch(string(data[xref:])); size == nil || size[1] != strconv.Itoa(count) {
+		t.Errorf("trailer /Size = %v, want %d", size, count)
+	}
+
+	objects := make(map[int]string)
+	for n := 1; n < count; n++ {
+		entry := lines[2+n]
+		if len(entry) != 19 || !strings.HasSuffix(entry, " n ") {
+			t.Fatalf("xref entry %d is %q", n, entry)
+		}
+		off, _ := strconv.Atoi(entry[:10])
+		head := fmt.Sprintf("%d 0 obj\n", n)
+		if !bytes.HasPrefix(data[off:], []byte(head)) {
+			t.Fatalf("xref entry %d points at %q", n, data[off:min(len(data), off+16)])
+		}
+		body := string(data[off+len(head):])
+		body = body[:strings.Index(body, "\nendobj\n")]
+		objects[n] = body
+
+		if i := strings.Index(body, "\nstream\n"); i >= 0 {
+			length := regexp.MustCompile(`/Length (\d+)`).FindStringSubmatch(body[:i])
+			stream := body[i+len("\nstream\n") : strings.LastIndex(body, "endstream")]
+			if length == nil || length[1] != strconv.Itoa(len(stream)) {
+				t.Errorf("object %d has /Length %v, but its stream is %d bytes", n, length, len(stream))
+			}
+		}
+	}
+	if !strings.Contains(objects[1], "/Type /Catalog") {
+		t.Errorf("object 1 is %q, want the catalog", objects[1])
+	}
+	return objects
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().WritePDF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	objects := pdfObjects(t, buf.Bytes())
+
+	pages := regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(objects[2])
+	if pages == nil || pages[1] != "2" {
+		t.Fatalf("pages = %v, want 60 rows to need 2", pages)
+	}
+	var page1, page2 string
+	for n := 1; n <= len(objects); n++ {
+		if strings.Contains(objects[n], "stream\n") {
+			if page1 == "" {
+				page1 = objects[n]
+			} else {
+				page2 = objects[n]
+			}
+		}
+	}
+
+	// Everything comes from the HTML template's output, escaped for PDF.
+	for _, want := range []string{"(Grade Report)", `(Chemistry \227 Period 2)`, `(Lab \(1\) & report)`, "(19.0/20)", "(Assignment)"} {
+		if !strings.Contains(page1, want) {
+			t.Errorf("page 1 doesn't show %s", want)
+		}
+	}
+	for _, want := range []string{"(Assignment)", `(Lab \(60\) & report)`, "(Overall)", "(95.0% A)", `(Fran\347ais)`, "(No graded work yet.)"} {
+		if !strings.Contains(page2, want) {
+			t.Errorf("page 2 doesn't show %s", want)
+		}
+	}
+}
+
+func TestPDFMatchesHTML(t *testing.T) {
+	var html bytes.Buffer
+	if err := sampleReport().WriteHTML(&html); err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := htmlBlocks(&html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, b := range blocks {
+		if b.kind != "row" {
+			kinds = append(kinds, b.kind+":"+strings.Join(b.cells, "|"))
+		}
+	}
+	want := []string{
+		"h1:Grade Report",
+		"meta:Generated " + sampleReport().GeneratedAt(),
+		"h2:Chemistry — Period 2",
+		"header:Assignment|Score|Percent|Submitted|Returned",
+		"total:Overall|57.0/60|95.0% A||",
+		"h2:Français",
+		"p:No graded work yet.",
+	}
+	if got := strings.Join(kinds, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("blocks:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	for in, want := range map[string]string{
+		"Lab (1)":     `Lab \(1\)`,
+		"Français":    `Fran\347ais`,
+		"it’s “done”": `it\222s \223done\224`,
+		"€5 – ™":      `\2005 \226 \231`,
+		"π\u0081":     `??`,
+		"tab\there":   `tab here`,
+	} {
+		if got := pdfString(in); got != want {
+			t.Errorf("pdfString(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	// 100pt at 10pt type fits 20 characters.
+	got := wrap("a long note that runs well past the edge of one line", 100, 10)
+	want := []string{"a long note that", "runs well past the", "edge of one line"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrap = %q, want %q", got, want)
+	}
+	if got := wrap(strings.Repeat("x", 30), 100, 10); len(got) != 1 || len([]rune(got[0])) != 20 {
+		t.Errorf("wrap of a long word = %q, want it cut to one line", got)
+	}
+	if got := wrap("", 100, 10); len(got) != 1 || got[0] != "" {
+		t.Errorf("wrap(\"\") = %q, want one empty line", got)
+	}
+}
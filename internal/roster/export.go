@@ -0,0 +1,96 @@
+// Package roster writes course rosters as contact lists.
+package roster
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatVCard = "vcard"
+)
+
+// Sort orders students by family name, then given name.
+func Sort(students []api.Student) {
+	sort.SliceStable(students, func(i, j int) bool {
+		return nameLess(students[i].Profile.Name, students[j].Profile.Name)
+	})
+}
+
+// SortTeachers orders teachers like Sort.
+func SortTeachers(teachers []api.Teacher) {
+	sort.SliceStable(teachers, func(i, j int) bool {
+		return nameLess(teachers[i].Profile.Name, teachers[j].Profile.Name)
+	})
+}
+
+func nameLess(a, b api.UserName) bool {
+	if !strings.EqualFold(a.FamilyName, b.FamilyName) {
+		return strings.ToLower(a.FamilyName) < strings.ToLower(b.FamilyName)
+	}
+	return strings.ToLower(a.GivenName) < strings.ToLower(b.GivenName)
+}
+
+func Write(w io.Writer, format, courseName string, students []api.Student) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, students)
+	case FormatVCard:
+		return WriteVCard(w, courseName, students)
+	}
+	return fmt.Errorf("unknown format %q (use csv or vcard)", format)
+}
+
+func WriteCSV(w io.Writer, students []api.Student) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"name", "given_name", "family_name", "email", "user_id"}); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, s := range students {
+		p := s.Profile
+		if err := cw.Write([]string{p.Name.FullName, p.Name.GivenName, p.Name.FamilyName, p.EmailAddress, s.UserID}); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteVCard writes one vCard 3.0 entry per student. courseName, if set, is
+// recorded as the contact's category so imports can be grouped by class.
+func WriteVCard(w io.Writer, courseName string, students []api.Student) error {
+	var b strings.Builder
+	for _, s := range students {
+		p := s.Profile
+		b.WriteString("BEGIN:VCARD\r\n")
+		b.WriteString("VERSION:3.0\r\n")
+		fmt.Fprintf(&b, "N:%s;%s;;;\r\n", escape(p.Name.FamilyName), escape(p.Name.GivenName))
+		fmt.Fprintf(&b, "FN:%s\r\n", escape(p.Name.FullName))
+		if p.EmailAddress != "" {
+			fmt.Fprintf(&b, "EMAIL;TYPE=INTERNET:%s\r\n", escape(p.EmailAddress))
+		}
+		if courseName != "" {
+			fmt.Fprintf(&b, "CATEGORIES:%s\r\n", escape(courseName))
+		}
+		b.WriteString("END:VCARD\r\n")
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write vCard: %w", err)
+	}
+	return nil
+}
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\n", `\n`)
+
+func escape(s string) string {
+	return vcardEscaper.Replace(s)
+}
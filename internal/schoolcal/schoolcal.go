@@ -0,0 +1,220 @@
+// Package schoolcal knows the shape of the school year: which days have
+// classes, when terms run and which days are breaks.
+package schoolcal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/config"
+)
+
+const dateLayout = "2006-01-02"
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseWeekday accepts day names such as "mon" or "Monday".
+func ParseWeekday(name string) (time.Weekday, bool) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if len(key) > 3 {
+		key = key[:3]
+	}
+	d, ok := weekdays[key]
+	return d, ok
+}
+
+type span struct {
+	name       string
+	start, end time.Time
+}
+
+func (s span) contains(day time.Time) bool {
+	return !day.Before(s.start) && !day.After(s.end)
+}
+
+// Calendar answers school-day questions. A nil Calendar is a plain Monday
+// to Friday week with no terms or holidays.
+type Calendar struct {
+	weekStart  time.Weekday
+	schoolDays map[time.Weekday]bool
+	terms      []span
+	holidays   []span
+}
+
+func FromConfig(cc config.CalendarConfig) (*Calendar, error) {
+	cal := &Calendar{weekStart: time.Monday}
+	if cc.WeekStart != "" {
+		d, ok := ParseWeekday(cc.WeekStart)
+		if !ok {
+			return nil, fmt.Errorf("calendar: unknown week_start %q", cc.WeekStart)
+		}
+		cal.weekStart = d
+	}
+	for _, name := range cc.SchoolDays {
+		d, ok := ParseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("calendar: unknown school day %q", name)
+		}
+		if cal.schoolDays == nil {
+			cal.schoolDays = make(map[time.Weekday]bool)
+		}
+		cal.schoolDays[d] = true
+	}
+
+	var err error
+	if cal.terms, err = parseSpans("term", cc.Terms, false); err != nil {
+		return nil, err
+	}
+	if cal.holidays, err = parseSpans("holiday", cc.Holidays, true); err != nil {
+		return nil, err
+	}
+	return cal, nil
+}
+
+func parseSpans(kind string, periods []config.PeriodConfig, singleDay bool) ([]span, error) {
+	spans := make([]span, 0, len(periods))
+	for _, p := range periods {
+		start, err := time.ParseInLocation(dateLayout, p.Start, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("calendar: %s %q: invalid start %q (want YYYY-MM-DD)", kind, p.Name, p.Start)
+		}
+		end := start
+		if p.End != "" || !singleDay {
+			if end, err = time.ParseInLocation(dateLayout, p.End, time.Local); err != nil {
+				return nil, fmt.Errorf("calendar: %s %q: invalid end %q (want YYYY-MM-DD)", kind, p.Name, p.End)
+			}
+		}
+		if end.Before(start) {
+			return nil, fmt.Errorf("calendar: %s %q ends before it starts", kind, p.Name)
+		}
+		spans = append(spans, span{name: p.Name, start: start, end: end})
+	}
+	return spans, nil
+}
+
+// Configured reports whether any terms or holidays are set.
+func (c *Calendar) Configured() bool {
+	return c != nil && (len(c.terms) > 0 || len(c.holidays) > 0)
+}
+
+func (c *Calendar) HasTerms() bool {
+	return c != nil && len(c.terms) > 0
+}
+
+// WeekStart returns midnight on the first day of t's week.
+func (c *Calendar) WeekStart(t time.Time) time.Time {
+	first := time.Monday
+	if c != nil {
+		first = c.weekStart
+	}
+	offset := (int(t.Weekday()) - int(first) + 7) % 7
+	return day(t.AddDate(0, 0, -offset))
+}
+
+// Break returns the name of the holiday covering t's day, if any.
+func (c *Calendar) Break(t time.Time) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	d := day(t)
+	for _, h := range c.holidays {
+		if h.contains(d) {
+			return h.name, true
+		}
+	}
+	return "", false
+}
+
+// IsSchoolDay reports whether t's day has classes: a school weekday, inside
+// a term when terms are configured, and not a holiday.
+func (c *Calendar) IsSchoolDay(t time.Time) bool {
+	wd := t.Weekday()
+	if c == nil || c.schoolDays == nil {
+		if wd == time.Saturday || wd == time.Sunday {
+			return false
+		}
+	} else if !c.schoolDays[wd] {
+		return false
+	}
+	if c == nil {
+		return true
+	}
+	if _, ok := c.Break(t); ok {
+		return false
+	}
+	if len(c.terms) > 0 {
+		_, ok := c.term(t)
+		return ok
+	}
+	return true
+}
+
+// SchoolDaysUntil counts the school days after now's day up to and
+// including due's day, so work due tomorrow is 1 school day away unless
+// tomorrow is a holiday.
+func (c *Calendar) SchoolDaysUntil(now, due time.Time) int {
+	n := 0
+	end := day(due)
+	for d := day(now).AddDate(0, 0, 1); !d.After(end); d = d.AddDate(0, 0, 1) {
+		if c.IsSchoolDay(d) {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *Calendar) term(t time.Time) (span, bool) {
+	d := day(t)
+	for _, term := range c.terms {
+		if term.contains(d) {
+			return term, true
+		}
+	}
+	return span{}, false
+}
+
+// TermName names the term t falls in: the configured term, or otherwise
+// the season and year, e.g. "Fall 2025", for years the calendar doesn't
+// cover.
+func (c *Calendar) TermName(t time.Time) string {
+	if c != nil {
+		if term, ok := c.term(t); ok {
+			return term.name
+		}
+	}
+	switch m := t.Month(); {
+	case m <= time.May:
+		return fmt.Sprintf("Spring %d", t.Year())
+	case m <= time.July:
+		return fmt.Sprintf("Summer %d", t.Year())
+	}
+	return fmt.Sprintf("Fall %d", t.Year())
+}
+
+// TermWeek labels t's week within its term, e.g. "Week 7 of Term 2". Weeks
+// are counted from the week the term starts in. It returns "" outside terms.
+func (c *Calendar) TermWeek(t time.Time) string {
+	if c == nil {
+		return ""
+	}
+	// A week belongs to a term if any of its days does, so the week a term
+	// starts midweek is its week 1.
+	term, ok := c.term(t)
+	for i := 0; !ok && i < 7; i++ {
+		term, ok = c.term(c.WeekStart(t).AddDate(0, 0, i))
+	}
+	if !ok {
+		return ""
+	}
+	weeks := int(c.WeekStart(t).Sub(c.WeekStart(term.start)).Hours()/(24*7)+0.5) + 1
+	return fmt.Sprintf("Week %d of %s", weeks, term.name)
+}
+
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
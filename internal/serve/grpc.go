@@ -0,0 +1,392 @@
+package serve
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/offline"
+)
+
+// The gRPC API is the Classroom service of proto/gccli/serve/v1/serve.proto,
+// served over HTTP/2 by the standard library: a message is a 5-byte prefix
+// and its protobuf encoding, and the status goes in the grpc-status trailer.
+
+const grpcService = "/gccli.serve.v1.Classroom/"
+
+// gRPC status codes used here.
+const (
+	codeOK               = 0
+	codeInvalidArgument  = 3
+	codeNotFound         = 5
+	codePermissionDenied = 7
+	codeUnimplemented    = 12
+	codeInternal         = 13
+	codeUnavailable      = 14
+	codeUnauthenticated  = 16
+)
+
+// Kinds of Change.
+const (
+	changeAdded   = 1
+	changeEdited  = 2
+	changeRemoved = 3
+)
+
+// maxRequestSize bounds a request message; requests are a few IDs.
+const maxRequestSize = 64 << 10
+
+// GRPCHandler answers gRPC calls. It needs HTTP/2 underneath, which
+// ListenAndServeGRPC sets up.
+func (s *Server) GRPCHandler() http.Handler {
+	return http.HandlerFunc(s.serveGRPC)
+}
+
+func (s *Server) serveGRPC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "this port only speaks gRPC", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc")
+
+	method := strings.TrimPrefix(r.URL.Path, grpcService)
+	scope, ok := grpcScopes[method]
+	if !ok || method == r.URL.Path {
+		writeGRPCStatus(w, codeUnimplemented, fmt.Sprintf("unknown method %s", r.URL.Path))
+		return
+	}
+	key, ok := s.authenticate(r)
+	if !ok {
+		writeGRPCStatus(w, codeUnauthenticated, "missing or invalid API key")
+		return
+	}
+	if scope != "" && !key.Scopes[scope] {
+		writeGRPCStatus(w, codePermissionDenied, fmt.Sprintf("API key is not allowed to read %s", scope))
+		return
+	}
+	req, err := readGRPCRequest(r.Body)
+	if err != nil {
+		writeGRPCStatus(w, codeInvalidArgument, err.Error())
+		return
+	}
+
+	if method == "Watch" {
+		s.watch(w, r, key, req[1])
+		return
+	}
+
+	snap, err := offline.Load(s.store)
+	if err != nil {
+		writeGRPCStatus(w, codeInternal, err.Error())
+		return
+	}
+	if snap.SyncedAt.IsZero() {
+		writeGRPCStatus(w, codeUnavailable, "nothing synced yet; run 'gc-cli sync'")
+		return
+	}
+	resp := appendTimestamp(nil, 1, snap.SyncedAt)
+	switch method {
+	case "ListCourses":
+		for _, c := range s.courses(snap) {
+			resp = appendMessage(resp, 2, courseMessage(c))
+		}
+	case "ListCoursework":
+		courseID := first(req[1])
+		found := courseID == ""
+		for _, c := range s.sorted(snap) {
+			if courseID != "" && c.Course.ID != courseID {
+				continue
+			}
+			found = true
+			for _, cw := range c.Coursework {
+				resp = appendMessage(resp, 2, courseworkMessage(c.Course.ID, cw, s.clock.Now(), true))
+			}
+		}
+		if !found {
+			writeGRPCStatus(w, codeNotFound, fmt.Sprintf("course %s isn't synced", courseID))
+			return
+		}
+	case "ListUpcoming":
+		now := s.clock.Now()
+		for _, cw := range s.upcomingWork(snap, now) {
+			resp = appendMessage(resp, 2, courseworkMessage(cw.CourseID, cw, now, true))
+		}
+	case "ListSubmissions":
+		courseID, courseWorkID := first(req[1]), first(req[2])
+		for _, c := range s.sorted(snap) {
+			if courseID != "" && c.Course.ID != courseID {
+				continue
+			}
+			for _, sub := range c.Submissions {
+				if courseWorkID != "" && sub.CourseWorkID != courseWorkID {
+					continue
+				}
+				resp = appendMessage(resp, 2, submissionMessage(c.Course.ID, sub))
+			}
+		}
+	case "ListGrades":
+		for _, g := range s.grades(snap) {
+			resp = appendMessage(resp, 2, gradeMessage(g))
+		}
+	}
+	if err := writeGRPCMessage(w, resp); err != nil {
+		return
+	}
+	writeGRPCStatus(w, codeOK, "")
+}
+
+// grpcScopes is the API key scope each method needs. Watch checks scopes
+// per item instead.
+var grpcScopes = map[string]string{
+	"ListCourses":     ScopeCourses,
+	"ListCoursework":  ScopeUpcoming,
+	"ListUpcoming":    ScopeUpcoming,
+	"ListSubmissions": ScopeUpcoming,
+	"ListGrades":      ScopeGrades,
+	"Watch":           "",
+}
+
+// watchItem is an item Watch reports on. Items are compared by cmp, which
+// leaves out what changes with the time rather than with a sync.
+type watchItem struct {
+	id    string
+	field int
+	msg   []byte
+	cmp   string
+}
+
+// watch sends every item once the snapshot has been synced, then, each time
+// a later sync is seen, a Change for every item it added, edited or removed.
+// It runs until the client goes away.
+func (s *Server) watch(w http.ResponseWriter, r *http.Request, key Key, courseIDs []string) {
+	if !key.Scopes[ScopeCourses] && !key.Scopes[ScopeUpcoming] {
+		writeGRPCStatus(w, codePermissionDenied, "API key is not allowed to read courses or upcoming work")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	flush(w)
+
+	var synced time.Time
+	var items []watchItem
+	ticker := time.NewTicker(s.pollEvery)
+	defer ticker.Stop()
+	for {
+		snap, err := offline.Load(s.store)
+		if err != nil {
+			writeGRPCStatus(w, codeInternal, err.Error())
+			return
+		}
+		if !snap.SyncedAt.IsZero() && !snap.SyncedAt.Equal(synced) {
+			next := s.watchItems(snap, key, courseIDs)
+			for _, change := range diffWatchItems(items, next) {
+				msg := appendInt(nil, 1, int64(change.kind))
+				msg = appendTimestamp(msg, 2, snap.SyncedAt)
+				msg = appendMessage(msg, change.item.field, change.item.msg)
+				if err := writeGRPCMessage(w, msg); err != nil {
+					return
+				}
+			}
+			flush(w)
+			synced, items = snap.SyncedAt, next
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) watchItems(snap *offline.Snapshot, key Key, courseIDs []string) []watchItem {
+	now := s.clock.Now()
+	var items []watchItem
+	for _, c := range s.sorted(snap) {
+		if len(courseIDs) > 0 && !contains(courseIDs, c.Course.ID) {
+			continue
+		}
+		if key.Scopes[ScopeCourses] {
+			msg := courseMessage(courseJSON{
+				ID:      c.Course.ID,
+				Name:    c.Course.Name,
+				Label:   s.order.Label(c.Course.ID, c.Course.Name),
+				Section: c.Course.Section,
+				Link:    c.Course.AlternateLink,
+			})
+			items = append(items, watchItem{id: "course/" + c.Course.ID, field: 3, msg: msg, cmp: string(msg)})
+		}
+		if !key.Scopes[ScopeUpcoming] {
+			continue
+		}
+		for _, cw := range c.Coursework {
+			items = append(items, watchItem{
+				id:    "coursework/" + cw.ID,
+				field: 4,
+				msg:   courseworkMessage(c.Course.ID, cw, now, true),
+				cmp:   string(courseworkMessage(c.Course.ID, cw, now, false)),
+			})
+		}
+		for _, sub := range c.Submissions {
+			msg := submissionMessage(c.Course.ID, sub)
+			items = append(items, watchItem{id: "submission/" + sub.ID, field: 5, msg: msg, cmp: string(msg)})
+		}
+	}
+	return items
+}
+
+type watchChange struct {
+	kind int
+	item watchItem
+}
+
+// diffWatchItems lists what changed from prev to next: additions and edits
+// in next's order, then removals in prev's.
+func diffWatchItems(prev, next []watchItem) []watchChange {
+	before := make(map[string]watchItem, len(prev))
+	for _, item := range prev {
+		before[item.id] = item
+	}
+	after := make(map[string]bool, len(next))
+	var changes []watchChange
+	for _, item := range next {
+		after[item.id] = true
+		old, ok := before[item.id]
+		switch {
+		case !ok:
+			changes = append(changes, watchChange{changeAdded, item})
+		case old.cmp != item.cmp:
+			changes = append(changes, watchChange{changeEdited, item})
+		}
+	}
+	for _, item := range prev {
+		if !after[item.id] {
+			changes = append(changes, watchChange{changeRemoved, item})
+		}
+	}
+	return changes
+}
+
+func courseMessage(c courseJSON) []byte {
+	var b []byte
+	b = appendString(b, 1, c.ID)
+	b = appendString(b, 2, c.Name)
+	b = appendString(b, 3, c.Label)
+	b = appendString(b, 4, c.Section)
+	b = appendString(b, 5, c.Link)
+	return b
+}
+
+// courseworkMessage encodes cw, with its window note relative to now when
+// window is set.
+func courseworkMessage(courseID string, cw api.CourseWork, now time.Time, window bool) []byte {
+	var b []byte
+	b = appendString(b, 1, courseID)
+	b = appendString(b, 2, cw.ID)
+	b = appendString(b, 3, cw.Title)
+	if cw.DueDate != nil {
+		b = appendTimestamp(b, 4, agenda.DueTime(cw))
+	}
+	b = appendInt(b, 5, cw.MaxPoints)
+	b = appendString(b, 6, cw.AlternateLink)
+	b = appendTimestamp(b, 7, agenda.OpensAt(cw, now))
+	if window {
+		b = appendString(b, 8, agenda.Window(cw, now))
+	}
+	return b
+}
+
+func submissionMessage(courseID string, sub api.StudentSubmission) []byte {
+	var b []byte
+	b = appendString(b, 1, courseID)
+	b = appendString(b, 2, sub.CourseWorkID)
+	b = appendString(b, 3, sub.ID)
+	b = appendString(b, 4, string(sub.State))
+	b = appendBool(b, 5, sub.Late)
+	if sub.State == api.SubmissionReturned {
+		b = appendOptionalDouble(b, 6, sub.AssignedGrade)
+	}
+	return b
+}
+
+func gradeMessage(g gradeJSON) []byte {
+	var b []byte
+	b = appendString(b, 1, g.CourseID)
+	b = appendString(b, 2, g.Course)
+	b = appendInt(b, 3, int64(g.Graded))
+	b = appendDouble(b, 4, g.Earned)
+	b = appendDouble(b, 5, g.Possible)
+	if g.Percent != nil {
+		b = appendOptionalDouble(b, 6, *g.Percent)
+	}
+	b = appendString(b, 7, g.Letter)
+	b = appendInt(b, 8, int64(g.Missing))
+	return b
+}
+
+// readGRPCRequest reads the single request message of a call.
+func readGRPCRequest(body io.Reader) (map[int][]string, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(body, prefix[:]); err != nil {
+		return nil, fmt.Errorf("missing request message")
+	}
+	if prefix[0] != 0 {
+		return nil, fmt.Errorf("compressed requests aren't supported")
+	}
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if size > maxRequestSize {
+		return nil, fmt.Errorf("request message too large")
+	}
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(body, msg); err != nil {
+		return nil, fmt.Errorf("truncated request message")
+	}
+	return stringFields(msg)
+}
+
+func writeGRPCMessage(w io.Writer, msg []byte) error {
+	var prefix [5]byte
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(msg)))
+	if _, err := w.Write(prefix[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(msg)
+	return err
+}
+
+// writeGRPCStatus ends a call. The status goes in trailers, after any
+// messages.
+func writeGRPCStatus(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set(http.TrailerPrefix+"Grpc-Status", strconv.Itoa(code))
+	if msg != "" {
+		w.Header().Set(http.TrailerPrefix+"Grpc-Message", url.PathEscape(msg))
+	}
+}
+
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+//go:build go1.24
+
+package serve
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/clock"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+var testNow = time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func testSnapshot(synced time.Time, work ...api.CourseWork) *offline.Snapshot {
+	return &offline.Snapshot{
+		SyncedAt: synced,
+		Courses: []offline.Course{{
+			Course:     api.Course{ID: "c1", Name: "Chemistry"},
+			Coursework: work,
+			Submissions: []api.StudentSubmission{
+				{ID: "s1", CourseWorkID: "w1", State: api.SubmissionReturned, AssignedGrade: 8},
+			},
+		}},
+	}
+}
+
+func dueWork(id, title string, due time.Time) api.CourseWork {
+	return api.CourseWork{
+		ID:        id,
+		CourseID:  "c1",
+		Title:     title,
+		State:     api.CourseWorkPublished,
+		MaxPoints: 10,
+		DueDate:   &api.Date{Year: due.Year(), Month: int(due.Month()), Day: due.Day()},
+		DueTime:   &api.TimeOfDay{Hours: due.Hour()},
+	}
+}
+
+// startGRPC serves the gRPC API of a fresh store over unencrypted HTTP/2,
+// as ListenAndServeGRPC does, and returns the store and a client for it.
+func startGRPC(t *testing.T, keys ...string) (*store.Store, *httptest.Server, *http.Client) {
+	t.Helper()
+	st := store.NewWithBackend(store.NewMemoryBackend())
+	var parsed []Key
+	for _, k := range keys {
+		key, err := ParseKey(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed = append(parsed, key)
+	}
+	server := NewServer(st, parsed, gradebook.NewScale(nil), clock.Fixed(testNow), courseorder.New(nil))
+	server.pollEvery = 10 * time.Millisecond
+
+	ts := httptest.NewUnstartedServer(server.GRPCHandler())
+	ts.Config.Protocols = new(http.Protocols)
+	ts.Config.Protocols.SetUnencryptedHTTP2(true)
+	ts.Start()
+	t.Cleanup(ts.Close)
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	return st, ts, client
+}
+
+func call(ctx context.Context, t *testing.T, ts *httptest.Server, client *http.Client, method, key string, req []byte) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	if err := writeGRPCMessage(&body, req); err != nil {
+		t.Fatal(err)
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, ts.URL+grpcService+method, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", "application/grpc")
+	r.Header.Set("TE", "trailers")
+	if key != "" {
+		r.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := client.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ProtoMajor != 2 {
+		t.Fatalf("got HTTP/%d, want HTTP/2", resp.ProtoMajor)
+	}
+	return resp
+}
+
+func readMessage(t *testing.T, r io.Reader) []wireField {
+	t.Helper()
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		t.Fatalf("reading message: %v", err)
+	}
+	msg := make([]byte, binary.BigEndian.Uint32(prefix[1:]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		t.Fatalf("reading message: %v", err)
+	}
+	fields, err := decodeMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func field(t *testing.T, fields []wireField, n int) wireField {
+	t.Helper()
+	for _, f := range fields {
+		if f.Field == n {
+			return f
+		}
+	}
+	t.Fatalf("field %d missing", n)
+	return wireField{}
+}
+
+func TestGRPCListUpcoming(t *testing.T) {
+	st, ts, client := startGRPC(t, "secret")
+	snap := testSnapshot(testNow.Add(-time.Hour),
+		dueWork("w1", "Lab report", testNow.Add(48*time.Hour)),
+		dueWork("w2", "Past quiz", testNow.Add(-48*time.Hour)),
+	)
+	if err := offline.Save(st, snap); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := call(context.Background(), t, ts, client, "ListUpcoming", "secret", nil)
+	defer resp.Body.Close()
+	fields := readMessage(t, resp.Body)
+	var titles []string
+	for _, f := range fields {
+		if f.Field == 2 {
+			work, err := decodeMessage(f.Bytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			titles = append(titles, string(field(t, work, 3).Bytes))
+		}
+	}
+	if len(titles) != 1 || titles[0] != "Lab report" {
+		t.Errorf("upcoming = %q, want only the lab report", titles)
+	}
+	io.Copy(io.Discard, resp.Body)
+	if got := resp.Trailer.Get("Grpc-Status"); got != "0" {
+		t.Errorf("grpc-status = %q, want 0", got)
+	}
+}
+
+func TestGRPCRejectsBadKeyAndScope(t *testing.T) {
+	_, ts, client := startGRPC(t, "secret=courses")
+	for _, tt := range []struct {
+		method, key, status string
+	}{
+		{"ListCourses", "wrong", "16"},
+		{"ListGrades", "secret", "7"},
+		{"Nope", "secret", "12"},
+	} {
+		resp := call(context.Background(), t, ts, client, tt.method, tt.key, nil)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		status := resp.Trailer.Get("Grpc-Status")
+		if status == "" {
+			status = resp.Header.Get("Grpc-Status")
+		}
+		if status != tt.status {
+			t.Errorf("%s with key %q: grpc-status = %q, want %s", tt.method, tt.key, status, tt.status)
+		}
+	}
+}
+
+func TestGRPCWatchStreamsChanges(t *testing.T) {
+	st, ts, client := startGRPC(t, "secret")
+	lab := dueWork("w1", "Lab report", testNow.Add(48*time.Hour))
+	if err := offline.Save(st, testSnapshot(testNow.Add(-time.Hour), lab)); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp := call(ctx, t, ts, client, "Watch", "secret", nil)
+	defer resp.Body.Close()
+	body := bufio.NewReader(resp.Body)
+
+	// The current state: the course, its work and my submission.
+	for _, want := range []int{3, 4, 5} {
+		change := readMessage(t, body)
+		if kind := field(t, change, 1).Number; kind != changeAdded {
+			t.Fatalf("initial change kind = %d, want ADDED", kind)
+		}
+		field(t, change, want)
+	}
+
+	// A later sync moves the lab's due date and adds a quiz.
+	lab.DueDate.Day++
+	quiz := dueWork("w2", "Quiz", testNow.Add(24*time.Hour))
+	if err := offline.Save(st, testSnapshot(testNow, lab, quiz)); err != nil {
+		t.Fatal(err)
+	}
+	wantKinds := map[string]uint64{"Lab report": changeEdited, "Quiz": changeAdded}
+	for range wantKinds {
+		change := readMessage(t, body)
+		work, err := decodeMessage(field(t, change, 4).Bytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		title := string(field(t, work, 3).Bytes)
+		if kind := field(t, change, 1).Number; kind != wantKinds[title] {
+			t.Errorf("%s: kind = %d, want %d", title, kind, wantKinds[title])
+		}
+	}
+}
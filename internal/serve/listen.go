@@ -0,0 +1,14 @@
+//go:build go1.24
+
+package serve
+
+import "net/http"
+
+// ListenAndServeGRPC serves h on addr over unencrypted HTTP/2, which is what
+// gRPC clients speak to a plaintext address.
+func ListenAndServeGRPC(addr string, h http.Handler) error {
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	server := &http.Server{Addr: addr, Handler: h, Protocols: &protocols}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,14 @@
+//go:build !go1.24
+
+package serve
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ListenAndServeGRPC needs unencrypted HTTP/2 from net/http, which came in
+// Go 1.24.
+func ListenAndServeGRPC(addr string, h http.Handler) error {
+	return errors.New("serving gRPC needs gc-cli built with Go 1.24 or later")
+}
@@ -0,0 +1,306 @@
+// Package serve exposes synced Classroom data as a small read-only JSON or
+// gRPC API, so home dashboards and apps can show it without their own Google
+// sign-in.
+package serve
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/clock"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// Scopes an API key can be limited to, one per endpoint.
+const (
+	ScopeCourses  = "courses"
+	ScopeUpcoming = "upcoming"
+	ScopeGrades   = "grades"
+)
+
+var allScopes = []string{ScopeCourses, ScopeUpcoming, ScopeGrades}
+
+// Key is an API key and the endpoints it may read.
+type Key struct {
+	Secret string
+	Scopes map[string]bool
+}
+
+// ParseKey reads "SECRET" (every endpoint) or "SECRET=scope,scope".
+func ParseKey(s string) (Key, error) {
+	secret, scopes, limited := strings.Cut(s, "=")
+	if secret == "" {
+		return Key{}, fmt.Errorf("empty API key")
+	}
+	key := Key{Secret: secret, Scopes: make(map[string]bool)}
+	if !limited {
+		for _, scope := range allScopes {
+			key.Scopes[scope] = true
+		}
+		return key, nil
+	}
+	for _, scope := range strings.Split(scopes, ",") {
+		scope = strings.TrimSpace(scope)
+		if !isScope(scope) {
+			return Key{}, fmt.Errorf("unknown API key scope %q (want %s)", scope, strings.Join(allScopes, ", "))
+		}
+		key.Scopes[scope] = true
+	}
+	return key, nil
+}
+
+func isScope(s string) bool {
+	for _, scope := range allScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Server answers from the offline snapshot, reloading it on every request
+// so a scheduled 'gc-cli sync' is picked up without a restart.
+type Server struct {
+	store *store.Store
+	keys  []Key
+	scale gradebook.Scale
+	clock clock.Clock
+	order *courseorder.Order
+
+	// pollEvery is how often a gRPC Watch checks for a new sync.
+	pollEvery time.Duration
+}
+
+func NewServer(st *store.Store, keys []Key, scale gradebook.Scale, clk clock.Clock, order *courseorder.Order) *Server {
+	return &Server{store: st, keys: keys, scale: scale, clock: clk, order: order, pollEvery: 5 * time.Second}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/courses", s.endpoint(ScopeCourses, func(snap *offline.Snapshot) any { return s.courses(snap) }))
+	mux.HandleFunc("/upcoming", s.endpoint(ScopeUpcoming, func(snap *offline.Snapshot) any { return s.upcoming(snap) }))
+	mux.HandleFunc("/grades", s.endpoint(ScopeGrades, func(snap *offline.Snapshot) any { return s.grades(snap) }))
+	return mux
+}
+
+type response struct {
+	SyncedAt time.Time `json:"syncedAt"`
+	Data     any       `json:"data"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// endpoint checks the method and API key, loads the snapshot and writes
+// build's result as JSON.
+func (s *Server) endpoint(scope string, build func(*offline.Snapshot) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"read-only API: use GET"})
+			return
+		}
+		key, ok := s.authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gc-cli"`)
+			writeJSON(w, http.StatusUnauthorized, errorResponse{"missing or invalid API key"})
+			return
+		}
+		if !key.Scopes[scope] {
+			writeJSON(w, http.StatusForbidden, errorResponse{fmt.Sprintf("API key is not allowed to read %s", scope)})
+			return
+		}
+
+		snap, err := offline.Load(s.store)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{err.Error()})
+			return
+		}
+		if snap.SyncedAt.IsZero() {
+			writeJSON(w, http.StatusServiceUnavailable, errorResponse{"nothing synced yet; run 'gc-cli sync'"})
+			return
+		}
+		writeJSON(w, http.StatusOK, response{SyncedAt: snap.SyncedAt, Data: build(snap)})
+	}
+}
+
+// authenticate accepts the key as a bearer token, an X-API-Key header or an
+// api_key query parameter, for dashboards that can't set headers.
+func (s *Server) authenticate(r *http.Request) (Key, bool) {
+	given := r.Header.Get("X-API-Key")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		given = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if given == "" {
+		given = r.URL.Query().Get("api_key")
+	}
+	if given == "" {
+		return Key{}, false
+	}
+	for _, key := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(given), []byte(key.Secret)) == 1 {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+type courseJSON struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Label   string `json:"label"`
+	Section string `json:"section,omitempty"`
+	Link    string `json:"link,omitempty"`
+}
+
+func (s *Server) courses(snap *offline.Snapshot) []courseJSON {
+	courses := make([]courseJSON, 0, len(snap.Courses))
+	for _, c := range s.sorted(snap) {
+		courses = append(courses, courseJSON{
+			ID:      c.Course.ID,
+			Name:    c.Course.Name,
+			Label:   s.order.Label(c.Course.ID, c.Course.Name),
+			Section: c.Course.Section,
+			Link:    c.Course.AlternateLink,
+		})
+	}
+	return courses
+}
+
+type upcomingJSON struct {
+	CourseID     string    `json:"courseId"`
+	Course       string    `json:"course"`
+	CourseWorkID string    `json:"courseWorkId"`
+	Title        string    `json:"title"`
+	Due          time.Time `json:"due"`
+	Link         string    `json:"link,omitempty"`
+	// OpensAt is set for scheduled work students can't see yet.
+	OpensAt *time.Time `json:"opensAt,omitempty"`
+	// Window is a short note such as "opens in 2h" or "closes in 40m".
+	Window string `json:"window,omitempty"`
+}
+
+func (s *Server) upcoming(snap *offline.Snapshot) []upcomingJSON {
+	now := s.clock.Now()
+	labels := make(map[string]string, len(snap.Courses))
+	for _, c := range snap.Courses {
+		labels[c.Course.ID] = s.order.Label(c.Course.ID, c.Course.Name)
+	}
+	upcoming := []upcomingJSON{}
+	for _, cw := range s.upcomingWork(snap, now) {
+		item := upcomingJSON{
+			CourseID:     cw.CourseID,
+			Course:       labels[cw.CourseID],
+			CourseWorkID: cw.ID,
+			Title:        cw.Title,
+			Due:          agenda.DueTime(cw),
+			Link:         cw.AlternateLink,
+			Window:       agenda.Window(cw, now),
+		}
+		if opens := agenda.OpensAt(cw, now); !opens.IsZero() {
+			item.OpensAt = &opens
+		}
+		upcoming = append(upcoming, item)
+	}
+	return upcoming
+}
+
+// upcomingWork is the work still to do that's due after now, soonest first.
+// It includes scheduled work, which students can't see yet.
+func (s *Server) upcomingWork(snap *offline.Snapshot, now time.Time) []api.CourseWork {
+	var upcoming []api.CourseWork
+	for _, c := range s.sorted(snap) {
+		done := make(map[string]bool, len(c.Done))
+		for _, id := range c.Done {
+			done[id] = true
+		}
+		for _, cw := range c.Coursework {
+			opens := agenda.OpensAt(cw, now)
+			visible := cw.State == api.CourseWorkPublished || !opens.IsZero()
+			if !visible || cw.DueDate == nil || done[cw.ID] {
+				continue
+			}
+			if agenda.DueTime(cw).Before(now) {
+				continue
+			}
+			if cw.CourseID == "" {
+				cw.CourseID = c.Course.ID
+			}
+			upcoming = append(upcoming, cw)
+		}
+	}
+	// Stable, so work due at the same time stays in period order.
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return agenda.DueTime(upcoming[i]).Before(agenda.DueTime(upcoming[j]))
+	})
+	return upcoming
+}
+
+type gradeJSON struct {
+	CourseID string   `json:"courseId"`
+	Course   string   `json:"course"`
+	Graded   int      `json:"graded"`
+	Earned   float64  `json:"earned"`
+	Possible float64  `json:"possible"`
+	Percent  *float64 `json:"percent"`
+	Letter   string   `json:"letter,omitempty"`
+	Missing  int      `json:"missing"`
+}
+
+func (s *Server) grades(snap *offline.Snapshot) []gradeJSON {
+	grades := make([]gradeJSON, 0, len(snap.Courses))
+	for _, c := range s.sorted(snap) {
+		summary := gradebook.Summarize(c.Coursework, c.Submissions)
+		g := gradeJSON{
+			CourseID: c.Course.ID,
+			Course:   s.order.Label(c.Course.ID, c.Course.Name),
+			Graded:   summary.Graded,
+			Earned:   summary.Earned,
+			Possible: summary.Possible,
+			Missing:  summary.Missing,
+		}
+		if pct, ok := summary.Percent(); ok {
+			g.Percent = &pct
+			g.Letter = s.scale.Letter(pct)
+		}
+		grades = append(grades, g)
+	}
+	return grades
+}
+
+// sorted returns the snapshot's courses in the configured order.
+func (s *Server) sorted(snap *offline.Snapshot) []offline.Course {
+	byID := make(map[string]offline.Course, len(snap.Courses))
+	courses := make([]api.Course, 0, len(snap.Courses))
+	for _, c := range snap.Courses {
+		byID[c.Course.ID] = c
+		courses = append(courses, c.Course)
+	}
+	s.order.SortCourses(courses)
+
+	result := make([]offline.Course, len(courses))
+	for i, c := range courses {
+		result[i] = byID[c.ID]
+	}
+	return result
+}
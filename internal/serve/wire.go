@@ -0,0 +1,151 @@
+package serve
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Just enough of the protobuf wire format for serve.proto's messages, so the
+// gRPC API needs no generated code or protobuf runtime. Appending a field
+// with its zero value writes nothing, as proto3 does.
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func appendTag(b []byte, field, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = appendTag(b, field, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendMessage writes msg even when it's empty, since an empty message
+// still says the field is set.
+func appendMessage(b []byte, field int, msg []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(msg)))
+	return append(b, msg...)
+}
+
+func appendInt(b []byte, field int, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, field, wireVarint)
+	return binary.AppendUvarint(b, uint64(v))
+}
+
+func appendBool(b []byte, field int, v bool) []byte {
+	if !v {
+		return b
+	}
+	b = appendTag(b, field, wireVarint)
+	return append(b, 1)
+}
+
+func appendDouble(b []byte, field int, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	return appendOptionalDouble(b, field, v)
+}
+
+// appendOptionalDouble writes an optional field, which is sent even when
+// it's zero.
+func appendOptionalDouble(b []byte, field int, v float64) []byte {
+	b = appendTag(b, field, wireFixed64)
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+}
+
+// appendTimestamp writes a google.protobuf.Timestamp, or nothing for the
+// zero time.
+func appendTimestamp(b []byte, field int, t time.Time) []byte {
+	if t.IsZero() {
+		return b
+	}
+	var ts []byte
+	ts = appendInt(ts, 1, t.Unix())
+	ts = appendInt(ts, 2, int64(t.Nanosecond()))
+	return appendMessage(b, field, ts)
+}
+
+// wireField is one decoded field. Varint and fixed-width values are in
+// Number; length-delimited ones in Bytes.
+type wireField struct {
+	Field  int
+	Wire   int
+	Number uint64
+	Bytes  []byte
+}
+
+// decodeMessage splits msg into its fields, in order.
+func decodeMessage(msg []byte) ([]wireField, error) {
+	var fields []wireField
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return nil, fmt.Errorf("malformed field tag")
+		}
+		msg = msg[n:]
+		f := wireField{Field: int(tag >> 3), Wire: int(tag & 7)}
+		switch f.Wire {
+		case wireVarint:
+			f.Number, n = binary.Uvarint(msg)
+			if n <= 0 {
+				return nil, fmt.Errorf("malformed varint in field %d", f.Field)
+			}
+			msg = msg[n:]
+		case wireFixed64:
+			if len(msg) < 8 {
+				return nil, fmt.Errorf("truncated field %d", f.Field)
+			}
+			f.Number = binary.LittleEndian.Uint64(msg)
+			msg = msg[8:]
+		case wireFixed32:
+			if len(msg) < 4 {
+				return nil, fmt.Errorf("truncated field %d", f.Field)
+			}
+			f.Number = uint64(binary.LittleEndian.Uint32(msg))
+			msg = msg[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < size {
+				return nil, fmt.Errorf("truncated field %d", f.Field)
+			}
+			f.Bytes = msg[n : n+int(size)]
+			msg = msg[n+int(size):]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d in field %d", f.Wire, f.Field)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// stringFields decodes msg and returns its string fields by number. Requests
+// in serve.proto only have strings, so anything else is ignored.
+func stringFields(msg []byte) (map[int][]string, error) {
+	fields, err := decodeMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	strs := make(map[int][]string)
+	for _, f := range fields {
+		if f.Wire == wireBytes {
+			strs[f.Field] = append(strs[f.Field], string(f.Bytes))
+		}
+	}
+	return strs, nil
+}
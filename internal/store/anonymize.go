@@ -0,0 +1,33 @@
+package store
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+const anonymizeName = "anonymize"
+
+type anonymizeState struct {
+	Salt []byte `json:"salt"`
+}
+
+// AnonymizeSalt returns the per-install salt used for --anonymize, creating it
+// on first use so fake names stay the same between runs.
+func (s *Store) AnonymizeSalt() ([]byte, error) {
+	state := &anonymizeState{}
+	if err := s.Load(anonymizeName, state); err != nil {
+		return nil, err
+	}
+	if len(state.Salt) > 0 {
+		return state.Salt, nil
+	}
+
+	state.Salt = make([]byte, 32)
+	if _, err := rand.Read(state.Salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if err := s.Save(anonymizeName, state); err != nil {
+		return nil, err
+	}
+	return state.Salt, nil
+}
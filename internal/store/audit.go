@@ -0,0 +1,60 @@
+package store
+
+import "time"
+
+const auditLogName = "audit"
+
+const (
+	AuditAttach = "attach"
+	AuditTurnIn = "turn_in"
+)
+
+// AuditEntry records a change gc-cli made to one of my submissions, with
+// enough detail to show later what was sent and when.
+type AuditEntry struct {
+	Time         time.Time `json:"time"`
+	Action       string    `json:"action"`
+	CourseID     string    `json:"courseId"`
+	CourseWorkID string    `json:"courseWorkId"`
+	SubmissionID string    `json:"submissionId,omitempty"`
+	File         string    `json:"file,omitempty"`
+	Size         int64     `json:"size,omitempty"`
+	SHA256       string    `json:"sha256,omitempty"`
+}
+
+type AuditLog struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+func (s *Store) AuditLog() (*AuditLog, error) {
+	log := &AuditLog{}
+	if err := s.Load(auditLogName, log); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+func (s *Store) SaveAuditLog(log *AuditLog) error {
+	return s.Save(auditLogName, log)
+}
+
+// Audit loads the log, appends e and saves it.
+func (s *Store) Audit(e AuditEntry) error {
+	log, err := s.AuditLog()
+	if err != nil {
+		return err
+	}
+	log.Entries = append(log.Entries, e)
+	return s.SaveAuditLog(log)
+}
+
+// For returns the entries for one assignment, oldest first.
+func (l *AuditLog) For(courseID, courseWorkID string) []AuditEntry {
+	var entries []AuditEntry
+	for _, e := range l.Entries {
+		if e.CourseID == courseID && e.CourseWorkID == courseWorkID {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Backend holds raw documents by name. Read returns nil data and no error for
+// a document that doesn't exist yet.
+type Backend interface {
+	Read(name string) ([]byte, error)
+	Write(name string, data []byte) error
+}
+
+const (
+	BackendJSON   = "json"
+	BackendMemory = "memory"
+	BackendSQLite = "sqlite"
+)
+
+// Open returns a store using the named backend. An empty name selects the
+// default JSON files in dir; SQLite keeps them in dir/gc-cli.db instead.
+func Open(backend, dir string) (*Store, error) {
+	switch backend {
+	case "", BackendJSON:
+		return New(dir), nil
+	case BackendMemory:
+		return &Store{backend: NewMemoryBackend(), dir: dir}, nil
+	case BackendSQLite:
+		b, err := openSQLite(filepath.Join(dir, "gc-cli.db"))
+		if err != nil {
+			return nil, err
+		}
+		return &Store{backend: b, dir: dir}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (want %q, %q or %q)", backend, BackendJSON, BackendMemory, BackendSQLite)
+	}
+}
+
+// FileBackend keeps each document as <name>.json in a directory. It needs
+// nothing beyond a writable filesystem.
+type FileBackend struct {
+	dir string
+}
+
+func NewFileBackend(dir string) *FileBackend {
+	return &FileBackend{dir: dir}
+}
+
+func (b *FileBackend) path(name string) string {
+	return filepath.Join(b.dir, name+".json")
+}
+
+func (b *FileBackend) Read(name string) ([]byte, error) {
+	data, err := os.ReadFile(b.path(name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Write replaces the document atomically: the data goes to a temporary file
+// that is synced and then renamed over the old one, so a crash or power
+// loss leaves either the old document or the new one, never half of each.
+func (b *FileBackend) Write(name string, data []byte) error {
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(b.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), b.path(name)); err != nil {
+		return err
+	}
+	// The rename itself only survives a crash once the directory is synced.
+	// Not every platform can sync a directory, so failing to is ignored.
+	if dir, err := os.Open(b.dir); err == nil {
+		_ = dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// MemoryBackend keeps documents in memory only. Nothing survives the
+// process, which suits tests and throwaway sessions.
+type MemoryBackend struct {
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{docs: make(map[string][]byte)}
+}
+
+func (b *MemoryBackend) Read(name string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.docs[name]
+	if !ok {
+		return nil, nil
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (b *MemoryBackend) Write(name string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.docs[name] = append([]byte(nil), data...)
+	return nil
+}
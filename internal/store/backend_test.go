@@ -0,0 +1,142 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Every backend runs the same suite, so one can stand in for another.
+var backends = map[string]func(t *testing.T) Backend{
+	BackendJSON:   func(t *testing.T) Backend { return NewFileBackend(filepath.Join(t.TempDir(), "data")) },
+	BackendMemory: func(*testing.T) Backend { return NewMemoryBackend() },
+}
+
+func init() {
+	if sqliteBuilt {
+		backends[BackendSQLite] = func(t *testing.T) Backend {
+			b, err := openSQLite(filepath.Join(t.TempDir(), "gc-cli.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return b
+		}
+	}
+}
+
+func TestBackends(t *testing.T) {
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			t.Run("missing", func(t *testing.T) {
+				data, err := open(t).Read("notes")
+				if data != nil || err != nil {
+					t.Errorf("Read = %q, %v; want nil, nil for a missing document", data, err)
+				}
+			})
+
+			t.Run("round trip", func(t *testing.T) {
+				b := open(t)
+				if err := b.Write("notes", []byte(`{"a":1}`)); err != nil {
+					t.Fatal(err)
+				}
+				if err := b.Write("notes", []byte(`{"a":2}`)); err != nil {
+					t.Fatal(err)
+				}
+				if err := b.Write("read", []byte(`[]`)); err != nil {
+					t.Fatal(err)
+				}
+				if data, err := b.Read("notes"); err != nil || string(data) != `{"a":2}` {
+					t.Errorf("Read(notes) = %q, %v; want the last write", data, err)
+				}
+				if data, err := b.Read("read"); err != nil || string(data) != `[]` {
+					t.Errorf("Read(read) = %q, %v; want its own document", data, err)
+				}
+			})
+
+			t.Run("copies", func(t *testing.T) {
+				b := open(t)
+				data := []byte(`{"a":1}`)
+				if err := b.Write("notes", data); err != nil {
+					t.Fatal(err)
+				}
+				data[1] = 'X'
+				got, _ := b.Read("notes")
+				got[1] = 'Y'
+				if again, _ := b.Read("notes"); !bytes.Equal(again, []byte(`{"a":1}`)) {
+					t.Errorf("Read = %q after changing the caller's slices, want them not shared", again)
+				}
+			})
+
+			t.Run("store", func(t *testing.T) {
+				s := NewWithBackend(open(t))
+				if err := s.Save("notes", map[string]string{"w1": "bring goggles"}); err != nil {
+					t.Fatal(err)
+				}
+				var notes map[string]string
+				if err := s.Load("notes", &notes); err != nil || notes["w1"] != "bring goggles" {
+					t.Errorf("Load = %v, %v; want what was saved", notes, err)
+				}
+			})
+
+			t.Run("corrupt", func(t *testing.T) {
+				b := open(t)
+				if err := b.Write("notes", []byte("{not json")); err != nil {
+					t.Fatal(err)
+				}
+				var notes map[string]string
+				var corrupt *CorruptError
+				if err := NewWithBackend(b).Load("notes", &notes); !errors.As(err, &corrupt) || corrupt.Name != "notes" {
+					t.Errorf("Load = %v, want a CorruptError for notes", err)
+				}
+			})
+		})
+	}
+}
+
+func TestFileBackendLeavesNoTemporaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	b := NewFileBackend(dir)
+	for i := 0; i < 3; i++ {
+		if err := b.Write("notes", []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if got := strings.Join(names, " "); got != "notes.json" {
+		t.Errorf("data directory holds %s, want only notes.json", got)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"", BackendJSON, BackendMemory} {
+		s, err := Open(name, dir)
+		if err != nil {
+			t.Errorf("Open(%q) = %v", name, err)
+			continue
+		}
+		if s.Dir() != dir {
+			t.Errorf("Open(%q).Dir() = %q, want %q", name, s.Dir(), dir)
+		}
+	}
+	_, err := Open(BackendSQLite, dir)
+	if sqliteBuilt && err != nil {
+		t.Errorf("Open(sqlite) = %v", err)
+	}
+	if !sqliteBuilt && (err == nil || !strings.Contains(err.Error(), "-tags sqlite")) {
+		t.Errorf("Open(sqlite) = %v, want it to say how to build it in", err)
+	}
+	if _, err := Open("postgres", dir); err == nil || !strings.Contains(err.Error(), `unknown storage backend "postgres"`) {
+		t.Errorf("Open(postgres) = %v, want it refused as unknown", err)
+	}
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// checked wraps a document with the SHA-256 of its compact JSON, so damage
+// is noticed instead of half a document being read.
+type checked struct {
+	SHA256 string          `json:"sha256"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// SaveChecked saves v like Save, with a checksum LoadChecked verifies.
+func (s *Store) SaveChecked(name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+	sum := sha256.Sum256(data)
+	return s.Save(name, checked{SHA256: hex.EncodeToString(sum[:]), Data: data})
+}
+
+// LoadChecked loads a document written by SaveChecked into v, returning a
+// *CorruptError if it doesn't match its checksum. Documents saved without
+// one, by older versions, are read as they are.
+func (s *Store) LoadChecked(name string, v any) error {
+	var doc checked
+	if err := s.Load(name, &doc); err != nil {
+		return err
+	}
+	if doc.SHA256 == "" {
+		return s.Load(name, v)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, doc.Data); err != nil {
+		return &CorruptError{Name: name, Err: err}
+	}
+	sum := sha256.Sum256(compact.Bytes())
+	if hex.EncodeToString(sum[:]) != doc.SHA256 {
+		return &CorruptError{Name: name, Err: errors.New("checksum mismatch")}
+	}
+	if err := json.Unmarshal(compact.Bytes(), v); err != nil {
+		return &CorruptError{Name: name, Err: err}
+	}
+	return nil
+}
+
+// setAside keeps a copy of a damaged document as <name>.corrupt for a look
+// later, so it can be replaced. It's best effort.
+func (s *Store) setAside(name string) string {
+	data, err := s.backend.Read(name)
+	if err != nil || data == nil {
+		return ""
+	}
+	if err := s.backend.Write(name+".corrupt", data); err != nil {
+		return ""
+	}
+	return name + ".corrupt"
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"strings"
+	"time"
+)
+
+const enrollmentName = "enrollment"
+
+// CourseGone is the state of a course that no longer shows up at all, e.g.
+// after being removed from it or it being deleted.
+const CourseGone = "GONE"
+
+// Enrollment event kinds. Other state changes are named after the state,
+// e.g. "suspended".
+const (
+	EnrollJoined   = "joined"
+	EnrollArchived = "archived"
+	EnrollRestored = "restored"
+	EnrollLeft     = "left"
+)
+
+// EnrolledCourse is what's remembered of a course I've been in, kept after
+// it's archived or gone so its ID can still be looked up.
+type EnrolledCourse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Section   string    `json:"section,omitempty"`
+	Created   time.Time `json:"created,omitempty"`
+	State     string    `json:"state"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+type EnrollmentEvent struct {
+	At       time.Time `json:"at"`
+	CourseID string    `json:"course_id"`
+	Kind     string    `json:"kind"`
+}
+
+// Enrollment is the timeline of courses appearing, being archived and
+// disappearing, built from course listings as they happen.
+type Enrollment struct {
+	Courses map[string]*EnrolledCourse `json:"courses"`
+	Events  []EnrollmentEvent          `json:"events"`
+}
+
+func (s *Store) Enrollment() (*Enrollment, error) {
+	e := &Enrollment{}
+	if err := s.Load(enrollmentName, e); err != nil {
+		return nil, err
+	}
+	if e.Courses == nil {
+		e.Courses = make(map[string]*EnrolledCourse)
+	}
+	return e, nil
+}
+
+func (s *Store) SaveEnrollment(e *Enrollment) error {
+	return s.Save(enrollmentName, e)
+}
+
+// Observe records a course listing. Only a complete listing, of courses in
+// every state, can show that a course is gone; a partial one just updates
+// the courses in it.
+func (e *Enrollment) Observe(courses []EnrolledCourse, complete bool, now time.Time) {
+	seen := make(map[string]bool, len(courses))
+	for _, c := range courses {
+		seen[c.ID] = true
+		prev := e.Courses[c.ID]
+		if prev == nil {
+			course := c
+			course.FirstSeen, course.LastSeen = now, now
+			e.Courses[c.ID] = &course
+			e.add(now, c.ID, EnrollJoined)
+			continue
+		}
+		if prev.State != c.State {
+			e.add(now, c.ID, stateChange(prev.State, c.State))
+		}
+		prev.Name, prev.Section, prev.State, prev.LastSeen = c.Name, c.Section, c.State, now
+		if !c.Created.IsZero() {
+			prev.Created = c.Created
+		}
+	}
+	if !complete {
+		return
+	}
+	for id, c := range e.Courses {
+		if !seen[id] && c.State != CourseGone {
+			c.State = CourseGone
+			e.add(now, id, EnrollLeft)
+		}
+	}
+}
+
+func (e *Enrollment) add(at time.Time, courseID, kind string) {
+	e.Events = append(e.Events, EnrollmentEvent{At: at, CourseID: courseID, Kind: kind})
+}
+
+func stateChange(from, to string) string {
+	switch {
+	case to == "ARCHIVED":
+		return EnrollArchived
+	case to == "ACTIVE" && (from == "ARCHIVED" || from == CourseGone):
+		return EnrollRestored
+	}
+	return strings.ToLower(to)
+}
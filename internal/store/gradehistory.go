@@ -0,0 +1,136 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+const gradeHistoryName = "grade_history"
+
+// GradeObservation is a grade as it was when gc-cli saw it.
+type GradeObservation struct {
+	At        time.Time `json:"at"`
+	Grade     float64   `json:"grade"`
+	MaxPoints float64   `json:"max_points"`
+}
+
+// GradedWork is the grades seen for one piece of coursework, oldest first.
+type GradedWork struct {
+	CourseID     string             `json:"course_id"`
+	CourseWorkID string             `json:"coursework_id"`
+	Title        string             `json:"title"`
+	Grades       []GradeObservation `json:"grades"`
+}
+
+// Latest is the grade seen most recently.
+func (w *GradedWork) Latest() GradeObservation {
+	return w.Grades[len(w.Grades)-1]
+}
+
+// GradeHistory remembers every grade value seen, since Classroom only shows
+// the current one. Work is keyed by course and coursework ID.
+type GradeHistory struct {
+	Work map[string]*GradedWork `json:"work"`
+}
+
+func (s *Store) GradeHistory() (*GradeHistory, error) {
+	h := &GradeHistory{}
+	if err := s.Load(gradeHistoryName, h); err != nil {
+		return nil, err
+	}
+	if h.Work == nil {
+		h.Work = make(map[string]*GradedWork)
+	}
+	return h, nil
+}
+
+func (s *Store) SaveGradeHistory(h *GradeHistory) error {
+	return s.Save(gradeHistoryName, h)
+}
+
+// Observe records a grade seen at now. Seeing the same grade again adds
+// nothing, so the history only grows when a grade is given or changed. It
+// reports whether anything was added.
+func (h *GradeHistory) Observe(courseID, courseWorkID, title string, grade, maxPoints float64, now time.Time) bool {
+	key := courseID + "/" + courseWorkID
+	w := h.Work[key]
+	if w == nil {
+		w = &GradedWork{CourseID: courseID, CourseWorkID: courseWorkID}
+		h.Work[key] = w
+	}
+	w.Title = title
+	if len(w.Grades) > 0 {
+		if last := w.Latest(); last.Grade == grade && last.MaxPoints == maxPoints {
+			return false
+		}
+	}
+	w.Grades = append(w.Grades, GradeObservation{At: now, Grade: grade, MaxPoints: maxPoints})
+	return true
+}
+
+// Course lists the graded work of a course in the order it was first graded.
+func (h *GradeHistory) Course(courseID string) []*GradedWork {
+	var work []*GradedWork
+	for _, w := range h.Work {
+		if w.CourseID == courseID && len(w.Grades) > 0 {
+			work = append(work, w)
+		}
+	}
+	sort.Slice(work, func(i, j int) bool {
+		if a, b := work[i].Grades[0].At, work[j].Grades[0].At; !a.Equal(b) {
+			return a.Before(b)
+		}
+		return work[i].CourseWorkID < work[j].CourseWorkID
+	})
+	return work
+}
+
+// AveragePoint is a course's average at a time, over the latest grade then
+// of each piece of work graded by then.
+type AveragePoint struct {
+	At       time.Time
+	Earned   float64
+	Possible float64
+}
+
+func (p AveragePoint) Percent() float64 {
+	if p.Possible == 0 {
+		return 0
+	}
+	return p.Earned / p.Possible * 100
+}
+
+// Averages replays a course's grade history into its average after each
+// change, oldest first. Changes seen at the same time make one point.
+func (h *GradeHistory) Averages(courseID string) []AveragePoint {
+	type change struct {
+		work string
+		obs  GradeObservation
+	}
+	var changes []change
+	for _, w := range h.Course(courseID) {
+		for _, obs := range w.Grades {
+			changes = append(changes, change{work: w.CourseWorkID, obs: obs})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].obs.At.Before(changes[j].obs.At)
+	})
+
+	latest := make(map[string]GradeObservation)
+	var points []AveragePoint
+	for _, c := range changes {
+		latest[c.work] = c.obs
+		p := AveragePoint{At: c.obs.At}
+		for _, obs := range latest {
+			p.Earned += obs.Grade
+			p.Possible += obs.MaxPoints
+		}
+		if n := len(points); n > 0 && points[n-1].At.Equal(p.At) {
+			points[n-1] = p
+			continue
+		}
+		points = append(points, p)
+	}
+	return points
+}
@@ -0,0 +1,75 @@
+package store
+
+import "time"
+
+const (
+	historyName = "history"
+	maxRecent   = 20
+)
+
+// Visit is a place in the TUI that can be jumped back to from the command
+// palette.
+type Visit struct {
+	Kind     string    `json:"kind"`
+	ID       string    `json:"id"`
+	Label    string    `json:"label"`
+	Parent   string    `json:"parent,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+func (v Visit) key() string {
+	return v.Kind + ":" + v.ID
+}
+
+type History struct {
+	Recent    []Visit `json:"recent"`
+	Favorites []Visit `json:"favorites"`
+}
+
+func (s *Store) History() (*History, error) {
+	h := &History{}
+	if err := s.Load(historyName, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (s *Store) SaveHistory(h *History) error {
+	return s.Save(historyName, h)
+}
+
+// Record moves v to the front of the recent list.
+func (h *History) Record(v Visit) {
+	recent := []Visit{v}
+	for _, r := range h.Recent {
+		if r.key() != v.key() {
+			recent = append(recent, r)
+		}
+	}
+	if len(recent) > maxRecent {
+		recent = recent[:maxRecent]
+	}
+	h.Recent = recent
+}
+
+func (h *History) IsFavorite(v Visit) bool {
+	for _, f := range h.Favorites {
+		if f.key() == v.key() {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleFavorite pins v, or unpins it if already pinned, and reports whether
+// it is now pinned.
+func (h *History) ToggleFavorite(v Visit) bool {
+	for i, f := range h.Favorites {
+		if f.key() == v.key() {
+			h.Favorites = append(h.Favorites[:i], h.Favorites[i+1:]...)
+			return false
+		}
+	}
+	h.Favorites = append(h.Favorites, v)
+	return true
+}
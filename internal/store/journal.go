@@ -0,0 +1,88 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const submitJournalName = "submit_journal"
+
+// SubmitOp records how far a submit got so an interrupted run can finish the
+// remaining steps without attaching the file twice or turning in twice.
+type SubmitOp struct {
+	ID           string `json:"id"`
+	CourseID     string `json:"courseId"`
+	CourseWorkID string `json:"courseWorkId"`
+	FilePath     string `json:"filePath"`
+	SubmissionID string `json:"submissionId,omitempty"`
+	DriveFileID  string `json:"driveFileId,omitempty"`
+	// UploadURL is the Drive upload session while the file is uploading.
+	UploadURL string `json:"uploadUrl,omitempty"`
+	Attached  bool   `json:"attached"`
+	TurnedIn  bool   `json:"turnedIn"`
+	// StageOnly ops stop once the file is attached, leaving the turn-in to
+	// 'submit finalize'.
+	StageOnly bool      `json:"stageOnly,omitempty"`
+	Started   time.Time `json:"started"`
+	Updated   time.Time `json:"updated"`
+}
+
+// Step describes the next thing the operation needs to do.
+func (op *SubmitOp) Step() string {
+	switch {
+	case op.DriveFileID == "" && !op.Attached:
+		return "upload"
+	case !op.Attached:
+		return "attach"
+	case !op.TurnedIn && !op.StageOnly:
+		return "turn in"
+	}
+	return "done"
+}
+
+type SubmitJournal struct {
+	Ops    map[string]*SubmitOp `json:"ops"`
+	NextID int                  `json:"nextId"`
+}
+
+func (s *Store) SubmitJournal() (*SubmitJournal, error) {
+	journal := &SubmitJournal{}
+	if err := s.Load(submitJournalName, journal); err != nil {
+		return nil, err
+	}
+	if journal.Ops == nil {
+		journal.Ops = make(map[string]*SubmitOp)
+	}
+	return journal, nil
+}
+
+func (s *Store) SaveSubmitJournal(journal *SubmitJournal) error {
+	return s.Save(submitJournalName, journal)
+}
+
+func (j *SubmitJournal) Begin(courseID, courseWorkID, filePath string, now time.Time) *SubmitOp {
+	j.NextID++
+	op := &SubmitOp{
+		ID:           fmt.Sprintf("op%d", j.NextID),
+		CourseID:     courseID,
+		CourseWorkID: courseWorkID,
+		FilePath:     filePath,
+		Started:      now,
+		Updated:      now,
+	}
+	j.Ops[op.ID] = op
+	return op
+}
+
+// Pending returns unfinished operations, oldest first.
+func (j *SubmitJournal) Pending() []*SubmitOp {
+	var ops []*SubmitOp
+	for _, op := range j.Ops {
+		if op.Step() != "done" {
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(a, b int) bool { return ops[a].Started.Before(ops[b].Started) })
+	return ops
+}
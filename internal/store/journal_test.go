@@ -0,0 +1,30 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubmitOpSteps(t *testing.T) {
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	j := &SubmitJournal{Ops: make(map[string]*SubmitOp)}
+	turnIn := j.Begin("c1", "w1", "essay.pdf", now)
+	stage := j.Begin("c1", "w2", "draft.pdf", now.Add(time.Minute))
+	stage.StageOnly = true
+
+	for _, op := range []*SubmitOp{turnIn, stage} {
+		if got := op.Step(); got != "upload" {
+			t.Errorf("%s: step = %q, want upload", op.ID, got)
+		}
+		op.DriveFileID, op.Attached = "f1", true
+	}
+	if got := turnIn.Step(); got != "turn in" {
+		t.Errorf("attached op: step = %q, want turn in", got)
+	}
+	if got := stage.Step(); got != "done" {
+		t.Errorf("attached stage-only op: step = %q, want done", got)
+	}
+	if pending := j.Pending(); len(pending) != 1 || pending[0] != turnIn {
+		t.Errorf("pending = %v, want only the op still to turn in", pending)
+	}
+}
@@ -0,0 +1,138 @@
+package store
+
+import (
+	"strings"
+	"time"
+)
+
+const notesName = "notes"
+
+// Subtask is a local checklist entry for breaking down a coursework item.
+type Subtask struct {
+	Text    string    `json:"text"`
+	Done    bool      `json:"done"`
+	Created time.Time `json:"created"`
+}
+
+// Note holds everything kept locally about a single coursework item.
+type Note struct {
+	Subtasks []Subtask `json:"subtasks,omitempty"`
+	// StepsOffered is set once importing the steps in the assignment's
+	// instructions has been offered, so it's only offered once.
+	StepsOffered bool `json:"steps_offered,omitempty"`
+}
+
+// Progress returns how many subtasks are done and the total.
+func (n *Note) Progress() (done, total int) {
+	if n == nil {
+		return 0, 0
+	}
+	for _, st := range n.Subtasks {
+		if st.Done {
+			done++
+		}
+	}
+	return done, len(n.Subtasks)
+}
+
+type Notes struct {
+	Items map[string]*Note `json:"items"`
+}
+
+func (s *Store) Notes() (*Notes, error) {
+	notes := &Notes{}
+	if err := s.Load(notesName, notes); err != nil {
+		return nil, err
+	}
+	if notes.Items == nil {
+		notes.Items = make(map[string]*Note)
+	}
+	return notes, nil
+}
+
+func (s *Store) SaveNotes(notes *Notes) error {
+	return s.Save(notesName, notes)
+}
+
+// Get returns the note for id, or nil if there is none.
+func (n *Notes) Get(id string) *Note {
+	return n.Items[id]
+}
+
+func (n *Notes) AddSubtask(id, text string, at time.Time) {
+	note := n.note(id)
+	note.Subtasks = append(note.Subtasks, Subtask{Text: text, Created: at})
+}
+
+// ImportSteps adds each step that isn't a subtask already and marks the
+// steps as offered. It returns how many were added.
+func (n *Notes) ImportSteps(id string, steps []string, at time.Time) int {
+	note := n.note(id)
+	note.StepsOffered = true
+	have := make(map[string]bool, len(note.Subtasks))
+	for _, st := range note.Subtasks {
+		have[strings.ToLower(st.Text)] = true
+	}
+	added := 0
+	for _, step := range steps {
+		if have[strings.ToLower(step)] {
+			continue
+		}
+		have[strings.ToLower(step)] = true
+		note.Subtasks = append(note.Subtasks, Subtask{Text: step, Created: at})
+		added++
+	}
+	return added
+}
+
+// DeclineSteps records that importing the steps was offered and turned down.
+func (n *Notes) DeclineSteps(id string) {
+	n.note(id).StepsOffered = true
+}
+
+func (n *Notes) note(id string) *Note {
+	note := n.Items[id]
+	if note == nil {
+		note = &Note{}
+		n.Items[id] = note
+	}
+	return note
+}
+
+// SetSubtaskDone toggles the subtask at the zero-based index. It reports
+// false if the index is out of range.
+func (n *Notes) SetSubtaskDone(id string, index int, done bool) bool {
+	note := n.Items[id]
+	if note == nil || index < 0 || index >= len(note.Subtasks) {
+		return false
+	}
+	note.Subtasks[index].Done = done
+	return true
+}
+
+// RemoveSubtask deletes the subtask at the zero-based index and returns it,
+// so it can be kept in the trash.
+func (n *Notes) RemoveSubtask(id string, index int) (Subtask, bool) {
+	note := n.Items[id]
+	if note == nil || index < 0 || index >= len(note.Subtasks) {
+		return Subtask{}, false
+	}
+	removed := note.Subtasks[index]
+	note.Subtasks = append(note.Subtasks[:index], note.Subtasks[index+1:]...)
+	if len(note.Subtasks) == 0 && !note.StepsOffered {
+		delete(n.Items, id)
+	}
+	return removed, true
+}
+
+// RestoreSubtask puts a removed subtask back at index, or at the end if the
+// list has since become shorter.
+func (n *Notes) RestoreSubtask(id string, index int, st Subtask) {
+	note := n.note(id)
+	if index < 0 || index > len(note.Subtasks) {
+		index = len(note.Subtasks)
+	}
+	note.Subtasks = append(note.Subtasks, Subtask{})
+	copy(note.Subtasks[index+1:], note.Subtasks[index:])
+	note.Subtasks[index] = st
+}
@@ -0,0 +1,45 @@
+package store
+
+import "time"
+
+const profilesName = "profiles"
+
+type CachedProfile struct {
+	Name     string    `json:"name"`
+	PhotoURL string    `json:"photoUrl,omitempty"`
+	Fetched  time.Time `json:"fetched"`
+}
+
+// Profiles caches names and photo URLs of teachers and classmates, which
+// rarely change and would otherwise cost one request per person.
+type Profiles struct {
+	Users map[string]CachedProfile `json:"users"`
+}
+
+func (s *Store) Profiles() (*Profiles, error) {
+	profiles := &Profiles{}
+	if err := s.Load(profilesName, profiles); err != nil {
+		return nil, err
+	}
+	if profiles.Users == nil {
+		profiles.Users = make(map[string]CachedProfile)
+	}
+	return profiles, nil
+}
+
+func (s *Store) SaveProfiles(profiles *Profiles) error {
+	return s.Save(profilesName, profiles)
+}
+
+// Get returns the cached profile if it was fetched within maxAge.
+func (p *Profiles) Get(userID string, maxAge time.Duration, now time.Time) (CachedProfile, bool) {
+	cp, ok := p.Users[userID]
+	if !ok || now.Sub(cp.Fetched) > maxAge {
+		return CachedProfile{}, false
+	}
+	return cp, true
+}
+
+func (p *Profiles) Set(userID string, profile CachedProfile) {
+	p.Users[userID] = profile
+}
@@ -0,0 +1,42 @@
+package store
+
+import "time"
+
+const readMarkersName = "read"
+
+// ReadMarkers records which announcements and coursework items have been
+// reviewed locally. Classroom has no read receipts, so this never leaves the
+// machine.
+type ReadMarkers struct {
+	Items map[string]time.Time `json:"items"`
+}
+
+func (s *Store) ReadMarkers() (*ReadMarkers, error) {
+	markers := &ReadMarkers{}
+	if err := s.Load(readMarkersName, markers); err != nil {
+		return nil, err
+	}
+	if markers.Items == nil {
+		markers.Items = make(map[string]time.Time)
+	}
+	return markers, nil
+}
+
+func (s *Store) SaveReadMarkers(markers *ReadMarkers) error {
+	return s.Save(readMarkersName, markers)
+}
+
+func (r *ReadMarkers) IsRead(id string) bool {
+	_, ok := r.Items[id]
+	return ok
+}
+
+func (r *ReadMarkers) MarkRead(id string, at time.Time) {
+	if _, ok := r.Items[id]; !ok {
+		r.Items[id] = at
+	}
+}
+
+func (r *ReadMarkers) MarkUnread(id string) {
+	delete(r.Items, id)
+}
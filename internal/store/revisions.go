@@ -0,0 +1,47 @@
+package store
+
+import "time"
+
+const revisionsName = "revisions"
+
+// CourseWorkVersion is what an assignment looked like at one sync.
+type CourseWorkVersion struct {
+	Seen      time.Time `json:"seen"`
+	CourseID  string    `json:"courseId"`
+	Title     string    `json:"title"`
+	Due       time.Time `json:"due,omitempty"`
+	MaxPoints int64     `json:"maxPoints,omitempty"`
+	// Description is nil when the sync that saw this version didn't fetch
+	// descriptions.
+	Description *string `json:"description,omitempty"`
+}
+
+// Revisions keeps earlier versions of assignments the teacher has edited,
+// keyed by coursework ID, oldest first.
+type Revisions struct {
+	CourseWork map[string][]CourseWorkVersion `json:"courseWork"`
+}
+
+func (s *Store) Revisions() (*Revisions, error) {
+	rev := &Revisions{}
+	if err := s.Load(revisionsName, rev); err != nil {
+		return nil, err
+	}
+	if rev.CourseWork == nil {
+		rev.CourseWork = make(map[string][]CourseWorkVersion)
+	}
+	return rev, nil
+}
+
+func (s *Store) SaveRevisions(rev *Revisions) error {
+	return s.Save(revisionsName, rev)
+}
+
+// Record notes that id changed from before to after. The first change for an
+// assignment keeps before as well, so there is always something to diff.
+func (r *Revisions) Record(id string, before, after CourseWorkVersion) {
+	if len(r.CourseWork[id]) == 0 {
+		r.CourseWork[id] = append(r.CourseWork[id], before)
+	}
+	r.CourseWork[id] = append(r.CourseWork[id], after)
+}
@@ -0,0 +1,60 @@
+package store
+
+import "time"
+
+const rolesName = "roles"
+
+const (
+	RoleTeacher = "teacher"
+	RoleStudent = "student"
+)
+
+type CourseRole struct {
+	Role    string    `json:"role"`
+	Checked time.Time `json:"checked"`
+}
+
+// Roles caches whether I teach or attend each course so teacher-only
+// commands can be hidden or refused without an API call.
+type Roles struct {
+	Courses map[string]CourseRole `json:"courses"`
+}
+
+func (s *Store) Roles() (*Roles, error) {
+	roles := &Roles{}
+	if err := s.Load(rolesName, roles); err != nil {
+		return nil, err
+	}
+	if roles.Courses == nil {
+		roles.Courses = make(map[string]CourseRole)
+	}
+	return roles, nil
+}
+
+func (s *Store) SaveRoles(roles *Roles) error {
+	return s.Save(rolesName, roles)
+}
+
+// Get returns the cached role for a course if it was checked within maxAge.
+func (r *Roles) Get(courseID string, maxAge time.Duration, now time.Time) (string, bool) {
+	cr, ok := r.Courses[courseID]
+	if !ok || now.Sub(cr.Checked) > maxAge {
+		return "", false
+	}
+	return cr.Role, true
+}
+
+func (r *Roles) Set(courseID, role string, now time.Time) {
+	r.Courses[courseID] = CourseRole{Role: role, Checked: now}
+}
+
+// Teaches reports whether any cached course is taught by me. known is false
+// when nothing has been cached yet.
+func (r *Roles) Teaches() (teaches, known bool) {
+	for _, cr := range r.Courses {
+		if cr.Role == RoleTeacher {
+			return true, true
+		}
+	}
+	return false, len(r.Courses) > 0
+}
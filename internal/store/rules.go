@@ -0,0 +1,64 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+const alertRulesName = "alert_rules"
+
+const (
+	RuleGradeBelow   = "grade-below"
+	RuleAverageBelow = "average-below"
+)
+
+// AlertRule fires when a returned grade (grade-below) or a course average
+// (average-below) falls under Threshold percent. An empty CourseID applies
+// the rule to every course.
+type AlertRule struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Threshold float64   `json:"threshold"`
+	CourseID  string    `json:"courseId,omitempty"`
+	Created   time.Time `json:"created"`
+}
+
+// AlertRules also remembers which alerts have already been delivered so a
+// rule only fires once per grade, or once each time an average drops.
+type AlertRules struct {
+	Rules  []AlertRule          `json:"rules"`
+	NextID int                  `json:"nextId"`
+	Fired  map[string]time.Time `json:"fired"`
+}
+
+func (s *Store) AlertRules() (*AlertRules, error) {
+	rules := &AlertRules{}
+	if err := s.Load(alertRulesName, rules); err != nil {
+		return nil, err
+	}
+	if rules.Fired == nil {
+		rules.Fired = make(map[string]time.Time)
+	}
+	return rules, nil
+}
+
+func (s *Store) SaveAlertRules(rules *AlertRules) error {
+	return s.Save(alertRulesName, rules)
+}
+
+func (r *AlertRules) Add(rule AlertRule) AlertRule {
+	r.NextID++
+	rule.ID = fmt.Sprintf("r%d", r.NextID)
+	r.Rules = append(r.Rules, rule)
+	return rule
+}
+
+func (r *AlertRules) Remove(id string) bool {
+	for i, rule := range r.Rules {
+		if rule.ID == id {
+			r.Rules = append(r.Rules[:i], r.Rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
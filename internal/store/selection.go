@@ -0,0 +1,32 @@
+package store
+
+import "time"
+
+const selectionName = "selection"
+
+// SelectedItem is one numbered row of the last listing.
+type SelectedItem struct {
+	CourseID string `json:"courseId"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Link     string `json:"link,omitempty"`
+}
+
+// Selection is the last numbered listing, so follow-up commands can refer
+// to rows as %1, %2, ... instead of by ID.
+type Selection struct {
+	Saved time.Time      `json:"saved"`
+	Items []SelectedItem `json:"items"`
+}
+
+func (s *Store) Selection() (*Selection, error) {
+	sel := &Selection{}
+	if err := s.Load(selectionName, sel); err != nil {
+		return nil, err
+	}
+	return sel, nil
+}
+
+func (s *Store) SaveSelection(sel *Selection) error {
+	return s.Save(selectionName, sel)
+}
@@ -0,0 +1,48 @@
+package store
+
+import "time"
+
+const snoozesName = "snoozes"
+
+// Snoozes holds notifications muted until a given time, keyed by the
+// coursework or announcement ID they're about.
+type Snoozes struct {
+	Until map[string]time.Time `json:"until"`
+}
+
+func (s *Store) Snoozes() (*Snoozes, error) {
+	snoozes := &Snoozes{}
+	if err := s.Load(snoozesName, snoozes); err != nil {
+		return nil, err
+	}
+	if snoozes.Until == nil {
+		snoozes.Until = make(map[string]time.Time)
+	}
+	return snoozes, nil
+}
+
+func (s *Store) SaveSnoozes(snoozes *Snoozes) error {
+	return s.Save(snoozesName, snoozes)
+}
+
+func (sn *Snoozes) Snooze(id string, until time.Time) {
+	sn.Until[id] = until
+}
+
+func (sn *Snoozes) Unsnooze(id string) {
+	delete(sn.Until, id)
+}
+
+func (sn *Snoozes) IsSnoozed(id string, now time.Time) bool {
+	until, ok := sn.Until[id]
+	return ok && now.Before(until)
+}
+
+// Prune drops expired snoozes so the file doesn't grow forever.
+func (sn *Snoozes) Prune(now time.Time) {
+	for id, until := range sn.Until {
+		if !now.Before(until) {
+			delete(sn.Until, id)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+//go:build sqlite
+
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	// The pure-Go driver, so the backend builds without cgo.
+	_ "modernc.org/sqlite"
+)
+
+const sqliteBuilt = true
+
+// SQLiteBackend keeps every document as a row in one SQLite database file,
+// for filesystems where many small files are slow. It's only built with
+// -tags sqlite. The database stays open for the life of the process.
+type SQLiteBackend struct {
+	db *sql.DB
+}
+
+func NewSQLiteBackend(path string) (*SQLiteBackend, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	// One connection serializes writes, which SQLite would do anyway.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS documents (name TEXT PRIMARY KEY, data BLOB NOT NULL)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set up %s: %w", path, err)
+	}
+	return &SQLiteBackend{db: db}, nil
+}
+
+func openSQLite(path string) (Backend, error) {
+	return NewSQLiteBackend(path)
+}
+
+func (b *SQLiteBackend) Read(name string) ([]byte, error) {
+	var data []byte
+	err := b.db.QueryRow(`SELECT data FROM documents WHERE name = ?`, name).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Write replaces the document in a single statement, so like FileBackend it
+// leaves either the old document or the new one.
+func (b *SQLiteBackend) Write(name string, data []byte) error {
+	_, err := b.db.Exec(`INSERT INTO documents (name, data) VALUES (?, ?)
+		ON CONFLICT(name) DO UPDATE SET data = excluded.data`, name, data)
+	return err
+}
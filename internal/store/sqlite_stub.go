@@ -0,0 +1,11 @@
+//go:build !sqlite
+
+package store
+
+import "fmt"
+
+const sqliteBuilt = false
+
+func openSQLite(string) (Backend, error) {
+	return nil, fmt.Errorf("storage backend %q isn't in this build; rebuild gc-cli with -tags sqlite", BackendSQLite)
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Store persists small pieces of local state (read markers, notes, etc.) as
+// JSON documents in a Backend, by default files inside a single data
+// directory.
+type Store struct {
+	backend Backend
+	dir     string
+}
+
+func New(dir string) *Store {
+	return &Store{backend: NewFileBackend(dir), dir: dir}
+}
+
+func NewWithBackend(backend Backend) *Store {
+	return &Store{backend: backend}
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Load decodes the named document into v. A missing document is not an
+// error; v is left untouched.
+func (s *Store) Load(name string, v any) error {
+	data, err := s.backend.Read(name)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if data == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return &CorruptError{Name: name, Err: err}
+	}
+	return nil
+}
+
+// CorruptError is returned for a document that can't be parsed or doesn't
+// match its checksum.
+type CorruptError struct {
+	Name string
+	Err  error
+}
+
+func (e *CorruptError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.Name, e.Err)
+}
+
+func (e *CorruptError) Unwrap() error {
+	return e.Err
+}
+
+func (s *Store) Save(name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	if err := s.backend.Write(name, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+const trackLogName = "track"
+
+// TimeEntry is time spent on a coursework item. Points is captured when the
+// entry is logged so history stays useful after the course is archived.
+type TimeEntry struct {
+	CourseID     string        `json:"course_id"`
+	CourseWorkID string        `json:"coursework_id"`
+	Title        string        `json:"title,omitempty"`
+	Points       float64       `json:"points,omitempty"`
+	Duration     time.Duration `json:"duration"`
+	LoggedAt     time.Time     `json:"logged_at"`
+}
+
+type TrackLog struct {
+	Entries []TimeEntry `json:"entries"`
+}
+
+func (s *Store) TrackLog() (*TrackLog, error) {
+	log := &TrackLog{}
+	if err := s.Load(trackLogName, log); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+func (s *Store) SaveTrackLog(log *TrackLog) error {
+	return s.Save(trackLogName, log)
+}
+
+func (t *TrackLog) Add(entry TimeEntry) {
+	t.Entries = append(t.Entries, entry)
+}
+
+// Remove deletes the entry at the zero-based index and returns it.
+func (t *TrackLog) Remove(index int) (TimeEntry, bool) {
+	if index < 0 || index >= len(t.Entries) {
+		return TimeEntry{}, false
+	}
+	removed := t.Entries[index]
+	t.Entries = append(t.Entries[:index], t.Entries[index+1:]...)
+	return removed, true
+}
+
+// Restore puts a removed entry back in order of when it was logged.
+func (t *TrackLog) Restore(entry TimeEntry) {
+	i := sort.Search(len(t.Entries), func(i int) bool {
+		return t.Entries[i].LoggedAt.After(entry.LoggedAt)
+	})
+	t.Entries = append(t.Entries, TimeEntry{})
+	copy(t.Entries[i+1:], t.Entries[i:])
+	t.Entries[i] = entry
+}
+
+// MinutesPerPoint returns the historical ratio of tracked minutes to points
+// across all entries for point-bearing work. ok is false when there isn't
+// any such history yet.
+func (t *TrackLog) MinutesPerPoint() (rate float64, ok bool) {
+	minutes := make(map[string]float64)
+	points := make(map[string]float64)
+	for _, e := range t.Entries {
+		if e.Points <= 0 {
+			continue
+		}
+		minutes[e.CourseWorkID] += e.Duration.Minutes()
+		points[e.CourseWorkID] = e.Points
+	}
+
+	var totalMinutes, totalPoints float64
+	for id, m := range minutes {
+		totalMinutes += m
+		totalPoints += points[id]
+	}
+	if totalPoints == 0 {
+		return 0, false
+	}
+	return totalMinutes / totalPoints, true
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+const trashName = "trash"
+
+const (
+	TrashSubtask   = "subtask"
+	TrashTimeEntry = "time"
+)
+
+// TrashItem is a deleted subtask or time entry, kept until it's restored or
+// purged. CourseWorkID and Index say where a subtask was, so restoring puts
+// it back in its place.
+type TrashItem struct {
+	ID           string     `json:"id"`
+	Kind         string     `json:"kind"`
+	CourseWorkID string     `json:"coursework_id,omitempty"`
+	Index        int        `json:"index,omitempty"`
+	Subtask      *Subtask   `json:"subtask,omitempty"`
+	Entry        *TimeEntry `json:"entry,omitempty"`
+	Deleted      time.Time  `json:"deleted"`
+}
+
+// Trash holds deleted local records, oldest first.
+type Trash struct {
+	Items  []TrashItem `json:"items"`
+	NextID int         `json:"nextId"`
+}
+
+func (s *Store) Trash() (*Trash, error) {
+	trash := &Trash{}
+	if err := s.Load(trashName, trash); err != nil {
+		return nil, err
+	}
+	return trash, nil
+}
+
+func (s *Store) SaveTrash(trash *Trash) error {
+	return s.Save(trashName, trash)
+}
+
+func (t *Trash) Add(item TrashItem) TrashItem {
+	t.NextID++
+	item.ID = fmt.Sprintf("t%d", t.NextID)
+	t.Items = append(t.Items, item)
+	return item
+}
+
+// Take removes the item with id from the trash and returns it.
+func (t *Trash) Take(id string) (TrashItem, bool) {
+	for i, item := range t.Items {
+		if item.ID == id {
+			t.Items = append(t.Items[:i], t.Items[i+1:]...)
+			return item, true
+		}
+	}
+	return TrashItem{}, false
+}
+
+// Purge drops items deleted before cutoff, or every item if cutoff is zero,
+// and returns how many it dropped.
+func (t *Trash) Purge(cutoff time.Time) int {
+	kept := t.Items[:0]
+	for _, item := range t.Items {
+		if !cutoff.IsZero() && !item.Deleted.Before(cutoff) {
+			kept = append(kept, item)
+		}
+	}
+	purged := len(t.Items) - len(kept)
+	t.Items = kept
+	return purged
+}
@@ -0,0 +1,37 @@
+package store
+
+const tuiStateName = "tui"
+
+// TUIViewState is where a TUI list view was left: the highlighted row and
+// how far it was scrolled.
+type TUIViewState struct {
+	Selected string `json:"selected,omitempty"`
+	Scroll   int    `json:"scroll,omitempty"`
+}
+
+// TUIState is where the last TUI session ended, so the next one reopens
+// there instead of at the main menu.
+type TUIState struct {
+	// View is the list view that was open, e.g. "coursework"; Detail is
+	// set if its highlighted item was open too.
+	View         string                  `json:"view,omitempty"`
+	Detail       bool                    `json:"detail,omitempty"`
+	DetailScroll int                     `json:"detail_scroll,omitempty"`
+	Menu         int                     `json:"menu"`
+	Views        map[string]TUIViewState `json:"views"`
+}
+
+func (s *Store) TUIState() (*TUIState, error) {
+	ts := &TUIState{}
+	if err := s.Load(tuiStateName, ts); err != nil {
+		return nil, err
+	}
+	if ts.Views == nil {
+		ts.Views = make(map[string]TUIViewState)
+	}
+	return ts, nil
+}
+
+func (s *Store) SaveTUIState(ts *TUIState) error {
+	return s.Save(tuiStateName, ts)
+}
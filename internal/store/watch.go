@@ -0,0 +1,63 @@
+package store
+
+import (
+	"errors"
+	"time"
+)
+
+const watchName = "watch"
+
+// WatchState is what `gc-cli watch` saw on its last poll, so a restarted
+// watcher reports what changed while it wasn't running.
+type WatchState struct {
+	Checked time.Time `json:"checked"`
+	// Courses lists the courses seen before; a new course's existing items
+	// are recorded without being reported.
+	Courses       map[string]bool              `json:"courses"`
+	Coursework    map[string]CourseWorkVersion `json:"coursework"`
+	Announcements map[string]bool              `json:"announcements"`
+	// Grades holds the grade of each returned submission, by submission ID.
+	Grades map[string]float64 `json:"grades"`
+	// Reminded holds the due time each coursework item was last reported
+	// as due soon, so a moved deadline is reported again.
+	Reminded map[string]time.Time `json:"reminded,omitempty"`
+
+	// Recovered is set when the saved state was damaged and this one starts
+	// afresh; SetAside names the copy kept of the damaged one, if any.
+	Recovered bool   `json:"-"`
+	SetAside  string `json:"-"`
+}
+
+// WatchState loads the watcher's state. Damaged state, e.g. from an older
+// version losing power mid-write, is set aside and replaced by a fresh one
+// rather than stopping the watcher.
+func (s *Store) WatchState() (*WatchState, error) {
+	ws := &WatchState{}
+	err := s.LoadChecked(watchName, ws)
+	var corrupt *CorruptError
+	if errors.As(err, &corrupt) {
+		ws = &WatchState{Recovered: true, SetAside: s.setAside(watchName)}
+	} else if err != nil {
+		return nil, err
+	}
+	if ws.Courses == nil {
+		ws.Courses = make(map[string]bool)
+	}
+	if ws.Coursework == nil {
+		ws.Coursework = make(map[string]CourseWorkVersion)
+	}
+	if ws.Announcements == nil {
+		ws.Announcements = make(map[string]bool)
+	}
+	if ws.Grades == nil {
+		ws.Grades = make(map[string]float64)
+	}
+	if ws.Reminded == nil {
+		ws.Reminded = make(map[string]time.Time)
+	}
+	return ws, nil
+}
+
+func (s *Store) SaveWatchState(ws *WatchState) error {
+	return s.SaveChecked(watchName, ws)
+}
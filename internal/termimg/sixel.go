@@ -0,0 +1,83 @@
+package termimg
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// sixel encodes img using a fixed 6×6×6 colour cube, which is plenty for
+// thumbnails and avoids a quantisation pass.
+func sixel(img image.Image) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	index := make([]int, w*h)
+	used := make(map[int]bool)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			c := int(r>>8)*6/256*36 + int(g>>8)*6/256*6 + int(b>>8)*6/256
+			index[y*w+x] = c
+			used[c] = true
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\x1bPq")
+	fmt.Fprintf(&sb, "\"1;1;%d;%d", w, h)
+	for c := range used {
+		r, g, b := c/36, c/6%6, c%6
+		fmt.Fprintf(&sb, "#%d;2;%d;%d;%d", c, r*100/5, g*100/5, b*100/5)
+	}
+
+	for band := 0; band < h; band += 6 {
+		first := true
+		for c := range used {
+			line := make([]byte, w)
+			found := false
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && band+dy < h; dy++ {
+					if index[(band+dy)*w+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				if bits != 0 {
+					found = true
+				}
+				line[x] = 63 + bits
+			}
+			if !found {
+				continue
+			}
+			if !first {
+				sb.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&sb, "#%d", c)
+			writeRLE(&sb, line)
+		}
+		sb.WriteByte('-')
+	}
+
+	sb.WriteString("\x1b\\")
+	return sb.String()
+}
+
+func writeRLE(sb *strings.Builder, line []byte) {
+	for i := 0; i < len(line); {
+		j := i
+		for j < len(line) && line[j] == line[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(sb, "!%d%c", n, line[i])
+		} else {
+			for k := i; k < j; k++ {
+				sb.WriteByte(line[k])
+			}
+		}
+		i = j
+	}
+}
@@ -0,0 +1,247 @@
+// Package termimg renders small images inline using the kitty graphics
+// protocol, iTerm2 inline images or sixel, whichever the terminal supports.
+package termimg
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/useragent"
+)
+
+type Protocol int
+
+const (
+	None Protocol = iota
+	Kitty
+	ITerm2
+	Sixel
+)
+
+// Approximate cell size used to scale sixel output, which is sized in pixels.
+const (
+	cellWidth  = 10
+	cellHeight = 20
+
+	maxImageBytes = 5 << 20
+	// maxImageSide bounds each dimension, since a small compressed file can
+	// still decode to an enormous bitmap.
+	maxImageSide = 4096
+)
+
+// Detect guesses the image protocol from the environment. GC_CLI_IMAGES
+// (kitty, iterm2, sixel or none) overrides detection.
+func Detect() Protocol {
+	switch strings.ToLower(os.Getenv("GC_CLI_IMAGES")) {
+	case "kitty":
+		return Kitty
+	case "iterm2":
+		return ITerm2
+	case "sixel":
+		return Sixel
+	case "none", "off":
+		return None
+	}
+
+	term := os.Getenv("TERM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty":
+		return Kitty
+	case os.Getenv("TERM_PROGRAM") == "iTerm.app" || os.Getenv("TERM_PROGRAM") == "WezTerm":
+		return ITerm2
+	case strings.Contains(term, "sixel") || term == "foot" || term == "mlterm":
+		return Sixel
+	}
+	return None
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second, Transport: &useragent.Transport{}}
+
+// Fetch downloads and decodes an image, refusing any larger than
+// maxImageSide pixels on a side before decoding it.
+func Fetch(ctx context.Context, url string) (image.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch image: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %w", err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if cfg.Width > maxImageSide || cfg.Height > maxImageSide {
+		return nil, fmt.Errorf("image is %dx%d, larger than %d pixels on a side", cfg.Width, cfg.Height, maxImageSide)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// Render encodes img to fit within cols×rows terminal cells, drawn at the
+// cursor. Callers are responsible for reserving that space.
+func Render(img image.Image, p Protocol, cols, rows int) (string, error) {
+	var seq string
+	switch p {
+	case Kitty:
+		data, err := encodePNG(img)
+		if err != nil {
+			return "", err
+		}
+		seq = kitty(data, cols, rows)
+	case ITerm2:
+		data, err := encodePNG(img)
+		if err != nil {
+			return "", err
+		}
+		seq = fmt.Sprintf("\x1b]1337;File=inline=1;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+			cols, rows, base64.StdEncoding.EncodeToString(data))
+	case Sixel:
+		seq = sixel(resize(img, cols*cellWidth, rows*cellHeight))
+	default:
+		return "", fmt.Errorf("terminal does not support images")
+	}
+	return seq, nil
+}
+
+// Clear removes previously drawn images. Only kitty keeps images on a
+// separate layer; other protocols are cleared by repainting the screen.
+func Clear(p Protocol) string {
+	if p == Kitty {
+		return "\x1b_Ga=d,q=2\x1b\\"
+	}
+	return ""
+}
+
+// At wraps seq so it is drawn at the given zero-based cell without moving
+// the cursor.
+func At(seq string, row, col int) string {
+	return fmt.Sprintf("\x1b7\x1b[%d;%dH%s\x1b8", row+1, col+1, seq)
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// kitty transmits PNG data in 4096-byte chunks and displays it without moving
+// the cursor, so surrounding layout stays predictable.
+func kitty(data []byte, cols, rows int) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	for first := true; len(encoded) > 0; first = false {
+		chunk := encoded
+		if len(chunk) > 4096 {
+			chunk = chunk[:4096]
+		}
+		encoded = encoded[len(chunk):]
+
+		more := 0
+		if len(encoded) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,c=%d,r=%d,C=1,q=2,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+// resize scales img to fit within w×h using nearest-neighbour sampling,
+// preserving the aspect ratio.
+func resize(img image.Image, w, h int) image.Image {
+	bounds := img.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	if sw == 0 || sh == 0 {
+		return img
+	}
+
+	scale := float64(w) / float64(sw)
+	if s := float64(h) / float64(sh); s < scale {
+		scale = s
+	}
+	dw, dh := int(float64(sw)*scale), int(float64(sh)*scale)
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			dst.Set(x, y, img.At(bounds.Min.X+x*sw/dw, bounds.Min.Y+y*sh/dh))
+		}
+	}
+	return dst
+}
+
+// Blocks draws img with "▀" half blocks in 24-bit colour, two pixels per
+// cell. It needs no graphics protocol, so it also works in the TUI and in
+// plain tables.
+func Blocks(img image.Image, cols, rows int) string {
+	img = resize(img, cols, rows*2)
+	bounds := img.Bounds()
+
+	var b strings.Builder
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		if y > bounds.Min.Y {
+			b.WriteByte('\n')
+		}
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			tr, tg, tb, _ := img.At(x, y).RGBA()
+			br, bg, bb := tr, tg, tb
+			if y+1 < bounds.Max.Y {
+				br, bg, bb, _ = img.At(x, y+1).RGBA()
+			}
+			fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀", tr>>8, tg>>8, tb>>8, br>>8, bg>>8, bb>>8)
+		}
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// BlocksSupported reports whether Blocks output is likely to look right:
+// the terminal speaks a graphics protocol or advertises 24-bit colour, and
+// images haven't been turned off with GC_CLI_IMAGES.
+func BlocksSupported() bool {
+	switch strings.ToLower(os.Getenv("GC_CLI_IMAGES")) {
+	case "none", "off":
+		return false
+	}
+	colorterm := strings.ToLower(os.Getenv("COLORTERM"))
+	return Detect() != None || colorterm == "truecolor" || colorterm == "24bit"
+}
@@ -0,0 +1,43 @@
+package termimg
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetch(t *testing.T) {
+	images := map[string]image.Image{
+		"/small": image.NewGray(image.Rect(0, 0, 4, 3)),
+		"/wide":  image.NewGray(image.Rect(0, 0, maxImageSide+1, 1)),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		img, ok := images[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Error(err)
+		}
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	img, err := Fetch(context.Background(), srv.URL+"/small")
+	if err != nil || img.Bounds().Dx() != 4 || img.Bounds().Dy() != 3 {
+		t.Errorf("Fetch(small) = %v, %v; want the 4x3 image", img, err)
+	}
+	if _, err := Fetch(context.Background(), srv.URL+"/wide"); err == nil || !strings.Contains(err.Error(), "larger than") {
+		t.Errorf("Fetch(wide) = %v, want it refused before decoding", err)
+	}
+	if _, err := Fetch(context.Background(), srv.URL+"/missing"); err == nil {
+		t.Error("Fetch(missing) succeeded, want the 404 reported")
+	}
+}
@@ -0,0 +1,130 @@
+// Package textdiff computes line-based diffs of short texts such as
+// assignment descriptions.
+package textdiff
+
+import "strings"
+
+type Op int
+
+const (
+	Equal Op = iota
+	Delete
+	Insert
+)
+
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Lines diffs a and b line by line using a longest common subsequence, which
+// is fine for the few hundred lines a description might have.
+func Lines(a, b string) []Line {
+	x, y := split(a), split(b)
+
+	// lcs[i][j] is the LCS length of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []Line
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, Line{Equal, x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Delete, x[i]})
+			i++
+		default:
+			lines = append(lines, Line{Insert, y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		lines = append(lines, Line{Delete, x[i]})
+	}
+	for ; j < len(y); j++ {
+		lines = append(lines, Line{Insert, y[j]})
+	}
+	return lines
+}
+
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// Hunk is a run of changes with surrounding context, numbered like a
+// unified diff (1-based; a zero-length side starts at the line before).
+type Hunk struct {
+	AStart, ALen int
+	BStart, BLen int
+	Lines        []Line
+}
+
+// Hunks groups lines into hunks with up to context unchanged lines around
+// each change. Equal texts have no hunks.
+func Hunks(lines []Line, context int) []Hunk {
+	// aAt[i] and bAt[i] are the line numbers lines[i] starts at.
+	aAt := make([]int, len(lines)+1)
+	bAt := make([]int, len(lines)+1)
+	aAt[0], bAt[0] = 1, 1
+	var changes []int
+	for i, l := range lines {
+		aAt[i+1], bAt[i+1] = aAt[i], bAt[i]
+		if l.Op != Insert {
+			aAt[i+1]++
+		}
+		if l.Op != Delete {
+			bAt[i+1]++
+		}
+		if l.Op != Equal {
+			changes = append(changes, i)
+		}
+	}
+
+	var hunks []Hunk
+	for k := 0; k < len(changes); {
+		first, last := changes[k], changes[k]
+		for k++; k < len(changes) && changes[k]-last <= 2*context+1; k++ {
+			last = changes[k]
+		}
+		from, to := first-context, last+context+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(lines) {
+			to = len(lines)
+		}
+		h := Hunk{
+			AStart: aAt[from], ALen: aAt[to] - aAt[from],
+			BStart: bAt[from], BLen: bAt[to] - bAt[from],
+			Lines: lines[from:to],
+		}
+		if h.ALen == 0 {
+			h.AStart--
+		}
+		if h.BLen == 0 {
+			h.BStart--
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
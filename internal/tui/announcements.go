@@ -0,0 +1,74 @@
+package tui
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+// fetchAnnouncements lists the announcements of every active course, newest
+// first, with authors' names looked up from their user IDs.
+func fetchAnnouncements(ctx context.Context, newClient ClientFunc) ([]AnnouncementItem, error) {
+	client, err := newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	courses, _, err := client.ListCourses(ctx, 0, api.WithCourseStates(api.CourseActive))
+	if err != nil {
+		return nil, err
+	}
+
+	var anns []api.Announcement
+	courseNames := make(map[string]string)
+	for _, course := range courses {
+		courseNames[course.ID] = course.Name
+		list, _, err := client.ListAnnouncements(ctx, course.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		anns = append(anns, list...)
+	}
+	sort.SliceStable(anns, func(i, j int) bool {
+		return anns[i].CreationTime.After(anns[j].CreationTime)
+	})
+
+	authors := make([]string, len(anns))
+	for i, a := range anns {
+		authors[i] = a.CreatorUserID
+	}
+	names := client.UserNames(ctx, authors)
+
+	items := make([]AnnouncementItem, len(anns))
+	for i, a := range anns {
+		author, ok := names[a.CreatorUserID]
+		if !ok {
+			author = "Unknown author"
+		}
+		items[i] = AnnouncementItem{
+			ID:            a.ID,
+			CourseName:    courseNames[a.CourseID],
+			AnnounceTitle: announcementTitle(a.Text),
+			Author:        author,
+			AuthorID:      a.CreatorUserID,
+			Text:          a.Text,
+			PostedAt:      a.CreationTime.Local().Format("2006-01-02"),
+			Link:          a.AlternateLink,
+		}
+	}
+	return items, nil
+}
+
+// announcementTitle is the first line of the text, since announcements
+// don't have titles of their own.
+func announcementTitle(text string) string {
+	title := strings.TrimSpace(text)
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = strings.TrimSpace(title[:i])
+	}
+	if r := []rune(title); len(r) > 60 {
+		title = string(r[:57]) + "..."
+	}
+	return title
+}
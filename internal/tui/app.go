@@ -3,15 +3,25 @@ package tui
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/agenda"
 	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/avatar"
+	"github.com/timboy697/gc-cli/internal/clock"
 	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+	"github.com/timboy697/gc-cli/internal/store"
+	"github.com/timboy697/gc-cli/internal/termimg"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -27,6 +37,10 @@ const (
 	ViewLoading
 	ViewError
 	ViewAuthRequired
+	ViewCourseworkDetail
+	ViewAnnouncementDetail
+	ViewCompose
+	ViewSubmit
 )
 
 type AuthState int
@@ -60,14 +74,68 @@ type Model struct {
 	Grades        []GradeItem
 	Announcements []AnnouncementItem
 
-	SelectedCoursework int
+	SelectedCoursework   int
+	SelectedAnnouncement int
+
+	// ViewState is where each view was left, carried across sessions;
+	// ResumeCmd is what reopening the last session's view needs to run.
+	ViewState *store.TUIState
+	ResumeCmd tea.Cmd
+
+	Store       *store.Store
+	ReadMarkers *store.ReadMarkers
+	History     *store.History
+	Notes       *store.Notes
+	Avatars     *avatar.Cache
+	Roles       *store.Roles
+	Order       *courseorder.Order
+
+	PaletteOpen  bool
+	PaletteIndex int
+
+	Compose   *Composer
+	NewClient ClientFunc
+
+	// OfflineClient reads from the last sync. Offline is set once the
+	// user chooses to work offline from the error view.
+	OfflineClient ClientFunc
+	Offline       bool
+
+	Submitter *Submitter
+	Submit    SubmitFunc
+
+	// Clock is the time due dates are judged against, pinned by --now.
+	Clock clock.Clock
+
+	ImageProtocol termimg.Protocol
+	Thumbnails    map[string]string
 
 	Viewport viewport.Model
 
 	IsLoading  bool
 	LoadingMsg string
+	Spinner    spinner.Model
+	load       *viewLoad
 
 	ErrorMsg string
+	Notice   string
+
+	// FailedView is the view whose load failed, loaded again by the error
+	// view's retry; RetryAfterLogin retries it once signing in again
+	// succeeds.
+	FailedView      ViewType
+	RetryAfterLogin bool
+
+	// Banner warns about work due within the configured window; Warned
+	// remembers which items already rang the bell.
+	Banner string
+	Warned map[string]bool
+
+	// login is the sign-in in progress, and LoginURL the page it's
+	// waiting on.
+	login    *loginAttempt
+	LoginURL string
+	LoginErr string
 
 	Config *config.Config
 
@@ -76,11 +144,13 @@ type Model struct {
 }
 
 type CourseItem struct {
-	ID      string
-	Name    string
-	Section string
-	Desc    string
-	Room    string
+	ID       string
+	Name     string
+	Section  string
+	Desc     string
+	Room     string
+	Period   string
+	Teaching bool
 }
 
 func (c CourseItem) Title() string       { return c.Name }
@@ -102,10 +172,15 @@ func (g GradeItem) Description() string {
 func (g GradeItem) FilterValue() string { return g.Assignment }
 
 type AnnouncementItem struct {
+	ID            string
 	CourseName    string
 	AnnounceTitle string
+	Author        string
+	AuthorID      string
 	Text          string
 	PostedAt      string
+	Link          string
+	Materials     []MaterialItem
 }
 
 func (a AnnouncementItem) Title() string { return a.AnnounceTitle }
@@ -120,8 +195,9 @@ const (
 	StatusPending CourseworkStatus = iota
 	StatusTurnedIn
 	StatusReturned
-	StatusOverdue
+	StatusMissing
 	StatusDraft
+	StatusScheduled
 )
 
 type CourseworkItem struct {
@@ -135,7 +211,12 @@ type CourseworkItem struct {
 	DueTime     string
 	Points      int64
 	Status      CourseworkStatus
+	Late        bool
 	WorkType    string
+	Expects     agenda.Expects
+	OpensAt     time.Time
+	Link        string
+	Materials   []MaterialItem
 }
 
 func (c CourseworkItem) Title() string { return c.AssignTitle }
@@ -144,16 +225,31 @@ func (c CourseworkItem) Description() string {
 }
 func (c CourseworkItem) FilterValue() string { return c.AssignTitle }
 
+// opensIn describes when scheduled work becomes visible, e.g.
+// "in 2h (Mon 09:00)", or "" if it isn't scheduled in the future.
+func (c CourseworkItem) opensIn(now time.Time) string {
+	if c.Status != StatusScheduled || !c.OpensAt.After(now) {
+		return ""
+	}
+	return fmt.Sprintf("in %s (%s)", agenda.Until(now, c.OpensAt), c.OpensAt.Format("Mon 15:04"))
+}
+
 func (c CourseworkItem) StatusString() string {
+	late := ""
+	if c.Late {
+		late = " (late)"
+	}
 	switch c.Status {
 	case StatusTurnedIn:
-		return "TURNED_IN"
+		return "TURNED_IN" + late
 	case StatusReturned:
-		return "RETURNED"
-	case StatusOverdue:
-		return "OVERDUE"
+		return "RETURNED" + late
+	case StatusMissing:
+		return "MISSING"
 	case StatusDraft:
 		return "DRAFT"
+	case StatusScheduled:
+		return "SCHEDULED"
 	default:
 		return "NEW"
 	}
@@ -170,6 +266,15 @@ type keyMap struct {
 	Refresh  key.Binding
 	PageUp   key.Binding
 	PageDown key.Binding
+	Palette  key.Binding
+	Favorite key.Binding
+	Yank     key.Binding
+	YankLink key.Binding
+	Save     key.Binding
+	Compose  key.Binding
+	Submit   key.Binding
+	Offline  key.Binding
+	Relogin  key.Binding
 }
 
 var keys = keyMap{
@@ -213,6 +318,42 @@ var keys = keyMap{
 		key.WithKeys("pgdown"),
 		key.WithHelp("pgdown", "page down"),
 	),
+	Palette: key.NewBinding(
+		key.WithKeys("ctrl+p"),
+		key.WithHelp("ctrl+p", "jump to..."),
+	),
+	Favorite: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "pin/unpin"),
+	),
+	Yank: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy"),
+	),
+	YankLink: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy link"),
+	),
+	Save: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "save as markdown"),
+	),
+	Compose: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "new announcement"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "submit a file"),
+	),
+	Offline: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "go offline"),
+	),
+	Relogin: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "sign in again"),
+	),
 }
 
 var (
@@ -255,6 +396,9 @@ var (
 			Bold(true).
 			Padding(2, 0)
 
+	spinnerStyle = lipgloss.NewStyle().
+			Foreground(accentPrimary)
+
 	errorStyle = lipgloss.NewStyle().
 			Background(bgPrimary).
 			Foreground(errorColor).
@@ -305,6 +449,7 @@ func New(cfg *config.Config) Model {
 	delegate := list.NewDefaultDelegate()
 	menuList := list.New(items, delegate, 0, 0)
 	menuList.SetShowHelp(false)
+	menuList.SetShowTitle(false)
 	menuList.SetShowStatusBar(false)
 	menuList.SetFilteringEnabled(false)
 	menuList.SetShowPagination(false)
@@ -314,22 +459,80 @@ func New(cfg *config.Config) Model {
 		authState = AuthAuthenticated
 	}
 
+	var st *store.Store
+	markers := &store.ReadMarkers{Items: map[string]time.Time{}}
+	history := &store.History{}
+	notes := &store.Notes{Items: map[string]*store.Note{}}
+	roles := &store.Roles{Courses: map[string]store.CourseRole{}}
+	viewState := &store.TUIState{Views: map[string]store.TUIViewState{}}
+	var order *courseorder.Order
+	var avatars *avatar.Cache
+	if cfg != nil {
+		// An unusable storage backend just means no local state this session.
+		st, _ = cfg.Store()
+		order = courseorder.New(cfg.Courses)
+		// Only photos already cached by the CLI are shown; the TUI doesn't
+		// fetch them itself.
+		if !cfg.Anonymize && termimg.BlocksSupported() {
+			avatars = avatar.NewCache(filepath.Join(cfg.DataDir, "avatars"))
+		}
+	}
+	if st != nil {
+		if loaded, err := st.ReadMarkers(); err == nil {
+			markers = loaded
+		}
+		if loaded, err := st.History(); err == nil {
+			history = loaded
+		}
+		if loaded, err := st.Notes(); err == nil {
+			notes = loaded
+		}
+		if loaded, err := st.Roles(); err == nil {
+			roles = loaded
+		}
+		if cfg.TUI.RestoreState {
+			if loaded, err := st.TUIState(); err == nil {
+				viewState = loaded
+			}
+		}
+	}
+
+	startView := ViewMainMenu
+	if authState != AuthAuthenticated {
+		startView = ViewAuthRequired
+	}
+
 	return Model{
-		CurrentView:  ViewMainMenu,
-		PreviousView: ViewMainMenu,
-		AuthState:    authState,
-		Menu:         menuList,
-		SelectedMenu: 0,
-		Config:       cfg,
-		IsLoading:    false,
-		LoadingMsg:   "Loading...",
-		Width:        80,
-		Height:       24,
+		CurrentView:   startView,
+		PreviousView:  ViewMainMenu,
+		AuthState:     authState,
+		Menu:          menuList,
+		SelectedMenu:  0,
+		Config:        cfg,
+		Store:         st,
+		ReadMarkers:   markers,
+		History:       history,
+		Notes:         notes,
+		Avatars:       avatars,
+		Roles:         roles,
+		ViewState:     viewState,
+		Order:         order,
+		ImageProtocol: termimg.Detect(),
+		Thumbnails:    make(map[string]string),
+		Clock:         clock.System{},
+		IsLoading:     false,
+		LoadingMsg:    "Loading...",
+		Spinner:       newSpinner(),
+		Width:         80,
+		Height:        24,
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	if m.CurrentView == ViewAuthRequired && m.Config != nil {
+		return tea.Batch(func() tea.Msg { return startLoginMsg{} }, deadlineTick())
+	}
+	return tea.Batch(deadlineTick(), m.ResumeCmd)
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -340,9 +543,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
-		m.Viewport.Width = msg.Width - 4
-		m.Viewport.Height = msg.Height - 6
-		m.Menu.SetSize(msg.Width-4, msg.Height-6)
+		m.layout()
+		m.relayout()
+		// Keep a restored scroll position within the new height.
+		m.Viewport.SetYOffset(m.Viewport.YOffset)
 		return m, nil
 
 	case tea.MouseMsg:
@@ -350,6 +554,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		return m.handleKey(msg)
+
+	case startLoginMsg, authStartedMsg, authDoneMsg:
+		return m.handleAuthMsg(msg)
+
+	case thumbnailMsg:
+		return m.handleThumbnail(msg)
+
+	case deadlineTickMsg:
+		return m.handleDeadlineTick(msg)
+
+	case announcementPostedMsg:
+		return m.handleAnnouncementPosted(msg)
+
+	case submitProgressMsg, submitDoneMsg:
+		return m.handleSubmitMsg(msg)
+
+	case viewLoadedMsg:
+		return m.handleViewLoaded(msg)
+
+	case spinner.TickMsg:
+		return m.handleSpinnerTick(msg)
+
+	case drawThumbnailsMsg:
+		if m.CurrentView == ViewCourseworkDetail || m.CurrentView == ViewAnnouncementDetail {
+			return m, m.drawThumbnails()
+		}
+		return m, nil
 	}
 
 	if m.IsLoading {
@@ -364,23 +595,71 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ViewCourses, ViewCoursework, ViewGrades, ViewAnnouncements:
 		m.Viewport, cmd = m.Viewport.Update(msg)
 		cmds = append(cmds, cmd)
+
+	case ViewCourseworkDetail, ViewAnnouncementDetail:
+		m.Viewport, cmd = m.Viewport.Update(msg)
+		cmds = append(cmds, cmd, m.scheduleThumbnailDraw())
+
+	case ViewCompose:
+		cmds = append(cmds, m.Compose.update(msg))
+
+	case ViewSubmit:
+		m.Submitter.Path, cmd = m.Submitter.Path.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.Notice = ""
+	m.rememberView()
+
+	if m.PaletteOpen {
+		return m.handlePaletteKey(msg)
+	}
+
+	// The composer takes every key, including q and ctrl+p, as text.
+	if m.CurrentView == ViewCompose {
+		return m.handleComposeKey(msg)
+	}
+	if m.CurrentView == ViewSubmit {
+		return m.handleSubmitKey(msg)
+	}
+
+	if key.Matches(msg, keys.Palette) && m.AuthState == AuthAuthenticated {
+		m.PaletteOpen = true
+		m.PaletteIndex = 0
+		return m, m.scheduleThumbnailDraw()
+	}
+
 	if key.Matches(msg, keys.Quit) {
 		if m.CurrentView == ViewMainMenu {
 			return m, tea.Quit
 		}
+		m.cancelLoad()
+		m.cancelLogin()
 		m.PreviousView = m.CurrentView
 		m.CurrentView = ViewMainMenu
-		return m, nil
+		return m, m.leaveThumbnails()
 	}
 
 	if key.Matches(msg, keys.Back) {
+		switch m.CurrentView {
+		case ViewCourseworkDetail:
+			m.PreviousView = m.CurrentView
+			m.CurrentView = ViewCoursework
+			m.updateViewport(m.renderCoursework())
+			return m, m.leaveThumbnails()
+		case ViewAnnouncementDetail:
+			m.PreviousView = m.CurrentView
+			m.CurrentView = ViewAnnouncements
+			m.updateViewport(m.renderAnnouncements())
+			return m, m.leaveThumbnails()
+		}
 		if m.CurrentView != ViewMainMenu {
+			m.cancelLoad()
+			m.cancelLogin()
 			m.PreviousView = m.CurrentView
 			m.CurrentView = ViewMainMenu
 		}
@@ -394,11 +673,28 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case ViewCourses, ViewCoursework, ViewGrades, ViewAnnouncements:
 		return m.handleContentKey(msg)
 
+	case ViewCourseworkDetail, ViewAnnouncementDetail:
+		switch {
+		case key.Matches(msg, keys.Favorite):
+			m.toggleCurrentFavorite()
+		case key.Matches(msg, keys.Yank):
+			m.yank(false)
+		case key.Matches(msg, keys.YankLink):
+			m.yank(true)
+		case key.Matches(msg, keys.Save):
+			m.saveMarkdown()
+		case key.Matches(msg, keys.Submit) && m.CurrentView == ViewCourseworkDetail:
+			return m.openSubmit()
+		}
+		return m, nil
+
 	case ViewAuthRequired:
 		if key.Matches(msg, keys.Select) {
-			m.PreviousView = m.CurrentView
-			m.CurrentView = ViewMainMenu
+			return m.beginLogin()
 		}
+
+	case ViewError:
+		return m.handleErrorKey(msg)
 	}
 
 	return m, nil
@@ -446,31 +742,31 @@ func (m Model) selectMenuItem() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	switch menuItem.view {
-	case ViewCourses:
-		m.PreviousView = m.CurrentView
-		m.CurrentView = ViewCourses
-		m.loadCourses()
-	case ViewCoursework:
-		m.PreviousView = m.CurrentView
-		m.CurrentView = ViewCoursework
-		m.loadCoursework()
-	case ViewGrades:
-		m.PreviousView = m.CurrentView
-		m.CurrentView = ViewGrades
-		m.loadGrades()
-	case ViewAnnouncements:
+	if menuItem.view != ViewMainMenu {
+		m.recordVisit(store.Visit{Kind: "view", ID: viewNames[menuItem.view], Label: menuItem.title})
+	}
+
+	return m.openView(menuItem.view)
+}
+
+func (m Model) openView(view ViewType) (tea.Model, tea.Cmd) {
+	switch view {
+	case ViewCourses, ViewCoursework, ViewGrades, ViewAnnouncements:
 		m.PreviousView = m.CurrentView
-		m.CurrentView = ViewAnnouncements
-		m.loadAnnouncements()
+		m.CurrentView = view
+		return m, m.loadView(view)
 	case ViewMainMenu:
 		return m, tea.Quit
 	}
-
 	return m, nil
 }
 
 func (m Model) handleContentKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Until the view has loaded, the items on hand are the last load's.
+	if m.IsLoading && !key.Matches(msg, keys.Refresh) {
+		return m, nil
+	}
+
 	if m.CurrentView == ViewCoursework {
 		if key.Matches(msg, keys.Up) {
 			if m.SelectedCoursework > 0 {
@@ -486,20 +782,39 @@ func (m Model) handleContentKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.Viewport.SetContent(m.renderCoursework())
 			return m, nil
 		}
+		if key.Matches(msg, keys.Select) && len(m.Coursework) > 0 {
+			return m, m.openCourseworkDetail()
+		}
+		if key.Matches(msg, keys.Submit) && len(m.Coursework) > 0 {
+			return m.openSubmit()
+		}
 	}
 
-	if key.Matches(msg, keys.Refresh) {
-		switch m.CurrentView {
-		case ViewCourses:
-			m.loadCourses()
-		case ViewCoursework:
-			m.loadCoursework()
-		case ViewGrades:
-			m.loadGrades()
-		case ViewAnnouncements:
-			m.loadAnnouncements()
+	if m.CurrentView == ViewAnnouncements {
+		if key.Matches(msg, keys.Up) {
+			if m.SelectedAnnouncement > 0 {
+				m.SelectedAnnouncement--
+			}
+			m.Viewport.SetContent(m.renderAnnouncements())
+			return m, nil
 		}
-		return m, nil
+		if key.Matches(msg, keys.Down) {
+			if m.SelectedAnnouncement < len(m.Announcements)-1 {
+				m.SelectedAnnouncement++
+			}
+			m.Viewport.SetContent(m.renderAnnouncements())
+			return m, nil
+		}
+		if key.Matches(msg, keys.Select) && len(m.Announcements) > 0 {
+			return m, m.openAnnouncementDetail()
+		}
+		if key.Matches(msg, keys.Compose) {
+			return m.openCompose()
+		}
+	}
+
+	if key.Matches(msg, keys.Refresh) {
+		return m, m.loadView(m.CurrentView)
 	}
 
 	return m, nil
@@ -507,7 +822,7 @@ func (m Model) handleContentKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	if m.CurrentView == ViewMainMenu && msg.Type == tea.MouseLeft {
-		menuHeight := m.Height - 6
+		menuHeight := m.bodyHeight()
 		itemHeight := 3
 		firstItemY := 2
 
@@ -523,55 +838,50 @@ func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) loadCourses() {
-	if m.AuthState != AuthAuthenticated {
-		m.CurrentView = ViewAuthRequired
-		m.ErrorMsg = "Please authenticate first using 'gc-cli auth login'"
-		return
+// showCourses fills the courses view with loaded courses.
+func (m *Model) showCourses(courses []CourseItem) {
+	m.Courses = courses
+	for i := range m.Courses {
+		m.Courses[i].Teaching = m.Roles.Courses[m.Courses[i].ID].Role == store.RoleTeacher
+		m.Courses[i].Period = m.Order.Period(m.Courses[i].ID, m.Courses[i].Name)
 	}
-
-	m.IsLoading = true
-	m.LoadingMsg = "Loading courses..."
-
-	time.Sleep(500 * time.Millisecond)
-
-	m.Courses = []CourseItem{
-		{ID: "course-1", Name: "CS 101: Introduction to Computer Science", Section: "Fall 2024", Desc: "Fundamental concepts of programming", Room: "Building A, Room 101"},
-		{ID: "course-2", Name: "MATH 201: Linear Algebra", Section: "Fall 2024", Desc: "Vector spaces, linear transformations", Room: "Building B, Room 205"},
-		{ID: "course-3", Name: "PHYS 150: General Physics I", Section: "Fall 2024", Desc: "Mechanics, thermodynamics, waves", Room: "Science Building, Room 302"},
-	}
-
-	m.IsLoading = false
+	m.sortCourses(m.Courses)
 	m.updateViewport(m.renderCourses())
 }
 
-func (m *Model) loadCoursework() {
-	if m.AuthState != AuthAuthenticated {
-		m.CurrentView = ViewAuthRequired
-		m.ErrorMsg = "Please authenticate first using 'gc-cli auth login'"
-		return
-	}
-
-	m.IsLoading = true
-	m.LoadingMsg = "Loading coursework..."
-
-	time.Sleep(500 * time.Millisecond)
-
-	m.Coursework = []CourseworkItem{
-		{ID: "cw-1", CourseID: "course-1", CourseName: "CS 101", AssignTitle: "Programming Assignment 1", Desc: "Implement a basic calculator", State: "PUBLISHED", DueDate: "2024-09-15", DueTime: "23:59", Points: 100, Status: StatusReturned, WorkType: "ASSIGNMENT"},
-		{ID: "cw-2", CourseID: "course-1", CourseName: "CS 101", AssignTitle: "Quiz 1: Variables and Data Types", Desc: "Online quiz on data types", State: "PUBLISHED", DueDate: "2024-09-20", DueTime: "23:59", Points: 20, Status: StatusReturned, WorkType: "QUIZ"},
-		{ID: "cw-3", CourseID: "course-1", CourseName: "CS 101", AssignTitle: "Programming Assignment 2", Desc: "OOP concepts", State: "PUBLISHED", DueDate: "2024-10-15", DueTime: "23:59", Points: 100, Status: StatusTurnedIn, WorkType: "ASSIGNMENT"},
-		{ID: "cw-4", CourseID: "course-2", CourseName: "MATH 201", AssignTitle: "Homework 1: Vectors", Desc: "Problems from Chapter 1", State: "PUBLISHED", DueDate: "2024-09-18", DueTime: "23:59", Points: 50, Status: StatusReturned, WorkType: "ASSIGNMENT"},
-		{ID: "cw-5", CourseID: "course-2", CourseName: "MATH 201", AssignTitle: "Homework 2: Matrices", Desc: "Problems from Chapter 2", State: "PUBLISHED", DueDate: "2024-09-25", DueTime: "23:59", Points: 50, Status: StatusTurnedIn, WorkType: "ASSIGNMENT"},
-		{ID: "cw-6", CourseID: "course-3", CourseName: "PHYS 150", AssignTitle: "Lab Report 1: Motion", Desc: "Motion experiment writeup", State: "PUBLISHED", DueDate: "2024-09-22", DueTime: "17:00", Points: 50, Status: StatusReturned, WorkType: "ASSIGNMENT"},
-		{ID: "cw-7", CourseID: "course-2", CourseName: "MATH 201", AssignTitle: "Midterm Exam", Desc: "Covers chapters 1-3", State: "PUBLISHED", DueDate: "2024-10-01", DueTime: "14:00", Points: 100, Status: StatusOverdue, WorkType: "EXAM"},
-		{ID: "cw-8", CourseID: "course-1", CourseName: "CS 101", AssignTitle: "Lab 3: Debugging", Desc: "Debugging practice", State: "DRAFT", DueDate: "", DueTime: "", Points: 25, Status: StatusDraft, WorkType: "ASSIGNMENT"},
-	}
-
+// showCoursework fills the classwork view and reports whether any of it is
+// newly due within the warning window, so the bell can ring for it.
+func (m *Model) showCoursework(coursework []CourseworkItem) bool {
+	m.Coursework = coursework
+	m.hideTeacherOnlyCoursework()
+	fresh := m.checkDeadlines(m.Clock.Now())
 	m.SelectedCoursework = 0
 	m.sortCourseworkByDueDate()
-	m.IsLoading = false
+	m.updateMenuCounts()
 	m.updateViewport(m.renderCoursework())
+	return fresh
+}
+
+// hideTeacherOnlyCoursework drops drafts and scheduled items from courses I'm
+// known to attend as a student; only teachers can act on them.
+func (m *Model) hideTeacherOnlyCoursework() {
+	visible := m.Coursework[:0]
+	for _, cw := range m.Coursework {
+		student := m.Roles.Courses[cw.CourseID].Role == store.RoleStudent
+		if student && (cw.Status == StatusDraft || cw.Status == StatusScheduled) {
+			continue
+		}
+		visible = append(visible, cw)
+	}
+	m.Coursework = visible
+}
+
+// sortCourses applies the configured course order, keeping the existing
+// order among unlisted courses.
+func (m Model) sortCourses(courses []CourseItem) {
+	sort.SliceStable(courses, func(i, j int) bool {
+		return m.Order.Rank(courses[i].ID, courses[i].Name) < m.Order.Rank(courses[j].ID, courses[j].Name)
+	})
 }
 
 func (m *Model) sortCourseworkByDueDate() {
@@ -585,54 +895,22 @@ func (m *Model) sortCourseworkByDueDate() {
 		if m.Coursework[j].DueDate == "" {
 			return true
 		}
+		if m.Coursework[i].DueDate == m.Coursework[j].DueDate {
+			return m.Order.Rank(m.Coursework[i].CourseID, m.Coursework[i].CourseName) < m.Order.Rank(m.Coursework[j].CourseID, m.Coursework[j].CourseName)
+		}
 		return m.Coursework[i].DueDate < m.Coursework[j].DueDate
 	})
 }
 
-func (m *Model) loadGrades() {
-	if m.AuthState != AuthAuthenticated {
-		m.CurrentView = ViewAuthRequired
-		m.ErrorMsg = "Please authenticate first using 'gc-cli auth login'"
-		return
-	}
-
-	m.IsLoading = true
-	m.LoadingMsg = "Loading grades..."
-
-	time.Sleep(500 * time.Millisecond)
-
-	m.Grades = []GradeItem{
-		{CourseName: "CS 101", Assignment: "Programming Assignment 1", Score: "95", MaxScore: "100", SubmittedAt: "2024-09-15"},
-		{CourseName: "CS 101", Assignment: "Quiz 1", Score: "18", MaxScore: "20", SubmittedAt: "2024-09-20"},
-		{CourseName: "MATH 201", Assignment: "Homework 1", Score: "90", MaxScore: "100", SubmittedAt: "2024-09-18"},
-		{CourseName: "MATH 201", Assignment: "Midterm Exam", Score: "82", MaxScore: "100", SubmittedAt: "2024-10-10"},
-		{CourseName: "PHYS 150", Assignment: "Lab Report 1", Score: "48", MaxScore: "50", SubmittedAt: "2024-09-22"},
-	}
-
-	m.IsLoading = false
+func (m *Model) showGrades(grades []GradeItem) {
+	m.Grades = grades
 	m.updateViewport(m.renderGrades())
 }
 
-func (m *Model) loadAnnouncements() {
-	if m.AuthState != AuthAuthenticated {
-		m.CurrentView = ViewAuthRequired
-		m.ErrorMsg = "Please authenticate first using 'gc-cli auth login'"
-		return
-	}
-
-	m.IsLoading = true
-	m.LoadingMsg = "Loading announcements..."
-
-	time.Sleep(500 * time.Millisecond)
-
-	m.Announcements = []AnnouncementItem{
-		{CourseName: "CS 101", AnnounceTitle: "Assignment 2 Posted", Text: "The second programming assignment has been posted. Due October 15th.", PostedAt: "2024-10-01"},
-		{CourseName: "MATH 201", AnnounceTitle: "Office Hours Change", Text: "Office hours this week will be Thursday 2-4 PM.", PostedAt: "2024-10-02"},
-		{CourseName: "PHYS 150", AnnounceTitle: "Lab Safety Reminder", Text: "Please review lab safety procedures before your session.", PostedAt: "2024-09-28"},
-		{CourseName: "CS 101", AnnounceTitle: "Guest Lecture Next Week", Text: "Guest speaker from Google next Tuesday.", PostedAt: "2024-10-03"},
-	}
-
-	m.IsLoading = false
+func (m *Model) showAnnouncements(announcements []AnnouncementItem) {
+	m.Announcements = announcements
+	m.SelectedAnnouncement = 0
+	m.updateMenuCounts()
 	m.updateViewport(m.renderAnnouncements())
 }
 
@@ -641,6 +919,10 @@ func (m *Model) updateViewport(content string) {
 }
 
 func (m Model) View() string {
+	if m.tooSmall() {
+		return m.renderTooSmall()
+	}
+
 	var content string
 
 	switch m.CurrentView {
@@ -675,6 +957,15 @@ func (m Model) View() string {
 			content = m.Viewport.View()
 		}
 
+	case ViewCourseworkDetail, ViewAnnouncementDetail:
+		content = m.Viewport.View()
+
+	case ViewCompose:
+		content = m.renderCompose()
+
+	case ViewSubmit:
+		content = m.renderSubmit()
+
 	case ViewAuthRequired:
 		content = m.renderAuthRequired()
 
@@ -685,15 +976,20 @@ func (m Model) View() string {
 		content = m.renderError()
 	}
 
+	if m.PaletteOpen {
+		content = m.renderPalette()
+	}
+
 	header := m.renderHeader()
 	statusBar := m.renderStatusBar()
 
-	output := lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		content,
-		statusBar,
-	)
+	sections := []string{header, content}
+	if m.Banner != "" {
+		sections = append(sections, m.renderBanner())
+	}
+	sections = append(sections, statusBar)
+
+	output := lipgloss.JoinVertical(lipgloss.Left, sections...)
 
 	return windowStyle.Height(m.Height).Render(output)
 }
@@ -712,6 +1008,14 @@ func (m Model) renderHeader() string {
 		title = " Grades "
 	case ViewAnnouncements:
 		title = " Announcements "
+	case ViewCourseworkDetail:
+		title = " Assignment "
+	case ViewAnnouncementDetail:
+		title = " Announcement "
+	case ViewCompose:
+		title = " New Announcement "
+	case ViewSubmit:
+		title = " Turn In "
 	case ViewAuthRequired:
 		title = " Authentication Required "
 	case ViewLoading:
@@ -730,7 +1034,7 @@ func (m Model) renderMainMenu() string {
 
 	menuBorder := borderStyle.
 		Width(m.Width - 4).
-		Height(m.Height - 6).
+		Height(m.bodyHeight()).
 		Render(menuView)
 
 	return menuBorder
@@ -738,17 +1042,17 @@ func (m Model) renderMainMenu() string {
 
 func (m Model) renderCourses() string {
 	if len(m.Courses) == 0 {
-		return contentStyle.Width(m.Width - 4).Height(m.Height - 6).Render(
+		return m.contentBox().Height(m.bodyHeight()).Render(
 			"\n\n\n" + lipgloss.NewStyle().
 				Foreground(textMuted).
 				Align(lipgloss.Center).
-				Width(m.Width-8).
+				Width(m.innerWidth()).
 				Render("No courses found"),
 		)
 	}
 
 	var output string
-	output += sectionTitleStyle.Width(m.Width-8).Render("Your Courses") + "\n\n"
+	output += sectionTitleStyle.Width(m.innerWidth()).Render("Your Courses") + "\n\n"
 
 	for i, course := range m.Courses {
 		courseNum := lipgloss.NewStyle().
@@ -756,10 +1060,14 @@ func (m Model) renderCourses() string {
 			Bold(true).
 			Render(fmt.Sprintf("%d.", i+1))
 
+		name := course.Name
+		if course.Period != "" {
+			name = course.Period + ": " + name
+		}
 		courseName := lipgloss.NewStyle().
 			Foreground(textPrimary).
 			Bold(true).
-			Render(course.Name)
+			Render(name)
 
 		section := lipgloss.NewStyle().
 			Foreground(accentTertiary).
@@ -773,30 +1081,43 @@ func (m Model) renderCourses() string {
 			Foreground(textMuted).
 			Render("📍 " + course.Room)
 
+		if course.Teaching {
+			courseName += lipgloss.NewStyle().
+				Foreground(accentSecondary).
+				Render("  (teaching)")
+		}
+
+		if m.compact() {
+			// The description is what's left out.
+			output += fmt.Sprintf("%s %s (%s)\n   %s\n\n", courseNum, courseName, section, room)
+			continue
+		}
 		output += fmt.Sprintf("%s %s (%s)\n%s\n%s\n\n", courseNum, courseName, section, desc, room)
 	}
 
-	return contentStyle.Width(m.Width - 4).Render(output)
+	return m.contentBox().Render(output)
 }
 
 func (m Model) renderCoursework() string {
 	if len(m.Coursework) == 0 {
-		return contentStyle.Width(m.Width - 4).Height(m.Height - 6).Render(
+		return m.contentBox().Height(m.bodyHeight()).Render(
 			"\n\n\n" + lipgloss.NewStyle().
 				Foreground(textMuted).
 				Align(lipgloss.Center).
-				Width(m.Width-8).
+				Width(m.innerWidth()).
 				Render("No assignments found"),
 		)
 	}
 
 	var output string
-	output += sectionTitleStyle.Width(m.Width-8).Render("Your Assignments") + "\n\n"
+	output += sectionTitleStyle.Width(m.innerWidth()).Render("Your Assignments") + "\n\n"
 
-	output += lipgloss.NewStyle().
-		Foreground(textMuted).
-		Width(m.Width-8).
-		Render("✓ RETURNED  ◐ TURNED_IN  ✗ OVERDUE  ○ NEW") + "\n\n"
+	if !m.compact() {
+		output += lipgloss.NewStyle().
+			Foreground(textMuted).
+			Width(m.innerWidth()).
+			Render("✓ RETURNED  ◐ TURNED_IN  ✗ MISSING  ○ NEW") + "\n\n"
+	}
 
 	for i, cw := range m.Coursework {
 		isSelected := i == m.SelectedCoursework
@@ -809,12 +1130,12 @@ func (m Model) renderCoursework() string {
 				Padding(1, 1).
 				Border(lipgloss.RoundedBorder()).
 				BorderForeground(accentPrimary).
-				Width(m.Width - 8)
+				Width(m.innerWidth() - 2)
 		} else {
 			itemStyle = lipgloss.NewStyle().
 				Foreground(textPrimary).
 				Padding(1, 1).
-				Width(m.Width - 8)
+				Width(m.innerWidth())
 		}
 
 		entryNum := lipgloss.NewStyle().
@@ -825,7 +1146,7 @@ func (m Model) renderCoursework() string {
 		title := lipgloss.NewStyle().
 			Foreground(textPrimary).
 			Bold(true).
-			Render(cw.Title())
+			Render(cw.Title()) + m.unreadBadge(cw.ID)
 
 		course := lipgloss.NewStyle().
 			Foreground(accentTertiary).
@@ -840,12 +1161,15 @@ func (m Model) renderCoursework() string {
 		case StatusTurnedIn:
 			statusColor = warningColor
 			statusIcon = "◐"
-		case StatusOverdue:
+		case StatusMissing:
 			statusColor = errorColor
 			statusIcon = "✗"
 		case StatusDraft:
 			statusColor = textMuted
 			statusIcon = "○"
+		case StatusScheduled:
+			statusColor = accentTertiary
+			statusIcon = "◷"
 		default:
 			statusColor = textSecondary
 			statusIcon = "○"
@@ -867,38 +1191,67 @@ func (m Model) renderCoursework() string {
 		due := lipgloss.NewStyle().
 			Foreground(textSecondary).
 			Render("Due: " + dueDate)
+		if opens := cw.opensIn(m.Clock.Now()); opens != "" {
+			due += lipgloss.NewStyle().
+				Foreground(accentTertiary).
+				Render("  •  Opens " + opens)
+		}
 
 		points := lipgloss.NewStyle().
 			Foreground(textMuted).
 			Render(fmt.Sprintf("%d pts", cw.Points))
 
-		workType := lipgloss.NewStyle().
+		expects := lipgloss.NewStyle().
 			Foreground(textMuted).
-			Render(cw.WorkType)
+			Render(cw.Expects.Label())
+
+		if m.compact() {
+			// One line of details, without points and type, and the
+			// selection marked by a highlight rather than a box.
+			rowStyle := lipgloss.NewStyle().Foreground(textPrimary).Width(m.innerWidth())
+			if isSelected {
+				rowStyle = rowStyle.Background(bgHighlight)
+				entryNum = lipgloss.NewStyle().
+					Foreground(accentSecondary).
+					Bold(true).
+					Render(fmt.Sprintf("▶ %d.", i+1))
+			}
+			output += rowStyle.Render(fmt.Sprintf("%s %s\n  %s  •  %s  •  %s\n  %s", entryNum, title, course, status, cw.Expects.Icon(), due)) + "\n\n"
+			continue
+		}
 
 		content := fmt.Sprintf("%s %s\n  %s  •  %s  •  %s\n  %s  •  %s",
-			entryNum, title, course, status, due, points, workType)
+			entryNum, title, course, status, due, points, expects)
+		if progress := m.subtaskProgress(cw.ID); progress != "" {
+			content += "  •  " + progress
+		}
 
 		output += itemStyle.Render(content) + "\n\n"
 	}
 
-	return contentStyle.Width(m.Width - 4).Render(output)
+	return m.contentBox().Render(output)
 }
 
 func (m Model) renderGrades() string {
 	if len(m.Grades) == 0 {
-		return contentStyle.Width(m.Width - 4).Height(m.Height - 6).Render(
+		return m.contentBox().Height(m.bodyHeight()).Render(
 			"\n\n\n" + lipgloss.NewStyle().
 				Foreground(textMuted).
 				Align(lipgloss.Center).
-				Width(m.Width-8).
+				Width(m.innerWidth()).
 				Render("No grades found"),
 		)
 	}
 
+	scale := gradebook.DefaultScale
+	if m.Config != nil {
+		scale = gradebook.NewScale(m.Config.Grades.Scale)
+	}
+
 	var output string
-	output += sectionTitleStyle.Width(m.Width-8).Render("Your Grades") + "\n\n"
+	output += sectionTitleStyle.Width(m.innerWidth()).Render("Your Grades") + "\n\n"
 
+	var totalEarned, totalPossible float64
 	for i, grade := range m.Grades {
 		entryNum := lipgloss.NewStyle().
 			Foreground(accentPrimary).
@@ -921,34 +1274,55 @@ func (m Model) renderGrades() string {
 			scoreColor = errorColor
 		}
 
+		scoreText := fmt.Sprintf("%s/%s", grade.Score, grade.MaxScore)
+		earned, errEarned := strconv.ParseFloat(grade.Score, 64)
+		possible, errPossible := strconv.ParseFloat(grade.MaxScore, 64)
+		if errEarned == nil && errPossible == nil && possible > 0 {
+			scoreText += fmt.Sprintf(" (%s)", scale.Letter(earned/possible*100))
+			totalEarned += earned
+			totalPossible += possible
+		}
+
 		score := lipgloss.NewStyle().
 			Foreground(scoreColor).
 			Bold(true).
-			Render(fmt.Sprintf("%s/%s", grade.Score, grade.MaxScore))
+			Render(scoreText)
 
 		submitted := lipgloss.NewStyle().
 			Foreground(textMuted).
 			Render("Submitted: " + grade.SubmittedAt)
 
+		if m.compact() {
+			output += fmt.Sprintf("%s %s\n  %s — %s\n\n", entryNum, assignment, course, score)
+			continue
+		}
 		output += fmt.Sprintf("%s %s\n  %s — %s\n  %s\n\n", entryNum, assignment, course, score, submitted)
 	}
 
-	return contentStyle.Width(m.Width - 4).Render(output)
+	if totalPossible > 0 {
+		pct := totalEarned / totalPossible * 100
+		output += lipgloss.NewStyle().
+			Foreground(accentTertiary).
+			Bold(true).
+			Render(fmt.Sprintf("Overall: %.1f/%.0f  •  %.1f%%  •  %s", totalEarned, totalPossible, pct, scale.Letter(pct))) + "\n"
+	}
+
+	return m.contentBox().Render(output)
 }
 
 func (m Model) renderAnnouncements() string {
 	if len(m.Announcements) == 0 {
-		return contentStyle.Width(m.Width - 4).Height(m.Height - 6).Render(
+		return m.contentBox().Height(m.bodyHeight()).Render(
 			"\n\n\n" + lipgloss.NewStyle().
 				Foreground(textMuted).
 				Align(lipgloss.Center).
-				Width(m.Width-8).
+				Width(m.innerWidth()).
 				Render("No announcements found"),
 		)
 	}
 
 	var output string
-	output += sectionTitleStyle.Width(m.Width-8).Render("Course Announcements") + "\n\n"
+	output += sectionTitleStyle.Width(m.innerWidth()).Render("Course Announcements") + "\n\n"
 
 	for i, ann := range m.Announcements {
 		annNum := lipgloss.NewStyle().
@@ -956,10 +1330,17 @@ func (m Model) renderAnnouncements() string {
 			Bold(true).
 			Render(fmt.Sprintf("%d.", i+1))
 
+		if i == m.SelectedAnnouncement {
+			annNum = lipgloss.NewStyle().
+				Foreground(accentSecondary).
+				Bold(true).
+				Render(fmt.Sprintf("▶ %d.", i+1))
+		}
+
 		title := lipgloss.NewStyle().
 			Foreground(textPrimary).
 			Bold(true).
-			Render(ann.Title())
+			Render(ann.Title()) + m.unreadBadge(ann.ID)
 
 		course := lipgloss.NewStyle().
 			Foreground(accentTertiary).
@@ -971,13 +1352,27 @@ func (m Model) renderAnnouncements() string {
 
 		text := lipgloss.NewStyle().
 			Foreground(textSecondary).
-			Width(m.Width - 12).
+			Width(m.innerWidth() - 4).
 			Render(ann.Text)
 
-		output += fmt.Sprintf("%s %s\n  📚 %s — %s\n\n%s\n\n", annNum, title, course, date, text)
+		author := ""
+		switch {
+		case ann.Author == "":
+		case m.compact():
+			// The author gets a line of their own, without the avatar.
+			author = "\n  " + lipgloss.NewStyle().
+				Foreground(textSecondary).
+				Render(ann.Author)
+		default:
+			author = " — " + m.avatarBadge(ann.AuthorID, ann.Author) + " " + lipgloss.NewStyle().
+				Foreground(textSecondary).
+				Render(ann.Author)
+		}
+
+		output += fmt.Sprintf("%s %s\n  📚 %s — %s%s\n\n%s\n\n", annNum, title, course, date, author, text)
 	}
 
-	return contentStyle.Width(m.Width - 4).Render(output)
+	return m.contentBox().Render(output)
 }
 
 func (m Model) renderLoading() string {
@@ -986,14 +1381,14 @@ func (m Model) renderLoading() string {
 		Bold(true).
 		Align(lipgloss.Center).
 		Width(m.Width - 8).
-		Render("⟳ " + m.LoadingMsg)
+		Render(m.Spinner.View() + " " + m.LoadingMsg)
 
 	return lipgloss.Place(
 		m.Width-4,
-		m.Height-6,
+		m.bodyHeight(),
 		lipgloss.Center,
 		lipgloss.Center,
-		loadingStyle.Width(m.Width-4).Height(m.Height-6).Render(loadingContent),
+		loadingStyle.Width(m.Width-4).Height(m.bodyHeight()).Render(loadingContent),
 	)
 }
 
@@ -1005,65 +1400,60 @@ func (m Model) renderError() string {
 		Width(m.Width - 8).
 		Render("⚠ " + m.ErrorMsg)
 
+	if actions := m.errorActions(); actions != "" {
+		errorContent = lipgloss.JoinVertical(
+			lipgloss.Center,
+			errorContent,
+			"\n",
+			lipgloss.NewStyle().
+				Foreground(textSecondary).
+				Align(lipgloss.Center).
+				Width(m.Width-8).
+				Render(actions),
+		)
+	}
+
 	return lipgloss.Place(
 		m.Width-4,
-		m.Height-6,
+		m.bodyHeight(),
 		lipgloss.Center,
 		lipgloss.Center,
-		errorStyle.Width(m.Width-4).Height(m.Height-6).Render(errorContent),
+		errorStyle.Width(m.Width-4).Height(m.bodyHeight()).Render(errorContent),
 	)
 }
 
-func (m Model) renderAuthRequired() string {
-	title := lipgloss.NewStyle().
-		Foreground(accentSecondary).
-		Bold(true).
-		Width(m.Width - 8).
-		Align(lipgloss.Center).
-		Render("🔒 Authentication Required")
-
-	message := lipgloss.NewStyle().
-		Foreground(textSecondary).
-		Width(m.Width - 8).
-		Align(lipgloss.Center).
-		Render("Please authenticate first using:\n\n  gc-cli auth login\n\nThen run 'gc-cli tui' again.")
-
-	hint := lipgloss.NewStyle().
-		Foreground(textMuted).
-		Width(m.Width - 8).
-		Align(lipgloss.Center).
-		Render("Press ESC or ← to go back")
-
-	content := lipgloss.NewStyle().
-		Width(m.Width-4).
-		Height(m.Height-6).
-		Background(bgSecondary).
-		Padding(2, 0).
-		Render(
-			lipgloss.JoinVertical(
-				lipgloss.Center,
-				"\n\n\n",
-				title,
-				"\n",
-				message,
-				"\n\n\n",
-				hint,
-			),
-		)
-
-	return content
-}
-
 func (m Model) renderStatusBar() string {
 	var status string
 
-	switch m.CurrentView {
-	case ViewMainMenu:
-		status = "↑↓/jk: navigate  •  enter/l: select  •  q: quit"
-	case ViewCourses, ViewCoursework, ViewGrades, ViewAnnouncements:
+	switch {
+	case m.Notice != "":
+		status = m.Notice
+	case m.PaletteOpen:
+		status = "↑↓/jk: select  •  enter: jump  •  f: pin/unpin  •  esc: close"
+	case m.CurrentView == ViewMainMenu:
+		status = "↑↓/jk: navigate  •  enter/l: select  •  ctrl+p: jump  •  q: quit"
+	case m.CurrentView == ViewCompose:
+		status = "tab: next field  •  ctrl+r: preview  •  ctrl+s: post  •  esc: discard"
+	case m.CurrentView == ViewSubmit && m.Submitter.Running:
+		status = "esc: stop (finish later with 'gc-cli submit journal')"
+	case m.CurrentView == ViewSubmit:
+		status = "tab: complete path  •  enter: upload and turn in  •  esc: cancel"
+	case m.CurrentView == ViewCoursework:
+		status = "↑↓/jk: select  •  enter: open  •  s: submit  •  r: refresh  •  esc/q: back"
+	case m.CurrentView == ViewAnnouncements && m.teaches():
+		status = "↑↓/jk: select  •  enter: open  •  n: new  •  r: refresh  •  esc/q: back"
+	case m.CurrentView == ViewAnnouncements:
+		status = "↑↓/jk: select  •  enter: open  •  r: refresh  •  esc/q: back"
+	case m.CurrentView == ViewCourses, m.CurrentView == ViewGrades:
 		status = "↑↓/jk: scroll  •  r: refresh  •  esc/q: back"
-	case ViewAuthRequired:
-		status = "esc: go back"
+	case m.CurrentView == ViewCourseworkDetail:
+		status = "↑↓/jk: scroll  •  s: submit  •  y/Y: copy text/link  •  S: save  •  f: pin  •  esc: back"
+	case m.CurrentView == ViewAnnouncementDetail:
+		status = "↑↓/jk: scroll  •  y/Y: copy text/link  •  S: save  •  f: pin  •  esc: back"
+	case m.CurrentView == ViewAuthRequired:
+		status = "enter: sign in  •  esc: go back"
+	case m.CurrentView == ViewError:
+		status = "esc/q: back"
 	default:
 		status = "q: quit"
 	}
@@ -1072,37 +1462,78 @@ func (m Model) renderStatusBar() string {
 	if m.AuthState == AuthAuthenticated {
 		authStatus = "✓ Logged in"
 	}
+	if m.Offline {
+		authStatus = "✓ Offline (last sync)"
+	}
 
-	authStyle := statusBarStyle
+	authStyle := statusBarStyle.Copy()
 	if m.AuthState == AuthAuthenticated {
 		authStyle = authStyle.Foreground(successColor)
 	} else {
 		authStyle = authStyle.Foreground(warningColor)
 	}
 
-	statusBar := lipgloss.NewStyle().
-		Width(m.Width).
-		Render(
-			lipgloss.JoinHorizontal(
-				lipgloss.Left,
-				statusBarStyle.Width(m.Width-len(authStatus)-3).Render(status),
-				authStyle.Render(authStatus),
-			),
-		)
+	// Compact status bars keep only the keys, cut to fit.
+	if m.compact() {
+		return statusBarStyle.Copy().Padding(0, 1).Width(m.Width - 2).Render(truncateBanner(status, m.Width-4))
+	}
+
+	auth := authStyle.Render(authStatus)
+	width := m.Width - 2 - lipgloss.Width(auth)
+	statusBar := lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		statusBarStyle.Copy().Width(width).Render(truncateBanner(status, width-4)),
+		auth,
+	)
 
 	return statusBar
 }
 
-func Run(cfg *config.Config) error {
+// Options connects the TUI to the rest of gc-cli.
+type Options struct {
+	// NewClient is used for actions that write to Classroom, such as
+	// posting announcements, and Submit for turning work in.
+	NewClient ClientFunc
+	Submit    SubmitFunc
+	// OfflineClient reads from the last 'gc-cli sync', offered when a
+	// view fails to load.
+	OfflineClient ClientFunc
+	// Demo shows made-up data without needing to sign in.
+	Demo bool
+	// Clock replaces the wall clock for due dates, for --now.
+	Clock clock.Clock
+}
+
+// Run starts the TUI.
+func Run(cfg *config.Config, opts Options) error {
+	m := New(cfg)
+	m.NewClient = opts.NewClient
+	m.Submit = opts.Submit
+	m.OfflineClient = opts.OfflineClient
+	if opts.Clock != nil {
+		m.Clock = opts.Clock
+	}
+	if opts.Demo {
+		m.AuthState = AuthAuthenticated
+		m.CurrentView = ViewMainMenu
+	}
+	restore := cfg != nil && cfg.TUI.RestoreState
+	if restore {
+		m.ResumeCmd = m.restoreState()
+	}
 	p := tea.NewProgram(
-		New(cfg),
+		m,
 		tea.WithAltScreen(),
 	)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running TUI: %v\n", err)
 		return err
 	}
+	if last, ok := final.(Model); ok && restore {
+		last.saveState()
+	}
 
 	return nil
 }
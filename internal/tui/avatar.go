@@ -0,0 +1,22 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/avatar"
+)
+
+var initialsStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(bgPrimary).
+	Background(accentTertiary)
+
+// avatarBadge shows a cached profile photo as a two-cell avatar, or the
+// person's initials when there's no photo or the terminal can't draw one.
+func (m Model) avatarBadge(userID, name string) string {
+	if m.Avatars != nil && userID != "" {
+		if img, ok := m.Avatars.Cached(userID); ok {
+			return avatar.Badge(img, name, true)
+		}
+	}
+	return initialsStyle.Render(avatar.Badge(nil, name, false))
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+)
+
+// fetchCourses lists my active courses.
+func fetchCourses(ctx context.Context, newClient ClientFunc) ([]CourseItem, error) {
+	client, err := newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	courses, _, err := client.ListCourses(ctx, 0, api.WithCourseStates(api.CourseActive))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]CourseItem, len(courses))
+	for i, c := range courses {
+		items[i] = CourseItem{ID: c.ID, Name: c.Name, Section: c.Section, Desc: c.Description, Room: c.Room}
+	}
+	return items, nil
+}
+
+// fetchAgenda gathers the published work of my active courses with my
+// submissions, which both the coursework and grades views are built from.
+func fetchAgenda(ctx context.Context, newClient ClientFunc) ([]agenda.Item, error) {
+	client, err := newClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return agenda.Collect(ctx, client)
+}
+
+func courseworkItems(items []agenda.Item, now time.Time) []CourseworkItem {
+	result := make([]CourseworkItem, len(items))
+	for i, item := range items {
+		cw := item.Work
+		ci := CourseworkItem{
+			ID:          cw.ID,
+			CourseID:    cw.CourseID,
+			CourseName:  item.Course.Name,
+			AssignTitle: cw.Title,
+			Desc:        cw.Description,
+			State:       string(cw.State),
+			Points:      cw.MaxPoints,
+			WorkType:    string(cw.WorkType),
+			Expects:     agenda.ExpectsOf(cw),
+			Link:        cw.AlternateLink,
+			Materials:   materialItems(cw.Materials, cw.AddOnAttachments),
+		}
+		if due := item.Due(); !due.IsZero() {
+			ci.DueDate, ci.DueTime = due.Format("2006-01-02"), due.Format("15:04")
+		}
+		// Classroom flags work that's past due as late; only work turned
+		// in or returned shows it, since missing already says as much.
+		// Work is missing as soon as its due time passes without it being
+		// turned in (or after it was reclaimed), as Classroom shows it.
+		ci.Late = item.Submission != nil && item.Submission.Late
+		switch {
+		case item.Submission != nil && item.Submission.State == api.SubmissionReturned:
+			ci.Status = StatusReturned
+		case item.Done():
+			ci.Status = StatusTurnedIn
+		case !item.Due().IsZero() && item.Due().Before(now):
+			ci.Status = StatusMissing
+		}
+		result[i] = ci
+	}
+	return result
+}
+
+// gradeItems are the graded work among items, most recently returned first.
+func gradeItems(items []agenda.Item) []GradeItem {
+	var grades []GradeItem
+	for _, item := range items {
+		sub := item.Submission
+		if sub == nil || sub.State != api.SubmissionReturned || item.Work.MaxPoints == 0 {
+			continue
+		}
+		grades = append(grades, GradeItem{
+			CourseName:  item.Course.Name,
+			Assignment:  item.Work.Title,
+			Score:       strconv.FormatFloat(sub.AssignedGrade, 'f', -1, 64),
+			MaxScore:    strconv.FormatInt(item.Work.MaxPoints, 10),
+			SubmittedAt: sub.SubmittedTimestamp.Local().Format("2006-01-02"),
+		})
+	}
+	return grades
+}
+
+func materialItems(materials []api.Material, addOns []api.AddOnAttachment) []MaterialItem {
+	var items []MaterialItem
+	for _, m := range materials {
+		switch {
+		case m.YouTubeVideo != nil:
+			items = append(items, MaterialItem{Kind: "youtube", Title: m.YouTubeVideo.Title, URL: m.YouTubeVideo.AlternateLink, ThumbnailURL: m.YouTubeVideo.ThumbnailURL})
+		// Drive and Forms thumbnails need the signed-in user's cookies, so
+		// they're left out rather than fetched only to be refused.
+		case m.DriveFile != nil:
+			f := m.DriveFile.DriveFile
+			items = append(items, MaterialItem{Kind: "drive", Title: f.Title, URL: f.AlternateLink})
+		case m.Form != nil:
+			items = append(items, MaterialItem{Kind: "form", Title: m.Form.Title, URL: m.Form.FormURL})
+		case m.Link != nil:
+			title := m.Link.Title
+			if title == "" {
+				title = m.Link.URL
+			}
+			items = append(items, MaterialItem{Kind: "link", Title: title, URL: m.Link.URL, ThumbnailURL: m.Link.ThumbnailURL})
+		}
+	}
+	for _, a := range addOns {
+		items = append(items, MaterialItem{Kind: "addon", Title: a.Title, URL: a.OpenURL()})
+	}
+	return items
+}
@@ -0,0 +1,323 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const scheduleLayout = "2006-01-02 15:04"
+
+type composeField int
+
+const (
+	fieldBody composeField = iota
+	fieldLinks
+	fieldSchedule
+	fieldCount
+)
+
+// Composer holds a draft announcement. The body is Markdown, posted as
+// written: Classroom shows announcement text as plain text, where Markdown
+// still reads well and HTML tags wouldn't.
+type Composer struct {
+	Courses   []CourseItem
+	CourseIdx int
+
+	Body     textarea.Model
+	Links    textinput.Model
+	Schedule textinput.Model
+	Focus    composeField
+
+	Preview bool
+	Posting bool
+	Err     string
+}
+
+type announcementPostedMsg struct {
+	announcement *api.Announcement
+	scheduled    bool
+	err          error
+}
+
+// ClientFunc creates the client the TUI reads and writes Classroom through.
+type ClientFunc func(ctx context.Context) (api.ClassroomService, error)
+
+func newComposer(courses []CourseItem, width, height int) *Composer {
+	body := textarea.New()
+	body.Placeholder = "Write your announcement (Markdown)..."
+	body.ShowLineNumbers = false
+	body.CharLimit = 0
+	body.SetWidth(width)
+	body.SetHeight(height)
+	body.Focus()
+
+	links := textinput.New()
+	links.Placeholder = "https://... (separate several with spaces)"
+	links.Width = width - 10
+
+	schedule := textinput.New()
+	schedule.Placeholder = "YYYY-MM-DD HH:MM (leave empty to post now)"
+	schedule.Width = width - 10
+
+	return &Composer{Courses: courses, Body: body, Links: links, Schedule: schedule}
+}
+
+// teachingCourses lists courses the role cache says I teach, using loaded
+// course names where available.
+func (m Model) teachingCourses() []CourseItem {
+	names := make(map[string]CourseItem, len(m.Courses))
+	for _, c := range m.Courses {
+		names[c.ID] = c
+	}
+
+	var courses []CourseItem
+	for id, cr := range m.Roles.Courses {
+		if cr.Role != store.RoleTeacher {
+			continue
+		}
+		course, ok := names[id]
+		if !ok {
+			course = CourseItem{ID: id, Name: id}
+		}
+		courses = append(courses, course)
+	}
+	sort.Slice(courses, func(i, j int) bool {
+		return courses[i].Name < courses[j].Name
+	})
+	m.sortCourses(courses)
+	return courses
+}
+
+func (m Model) teaches() bool {
+	teaches, _ := m.Roles.Teaches()
+	return teaches
+}
+
+func (m Model) openCompose() (tea.Model, tea.Cmd) {
+	courses := m.teachingCourses()
+	if len(courses) == 0 {
+		m.Notice = "No courses you teach (run 'gc-cli courses list' to refresh roles)"
+		return m, nil
+	}
+	m.Compose = newComposer(courses, m.Width-8, m.Height-16)
+	m.PreviousView = m.CurrentView
+	m.CurrentView = ViewCompose
+	return m, textarea.Blink
+}
+
+func (c *Composer) focus(field composeField) {
+	c.Body.Blur()
+	c.Links.Blur()
+	c.Schedule.Blur()
+	c.Focus = field
+	switch field {
+	case fieldBody:
+		c.Body.Focus()
+	case fieldLinks:
+		c.Links.Focus()
+	case fieldSchedule:
+		c.Schedule.Focus()
+	}
+}
+
+func (m Model) handleComposeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := m.Compose
+	if c.Posting {
+		return m, nil
+	}
+	c.Err = ""
+
+	switch msg.String() {
+	case "esc":
+		m.Compose = nil
+		m.CurrentView = m.PreviousView
+		m.Notice = "Draft discarded"
+		return m, nil
+	case "tab":
+		c.focus((c.Focus + 1) % fieldCount)
+		return m, nil
+	case "shift+tab":
+		c.focus((c.Focus + fieldCount - 1) % fieldCount)
+		return m, nil
+	case "ctrl+t":
+		c.CourseIdx = (c.CourseIdx + 1) % len(c.Courses)
+		return m, nil
+	case "ctrl+r":
+		c.Preview = !c.Preview
+		return m, nil
+	case "ctrl+s":
+		req, err := c.request(time.Now())
+		if err != nil {
+			c.Err = err.Error()
+			return m, nil
+		}
+		c.Posting = true
+		return m, m.postAnnouncement(c.Courses[c.CourseIdx].ID, req)
+	}
+
+	if c.Preview {
+		return m, nil
+	}
+	return m, c.update(msg)
+}
+
+// update forwards input to the focused field.
+func (c *Composer) update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	switch c.Focus {
+	case fieldBody:
+		c.Body, cmd = c.Body.Update(msg)
+	case fieldLinks:
+		c.Links, cmd = c.Links.Update(msg)
+	case fieldSchedule:
+		c.Schedule, cmd = c.Schedule.Update(msg)
+	}
+	return cmd
+}
+
+func (c *Composer) links() ([]string, error) {
+	fields := strings.FieldsFunc(c.Links.Value(), func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	for _, link := range fields {
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("not a web link: %s", link)
+		}
+	}
+	return fields, nil
+}
+
+func (c *Composer) scheduledAt(now time.Time) (*time.Time, error) {
+	value := strings.TrimSpace(c.Schedule.Value())
+	if value == "" {
+		return nil, nil
+	}
+	at, err := time.ParseInLocation(scheduleLayout, value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("schedule must look like %s", scheduleLayout)
+	}
+	if !at.After(now) {
+		return nil, fmt.Errorf("scheduled time is in the past")
+	}
+	return &at, nil
+}
+
+func (c *Composer) request(now time.Time) (*api.AnnouncementCreate, error) {
+	if strings.TrimSpace(c.Body.Value()) == "" {
+		return nil, fmt.Errorf("announcement is empty")
+	}
+	links, err := c.links()
+	if err != nil {
+		return nil, err
+	}
+	at, err := c.scheduledAt(now)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &api.AnnouncementCreate{Text: c.Body.Value(), State: api.AnnouncementPublished}
+	if at != nil {
+		req.State = api.AnnouncementDraft
+		req.ScheduledTime = at
+	}
+	for _, link := range links {
+		req.Materials = append(req.Materials, api.Material{Link: &api.Link{URL: link}})
+	}
+	return req, nil
+}
+
+func (m Model) postAnnouncement(courseID string, req *api.AnnouncementCreate) tea.Cmd {
+	newClient := m.client()
+	return func() tea.Msg {
+		if newClient == nil {
+			return announcementPostedMsg{err: fmt.Errorf("posting is not available")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		client, err := newClient(ctx)
+		if err != nil {
+			return announcementPostedMsg{err: err}
+		}
+		created, err := client.CreateAnnouncement(ctx, courseID, req)
+		return announcementPostedMsg{announcement: created, scheduled: req.ScheduledTime != nil, err: err}
+	}
+}
+
+func (m Model) handleAnnouncementPosted(msg announcementPostedMsg) (tea.Model, tea.Cmd) {
+	if m.Compose == nil {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.Compose.Posting = false
+		if api.IsForbidden(msg.err) {
+			m.Compose.Err = "Not allowed: only teachers can post, and older sign-ins need 'gc-cli auth login' again"
+		} else {
+			m.Compose.Err = msg.err.Error()
+		}
+		return m, nil
+	}
+
+	course := m.Compose.Courses[m.Compose.CourseIdx].Name
+	m.Compose = nil
+	m.CurrentView = m.PreviousView
+	if msg.scheduled {
+		m.Notice = "Scheduled announcement in " + course
+	} else {
+		m.Notice = "Posted announcement in " + course
+	}
+	return m, nil
+}
+
+var composeLabelStyle = lipgloss.NewStyle().
+	Foreground(textSecondary).
+	Width(10)
+
+func (m Model) renderCompose() string {
+	c := m.Compose
+	var output string
+
+	course := c.Courses[c.CourseIdx]
+	output += composeLabelStyle.Render("Course") + infoValueStyle.Render(course.Name)
+	if len(c.Courses) > 1 {
+		output += lipgloss.NewStyle().Foreground(textMuted).Render("  (ctrl+t: change)")
+	}
+	output += "\n\n"
+
+	if c.Preview {
+		// Classroom shows announcements as plain text, so this is exactly
+		// what gets posted.
+		output += sectionTitleStyle.Render("Preview") + "\n"
+		output += lipgloss.NewStyle().
+			Foreground(textSecondary).
+			Width(m.Width-12).
+			Render(c.Body.Value()) + "\n\n"
+	} else {
+		output += c.Body.View() + "\n\n"
+	}
+
+	output += composeLabelStyle.Render("Links") + c.Links.View() + "\n"
+	output += composeLabelStyle.Render("Schedule") + c.Schedule.View() + "\n"
+
+	switch {
+	case c.Posting:
+		output += "\n" + lipgloss.NewStyle().Foreground(accentPrimary).Render("⟳ Posting...")
+	case c.Err != "":
+		output += "\n" + lipgloss.NewStyle().Foreground(errorColor).Render("✗ "+c.Err)
+	}
+
+	return contentStyle.Width(m.Width - 4).Render(output)
+}
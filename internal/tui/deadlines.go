@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const deadlineCheckInterval = time.Minute
+
+var bannerStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(lipgloss.Color("#1a1a1a")).
+	Background(warningColor).
+	Padding(0, 1)
+
+type deadlineTickMsg time.Time
+
+func deadlineTick() tea.Cmd {
+	return tea.Tick(deadlineCheckInterval, func(t time.Time) tea.Msg {
+		return deadlineTickMsg(t)
+	})
+}
+
+func (m Model) deadlineWarning() time.Duration {
+	if m.Config == nil {
+		return 0
+	}
+	return m.Config.TUI.DeadlineWarning
+}
+
+// dueAt parses the item's due date and time as local time. Items without a
+// due date report false.
+func (c CourseworkItem) dueAt() (time.Time, bool) {
+	if c.DueDate == "" {
+		return time.Time{}, false
+	}
+	clock := c.DueTime
+	if clock == "" {
+		clock = "23:59"
+	}
+	due, err := time.ParseInLocation("2006-01-02 15:04", c.DueDate+" "+clock, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+// checkDeadlines refreshes the banner from the loaded coursework and reports
+// whether anything entered the warning window since the last check.
+func (m *Model) checkDeadlines(now time.Time) bool {
+	// The banner takes its lines from the view below it.
+	defer m.layout()
+	window := m.deadlineWarning()
+	if window <= 0 {
+		m.Banner = ""
+		return false
+	}
+	if m.Warned == nil {
+		m.Warned = make(map[string]bool)
+	}
+
+	var soonest *CourseworkItem
+	var soonestDue time.Time
+	var count int
+	var fresh bool
+	for i := range m.Coursework {
+		cw := &m.Coursework[i]
+		if cw.Status != StatusPending {
+			continue
+		}
+		due, ok := cw.dueAt()
+		if !ok || !due.After(now) || due.Sub(now) > window {
+			continue
+		}
+		count++
+		if !m.Warned[cw.ID] {
+			m.Warned[cw.ID] = true
+			fresh = true
+		}
+		if soonest == nil || due.Before(soonestDue) {
+			soonest = cw
+			soonestDue = due
+		}
+	}
+
+	if soonest == nil {
+		m.Banner = ""
+		return false
+	}
+	m.Banner = fmt.Sprintf("⏰ %s (%s) due in %s", soonest.AssignTitle, soonest.CourseName, formatRemaining(soonestDue.Sub(now)))
+	if count > 1 {
+		m.Banner += fmt.Sprintf(" (+%d more)", count-1)
+	}
+	return fresh
+}
+
+func formatRemaining(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func (m Model) handleDeadlineTick(deadlineTickMsg) (tea.Model, tea.Cmd) {
+	bell := m.bell(m.checkDeadlines(m.Clock.Now()))
+	return m, tea.Batch(deadlineTick(), bell)
+}
+
+// bell rings the terminal bell for work that just entered the warning
+// window, if the bell is turned on.
+func (m Model) bell(fresh bool) tea.Cmd {
+	if !fresh || m.Config == nil || !m.Config.TUI.Bell {
+		return nil
+	}
+	return ringBell
+}
+
+// ringBell writes BEL straight to the terminal; most emulators turn it into
+// a beep or a visual flash depending on the user's settings.
+func ringBell() tea.Msg {
+	fmt.Fprint(os.Stderr, "\a")
+	return nil
+}
+
+func (m Model) renderBanner() string {
+	return bannerStyle.Width(m.Width - 2).Render(truncateBanner(m.Banner, m.Width-4))
+}
+
+func truncateBanner(s string, width int) string {
+	runes := []rune(s)
+	if width <= 3 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-3]) + "..."
+}
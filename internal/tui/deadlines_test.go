@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/clock"
+	"github.com/timboy697/gc-cli/internal/config"
+)
+
+var testNow = time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+
+func agendaItem(id, title string, due time.Time, sub *api.StudentSubmission) agenda.Item {
+	due = due.UTC()
+	return agenda.Item{
+		Course: api.Course{ID: "c1", Name: "Chemistry"},
+		Work: api.CourseWork{
+			ID:       id,
+			CourseID: "c1",
+			Title:    title,
+			State:    api.CourseWorkPublished,
+			DueDate:  &api.Date{Year: due.Year(), Month: int(due.Month()), Day: due.Day()},
+			DueTime:  &api.TimeOfDay{Hours: due.Hour(), Minutes: due.Minute()},
+		},
+		Submission: sub,
+	}
+}
+
+// clockedModel is a model whose clock is pinned to testNow and that warns
+// about work due within two hours.
+func clockedModel() Model {
+	m := New(nil)
+	m.Config = &config.Config{TUI: config.TUIConfig{DeadlineWarning: 2 * time.Hour}}
+	m.Clock = clock.Fixed(testNow)
+	return m
+}
+
+func TestCourseworkItemsMissing(t *testing.T) {
+	items := courseworkItems([]agenda.Item{
+		agendaItem("w1", "Past lab", testNow.Add(-time.Hour), nil),
+		agendaItem("w2", "Next lab", testNow.Add(time.Hour), nil),
+		agendaItem("w3", "Past quiz", testNow.Add(-time.Hour), &api.StudentSubmission{State: api.SubmissionTurnedIn}),
+		agendaItem("w4", "Past essay", testNow.Add(-time.Hour), &api.StudentSubmission{State: api.SubmissionCreated}),
+		agendaItem("w5", "Past poster", testNow.Add(-time.Hour), &api.StudentSubmission{State: api.SubmissionReclaimed}),
+		agendaItem("w6", "Past test", testNow.Add(-time.Hour), &api.StudentSubmission{State: api.SubmissionReturned}),
+	}, testNow)
+
+	want := map[string]CourseworkStatus{"w1": StatusMissing, "w2": StatusPending, "w3": StatusTurnedIn, "w4": StatusMissing, "w5": StatusMissing, "w6": StatusReturned}
+	for _, item := range items {
+		if item.Status != want[item.ID] {
+			t.Errorf("%s: status = %d, want %d", item.AssignTitle, item.Status, want[item.ID])
+		}
+		if item.Status == StatusMissing && !strings.HasPrefix(item.StatusString(), "MISSING") {
+			t.Errorf("%s: shows %q, want MISSING", item.AssignTitle, item.StatusString())
+		}
+	}
+}
+
+func TestDeadlinesFollowTheClock(t *testing.T) {
+	m := clockedModel()
+	m.showCoursework(courseworkItems([]agenda.Item{
+		agendaItem("w1", "Lab report", testNow.Add(90*time.Minute), nil),
+		agendaItem("w2", "Essay", testNow.Add(5*time.Hour), nil),
+		agendaItem("w3", "Quiz", testNow.Add(-time.Hour), nil),
+	}, m.Clock.Now()))
+
+	if want := "Lab report (Chemistry) due in 1h30m"; !strings.Contains(m.Banner, want) {
+		t.Errorf("banner = %q, want it to mention %q", m.Banner, want)
+	}
+	if strings.Contains(m.Banner, "more") {
+		t.Errorf("banner = %q counts work outside the window", m.Banner)
+	}
+
+	// A tick judges the deadlines by the clock, not the time it fired at.
+	m.Clock = clock.Fixed(testNow.Add(2 * time.Hour))
+	updated, _ := m.handleDeadlineTick(deadlineTickMsg(testNow))
+	if banner := updated.(Model).Banner; banner != "" {
+		t.Errorf("banner = %q once the lab is overdue and the essay is 3h away, want none", banner)
+	}
+}
@@ -0,0 +1,194 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var menuDescriptions = map[ViewType]string{
+	ViewCoursework:    "View assignments and deadlines",
+	ViewAnnouncements: "View course announcements",
+}
+
+func (m *Model) openCourseworkDetail() tea.Cmd {
+	cw := m.Coursework[m.SelectedCoursework]
+	m.markRead(cw.ID)
+
+	m.PreviousView = m.CurrentView
+	m.CurrentView = ViewCourseworkDetail
+	if v, ok := m.currentVisit(); ok {
+		m.recordVisit(v)
+	}
+	m.Viewport.GotoTop()
+	m.updateViewport(m.renderCourseworkDetail())
+	return m.loadThumbnails(cw.Materials)
+}
+
+func (m *Model) openAnnouncementDetail() tea.Cmd {
+	ann := m.Announcements[m.SelectedAnnouncement]
+	m.markRead(ann.ID)
+
+	m.PreviousView = m.CurrentView
+	m.CurrentView = ViewAnnouncementDetail
+	if v, ok := m.currentVisit(); ok {
+		m.recordVisit(v)
+	}
+	m.Viewport.GotoTop()
+	m.updateViewport(m.renderAnnouncementDetail())
+	return m.loadThumbnails(ann.Materials)
+}
+
+// markRead records an item as reviewed. Persisting is best effort: a read-only
+// data directory should never break navigation.
+func (m *Model) markRead(id string) {
+	if m.ReadMarkers.IsRead(id) {
+		return
+	}
+	m.ReadMarkers.MarkRead(id, time.Now())
+	if m.Store != nil {
+		_ = m.Store.SaveReadMarkers(m.ReadMarkers)
+	}
+	m.updateMenuCounts()
+}
+
+func (m Model) unreadBadge(id string) string {
+	if m.ReadMarkers.IsRead(id) {
+		return ""
+	}
+	return lipgloss.NewStyle().
+		Foreground(accentSecondary).
+		Bold(true).
+		Render(" ●")
+}
+
+// updateMenuCounts appends unread counts to the main menu entries for the
+// collections that have been loaded so far.
+func (m *Model) updateMenuCounts() {
+	counts := map[ViewType]int{}
+	for _, cw := range m.Coursework {
+		if !m.ReadMarkers.IsRead(cw.ID) {
+			counts[ViewCoursework]++
+		}
+	}
+	for _, ann := range m.Announcements {
+		if !m.ReadMarkers.IsRead(ann.ID) {
+			counts[ViewAnnouncements]++
+		}
+	}
+
+	items := m.Menu.Items()
+	updated := make([]list.Item, len(items))
+	for i, item := range items {
+		menuItem, ok := item.(MenuItem)
+		if ok {
+			if base, tracked := menuDescriptions[menuItem.view]; tracked {
+				menuItem.description = base
+				if n := counts[menuItem.view]; n > 0 {
+					menuItem.description = fmt.Sprintf("%s (%d unread)", base, n)
+				}
+			}
+		}
+		updated[i] = menuItem
+	}
+	m.Menu.SetItems(updated)
+}
+
+func (m Model) renderCourseworkDetail() string {
+	cw := m.Coursework[m.SelectedCoursework]
+
+	dueDate := cw.DueDate
+	if cw.DueTime != "" {
+		dueDate += " " + cw.DueTime
+	}
+	if dueDate == "" {
+		dueDate = "-"
+	}
+
+	var output string
+	output += sectionTitleStyle.Width(m.innerWidth()).Render(cw.Title()) + "\n"
+	output += m.infoRow("Course:", infoValueStyle.Render(cw.CourseName))
+	output += m.infoRow("Status:", infoValueStyle.Render(cw.StatusString()))
+	output += m.infoRow("Due:", infoValueStyle.Render(dueDate))
+	if opens := cw.opensIn(m.Clock.Now()); opens != "" {
+		output += m.infoRow("Opens:", infoValueStyle.Render(opens))
+	}
+	output += m.infoRow("Points:", infoValueStyle.Render(fmt.Sprintf("%d", cw.Points)))
+	output += m.infoRow("Type:", infoValueStyle.Render(cw.WorkType))
+	output += m.infoRow("Expects:", infoValueStyle.Render(cw.Expects.Label()))
+	if !cw.Expects.InCLI() {
+		output += m.infoRow("Hand in:", infoValueStyle.Render("in the browser (Y copies the link)"))
+	}
+	if progress := m.subtaskProgress(cw.ID); progress != "" {
+		output += m.infoRow("Subtasks:", progress)
+	}
+	output += "\n"
+	output += lipgloss.NewStyle().
+		Foreground(textSecondary).
+		Width(m.innerWidth()-4).
+		Render(cw.Desc) + "\n"
+	output += m.renderSubtasks(cw.ID)
+	output += m.renderMaterials(cw.Materials)
+
+	return m.contentBox().Render(output)
+}
+
+func (m Model) renderAnnouncementDetail() string {
+	ann := m.Announcements[m.SelectedAnnouncement]
+
+	var output string
+	output += sectionTitleStyle.Width(m.innerWidth()).Render(ann.Title()) + "\n"
+	output += m.infoRow("Course:", infoValueStyle.Render(ann.CourseName))
+	if ann.Author != "" {
+		output += m.infoRow("Author:", m.avatarBadge(ann.AuthorID, ann.Author)+" "+infoValueStyle.Render(ann.Author))
+	}
+	output += m.infoRow("Posted:", infoValueStyle.Render(ann.PostedAt)) + "\n"
+	output += lipgloss.NewStyle().
+		Foreground(textSecondary).
+		Width(m.innerWidth()-4).
+		Render(ann.Text) + "\n"
+	output += m.renderMaterials(ann.Materials)
+
+	return m.contentBox().Render(output)
+}
+
+// subtaskProgress summarizes the local checklist for a coursework item, or
+// returns "" when it has none.
+func (m Model) subtaskProgress(id string) string {
+	done, total := m.Notes.Get(id).Progress()
+	if total == 0 {
+		return ""
+	}
+
+	color := textSecondary
+	if done == total {
+		color = successColor
+	}
+	return lipgloss.NewStyle().
+		Foreground(color).
+		Render(fmt.Sprintf("☑ %d/%d (%d%%)", done, total, done*100/total))
+}
+
+func (m Model) renderSubtasks(id string) string {
+	note := m.Notes.Get(id)
+	if note == nil || len(note.Subtasks) == 0 {
+		return ""
+	}
+
+	doneStyle := lipgloss.NewStyle().Foreground(textMuted).Strikethrough(true)
+	todoStyle := lipgloss.NewStyle().Foreground(textPrimary)
+
+	output := "\n" + sectionTitleStyle.Render("Subtasks") + "\n"
+	for _, st := range note.Subtasks {
+		if st.Done {
+			output += "☑ " + doneStyle.Render(st.Text) + "\n"
+		} else {
+			output += "☐ " + todoStyle.Render(st.Text) + "\n"
+		}
+	}
+	return output
+}
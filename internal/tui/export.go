@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/timboy697/gc-cli/internal/clipboard"
+)
+
+// currentMarkdown renders the open detail view as Markdown for yanking or
+// saving. ok is false outside detail views.
+func (m Model) currentMarkdown() (title, md string, ok bool) {
+	switch m.CurrentView {
+	case ViewCourseworkDetail:
+		cw := m.Coursework[m.SelectedCoursework]
+		return cw.AssignTitle, courseworkMarkdown(cw), true
+	case ViewAnnouncementDetail:
+		ann := m.Announcements[m.SelectedAnnouncement]
+		return ann.AnnounceTitle, announcementMarkdown(ann), true
+	}
+	return "", "", false
+}
+
+func (m Model) currentLink() string {
+	switch m.CurrentView {
+	case ViewCourseworkDetail:
+		return m.Coursework[m.SelectedCoursework].Link
+	case ViewAnnouncementDetail:
+		return m.Announcements[m.SelectedAnnouncement].Link
+	}
+	return ""
+}
+
+func courseworkMarkdown(cw CourseworkItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", cw.AssignTitle)
+	fmt.Fprintf(&b, "- **Course:** %s\n", cw.CourseName)
+	fmt.Fprintf(&b, "- **Status:** %s\n", cw.StatusString())
+	if cw.DueDate != "" {
+		fmt.Fprintf(&b, "- **Due:** %s %s\n", cw.DueDate, cw.DueTime)
+	}
+	fmt.Fprintf(&b, "- **Points:** %d\n", cw.Points)
+	if cw.Link != "" {
+		fmt.Fprintf(&b, "- **Link:** %s\n", cw.Link)
+	}
+	if cw.Desc != "" {
+		fmt.Fprintf(&b, "\n%s\n", cw.Desc)
+	}
+	writeMaterialsMarkdown(&b, cw.Materials)
+	return b.String()
+}
+
+func announcementMarkdown(ann AnnouncementItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", ann.AnnounceTitle)
+	fmt.Fprintf(&b, "- **Course:** %s\n", ann.CourseName)
+	fmt.Fprintf(&b, "- **Posted:** %s\n", ann.PostedAt)
+	if ann.Link != "" {
+		fmt.Fprintf(&b, "- **Link:** %s\n", ann.Link)
+	}
+	fmt.Fprintf(&b, "\n%s\n", ann.Text)
+	writeMaterialsMarkdown(&b, ann.Materials)
+	return b.String()
+}
+
+func writeMaterialsMarkdown(b *strings.Builder, materials []MaterialItem) {
+	if len(materials) == 0 {
+		return
+	}
+	b.WriteString("\n## Materials\n\n")
+	for _, mat := range materials {
+		if mat.URL != "" {
+			fmt.Fprintf(b, "- [%s](%s)\n", mat.Title, mat.URL)
+		} else {
+			fmt.Fprintf(b, "- %s\n", mat.Title)
+		}
+	}
+}
+
+func (m *Model) yank(link bool) {
+	text := m.currentLink()
+	what := "link"
+	if !link {
+		_, text, _ = m.currentMarkdown()
+		what = "details"
+	}
+	if text == "" {
+		m.Notice = "Nothing to copy"
+		return
+	}
+	if err := clipboard.Write(text); err != nil {
+		m.Notice = "Copy failed: " + err.Error()
+		return
+	}
+	m.Notice = "Copied " + what + " to clipboard"
+}
+
+var unsafeFilename = regexp.MustCompile(`[^a-z0-9]+`)
+
+// saveMarkdown writes the current view to <title>.md in the working
+// directory, never overwriting an existing file.
+func (m *Model) saveMarkdown() {
+	title, md, ok := m.currentMarkdown()
+	if !ok {
+		return
+	}
+
+	base := strings.Trim(unsafeFilename.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if base == "" {
+		base = "item"
+	}
+	name := base + ".md"
+	for i := 2; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			break
+		}
+		name = fmt.Sprintf("%s-%d.md", base, i)
+	}
+
+	if err := os.WriteFile(name, []byte(md), 0644); err != nil {
+		m.Notice = "Save failed: " + err.Error()
+		return
+	}
+	m.Notice = "Saved " + name
+}
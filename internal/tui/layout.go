@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Below minWidth×minHeight nothing fits, so the TUI asks for a bigger
+// terminal rather than drawing a broken layout. Below compactWidth, lists
+// drop their padding and secondary columns, and details stack each label
+// above its value.
+const (
+	minWidth     = 40
+	minHeight    = 12
+	compactWidth = 80
+)
+
+func (m Model) tooSmall() bool {
+	return m.Width < minWidth || m.Height < minHeight
+}
+
+func (m Model) compact() bool {
+	return m.Width < compactWidth
+}
+
+// contentBox is the style of a view's content area, with less padding when
+// compact.
+func (m Model) contentBox() lipgloss.Style {
+	if m.compact() {
+		return contentStyle.Copy().Padding(0, 1).Width(m.Width - 4)
+	}
+	return contentStyle.Copy().Width(m.Width - 4)
+}
+
+// innerWidth is the width left for text inside contentBox.
+func (m Model) innerWidth() int {
+	if m.compact() {
+		return m.Width - 6
+	}
+	return m.Width - 8
+}
+
+// infoRow is one label and value of a detail view: side by side, or the
+// value on its own line under the label when compact.
+func (m Model) infoRow(label, value string) string {
+	if m.compact() {
+		return infoLabelStyle.Copy().Width(0).Align(lipgloss.Left).Render(label) + "\n  " + value + "\n"
+	}
+	return infoLabelStyle.Render(label) + " " + value + "\n"
+}
+
+func (m Model) renderTooSmall() string {
+	msg := lipgloss.NewStyle().
+		Foreground(warningColor).
+		Bold(true).
+		Align(lipgloss.Center).
+		Render(fmt.Sprintf("Terminal too small\n%d×%d, needs %d×%d", m.Width, m.Height, minWidth, minHeight))
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, msg)
+}
+
+// bodyHeight is the height left for a view between the header and the
+// status bar, less the deadline banner while it shows.
+func (m Model) bodyHeight() int {
+	height := m.Height - 6
+	if m.Banner != "" {
+		height -= lipgloss.Height(m.renderBanner())
+	}
+	return height
+}
+
+// layout fits the viewport and the main menu to the body, e.g. for a new
+// terminal size or when the banner comes or goes.
+func (m *Model) layout() {
+	if m.Width == 0 {
+		return
+	}
+	m.Viewport.Width = m.Width - 4
+	m.Viewport.Height = m.bodyHeight()
+	m.sizeMenu()
+}
+
+// sizeMenu fits the main menu inside its border and padding. When the
+// terminal is too short for every item with its description, the
+// descriptions go, and if even that doesn't fit, the page dots show that
+// there's more.
+func (m *Model) sizeMenu() {
+	width, height := m.Width-6, m.bodyHeight()-2
+	items := len(m.Menu.Items())
+	delegate := list.NewDefaultDelegate()
+	// Each item is a title and description, then a blank line.
+	if height < 3*items {
+		delegate.ShowDescription = false
+		delegate.SetSpacing(0)
+	}
+	m.Menu.SetDelegate(delegate)
+	m.Menu.SetShowPagination(height < items)
+	m.Menu.SetSize(width, height)
+}
+
+// relayout renders the open view again, e.g. for a new terminal size.
+func (m *Model) relayout() {
+	switch m.CurrentView {
+	case ViewCourses:
+		m.updateViewport(m.renderCourses())
+	case ViewCoursework:
+		m.updateViewport(m.renderCoursework())
+	case ViewGrades:
+		m.updateViewport(m.renderGrades())
+	case ViewAnnouncements:
+		m.updateViewport(m.renderAnnouncements())
+	case ViewCourseworkDetail:
+		m.updateViewport(m.renderCourseworkDetail())
+	case ViewAnnouncementDetail:
+		m.updateViewport(m.renderAnnouncementDetail())
+	}
+}
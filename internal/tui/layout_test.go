@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/timboy697/gc-cli/internal/clock"
+	"github.com/timboy697/gc-cli/internal/termimg"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestMain(m *testing.M) {
+	// Golden files hold plain text, whatever terminal the tests run in.
+	lipgloss.SetColorProfile(termenv.Ascii)
+	os.Exit(m.Run())
+}
+
+// golden compares a rendered view with testdata/<name>.golden.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run go test -update to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s changed; if that's intended, run go test -update\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// sizedModel is a signed-in model at the main menu of a width×height
+// terminal, with no images and the clock pinned.
+func sizedModel(t *testing.T, width, height int) Model {
+	t.Helper()
+	m := New(nil)
+	m.AuthState = AuthAuthenticated
+	m.CurrentView = ViewMainMenu
+	m.ImageProtocol = termimg.None
+	m.Clock = clock.Fixed(testNow)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	return updated.(Model)
+}
+
+func TestViewNarrow(t *testing.T) {
+	m := sizedModel(t, 60, 24)
+	golden(t, "narrow_menu", m.View())
+
+	m.CurrentView = ViewCoursework
+	m.showCoursework(sampleCoursework())
+	golden(t, "narrow_coursework", m.View())
+
+	m.openCourseworkDetail()
+	golden(t, "narrow_coursework_detail", m.View())
+}
+
+// Every view fills the terminal without running past its edges, from the
+// smallest size drawn up to a wide one.
+func TestViewFitsTerminal(t *testing.T) {
+	for _, size := range [][2]int{{minWidth, minHeight}, {60, 24}, {80, 24}, {120, 40}} {
+		m := sizedModel(t, size[0], size[1])
+		views := map[string]func(){
+			"menu":          func() { m.CurrentView = ViewMainMenu },
+			"courses":       func() { m.CurrentView = ViewCourses; m.showCourses(sampleCourses()) },
+			"coursework":    func() { m.CurrentView = ViewCoursework; m.showCoursework(sampleCoursework()) },
+			"grades":        func() { m.CurrentView = ViewGrades; m.showGrades(sampleGrades()) },
+			"announcements": func() { m.CurrentView = ViewAnnouncements; m.showAnnouncements(sampleAnnouncements()) },
+			"detail":        func() { m.CurrentView = ViewCoursework; m.showCoursework(sampleCoursework()); m.openCourseworkDetail() },
+		}
+		for name, open := range views {
+			open()
+			view := m.View()
+			if w, h := lipgloss.Width(view), lipgloss.Height(view); w > size[0] || h > size[1] {
+				t.Errorf("%s at %d×%d is %d×%d", name, size[0], size[1], w, h)
+			}
+		}
+	}
+}
+
+func TestViewTooSmall(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		width, height int
+	}{
+		{"too_narrow", minWidth - 1, 20},
+		{"too_short", 60, minHeight - 1},
+	} {
+		m := sizedModel(t, tt.width, tt.height)
+		golden(t, tt.name, m.View())
+	}
+
+	// At the minimum the view itself is drawn.
+	m := sizedModel(t, minWidth, minHeight)
+	if m.tooSmall() {
+		t.Errorf("%d×%d is too small, want it drawn", minWidth, minHeight)
+	}
+}
+
+func TestLoginQR(t *testing.T) {
+	const url = "https://accounts.google.com/o/oauth2/auth?client_id=x&redirect_uri=http%3A%2F%2Flocalhost%3A4242&state=s"
+	for _, tt := range []struct {
+		height int
+		want   string
+	}{
+		{60, "completes in a browser on this computer"},
+		{24, "A bigger terminal shows the link as a QR code too"},
+	} {
+		m := sizedModel(t, 100, tt.height)
+		m.LoginURL = url
+		view := m.renderAuthRequired()
+		if !strings.Contains(view, tt.want) {
+			t.Errorf("%d lines high: the sign-in view doesn't say %q:\n%s", tt.height, tt.want, view)
+		}
+	}
+}
+
+func TestComposePreviewShowsPostedText(t *testing.T) {
+	const body = "**Quiz** on _Friday_"
+	m := sizedModel(t, 100, 30)
+	m.Compose = newComposer([]CourseItem{{ID: "c1", Name: "Chemistry"}}, m.Width-8, m.Height-16)
+	m.Compose.Body.SetValue(body)
+	m.Compose.Preview = true
+	if view := m.renderCompose(); !strings.Contains(view, body) {
+		t.Errorf("the preview doesn't show the body as it will be posted:\n%s", view)
+	}
+}
@@ -0,0 +1,198 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// viewLoad is a list view's data being fetched in the background. Leaving
+// the view cancels it; a result that arrives for a load that's no longer
+// current is dropped.
+type viewLoad struct {
+	view   ViewType
+	cancel context.CancelFunc
+
+	// open is the ID of an item to open once the view has loaded, e.g.
+	// one picked from the palette, and scroll where to put its detail.
+	open   string
+	scroll int
+}
+
+// viewLoadedMsg delivers what a viewLoad fetched: the items of its view,
+// or the error that stopped it.
+type viewLoadedMsg struct {
+	load          *viewLoad
+	courses       []CourseItem
+	coursework    []CourseworkItem
+	grades        []GradeItem
+	announcements []AnnouncementItem
+	err           error
+}
+
+var loadingMessages = map[ViewType]string{
+	ViewCourses:       "Loading courses...",
+	ViewCoursework:    "Loading coursework...",
+	ViewGrades:        "Loading grades...",
+	ViewAnnouncements: "Loading announcements...",
+}
+
+func newSpinner() spinner.Model {
+	return spinner.New(spinner.WithSpinner(spinner.Dot), spinner.WithStyle(spinnerStyle))
+}
+
+// loadView starts loading the data of a list view, replacing any load
+// still running. The view shows a spinner until the result arrives.
+func (m *Model) loadView(view ViewType) tea.Cmd {
+	m.cancelLoad()
+	if m.AuthState != AuthAuthenticated {
+		m.CurrentView = ViewAuthRequired
+		m.ErrorMsg = "Please authenticate first using 'gc-cli auth login'"
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	load := &viewLoad{view: view, cancel: cancel}
+	m.load = load
+	m.IsLoading = true
+	m.LoadingMsg = loadingMessages[view]
+
+	// Without a client, e.g. in previews, views show sample data.
+	newClient := m.client()
+	now := m.Clock.Now()
+	fetch := func() tea.Msg {
+		defer cancel()
+		msg := viewLoadedMsg{load: load}
+		switch view {
+		case ViewCourses:
+			if newClient == nil {
+				msg.courses = sampleCourses()
+			} else {
+				msg.courses, msg.err = fetchCourses(ctx, newClient)
+			}
+		case ViewCoursework:
+			if newClient == nil {
+				msg.coursework = sampleCoursework()
+			} else if items, err := fetchAgenda(ctx, newClient); err != nil {
+				msg.err = err
+			} else {
+				msg.coursework = courseworkItems(items, now)
+			}
+		case ViewGrades:
+			if newClient == nil {
+				msg.grades = sampleGrades()
+			} else if items, err := fetchAgenda(ctx, newClient); err != nil {
+				msg.err = err
+			} else {
+				msg.grades = gradeItems(items)
+			}
+		case ViewAnnouncements:
+			if newClient == nil {
+				msg.announcements = sampleAnnouncements()
+			} else {
+				msg.announcements, msg.err = fetchAnnouncements(ctx, newClient)
+			}
+		}
+		return msg
+	}
+	return tea.Batch(fetch, m.Spinner.Tick)
+}
+
+// cancelLoad stops the load in progress, if any, e.g. when the user leaves
+// the view before it arrives.
+func (m *Model) cancelLoad() {
+	if m.load == nil {
+		return
+	}
+	m.load.cancel()
+	m.load = nil
+	m.IsLoading = false
+}
+
+func (m Model) handleViewLoaded(msg viewLoadedMsg) (tea.Model, tea.Cmd) {
+	load := msg.load
+	if load != m.load {
+		return m, nil
+	}
+	m.load = nil
+	m.IsLoading = false
+	if msg.err != nil {
+		m.showLoadError(load.view, msg.err)
+		return m, nil
+	}
+
+	var bell tea.Cmd
+	switch load.view {
+	case ViewCourses:
+		m.showCourses(msg.courses)
+	case ViewCoursework:
+		bell = m.bell(m.showCoursework(msg.coursework))
+	case ViewGrades:
+		m.showGrades(msg.grades)
+	case ViewAnnouncements:
+		m.showAnnouncements(msg.announcements)
+	}
+	m.restoreViewState(load.view)
+	if load.open == "" {
+		return m, bell
+	}
+	open := m.openLoadedItem(load.view, load.open, load.scroll)
+	return m, tea.Batch(bell, open)
+}
+
+// openLoadedItem opens the detail of the item with id, if the view still
+// has it, scrolled to scroll.
+func (m *Model) openLoadedItem(view ViewType, id string, scroll int) tea.Cmd {
+	var cmd tea.Cmd
+	switch view {
+	case ViewCoursework:
+		i := m.indexOfCoursework(id)
+		if i < 0 {
+			return nil
+		}
+		m.SelectedCoursework = i
+		cmd = m.openCourseworkDetail()
+	case ViewAnnouncements:
+		i := m.indexOfAnnouncement(id)
+		if i < 0 {
+			return nil
+		}
+		m.SelectedAnnouncement = i
+		cmd = m.openAnnouncementDetail()
+	default:
+		return nil
+	}
+	m.Viewport.SetYOffset(scroll)
+	return cmd
+}
+
+func (m Model) indexOfCoursework(id string) int {
+	for i, cw := range m.Coursework {
+		if cw.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m Model) indexOfAnnouncement(id string) int {
+	for i, ann := range m.Announcements {
+		if ann.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m Model) handleSpinnerTick(msg spinner.TickMsg) (tea.Model, tea.Cmd) {
+	// The spinner stops ticking once nothing is loading.
+	if !m.IsLoading {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.Spinner, cmd = m.Spinner.Update(msg)
+	return m, cmd
+}
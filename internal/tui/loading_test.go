@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/clock"
+	"github.com/timboy697/gc-cli/internal/config"
+	"github.com/timboy697/gc-cli/internal/demo"
+)
+
+// One course as of testNow: graded work, work turned in, work overdue and
+// work due within the hour.
+const testFixture = `{
+  "me": "me",
+  "profiles": [{"id": "me", "name": "Sam Lee"}, {"id": "t1", "name": "Ana Ruiz"}],
+  "courses": [{
+    "id": "chem", "name": "Chemistry", "teacher": "t1",
+    "coursework": [
+      {"id": "w1", "title": "Lab 1", "type": "ASSIGNMENT", "points": 20, "posted": -9, "due": -7, "at": "23:59", "submission": "RETURNED", "grade": 17},
+      {"id": "w2", "title": "Lab 2", "type": "ASSIGNMENT", "points": 20, "posted": -5, "due": -3, "at": "23:59", "submission": "TURNED_IN"},
+      {"id": "w3", "title": "Lab 3", "type": "ASSIGNMENT", "points": 20, "posted": -3, "due": -1, "at": "23:59"},
+      {"id": "w4", "title": "Exit ticket", "type": "SHORT_ANSWER_QUESTION", "points": 5, "posted": 0, "due": 0, "at": "12:45"}
+    ],
+    "announcements": [{"id": "a1", "posted": -1, "at": "08:00", "author": "t1", "text": "Goggles on Thursday."}]
+  }]
+}`
+
+// demoModel is a signed-in model reading testFixture through a demo
+// client, the way --demo runs the TUI.
+func demoModel(t *testing.T) Model {
+	t.Helper()
+	now := time.Date(testNow.Year(), testNow.Month(), testNow.Day(), 12, 0, 0, 0, time.Local)
+	d, err := demo.Parse([]byte(testFixture), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := sizedModel(t, 100, 30)
+	m.Config = &config.Config{TUI: config.TUIConfig{DeadlineWarning: time.Hour}}
+	m.Clock = clock.Fixed(now)
+	m.NewClient = func(ctx context.Context) (api.ClassroomService, error) {
+		return demo.NewClient(ctx, d)
+	}
+	return m
+}
+
+// load opens view and delivers what its load fetched, as the program would.
+func load(t *testing.T, m Model, view ViewType) Model {
+	t.Helper()
+	m.CurrentView = view
+	cmd := m.loadView(view)
+	if !m.IsLoading {
+		t.Fatalf("view %d isn't loading", view)
+	}
+	for _, msg := range runBatch(cmd) {
+		if loaded, ok := msg.(viewLoadedMsg); ok {
+			updated, _ := m.Update(loaded)
+			return updated.(Model)
+		}
+	}
+	t.Fatalf("view %d loaded nothing", view)
+	return m
+}
+
+// runBatch runs cmd and any batch it returns, collecting the messages.
+func runBatch(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	batch, ok := msg.(tea.BatchMsg)
+	if !ok {
+		return []tea.Msg{msg}
+	}
+	var msgs []tea.Msg
+	for _, c := range batch {
+		msgs = append(msgs, runBatch(c)...)
+	}
+	return msgs
+}
+
+func TestLoadCourseworkFromClient(t *testing.T) {
+	m := load(t, demoModel(t), ViewCoursework)
+	if m.IsLoading {
+		t.Fatal("still loading")
+	}
+
+	want := map[string]CourseworkStatus{"Lab 1": StatusReturned, "Lab 2": StatusTurnedIn, "Lab 3": StatusMissing, "Exit ticket": StatusPending}
+	if len(m.Coursework) != len(want) {
+		t.Fatalf("loaded %d items, want %d", len(m.Coursework), len(want))
+	}
+	for _, cw := range m.Coursework {
+		if cw.Status != want[cw.AssignTitle] {
+			t.Errorf("%s: status = %d, want %d", cw.AssignTitle, cw.Status, want[cw.AssignTitle])
+		}
+		if cw.CourseName != "Chemistry" {
+			t.Errorf("%s: course = %q, want Chemistry", cw.AssignTitle, cw.CourseName)
+		}
+	}
+	if !strings.Contains(m.Banner, "Exit ticket (Chemistry) due in 45m") {
+		t.Errorf("banner = %q, want the exit ticket due in 45m", m.Banner)
+	}
+	if view := m.View(); !strings.Contains(view, "Lab 3") {
+		t.Errorf("view doesn't list the loaded work:\n%s", view)
+	}
+}
+
+func TestLoadGradesAndAnnouncementsFromClient(t *testing.T) {
+	m := load(t, demoModel(t), ViewGrades)
+	if len(m.Grades) != 1 || m.Grades[0].Assignment != "Lab 1" || m.Grades[0].Score != "17" || m.Grades[0].MaxScore != "20" {
+		t.Errorf("grades = %+v, want Lab 1 at 17/20", m.Grades)
+	}
+
+	m = load(t, m, ViewAnnouncements)
+	if len(m.Announcements) != 1 || !strings.Contains(m.Announcements[0].Text, "Goggles") {
+		t.Errorf("announcements = %+v, want the one about goggles", m.Announcements)
+	}
+}
+
+func TestLoadErrorShowsErrorView(t *testing.T) {
+	m := demoModel(t)
+	m.NewClient = func(context.Context) (api.ClassroomService, error) {
+		return nil, errors.New("no network")
+	}
+	m = load(t, m, ViewCourses)
+	if m.CurrentView != ViewError || !strings.Contains(m.ErrorMsg, "no network") {
+		t.Errorf("view = %d, error = %q; want the error view explaining the failure", m.CurrentView, m.ErrorMsg)
+	}
+}
+
+func TestLeavingDropsLoad(t *testing.T) {
+	m := demoModel(t)
+	m.CurrentView = ViewCoursework
+	cmd := m.loadView(ViewCoursework)
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.CurrentView != ViewMainMenu || m.IsLoading {
+		t.Fatalf("esc left view %d, loading %v; want the menu, not loading", m.CurrentView, m.IsLoading)
+	}
+	for _, msg := range runBatch(cmd) {
+		if loaded, ok := msg.(viewLoadedMsg); ok {
+			updated, _ = m.Update(loaded)
+			m = updated.(Model)
+		}
+	}
+	if len(m.Coursework) != 0 || m.CurrentView != ViewMainMenu {
+		t.Errorf("a cancelled load still showed %d items in view %d", len(m.Coursework), m.CurrentView)
+	}
+}
+
+func TestBannerFitsTerminal(t *testing.T) {
+	m := load(t, demoModel(t), ViewCoursework)
+	if m.Banner == "" {
+		t.Fatal("no banner for the exit ticket")
+	}
+	for _, view := range []ViewType{ViewCoursework, ViewMainMenu} {
+		m.CurrentView = view
+		if h := lipgloss.Height(m.View()); h > m.Height {
+			t.Errorf("view %d with the banner is %d lines, want at most %d", view, h, m.Height)
+		}
+	}
+}
+
+func TestBellRingsOnLoad(t *testing.T) {
+	for _, bell := range []bool{false, true} {
+		m := demoModel(t)
+		m.Config.TUI.Bell = bell
+		m.CurrentView = ViewCoursework
+		var cmd tea.Cmd
+		for _, msg := range runBatch(m.loadView(ViewCoursework)) {
+			if loaded, ok := msg.(viewLoadedMsg); ok {
+				var updated tea.Model
+				updated, cmd = m.Update(loaded)
+				m = updated.(Model)
+			}
+		}
+		if rang := cmd != nil; rang != bell {
+			t.Errorf("bell on = %v: load rang it = %v", bell, rang)
+		}
+
+		// The next check doesn't ring again for the same work.
+		if m.bell(m.checkDeadlines(m.Clock.Now())) != nil {
+			t.Errorf("bell on = %v: the next check rang it again", bell)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/termimg"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	thumbnailCols = 32
+	thumbnailRows = 9
+)
+
+// MaterialItem is an attachment on a coursework item or announcement. Kind
+// "addon" is a Classroom add-on such as Kami or Edpuzzle.
+type MaterialItem struct {
+	Kind         string
+	Title        string
+	URL          string
+	ThumbnailURL string
+}
+
+func (mi MaterialItem) icon() string {
+	switch mi.Kind {
+	case "youtube":
+		return "▶"
+	case "drive":
+		return "📄"
+	case "form":
+		return "📝"
+	case "addon":
+		return "🧩"
+	}
+	return "🔗"
+}
+
+type thumbnailMsg struct {
+	url      string
+	rendered string
+}
+
+type drawThumbnailsMsg struct{}
+
+// loadThumbnails fetches previews for any materials not already cached. It
+// returns nil on terminals without image support so detail views stay text.
+func (m Model) loadThumbnails(materials []MaterialItem) tea.Cmd {
+	if m.ImageProtocol == termimg.None {
+		return nil
+	}
+
+	cmds := []tea.Cmd{m.scheduleThumbnailDraw()}
+	for _, mat := range materials {
+		if mat.ThumbnailURL == "" {
+			continue
+		}
+		if _, ok := m.Thumbnails[mat.ThumbnailURL]; ok {
+			continue
+		}
+		// Reserve the slot so reopening the view doesn't refetch.
+		m.Thumbnails[mat.ThumbnailURL] = ""
+
+		url, protocol := mat.ThumbnailURL, m.ImageProtocol
+		cmds = append(cmds, func() tea.Msg {
+			img, err := termimg.Fetch(context.Background(), url)
+			if err != nil {
+				return thumbnailMsg{url: url}
+			}
+			rendered, err := termimg.Render(img, protocol, thumbnailCols, thumbnailRows)
+			if err != nil {
+				return thumbnailMsg{url: url}
+			}
+			return thumbnailMsg{url: url, rendered: rendered}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m Model) currentMaterials() []MaterialItem {
+	switch m.CurrentView {
+	case ViewCourseworkDetail:
+		return m.Coursework[m.SelectedCoursework].Materials
+	case ViewAnnouncementDetail:
+		return m.Announcements[m.SelectedAnnouncement].Materials
+	}
+	return nil
+}
+
+func (m Model) handleThumbnail(msg thumbnailMsg) (tea.Model, tea.Cmd) {
+	m.Thumbnails[msg.url] = msg.rendered
+	switch m.CurrentView {
+	case ViewCourseworkDetail:
+		m.updateViewport(m.renderCourseworkDetail())
+	case ViewAnnouncementDetail:
+		m.updateViewport(m.renderAnnouncementDetail())
+	default:
+		return m, nil
+	}
+	return m, m.scheduleThumbnailDraw()
+}
+
+// scheduleThumbnailDraw defers drawing until the frame for the current state
+// has been painted, since images are written around the renderer.
+func (m Model) scheduleThumbnailDraw() tea.Cmd {
+	if m.ImageProtocol == termimg.None {
+		return nil
+	}
+	return tea.Tick(50*time.Millisecond, func(time.Time) tea.Msg {
+		return drawThumbnailsMsg{}
+	})
+}
+
+// drawThumbnails paints loaded previews over their placeholder lines when
+// the whole reserved area is visible in the viewport.
+func (m Model) drawThumbnails() tea.Cmd {
+	var out strings.Builder
+	out.WriteString(termimg.Clear(m.ImageProtocol))
+
+	if !m.PaletteOpen {
+		top := lipgloss.Height(m.renderHeader())
+		lines := strings.Split(m.Viewport.View(), "\n")
+		for i, mat := range m.currentMaterials() {
+			rendered := m.Thumbnails[mat.ThumbnailURL]
+			if rendered == "" {
+				continue
+			}
+			marker := thumbnailPlaceholder(i)
+			for row, line := range lines {
+				if strings.Contains(line, marker) && row+thumbnailRows <= len(lines) {
+					out.WriteString(termimg.At(rendered, top+row, 4))
+					break
+				}
+			}
+		}
+	}
+
+	seq := out.String()
+	if seq == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		fmt.Fprint(os.Stdout, seq)
+		return nil
+	}
+}
+
+// leaveThumbnails clears previews when navigating away from a detail view.
+// Protocols without an image layer need a full repaint to erase them.
+func (m Model) leaveThumbnails() tea.Cmd {
+	switch m.ImageProtocol {
+	case termimg.None:
+		return nil
+	case termimg.Kitty:
+		seq := termimg.Clear(termimg.Kitty)
+		return func() tea.Msg {
+			fmt.Fprint(os.Stdout, seq)
+			return nil
+		}
+	}
+	return tea.ClearScreen
+}
+
+func thumbnailPlaceholder(i int) string {
+	return fmt.Sprintf("⧉ preview %d", i+1)
+}
+
+// renderMaterials lists attachments. Loaded previews get a placeholder line
+// plus blank rows that drawThumbnails paints the image over; on terminals
+// without image support only the text is shown.
+func (m Model) renderMaterials(materials []MaterialItem) string {
+	if len(materials) == 0 {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(textPrimary).Bold(true)
+	linkStyle := lipgloss.NewStyle().Foreground(textMuted)
+
+	output := "\n" + sectionTitleStyle.Render("Materials") + "\n"
+	for i, mat := range materials {
+		output += fmt.Sprintf("%s %s\n", mat.icon(), titleStyle.Render(mat.Title))
+		if mat.URL != "" {
+			output += "  " + linkStyle.Render(mat.URL) + "\n"
+		}
+		if m.Thumbnails[mat.ThumbnailURL] != "" {
+			output += linkStyle.Render(thumbnailPlaceholder(i)) + strings.Repeat("\n", thumbnailRows)
+		}
+	}
+	return output
+}
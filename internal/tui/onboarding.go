@@ -0,0 +1,222 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/auth"
+	"github.com/timboy697/gc-cli/internal/qr"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/oauth2"
+)
+
+const loginTimeout = 5 * time.Minute
+
+// loginAttempt is a sign-in in progress: its callback listener, once
+// started, and a context that cancelling stops it with. Only the model's
+// current attempt is acted on; a message from an abandoned one just closes
+// what it opened.
+type loginAttempt struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// startLoginMsg asks Update to begin signing in, so the attempt is recorded
+// in the model like one started with enter.
+type startLoginMsg struct{}
+
+type authStartedMsg struct {
+	attempt *loginAttempt
+	flow    *auth.CallbackFlow
+	err     error
+}
+
+type authDoneMsg struct {
+	attempt *loginAttempt
+	token   *oauth2.Token
+	err     error
+}
+
+func (m Model) authConfig() *auth.Config {
+	return m.Config.AuthConfig()
+}
+
+// startLogin begins the loopback OAuth flow so first-time users can sign in
+// without leaving the TUI.
+func (m Model) startLogin(attempt *loginAttempt) tea.Cmd {
+	authCfg := m.authConfig()
+	return func() tea.Msg {
+		flow, err := auth.StartCallbackFlow(authCfg)
+		if err == nil && attempt.ctx.Err() == nil {
+			_ = auth.OpenBrowser(flow.AuthURL)
+		}
+		return authStartedMsg{attempt: attempt, flow: flow, err: err}
+	}
+}
+
+func waitForLogin(attempt *loginAttempt, flow *auth.CallbackFlow) tea.Cmd {
+	return func() tea.Msg {
+		token, err := flow.Wait(attempt.ctx, loginTimeout)
+		return authDoneMsg{attempt: attempt, token: token, err: err}
+	}
+}
+
+func (m Model) handleAuthMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case startLoginMsg:
+		return m.beginLogin()
+
+	case authStartedMsg:
+		if msg.attempt != m.login {
+			if msg.flow != nil {
+				msg.flow.Close()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			m.cancelLogin()
+			m.LoginErr = msg.err.Error()
+			return m, nil
+		}
+		m.LoginURL = msg.flow.AuthURL
+		return m, waitForLogin(msg.attempt, msg.flow)
+
+	case authDoneMsg:
+		if msg.attempt != m.login {
+			return m, nil
+		}
+		m.cancelLogin()
+		if msg.err != nil {
+			m.LoginErr = msg.err.Error()
+			return m, nil
+		}
+		if err := auth.TokenToFile(m.Config.Auth.TokenFile, msg.token); err != nil {
+			m.LoginErr = err.Error()
+			return m, nil
+		}
+		m.LoginErr = ""
+		m.AuthState = AuthAuthenticated
+		if m.RetryAfterLogin && m.CurrentView == ViewAuthRequired {
+			// Signed in again from the error view: back online, and on
+			// to the view that failed.
+			m.RetryAfterLogin = false
+			m.Offline = false
+			return m.retryLoad()
+		}
+		m.PreviousView = m.CurrentView
+		m.CurrentView = ViewMainMenu
+	}
+	return m, nil
+}
+
+func (m Model) beginLogin() (Model, tea.Cmd) {
+	if m.login != nil || m.Config == nil {
+		return m, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.login = &loginAttempt{ctx: ctx, cancel: cancel}
+	m.LoginErr = ""
+	return m, m.startLogin(m.login)
+}
+
+// cancelLogin stops the sign-in in progress, if any, closing its callback
+// listener, e.g. when the user leaves the sign-in view.
+func (m *Model) cancelLogin() {
+	if m.login == nil {
+		return
+	}
+	m.login.cancel()
+	m.login = nil
+	m.LoginURL = ""
+}
+
+func (m Model) renderAuthRequired() string {
+	title := lipgloss.NewStyle().
+		Foreground(accentSecondary).
+		Bold(true).
+		Width(m.Width - 8).
+		Align(lipgloss.Center).
+		Render("🔒 Sign in to Google Classroom")
+
+	var body, hint, code string
+	switch {
+	case m.LoginURL != "":
+		body = "A browser window should have opened.\nIf it didn't, visit this URL to sign in:\n\n" + m.LoginURL
+		hint = "⏳ Waiting for Google to redirect back...  •  esc to cancel"
+		code = m.renderLoginQR()
+	case m.login != nil:
+		body = "Starting sign-in..."
+	case m.LoginErr != "":
+		body = "Sign-in failed: " + m.LoginErr
+		hint = "Press enter to try again  •  esc to go back"
+	default:
+		body = "gc-cli needs access to your Google Classroom account."
+		hint = "Press enter to sign in  •  esc to go back"
+	}
+
+	message := lipgloss.NewStyle().
+		Foreground(textSecondary).
+		Width(m.Width - 8).
+		Align(lipgloss.Center).
+		Render(body)
+
+	hintView := lipgloss.NewStyle().
+		Foreground(textMuted).
+		Width(m.Width - 8).
+		Align(lipgloss.Center).
+		Render(hint)
+
+	note := lipgloss.NewStyle().
+		Foreground(textMuted).
+		Width(m.Width - 8).
+		Align(lipgloss.Center)
+
+	parts := []string{"\n\n\n", title, "\n", message, "\n\n\n", hintView}
+	showQR := false
+	if code != "" {
+		// The QR code goes under the URL when there's room for it, with
+		// less space around the rest. Google sends the browser back to
+		// localhost on this computer, so a phone can carry the link over
+		// but can't finish signing in itself.
+		caption := note.Render("Scan to copy the link; signing in only completes in a browser on this computer")
+		withCode := []string{"\n", title, "\n", message, "\n", code, caption, "\n", hintView}
+		if lipgloss.Height(lipgloss.JoinVertical(lipgloss.Center, withCode...)) <= m.Height-10 {
+			parts = withCode
+			showQR = true
+		}
+	}
+	if m.LoginURL != "" && !showQR {
+		parts[len(parts)-1] = hintView + "\n" + note.Render("A bigger terminal shows the link as a QR code too")
+	}
+
+	content := lipgloss.NewStyle().
+		Width(m.Width-4).
+		Height(m.Height-6).
+		Background(bgSecondary).
+		Padding(2, 0).
+		Render(lipgloss.JoinVertical(lipgloss.Center, parts...))
+
+	return content
+}
+
+var qrStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#FFFFFF")).
+	Background(lipgloss.Color("#000000"))
+
+// renderLoginQR draws the sign-in URL as a QR code, or nothing if the
+// terminal is too narrow for it. Sign-in still has to finish in a browser on
+// this computer.
+func (m Model) renderLoginQR() string {
+	code, err := qr.Encode(m.LoginURL)
+	if err != nil || code.Size()+4 > m.Width-8 {
+		return ""
+	}
+	lines := code.Lines(2)
+	for i, line := range lines {
+		lines[i] = qrStyle.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/timboy697/gc-cli/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var viewNames = map[ViewType]string{
+	ViewCourses:       "courses",
+	ViewCoursework:    "coursework",
+	ViewGrades:        "grades",
+	ViewAnnouncements: "announcements",
+}
+
+func viewByName(name string) (ViewType, bool) {
+	for view, n := range viewNames {
+		if n == name {
+			return view, true
+		}
+	}
+	return ViewMainMenu, false
+}
+
+// recordVisit adds v to the palette's recent list. Like read markers, history
+// is saved best effort.
+func (m *Model) recordVisit(v store.Visit) {
+	v.LastSeen = time.Now()
+	m.History.Record(v)
+	if m.Store != nil {
+		_ = m.Store.SaveHistory(m.History)
+	}
+}
+
+func (m *Model) currentVisit() (store.Visit, bool) {
+	switch m.CurrentView {
+	case ViewCourseworkDetail:
+		cw := m.Coursework[m.SelectedCoursework]
+		return store.Visit{Kind: "coursework", ID: cw.ID, Label: cw.CourseName + " → " + cw.AssignTitle, Parent: cw.CourseID}, true
+	case ViewAnnouncementDetail:
+		ann := m.Announcements[m.SelectedAnnouncement]
+		return store.Visit{Kind: "announcement", ID: ann.ID, Label: ann.CourseName + " → " + ann.AnnounceTitle}, true
+	}
+	return store.Visit{}, false
+}
+
+func (m *Model) toggleCurrentFavorite() {
+	v, ok := m.currentVisit()
+	if !ok {
+		return
+	}
+	m.History.ToggleFavorite(v)
+	if m.Store != nil {
+		_ = m.Store.SaveHistory(m.History)
+	}
+}
+
+// paletteEntries lists pinned favorites first, then recent visits that
+// aren't already pinned.
+func (m Model) paletteEntries() []store.Visit {
+	entries := append([]store.Visit{}, m.History.Favorites...)
+	for _, v := range m.History.Recent {
+		if !m.History.IsFavorite(v) {
+			entries = append(entries, v)
+		}
+	}
+	return entries
+}
+
+func (m Model) handlePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.paletteEntries()
+
+	switch {
+	case key.Matches(msg, keys.Back), key.Matches(msg, keys.Palette):
+		m.PaletteOpen = false
+		return m, m.scheduleThumbnailDraw()
+	case key.Matches(msg, keys.Up):
+		if m.PaletteIndex > 0 {
+			m.PaletteIndex--
+		}
+	case key.Matches(msg, keys.Down):
+		if m.PaletteIndex < len(entries)-1 {
+			m.PaletteIndex++
+		}
+	case key.Matches(msg, keys.Favorite):
+		if m.PaletteIndex < len(entries) {
+			m.History.ToggleFavorite(entries[m.PaletteIndex])
+			if m.Store != nil {
+				_ = m.Store.SaveHistory(m.History)
+			}
+		}
+	case key.Matches(msg, keys.Select):
+		m.PaletteOpen = false
+		if m.PaletteIndex < len(entries) {
+			return m.jumpTo(entries[m.PaletteIndex])
+		}
+	case msg.String() == "ctrl+c":
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m Model) jumpTo(v store.Visit) (tea.Model, tea.Cmd) {
+	switch v.Kind {
+	case "view":
+		if view, ok := viewByName(v.ID); ok {
+			m.recordVisit(v)
+			return m.openView(view)
+		}
+	case "coursework", "announcement":
+		// The item opens once its view has loaded.
+		view := ViewCoursework
+		if v.Kind == "announcement" {
+			view = ViewAnnouncements
+		}
+		m.PreviousView = m.CurrentView
+		m.CurrentView = view
+		cmd := m.loadView(view)
+		if m.load != nil {
+			m.load.open = v.ID
+		}
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m Model) renderPalette() string {
+	entries := m.paletteEntries()
+
+	var output string
+	output += sectionTitleStyle.Width(m.Width-8).Render("Jump to...") + "\n\n"
+
+	if len(entries) == 0 {
+		output += lipgloss.NewStyle().
+			Foreground(textMuted).
+			Render("Nothing here yet — places you visit show up here, and f pins them.")
+	}
+
+	for i, v := range entries {
+		marker := "  "
+		if m.History.IsFavorite(v) {
+			marker = "★ "
+		}
+
+		line := fmt.Sprintf("%s%s", marker, v.Label)
+		style := lipgloss.NewStyle().Foreground(textPrimary).Width(m.Width - 12)
+		if i == m.PaletteIndex {
+			style = style.Background(bgHighlight).Foreground(accentPrimary).Bold(true)
+		}
+
+		kind := lipgloss.NewStyle().Foreground(textMuted).Render(v.Kind)
+		output += style.Render(line) + " " + kind + "\n"
+	}
+
+	return contentStyle.Width(m.Width - 4).Render(output)
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showLoadError replaces a view that failed to load with the error view,
+// remembering the view so it can be loaded again from there.
+func (m *Model) showLoadError(view ViewType, err error) {
+	m.IsLoading = false
+	m.FailedView = view
+	m.ErrorMsg = err.Error()
+	m.CurrentView = ViewError
+}
+
+// errorActions is the action bar of the error view, offering only what
+// applies: going offline needs a sync to read from, and signing in again
+// needs the config.
+func (m Model) errorActions() string {
+	if m.FailedView == ViewMainMenu {
+		return ""
+	}
+	actions := []string{"r to retry"}
+	if m.OfflineClient != nil && !m.Offline {
+		actions = append(actions, "o to go offline")
+	}
+	if m.Config != nil {
+		actions = append(actions, "l to sign in again")
+	}
+	return "Press " + strings.Join(actions, "  •  ")
+}
+
+func (m Model) handleErrorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.FailedView == ViewMainMenu {
+		return m, nil
+	}
+	switch {
+	case key.Matches(msg, keys.Refresh):
+		return m.retryLoad()
+	case key.Matches(msg, keys.Offline) && m.OfflineClient != nil && !m.Offline:
+		m.Offline = true
+		return m.retryLoad()
+	case key.Matches(msg, keys.Relogin) && m.Config != nil:
+		m.CurrentView = ViewAuthRequired
+		m.RetryAfterLogin = true
+		return m.beginLogin()
+	}
+	return m, nil
+}
+
+// retryLoad loads the view that failed again.
+func (m Model) retryLoad() (tea.Model, tea.Cmd) {
+	view := m.FailedView
+	m.FailedView = ViewMainMenu
+	m.ErrorMsg = ""
+	m.CurrentView = view
+	return m, m.loadView(view)
+}
+
+// client is the ClientFunc for reading Classroom: the last sync once the
+// user has chosen to work offline.
+func (m Model) client() ClientFunc {
+	if m.Offline && m.OfflineClient != nil {
+		return m.OfflineClient
+	}
+	return m.NewClient
+}
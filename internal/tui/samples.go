@@ -0,0 +1,52 @@
+package tui
+
+import "github.com/timboy697/gc-cli/internal/agenda"
+
+// Sample data for previews, where the TUI runs without a client.
+
+func sampleCourses() []CourseItem {
+	return []CourseItem{
+		{ID: "course-1", Name: "CS 101: Introduction to Computer Science", Section: "Fall 2024", Desc: "Fundamental concepts of programming", Room: "Building A, Room 101"},
+		{ID: "course-2", Name: "MATH 201: Linear Algebra", Section: "Fall 2024", Desc: "Vector spaces, linear transformations", Room: "Building B, Room 205"},
+		{ID: "course-3", Name: "PHYS 150: General Physics I", Section: "Fall 2024", Desc: "Mechanics, thermodynamics, waves", Room: "Science Building, Room 302"},
+	}
+}
+
+func sampleCoursework() []CourseworkItem {
+	return []CourseworkItem{
+		{ID: "cw-1", CourseID: "course-1", Link: "https://classroom.google.com/c/course-1/a/cw-1/details", CourseName: "CS 101", AssignTitle: "Programming Assignment 1", Desc: "Implement a basic calculator", State: "PUBLISHED", DueDate: "2024-09-15", DueTime: "23:59", Points: 100, Status: StatusReturned, WorkType: "ASSIGNMENT", Expects: agenda.ExpectsFile, Materials: []MaterialItem{
+			{Kind: "youtube", Title: "Calculator walkthrough", URL: "https://www.youtube.com/watch?v=zOjov-2OZ0E", ThumbnailURL: "https://i.ytimg.com/vi/zOjov-2OZ0E/mqdefault.jpg"},
+			{Kind: "drive", Title: "Assignment 1 starter code", URL: "https://drive.google.com/file/d/starter-1/view"},
+		}},
+		{ID: "cw-2", CourseID: "course-1", Link: "https://classroom.google.com/c/course-1/a/cw-2/details", CourseName: "CS 101", AssignTitle: "Quiz 1: Variables and Data Types", Desc: "Online quiz on data types", State: "PUBLISHED", DueDate: "2024-09-20", DueTime: "23:59", Points: 20, Status: StatusReturned, WorkType: "QUIZ", Expects: agenda.ExpectsQuiz},
+		{ID: "cw-3", CourseID: "course-1", Link: "https://classroom.google.com/c/course-1/a/cw-3/details", CourseName: "CS 101", AssignTitle: "Programming Assignment 2", Desc: "OOP concepts", State: "PUBLISHED", DueDate: "2024-10-15", DueTime: "23:59", Points: 100, Status: StatusTurnedIn, WorkType: "ASSIGNMENT", Expects: agenda.ExpectsAddOn, Materials: []MaterialItem{
+			{Kind: "addon", Title: "Kami: Class diagram worksheet", URL: "https://web.kamihq.com/web/viewer.html?source=classroom&id=oop-diagram"},
+		}},
+		{ID: "cw-4", CourseID: "course-2", Link: "https://classroom.google.com/c/course-2/a/cw-4/details", CourseName: "MATH 201", AssignTitle: "Homework 1: Vectors", Desc: "Problems from Chapter 1", State: "PUBLISHED", DueDate: "2024-09-18", DueTime: "23:59", Points: 50, Status: StatusReturned, WorkType: "ASSIGNMENT", Expects: agenda.ExpectsFile},
+		{ID: "cw-5", CourseID: "course-2", Link: "https://classroom.google.com/c/course-2/a/cw-5/details", CourseName: "MATH 201", AssignTitle: "Homework 2: Matrices", Desc: "Problems from Chapter 2", State: "PUBLISHED", DueDate: "2024-09-25", DueTime: "23:59", Points: 50, Status: StatusTurnedIn, WorkType: "ASSIGNMENT", Expects: agenda.ExpectsFile},
+		{ID: "cw-6", CourseID: "course-3", Link: "https://classroom.google.com/c/course-3/a/cw-6/details", CourseName: "PHYS 150", AssignTitle: "Lab Report 1: Motion", Desc: "Motion experiment writeup", State: "PUBLISHED", DueDate: "2024-09-22", DueTime: "17:00", Points: 50, Status: StatusReturned, WorkType: "ASSIGNMENT", Expects: agenda.ExpectsFile},
+		{ID: "cw-7", CourseID: "course-2", Link: "https://classroom.google.com/c/course-2/a/cw-7/details", CourseName: "MATH 201", AssignTitle: "Midterm Exam", Desc: "Covers chapters 1-3", State: "PUBLISHED", DueDate: "2024-10-01", DueTime: "14:00", Points: 100, Status: StatusMissing, WorkType: "EXAM", Expects: agenda.ExpectsFile},
+		{ID: "cw-8", CourseID: "course-1", Link: "https://classroom.google.com/c/course-1/a/cw-8/details", CourseName: "CS 101", AssignTitle: "Lab 3: Debugging", Desc: "Debugging practice", State: "DRAFT", DueDate: "", DueTime: "", Points: 25, Status: StatusDraft, WorkType: "ASSIGNMENT", Expects: agenda.ExpectsFile},
+	}
+}
+
+func sampleGrades() []GradeItem {
+	return []GradeItem{
+		{CourseName: "CS 101", Assignment: "Programming Assignment 1", Score: "95", MaxScore: "100", SubmittedAt: "2024-09-15"},
+		{CourseName: "CS 101", Assignment: "Quiz 1", Score: "18", MaxScore: "20", SubmittedAt: "2024-09-20"},
+		{CourseName: "MATH 201", Assignment: "Homework 1", Score: "90", MaxScore: "100", SubmittedAt: "2024-09-18"},
+		{CourseName: "MATH 201", Assignment: "Midterm Exam", Score: "82", MaxScore: "100", SubmittedAt: "2024-10-10"},
+		{CourseName: "PHYS 150", Assignment: "Lab Report 1", Score: "48", MaxScore: "50", SubmittedAt: "2024-09-22"},
+	}
+}
+
+func sampleAnnouncements() []AnnouncementItem {
+	return []AnnouncementItem{
+		{ID: "ann-1", Link: "https://classroom.google.com/u/0/c/ann-1", Author: "Dr. Alice Smith", CourseName: "CS 101", AnnounceTitle: "Assignment 2 Posted", Text: "The second programming assignment has been posted. Due October 15th.", PostedAt: "2024-10-01"},
+		{ID: "ann-2", Link: "https://classroom.google.com/u/0/c/ann-2", Author: "Prof. Ravi Patel", CourseName: "MATH 201", AnnounceTitle: "Office Hours Change", Text: "Office hours this week will be Thursday 2-4 PM.", PostedAt: "2024-10-02"},
+		{ID: "ann-3", Link: "https://classroom.google.com/u/0/c/ann-3", Author: "Dr. Maria Gomez", CourseName: "PHYS 150", AnnounceTitle: "Lab Safety Reminder", Text: "Please review lab safety procedures before your session.", PostedAt: "2024-09-28"},
+		{ID: "ann-4", Link: "https://classroom.google.com/u/0/c/ann-4", Author: "Dr. Alice Smith", CourseName: "CS 101", AnnounceTitle: "Guest Lecture Next Week", Text: "Guest speaker from Google next Tuesday.", PostedAt: "2024-10-03", Materials: []MaterialItem{
+			{Kind: "link", Title: "Speaker bio", URL: "https://example.com/speakers/guest"},
+		}},
+	}
+}
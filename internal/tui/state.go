@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"github.com/timboy697/gc-cli/internal/store"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectedID is the ID of the highlighted row in a list view, or "" for
+// views without a selection.
+func (m Model) selectedID(view ViewType) string {
+	switch view {
+	case ViewCoursework:
+		if m.SelectedCoursework < len(m.Coursework) {
+			return m.Coursework[m.SelectedCoursework].ID
+		}
+	case ViewAnnouncements:
+		if m.SelectedAnnouncement < len(m.Announcements) {
+			return m.Announcements[m.SelectedAnnouncement].ID
+		}
+	}
+	return ""
+}
+
+// rememberView notes the selection and scroll position of the view on
+// screen, so reopening it later lands in the same place.
+func (m *Model) rememberView() {
+	switch m.CurrentView {
+	case ViewCourses, ViewCoursework, ViewGrades, ViewAnnouncements:
+		m.ViewState.Views[viewNames[m.CurrentView]] = store.TUIViewState{
+			Selected: m.selectedID(m.CurrentView),
+			Scroll:   m.Viewport.YOffset,
+		}
+	case ViewCourseworkDetail, ViewAnnouncementDetail:
+		m.ViewState.DetailScroll = m.Viewport.YOffset
+	}
+}
+
+// restoreViewState puts a freshly loaded list view back where it was left.
+// A selected item that has since disappeared leaves the top row selected.
+func (m *Model) restoreViewState(view ViewType) {
+	vs, ok := m.ViewState.Views[viewNames[view]]
+	if !ok || m.CurrentView != view {
+		return
+	}
+	switch view {
+	case ViewCoursework:
+		for i, cw := range m.Coursework {
+			if cw.ID == vs.Selected {
+				m.SelectedCoursework = i
+				m.updateViewport(m.renderCoursework())
+			}
+		}
+	case ViewAnnouncements:
+		for i, ann := range m.Announcements {
+			if ann.ID == vs.Selected {
+				m.SelectedAnnouncement = i
+				m.updateViewport(m.renderAnnouncements())
+			}
+		}
+	}
+	m.Viewport.SetYOffset(vs.Scroll)
+}
+
+// restoreState reopens the view the last session ended in, and the item
+// that was open in it, if any, once the view has loaded. It returns the
+// command that loads it.
+func (m *Model) restoreState() tea.Cmd {
+	ts := m.ViewState
+	if ts.Menu > 0 && ts.Menu < len(m.Menu.Items()) {
+		m.Menu.Select(ts.Menu)
+	}
+	if m.AuthState != AuthAuthenticated {
+		return nil
+	}
+	view, ok := viewByName(ts.View)
+	if !ok {
+		return nil
+	}
+	next, cmd := m.openView(view)
+	*m = next.(Model)
+	if ts.Detail && m.load != nil {
+		m.load.open = ts.Views[ts.View].Selected
+		m.load.scroll = ts.DetailScroll
+	}
+	return cmd
+}
+
+// saveState records where the session ended. Quitting always passes
+// through the main menu, so the view left for it is what gets reopened.
+// Like history, the state is saved best effort.
+func (m *Model) saveState() {
+	if m.Store == nil {
+		return
+	}
+	m.rememberView()
+
+	place := m.CurrentView
+	if place == ViewMainMenu {
+		place = m.PreviousView
+	}
+	ts := m.ViewState
+	ts.Menu = m.Menu.Index()
+	ts.View, ts.Detail = viewNames[place], false
+	switch place {
+	case ViewCourseworkDetail:
+		ts.View, ts.Detail = viewNames[ViewCoursework], true
+	case ViewAnnouncementDetail:
+		ts.View, ts.Detail = viewNames[ViewAnnouncements], true
+	}
+	_ = m.Store.SaveTUIState(ts)
+}
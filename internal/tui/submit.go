@@ -0,0 +1,287 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SubmitProgress is one update from a running submit: a step started, or
+// more of the file reached Drive.
+type SubmitProgress struct {
+	Step  string
+	Sent  int64
+	Total int64
+}
+
+// SubmitFunc uploads the file at path, attaches it to my submission for the
+// coursework and turns it in, calling progress as it goes.
+type SubmitFunc func(ctx context.Context, courseID, courseWorkID, path string, progress func(SubmitProgress)) error
+
+// Submitter holds the submit view: the path being typed, then the upload's
+// progress.
+type Submitter struct {
+	Work CourseworkItem
+	Path textinput.Model
+
+	Running bool
+	Steps   []string
+	Sent    int64
+	Total   int64
+	Err     string
+
+	updates chan tea.Msg
+	cancel  context.CancelFunc
+}
+
+type submitProgressMsg SubmitProgress
+
+type submitDoneMsg struct {
+	err error
+}
+
+const submitBarWidth = 30
+
+func (m Model) openSubmit() (tea.Model, tea.Cmd) {
+	cw := m.Coursework[m.SelectedCoursework]
+	switch {
+	case cw.Status == StatusTurnedIn || cw.Status == StatusReturned:
+		m.Notice = "Already turned in"
+		return m, nil
+	case cw.Status == StatusDraft || cw.Status == StatusScheduled:
+		m.Notice = "Only published work can be turned in"
+		return m, nil
+	case !cw.Expects.InCLI():
+		m.Notice = "This is done in the browser; open it and press Y to copy its link"
+		return m, nil
+	case m.Submit == nil:
+		m.Notice = "Submitting is not available"
+		return m, nil
+	}
+
+	path := textinput.New()
+	path.Placeholder = "~/Documents/essay.pdf"
+	path.Width = m.Width - 24
+	path.Focus()
+
+	m.Submitter = &Submitter{Work: cw, Path: path}
+	m.PreviousView = m.CurrentView
+	m.CurrentView = ViewSubmit
+	return m, tea.Batch(m.leaveThumbnails(), textinput.Blink)
+}
+
+func (m Model) handleSubmitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.Submitter
+	if s.Running {
+		// Stopping leaves the submit in the journal, to finish later.
+		if msg.String() == "esc" || msg.String() == "ctrl+c" {
+			s.cancel()
+		}
+		return m, nil
+	}
+	s.Err = ""
+
+	switch msg.String() {
+	case "esc":
+		m.Submitter = nil
+		m.CurrentView = m.PreviousView
+		return m, m.scheduleThumbnailDraw()
+	case "tab":
+		s.Path.SetValue(completePath(s.Path.Value()))
+		s.Path.CursorEnd()
+		return m, nil
+	case "enter":
+		path := expandHome(strings.TrimSpace(s.Path.Value()))
+		info, err := os.Stat(path)
+		switch {
+		case path == "":
+			s.Err = "Type the path of the file to turn in"
+		case err != nil:
+			s.Err = "Can't read " + path
+		case info.IsDir():
+			s.Err = path + " is a folder, not a file"
+		default:
+			return m, m.startSubmit(path)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	s.Path, cmd = s.Path.Update(msg)
+	return m, cmd
+}
+
+// startSubmit runs the submit in the background, passing its progress back
+// through a channel that waitSubmit reads one message at a time.
+func (m Model) startSubmit(path string) tea.Cmd {
+	s := m.Submitter
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Running, s.Steps, s.Sent, s.Total = true, nil, 0, 0
+	s.cancel = cancel
+	s.updates = make(chan tea.Msg, 16)
+
+	submit, cw, updates := m.Submit, s.Work, s.updates
+	go func() {
+		defer cancel()
+		err := submit(ctx, cw.CourseID, cw.ID, path, func(p SubmitProgress) {
+			updates <- submitProgressMsg(p)
+		})
+		updates <- submitDoneMsg{err: err}
+	}()
+	return waitSubmit(updates)
+}
+
+func waitSubmit(updates chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+func (m Model) handleSubmitMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	s := m.Submitter
+	if s == nil {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case submitProgressMsg:
+		if msg.Step != "" {
+			s.Steps = append(s.Steps, msg.Step)
+		} else {
+			s.Sent, s.Total = msg.Sent, msg.Total
+		}
+		return m, waitSubmit(s.updates)
+
+	case submitDoneMsg:
+		s.Running = false
+		if msg.err != nil {
+			if errors.Is(msg.err, context.Canceled) {
+				s.Err = "Stopped. Finish it later with 'gc-cli submit journal'"
+			} else {
+				s.Err = msg.err.Error()
+			}
+			return m, nil
+		}
+
+		for i := range m.Coursework {
+			if m.Coursework[i].ID == s.Work.ID {
+				m.Coursework[i].Status = StatusTurnedIn
+			}
+		}
+		m.Submitter = nil
+		m.CurrentView = m.PreviousView
+		if m.CurrentView == ViewCourseworkDetail {
+			m.updateViewport(m.renderCourseworkDetail())
+		} else {
+			m.updateViewport(m.renderCoursework())
+		}
+		m.Notice = "Turned in " + s.Work.AssignTitle
+		return m, m.scheduleThumbnailDraw()
+	}
+	return m, nil
+}
+
+// expandHome turns a leading ~ into the home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// completePath extends path as far as the files it could name agree, adding
+// a slash once it names a single folder. Hidden files are only offered once
+// a dot has been typed.
+func completePath(path string) string {
+	dir, prefix := filepath.Split(path)
+	readDir := expandHome(dir)
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return path
+	}
+
+	var matches []os.DirEntry
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), prefix) && (strings.HasPrefix(prefix, ".") || !strings.HasPrefix(e.Name(), ".")) {
+			matches = append(matches, e)
+		}
+	}
+	if len(matches) == 0 {
+		return path
+	}
+
+	common := matches[0].Name()
+	for _, e := range matches[1:] {
+		n := 0
+		for n < len(common) && n < len(e.Name()) && common[n] == e.Name()[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	if len(matches) == 1 && matches[0].IsDir() {
+		common += string(filepath.Separator)
+	}
+	return dir + common
+}
+
+func (m Model) renderSubmit() string {
+	s := m.Submitter
+	cw := s.Work
+
+	due := cw.DueDate
+	if cw.DueTime != "" {
+		due += " " + cw.DueTime
+	}
+	if due == "" {
+		due = "-"
+	}
+
+	var output string
+	output += sectionTitleStyle.Width(m.Width-8).Render(cw.AssignTitle) + "\n"
+	output += composeLabelStyle.Render("Course") + infoValueStyle.Render(cw.CourseName) + "\n"
+	output += composeLabelStyle.Render("Due") + infoValueStyle.Render(due) + "\n\n"
+	output += composeLabelStyle.Render("File") + s.Path.View() + "\n"
+
+	muted := lipgloss.NewStyle().Foreground(textMuted)
+	if !s.Running && len(s.Steps) == 0 {
+		output += muted.Render("The file is uploaded to your Drive, attached and turned in.") + "\n"
+	}
+
+	if len(s.Steps) > 0 {
+		output += "\n"
+		for i, step := range s.Steps {
+			if i == len(s.Steps)-1 && s.Running {
+				output += lipgloss.NewStyle().Foreground(accentPrimary).Render("⟳ "+step) + "\n"
+			} else {
+				output += muted.Render("✓ "+step) + "\n"
+			}
+		}
+	}
+	if s.Total > 0 {
+		filled := int(s.Sent * submitBarWidth / s.Total)
+		bar := lipgloss.NewStyle().Foreground(accentTertiary).Render(strings.Repeat("█", filled)) +
+			muted.Render(strings.Repeat("░", submitBarWidth-filled))
+		output += "\n" + bar + fmt.Sprintf(" %3d%%", s.Sent*100/s.Total) + "\n"
+	}
+
+	if s.Err != "" {
+		output += "\n" + lipgloss.NewStyle().Foreground(errorColor).Render("✗ "+s.Err)
+	}
+
+	return contentStyle.Width(m.Width - 4).Render(output)
+}
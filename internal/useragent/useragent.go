@@ -0,0 +1,59 @@
+// Package useragent identifies gc-cli on outgoing HTTP requests, as Google's
+// API guidelines ask, so requests can be told apart in logs and support
+// tickets.
+package useragent
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+var current = Build("dev", "")
+
+// Build returns e.g. "gc-cli/1.4.0 (linux; amd64)". suffix is appended as
+// is, so school deployments can tag their builds.
+func Build(version, suffix string) string {
+	ua := "gc-cli/" + version + " (" + runtime.GOOS + "; " + runtime.GOARCH + ")"
+	if suffix = strings.TrimSpace(suffix); suffix != "" {
+		ua += " " + suffix
+	}
+	return ua
+}
+
+// Set changes the User-Agent sent from now on. main calls it once the
+// version and config are known.
+func Set(version, suffix string) {
+	current = Build(version, suffix)
+}
+
+func String() string {
+	return current
+}
+
+// Transport sets the User-Agent on each request before passing it to Base
+// (http.DefaultTransport if nil).
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", current)
+	return base.RoundTrip(req)
+}
+
+var client = &http.Client{Transport: &Transport{}}
+
+// Context returns ctx set up so that oauth2 token exchanges and refreshes,
+// and clients built with oauth2.NewClient, send the User-Agent.
+func Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, client)
+}
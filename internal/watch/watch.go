@@ -0,0 +1,197 @@
+// Package watch finds what changed in Classroom since the last look: new
+// and edited assignments, new announcements, returned grades and deadlines
+// coming up.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/offline"
+	"github.com/timboy697/gc-cli/internal/store"
+)
+
+// Event types.
+const (
+	CourseworkPosted   = "coursework.posted"
+	CourseworkEdited   = "coursework.edited"
+	AnnouncementPosted = "announcement.posted"
+	GradeReturned      = "grade.returned"
+	DeadlineSoon       = "deadline.soon"
+)
+
+// Event is one change, shaped for printing as a line of JSON.
+type Event struct {
+	Type     string    `json:"type"`
+	Detected time.Time `json:"detected"`
+	CourseID string    `json:"courseId"`
+	Course   string    `json:"course"`
+	// ItemID is the coursework or announcement the event is about.
+	ItemID string `json:"itemId"`
+	Title  string `json:"title"`
+	Link   string `json:"link,omitempty"`
+	// Posted is when the teacher published the item.
+	Posted *time.Time `json:"posted,omitempty"`
+	Due    *time.Time `json:"due,omitempty"`
+	// Fields lists what an edit changed: title, due or points.
+	Fields    []string `json:"fields,omitempty"`
+	Grade     *float64 `json:"grade,omitempty"`
+	MaxPoints int64    `json:"maxPoints,omitempty"`
+}
+
+// announcementsPerPoll is how many of a course's latest announcements are
+// checked; more than this between polls is unlikely.
+const announcementsPerPoll = 20
+
+// Course is one poll's view of a course.
+type Course struct {
+	Course        api.Course
+	Items         []agenda.Item
+	Announcements []api.Announcement
+}
+
+// Fetch reads the active courses with their published coursework, my
+// submissions and recent announcements.
+func Fetch(ctx context.Context, client api.ClassroomService) ([]Course, error) {
+	courses, _, err := client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list courses: %w", err)
+	}
+
+	var result []Course
+	for _, course := range courses {
+		if course.CourseState != api.CourseActive {
+			continue
+		}
+		items, err := agenda.CollectCourse(ctx, client, course)
+		if err != nil {
+			return nil, err
+		}
+		announcements, _, err := client.ListAnnouncements(ctx, course.ID, announcementsPerPoll)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list announcements for %s: %w", course.Name, err)
+		}
+		result = append(result, Course{Course: course, Items: items, Announcements: announcements})
+	}
+	return result, nil
+}
+
+// Diff compares a poll with what ws last saw, returns the changes and
+// records the poll in ws. Courses seen for the first time only set the
+// baseline, so starting to watch doesn't replay the whole term. Work not
+// turned in and due within dueSoon is reported once per deadline, from the
+// first poll on; 0 turns that off. After ws was recovered from damage those
+// reminders are only recorded, since they may have been sent before.
+func Diff(ws *store.WatchState, courses []Course, now time.Time, dueSoon time.Duration) []Event {
+	var events []Event
+	for _, c := range courses {
+		known := ws.Courses[c.Course.ID]
+		ws.Courses[c.Course.ID] = true
+		base := Event{Detected: now, CourseID: c.Course.ID, Course: c.Course.Name}
+
+		for _, item := range c.Items {
+			cw := item.Work
+			next := store.CourseWorkVersion{Seen: now, CourseID: cw.CourseID, Title: cw.Title, Due: item.Due(), MaxPoints: cw.MaxPoints}
+			prev, seen := ws.Coursework[cw.ID]
+			ws.Coursework[cw.ID] = next
+
+			e := base
+			e.ItemID, e.Title, e.Link, e.MaxPoints = cw.ID, cw.Title, cw.AlternateLink, cw.MaxPoints
+			if due := item.Due(); !due.IsZero() {
+				e.Due = &due
+			}
+			switch {
+			case !known:
+			case !seen:
+				e.Type = CourseworkPosted
+				if !cw.CreateTime.IsZero() {
+					posted := cw.CreateTime
+					e.Posted = &posted
+				}
+				events = append(events, e)
+			default:
+				if fields := editedFields(prev, next); len(fields) > 0 {
+					e.Type = CourseworkEdited
+					e.Fields = fields
+					events = append(events, e)
+				}
+			}
+
+			if due := item.Due(); dueSoon > 0 && !item.Done() && due.After(now) && due.Sub(now) <= dueSoon {
+				if reminded, ok := ws.Reminded[cw.ID]; !ok || !reminded.Equal(due) {
+					ws.Reminded[cw.ID] = due
+					if !ws.Recovered {
+						soon := e
+						soon.Type, soon.Fields, soon.Posted = DeadlineSoon, nil, nil
+						events = append(events, soon)
+					}
+				}
+			} else if due.Before(now) {
+				delete(ws.Reminded, cw.ID)
+			}
+
+			sub := item.Submission
+			if sub == nil || sub.State != api.SubmissionReturned {
+				continue
+			}
+			grade, graded := ws.Grades[sub.ID]
+			ws.Grades[sub.ID] = sub.AssignedGrade
+			if known && (!graded || grade != sub.AssignedGrade) {
+				g := sub.AssignedGrade
+				e.Type = GradeReturned
+				e.Fields = nil
+				e.Grade = &g
+				events = append(events, e)
+			}
+		}
+
+		for _, a := range c.Announcements {
+			if ws.Announcements[a.ID] {
+				continue
+			}
+			ws.Announcements[a.ID] = true
+			if !known {
+				continue
+			}
+			e := base
+			e.Type = AnnouncementPosted
+			e.ItemID, e.Title, e.Link = a.ID, headline(a.Text), a.AlternateLink
+			if !a.CreationTime.IsZero() {
+				posted := a.CreationTime
+				e.Posted = &posted
+			}
+			events = append(events, e)
+		}
+	}
+	ws.Checked = now
+	ws.Recovered = false
+	return events
+}
+
+func editedFields(a, b store.CourseWorkVersion) []string {
+	var fields []string
+	if a.Title != b.Title {
+		fields = append(fields, offline.FieldTitle)
+	}
+	if !a.Due.Equal(b.Due) {
+		fields = append(fields, offline.FieldDue)
+	}
+	if a.MaxPoints != b.MaxPoints {
+		fields = append(fields, offline.FieldPoints)
+	}
+	return fields
+}
+
+// headline is the first line of an announcement, shortened to fit a
+// notification.
+func headline(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	if r := []rune(line); len(r) > 80 {
+		return string(r[:77]) + "..."
+	}
+	return line
+}
@@ -0,0 +1,233 @@
+package web
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/timboy697/gc-cli/internal/agenda"
+	"github.com/timboy697/gc-cli/internal/api"
+	"github.com/timboy697/gc-cli/internal/clock"
+	"github.com/timboy697/gc-cli/internal/courseorder"
+	"github.com/timboy697/gc-cli/internal/gradebook"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
+
+// Server renders a read-only dashboard of upcoming work, grades and
+// announcements across all active courses.
+type Server struct {
+	client api.ClassroomService
+	scale  gradebook.Scale
+	clock  clock.Clock
+	order  *courseorder.Order
+}
+
+func NewServer(client api.ClassroomService, scale gradebook.Scale, clk clock.Clock, order *courseorder.Order) *Server {
+	return &Server{client: client, scale: scale, clock: clk, order: order}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleDashboard)
+	return mux
+}
+
+type upcomingItem struct {
+	Course string
+	Title  string
+	Due    time.Time
+	Link   string
+	// Window is e.g. "opens in 2h" for scheduled work.
+	Window string
+}
+
+type gradeRow struct {
+	Course  string
+	Points  string
+	Percent string
+	Letter  string
+	Missing int
+}
+
+type announcementItem struct {
+	Course string
+	Text   string
+	Posted time.Time
+	Link   string
+}
+
+type dashboard struct {
+	Generated     time.Time
+	Upcoming      []upcomingItem
+	Grades        []gradeRow
+	Announcements []announcementItem
+}
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := s.load(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := templates.ExecuteTemplate(w, "dashboard.html", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// maxCourseFetches bounds how many courses load fetches at once.
+const maxCourseFetches = 4
+
+// announcementsPerCourse is how many of each course's latest announcements
+// the dashboard considers; only the newest 20 overall are shown.
+const announcementsPerCourse = 10
+
+func (s *Server) load(ctx context.Context) (*dashboard, error) {
+	courses, _, err := s.client.ListCourses(ctx, 100, api.WithCourseStates(api.CourseActive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list courses: %w", err)
+	}
+
+	now := s.clock.Now()
+	data := &dashboard{Generated: now}
+
+	s.order.SortCourses(courses)
+
+	// The first failure cancels the other fetches.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*dashboard, len(courses))
+	sem := make(chan struct{}, maxCourseFetches)
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i, course := range courses {
+		if course.CourseState != api.CourseActive {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, course api.Course) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			part, err := s.loadCourse(ctx, course, now)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = part
+		}(i, course)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	// Merged in course order, so grades stay in period order.
+	for _, part := range results {
+		if part == nil {
+			continue
+		}
+		data.Upcoming = append(data.Upcoming, part.Upcoming...)
+		data.Grades = append(data.Grades, part.Grades...)
+		data.Announcements = append(data.Announcements, part.Announcements...)
+	}
+
+	// Stable, so work due at the same time stays in period order.
+	sort.SliceStable(data.Upcoming, func(i, j int) bool {
+		return data.Upcoming[i].Due.Before(data.Upcoming[j].Due)
+	})
+	sort.Slice(data.Announcements, func(i, j int) bool {
+		return data.Announcements[i].Posted.After(data.Announcements[j].Posted)
+	})
+	if len(data.Announcements) > 20 {
+		data.Announcements = data.Announcements[:20]
+	}
+
+	return data, nil
+}
+
+// loadCourse fetches one course's part of the dashboard.
+func (s *Server) loadCourse(ctx context.Context, course api.Course, now time.Time) (*dashboard, error) {
+	label := s.order.Label(course.ID, course.Name)
+	data := &dashboard{}
+
+	coursework, _, err := s.client.ListCourseWork(ctx, course.ID, 100)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coursework for %s: %w", course.Name, err)
+	}
+	submissions, _, err := s.client.ListStudentSubmissions(ctx, course.ID, "-", 100, api.WithSubmissionFilter(api.SubmissionFilter{UserID: "me"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list submissions for %s: %w", course.Name, err)
+	}
+	announcements, _, err := s.client.ListAnnouncements(ctx, course.ID, announcementsPerCourse, api.WithLimit(announcementsPerCourse))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list announcements for %s: %w", course.Name, err)
+	}
+
+	done := make(map[string]bool)
+	for _, sub := range submissions {
+		if sub.State.Done() {
+			done[sub.CourseWorkID] = true
+		}
+	}
+
+	for _, cw := range coursework {
+		// Scheduled work is listed too, so it isn't mistaken for missing.
+		visible := cw.State == api.CourseWorkPublished || !agenda.OpensAt(cw, now).IsZero()
+		if !visible || cw.DueDate == nil || done[cw.ID] {
+			continue
+		}
+		due := agenda.DueTime(cw)
+		if due.Before(now) {
+			continue
+		}
+		data.Upcoming = append(data.Upcoming, upcomingItem{
+			Course: label,
+			Title:  cw.Title,
+			Due:    due,
+			Link:   cw.AlternateLink,
+			Window: agenda.Window(cw, now),
+		})
+	}
+
+	summary := gradebook.Summarize(coursework, submissions)
+	row := gradeRow{Course: label, Points: "-", Percent: "-", Missing: summary.Missing}
+	if pct, ok := summary.Percent(); ok {
+		row.Points = fmt.Sprintf("%.1f/%.0f", summary.Earned, summary.Possible)
+		row.Percent = fmt.Sprintf("%.1f%%", pct)
+		row.Letter = s.scale.Letter(pct)
+	}
+	data.Grades = append(data.Grades, row)
+
+	for _, a := range announcements {
+		data.Announcements = append(data.Announcements, announcementItem{
+			Course: label,
+			Text:   a.Text,
+			Posted: a.CreationTime,
+			Link:   a.AlternateLink,
+		})
+	}
+	return data, nil
+}